@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
 	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+	"github.com/crypto-trading-connector/backend/internal/service"
+	"github.com/crypto-trading-connector/backend/pkg/database"
 	"github.com/crypto-trading-connector/backend/utils"
 	"github.com/joho/godotenv"
 )
@@ -16,76 +21,138 @@ func main() {
 		log.Println("Warning: .env file not found, using environment variables")
 	}
 
-	// Get bitFlyer API credentials from environment
-	apiURL := utils.GetEnv("BITFLYER_API_URL", "https://api.bitflyer.com")
-	apiKey := utils.GetEnv("BITFLYER_API_KEY", "")
-	apiSecret := utils.GetEnv("BITFLYER_API_SECRET", "")
+	// Connect to database to follow the trading halt of the server
+	db, err := database.Connect(database.LoadConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Error: failed to connect to database: %v", err)
+	}
+	defer db.Close()
+	tradingHaltRepo := repository.NewMySQLTradingHaltRepository(db)
+
+	// Orders are sent to the exchange their pair is routed to (EXCHANGE_ROUTES), as in the server; tickers go
+	// through the same cache as the server, and no order is sent while the trading halt is engaged
+	bitflyerAPIKey := utils.GetEnv("BITFLYER_API_KEY", "")
+	bitflyerAPISecret := utils.GetEnv("BITFLYER_API_SECRET", "")
+	gmocoinAPIKey := utils.GetEnv("GMOCOIN_API_KEY", "")
+	gmocoinAPISecret := utils.GetEnv("GMOCOIN_API_SECRET", "")
+	bitflyerClient := client.NewHaltGuard(
+		client.NewTickerCache(client.NewBitFlyerClientWithAuth(utils.GetEnv("BITFLYER_API_URL", "https://api.bitflyer.com"), bitflyerAPIKey, bitflyerAPISecret), client.DefaultTickerCacheConfig()),
+		tradingHaltRepo,
+	)
+	gmocoinClient := client.NewHaltGuard(
+		client.NewTickerCache(client.NewGMOCoinClientWithAuth(utils.GetEnv("GMOCOIN_API_URL", "https://api.coin.z.com"), gmocoinAPIKey, gmocoinAPISecret), client.DefaultTickerCacheConfig()),
+		tradingHaltRepo,
+	)
+	exchangeRoutes, err := client.ParseExchangeRoutes(utils.GetEnv("EXCHANGE_ROUTES", ""))
+	if err != nil {
+		log.Fatalf("Invalid EXCHANGE_ROUTES: %v", err)
+	}
+	exchangeClient, err := client.NewExchangeRouter(map[string]client.CryptoExchangeClient{
+		bitflyerClient.Name(): bitflyerClient,
+		gmocoinClient.Name():  gmocoinClient,
+	}, exchangeRoutes, bitflyerClient.Name())
+	if err != nil {
+		log.Fatalf("Invalid EXCHANGE_ROUTES: %v", err)
+	}
+	authenticated := map[string]bool{
+		bitflyerClient.Name(): bitflyerAPIKey != "" && bitflyerAPISecret != "",
+		gmocoinClient.Name():  gmocoinAPIKey != "" && gmocoinAPISecret != "",
+	}
 
-	if apiKey == "" || apiSecret == "" {
-		log.Fatal("Error: BITFLYER_API_KEY and BITFLYER_API_SECRET must be set in .env file")
+	ctx := context.Background()
+
+	// Load the listed assets and the minimum sizes and tick sizes of their products
+	assets, err := model.LoadAssetCatalog(utils.GetEnv("ASSET_CATALOG_FILE", ""))
+	if err != nil {
+		log.Fatalf("Error: failed to load asset catalog: %v", err)
+	}
+	// Every exchange receiving orders needs its API credentials
+	for _, asset := range assets.Assets() {
+		if exchange := client.ForSymbol(exchangeClient, asset.Pair).Name(); !authenticated[exchange] {
+			log.Fatalf("Error: the API key and secret of %s must be set in .env file to order %s", exchange, asset.Pair)
+		}
+	}
+	// Stop at once rather than rejecting every order
+	if err := client.CheckTradingHalt(ctx, tradingHaltRepo); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	products, err := service.LoadProductRegistry(ctx, exchangeClient, assets, utils.GetEnv("PRODUCT_OVERRIDES_FILE", ""))
+	if err != nil {
+		log.Fatalf("Error: failed to load products: %v", err)
 	}
 
-	// Initialize bitFlyer client with authentication
-	bitflyerClient := client.NewBitFlyerClientWithAuth(apiURL, apiKey, apiSecret)
+	// Orders go through the order service, as those of the order API: they are checked against the same risk
+	// limits (RISK_*) and balances, journaled before they are sent and saved in buy_orders
+	riskConfig, err := service.LoadRiskConfigFromEnv(assets)
+	if err != nil {
+		log.Fatalf("Error: invalid risk limits: %v", err)
+	}
+	orderRepo := repository.NewOrderRepository(db)
+	orderService := service.NewOrderService(exchangeClient, orderRepo, products)
+	orderService.SetRiskEngine(service.NewRiskEngine(service.NewRiskRules(riskConfig, orderRepo)...))
+	orderService.SetOrderJournal(repository.NewMySQLOrderJournalRepository(db))
+	orderService.SetTradingHalts(tradingHaltRepo)
 
 	fmt.Println("🚀 Starting buy order process...")
 	fmt.Println("=" + string(make([]byte, 50)) + "=")
 
-	// Place orders for both BTC and ETH
-	pairs := []struct {
-		productCode string
-		symbol      string
-		minSize     float64
-	}{
-		{"BTC_JPY", "BTC", 0.001},
-		{"ETH_JPY", "ETH", 0.01},
-	}
+	// Place an order for every asset in the catalog
+	for _, asset := range assets.Assets() {
+		symbol := asset.Pair
+		fmt.Printf("\n📊 Processing %s order...\n", symbol.Base())
 
-	for _, pair := range pairs {
-		fmt.Printf("\n📊 Processing %s order...\n", pair.symbol)
+		product, ok := products.Get(symbol)
+		if !ok {
+			log.Printf("❌ %s is not supported by %s", symbol, client.ForSymbol(exchangeClient, symbol).Name())
+			continue
+		}
 
 		// Get current price
-		ticker, err := bitflyerClient.GetTicker(pair.productCode)
+		ticker, err := exchangeClient.GetTicker(ctx, symbol)
 		if err != nil {
-			log.Printf("❌ Failed to get %s ticker: %v", pair.symbol, err)
+			log.Printf("❌ Failed to get %s ticker: %v", symbol.Base(), err)
+			continue
+		}
+
+		if ticker.Stale {
+			log.Printf("❌ %s price is stale, skipping the order", symbol.Base())
 			continue
 		}
 
-		currentPrice := ticker.Ltp
+		currentPrice := ticker.Last
 		fmt.Printf("   Current Price: ¥%.0f\n", currentPrice)
 
-		// Calculate 97% of current price and round to integer (bitFlyer requires integer prices)
-		orderPrice := float64(int(currentPrice * 0.97))
+		// Calculate 97% of current price and round down to the tick size
+		orderPrice := product.RoundPrice(currentPrice*0.97, model.OrderSideBuy)
 		fmt.Printf("   Order Price (97%%): ¥%.0f\n", orderPrice)
 
 		// Use minimum order size
-		orderSize := pair.minSize
-		fmt.Printf("   Order Size: %f %s\n", orderSize, pair.symbol)
+		orderSize := product.MinSize
+		fmt.Printf("   Order Size: %f %s\n", orderSize, symbol.Base())
 
 		estimatedTotal := orderPrice * orderSize
 		fmt.Printf("   Estimated Total: ¥%.2f\n", estimatedTotal)
 
-		// Create order request
-		orderReq := &model.BitFlyerOrderRequest{
-			ProductCode:    pair.productCode,
-			ChildOrderType: "LIMIT",
-			Side:           "BUY",
-			Price:          orderPrice,
-			Size:           orderSize,
-			TimeInForce:    "GTC", // Good Till Cancelled
+		// Create order request (limit orders are Good Till Cancelled)
+		orderReq := &generated.CreateOrderRequest{
+			Pair:      string(symbol),
+			Side:      generated.CreateOrderRequestSideBUY,
+			OrderType: generated.CreateOrderRequestOrderTypeLimit,
+			Price:     orderPrice,
+			Amount:    orderSize,
 		}
 
 		// Submit order
 		fmt.Printf("   Submitting order...\n")
-		orderResp, err := bitflyerClient.SendOrder(orderReq)
+		order, err := orderService.CreateOrder(ctx, nil, orderReq)
 		if err != nil {
-			log.Printf("❌ Failed to submit %s order: %v\n", pair.symbol, err)
+			log.Printf("❌ Failed to submit %s order: %v\n", symbol.Base(), err)
 			continue
 		}
 
 		// Display success
 		fmt.Printf("   ✅ Order submitted successfully!\n")
-		fmt.Printf("   Order ID: %s\n", orderResp.ChildOrderAcceptanceID)
+		fmt.Printf("   Order ID: %s\n", order.OrderId)
 	}
 
 	fmt.Println("\n" + string(make([]byte, 50)) + "=")
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+	"github.com/crypto-trading-connector/backend/pkg/database"
+	"github.com/crypto-trading-connector/backend/utils"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	productCode := flag.String("product", "BTC_JPY", "Product code of the order(s) to cancel (e.g. BTC_JPY, ETH_JPY)")
+	orderID := flag.String("id", "", "Child order acceptance ID of the order to cancel")
+	cancelAll := flag.Bool("all", false, "Cancel all open orders for the product")
+	flag.Parse()
+
+	if *orderID == "" && !*cancelAll {
+		log.Fatal("Error: specify either -id <acceptance ID> or -all")
+	}
+	if *orderID != "" && *cancelAll {
+		log.Fatal("Error: -id and -all cannot be used together")
+	}
+
+	// Load .env file
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, using environment variables")
+	}
+
+	// Connect to database to record the cancellation in buy_orders/sell_orders
+	db, err := database.Connect(database.LoadConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Error: failed to connect to database: %v", err)
+	}
+	defer db.Close()
+	orderRepo := repository.NewOrderRepository(db)
+
+	// Orders are cancelled on the exchange their pair is routed to (EXCHANGE_ROUTES), as in the server
+	bitflyerClient := client.NewBitFlyerClientWithAuth(
+		utils.GetEnv("BITFLYER_API_URL", "https://api.bitflyer.com"),
+		utils.GetEnv("BITFLYER_API_KEY", ""),
+		utils.GetEnv("BITFLYER_API_SECRET", ""),
+	)
+	gmocoinClient := client.NewGMOCoinClientWithAuth(
+		utils.GetEnv("GMOCOIN_API_URL", "https://api.coin.z.com"),
+		utils.GetEnv("GMOCOIN_API_KEY", ""),
+		utils.GetEnv("GMOCOIN_API_SECRET", ""),
+	)
+	exchangeRoutes, err := client.ParseExchangeRoutes(utils.GetEnv("EXCHANGE_ROUTES", ""))
+	if err != nil {
+		log.Fatalf("Invalid EXCHANGE_ROUTES: %v", err)
+	}
+	exchangeClient, err := client.NewExchangeRouter(map[string]client.CryptoExchangeClient{
+		bitflyerClient.Name(): bitflyerClient,
+		gmocoinClient.Name():  gmocoinClient,
+	}, exchangeRoutes, bitflyerClient.Name())
+	if err != nil {
+		log.Fatalf("Invalid EXCHANGE_ROUTES: %v", err)
+	}
+
+	// Accept both BTC_JPY and BTC/JPY
+	symbol := model.Symbol(strings.ReplaceAll(strings.ToUpper(*productCode), "_", "/"))
+	product := symbol.ProductCode()
+	exchange := client.ForSymbol(exchangeClient, symbol).Name()
+
+	ctx := context.Background()
+
+	if *cancelAll {
+		fmt.Printf("🛑 Cancelling all open %s orders on %s...\n", product, exchange)
+		if err := exchangeClient.CancelAllOrders(ctx, product); err != nil {
+			log.Fatalf("❌ Failed to cancel orders: %v", err)
+		}
+		fmt.Printf("✅ Cancel request for all %s orders accepted\n", product)
+		markCancelled(ctx, orderRepo, func(order model.OrderRecord) bool {
+			return order.ProductCode == product && (order.Exchange == "" || order.Exchange == exchange)
+		})
+		return
+	}
+
+	fmt.Printf("🛑 Cancelling order %s (%s) on %s...\n", *orderID, product, exchange)
+	if err := exchangeClient.CancelOrder(ctx, product, *orderID); err != nil {
+		log.Fatalf("❌ Failed to cancel order: %v", err)
+	}
+	fmt.Printf("✅ Cancel request for order %s accepted\n", *orderID)
+	markCancelled(ctx, orderRepo, func(order model.OrderRecord) bool {
+		return order.OrderID == *orderID
+	})
+}
+
+// markCancelled moves the recorded open orders matching the cancellation to CANCELLED, so that their funds are
+// no longer reserved before the synchronizer sees the cancellation. Orders changed in the meantime are left alone.
+func markCancelled(ctx context.Context, orderRepo repository.OrderRepository, cancelled func(order model.OrderRecord) bool) {
+	orders, err := orderRepo.ListOpenOrders(ctx)
+	if err != nil {
+		log.Printf("Warning: failed to list open orders, the synchronizer records the cancellation: %v", err)
+		return
+	}
+
+	for _, order := range orders {
+		if !cancelled(order) {
+			continue
+		}
+		updated, err := orderRepo.TransitionOrderStatus(ctx, order.Side, order.OrderID, order.Status, model.OrderStatusCancelled)
+		if err != nil {
+			log.Printf("Warning: failed to update order %s to %s: %v", order.OrderID, model.OrderStatusCancelled, err)
+			continue
+		}
+		if updated {
+			fmt.Printf("   Order %s recorded as %s\n", order.OrderID, model.OrderStatusCancelled)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -29,7 +30,7 @@ func main() {
 
 	// Get balance
 	fmt.Println("Fetching balance from bitFlyer API...")
-	balance, err := bitflyerClient.GetBalance()
+	balance, err := bitflyerClient.GetBalance(context.Background())
 	if err != nil {
 		log.Fatalf("Failed to get balance: %v", err)
 	}
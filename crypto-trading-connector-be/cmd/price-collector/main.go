@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+	"github.com/crypto-trading-connector/backend/internal/service"
+	"github.com/crypto-trading-connector/backend/pkg/database"
+	"github.com/crypto-trading-connector/backend/utils"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// Load .env file
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, using environment variables")
+	}
+
+	// Connect to database
+	db, err := database.Connect(database.LoadConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Prices only need the public API of each exchange
+	bitflyerClient := client.NewBitFlyerClient(utils.GetEnv("BITFLYER_API_URL", "https://api.bitflyer.com"))
+	gmocoinClient := client.NewGMOCoinClient(utils.GetEnv("GMOCOIN_API_URL", "https://api.coin.z.com"))
+
+	// Route every pair to the same exchange as the server (EXCHANGE_ROUTES)
+	exchangeRoutes, err := client.ParseExchangeRoutes(utils.GetEnv("EXCHANGE_ROUTES", ""))
+	if err != nil {
+		log.Fatalf("Invalid EXCHANGE_ROUTES: %v", err)
+	}
+	exchangeClient, err := client.NewExchangeRouter(map[string]client.CryptoExchangeClient{
+		bitflyerClient.Name(): client.NewResilientClient(bitflyerClient, client.DefaultResilienceConfig()),
+		gmocoinClient.Name():  client.NewResilientClient(gmocoinClient, client.DefaultResilienceConfig()),
+	}, exchangeRoutes, bitflyerClient.Name())
+	if err != nil {
+		log.Fatalf("Invalid EXCHANGE_ROUTES: %v", err)
+	}
+
+	// Sample every pair of the asset catalog
+	assets, err := model.LoadAssetCatalog(utils.GetEnv("ASSET_CATALOG_FILE", ""))
+	if err != nil {
+		log.Fatalf("Failed to load asset catalog: %v", err)
+	}
+
+	// Sample every PRICE_COLLECTOR_INTERVAL; price_ratio_24h compares with the sample closest to 24 hours
+	// earlier, if one was taken within PRICE_COLLECTOR_RATIO_TOLERANCE of that time
+	config := service.DefaultPriceCollectorConfig()
+	if config.Interval, err = time.ParseDuration(utils.GetEnv("PRICE_COLLECTOR_INTERVAL", "5m")); err != nil || config.Interval <= 0 {
+		log.Fatalf("Invalid PRICE_COLLECTOR_INTERVAL: %q", utils.GetEnv("PRICE_COLLECTOR_INTERVAL", "5m"))
+	}
+	if config.Tolerance, err = time.ParseDuration(utils.GetEnv("PRICE_COLLECTOR_RATIO_TOLERANCE", "30m")); err != nil {
+		log.Fatalf("Invalid PRICE_COLLECTOR_RATIO_TOLERANCE: %v", err)
+	}
+
+	collector := service.NewPriceCollector(exchangeClient, repository.NewMySQLPriceHistoryRepository(db), assets, config)
+
+	// Stop on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Price collector started (%d pairs, interval: %s)", len(assets.Assets()), config.Interval)
+	collector.Run(ctx)
+	log.Println("Price collector stopped")
+}
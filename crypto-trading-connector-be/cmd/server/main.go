@@ -1,11 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/crypto-trading-connector/backend/internal/client"
 	"github.com/crypto-trading-connector/backend/internal/handler"
+	"github.com/crypto-trading-connector/backend/internal/model"
 	"github.com/crypto-trading-connector/backend/internal/repository"
 	"github.com/crypto-trading-connector/backend/internal/service"
 	"github.com/crypto-trading-connector/backend/pkg/database"
@@ -44,29 +53,235 @@ func main() {
 	bitflyerAPIKey := utils.GetEnv("BITFLYER_API_KEY", "xxxx")
 	bitflyerAPISecret := utils.GetEnv("BITFLYER_API_SECRET", "xxxx")
 
-	var exchangeClient client.CryptoExchangeClient
+	var bitflyerClient *client.BitFlyerClient
 	if bitflyerAPIKey != "" && bitflyerAPISecret != "" {
-		exchangeClient = client.NewBitFlyerClientWithAuth(bitflyerAPIURL, bitflyerAPIKey, bitflyerAPISecret)
+		bitflyerClient = client.NewBitFlyerClientWithAuth(bitflyerAPIURL, bitflyerAPIKey, bitflyerAPISecret)
 		log.Println("Exchange client (bitFlyer) initialized with authentication")
 	} else {
-		exchangeClient = client.NewBitFlyerClient(bitflyerAPIURL)
+		bitflyerClient = client.NewBitFlyerClient(bitflyerAPIURL)
 		log.Println("Exchange client (bitFlyer) initialized without authentication (public API only)")
 	}
 
+	// Calls over the bitFlyer request budget are queued for up to BITFLYER_RATE_LIMIT_MAX_WAIT, then rejected
+	rateLimitMaxWait, err := time.ParseDuration(utils.GetEnv("BITFLYER_RATE_LIMIT_MAX_WAIT", "5s"))
+	if err != nil {
+		log.Fatalf("Invalid BITFLYER_RATE_LIMIT_MAX_WAIT: %v", err)
+	}
+	bitflyerClient.SetRateLimitMaxWait(rateLimitMaxWait)
+
+	// Initialize exchange client (GMO Coin)
+	gmocoinAPIURL := utils.GetEnv("GMOCOIN_API_URL", "https://api.coin.z.com")
+	gmocoinAPIKey := utils.GetEnv("GMOCOIN_API_KEY", "")
+	gmocoinAPISecret := utils.GetEnv("GMOCOIN_API_SECRET", "")
+
+	var gmocoinClient client.CryptoExchangeClient
+	if gmocoinAPIKey != "" && gmocoinAPISecret != "" {
+		gmocoinClient = client.NewGMOCoinClientWithAuth(gmocoinAPIURL, gmocoinAPIKey, gmocoinAPISecret)
+		log.Println("Exchange client (GMO Coin) initialized with authentication")
+	} else {
+		gmocoinClient = client.NewGMOCoinClient(gmocoinAPIURL)
+		log.Println("Exchange client (GMO Coin) initialized without authentication (public API only)")
+	}
+
+	// Retry transient failures of exchange calls and fail fast while an exchange is down
+	resilienceConfig := client.DefaultResilienceConfig()
+	if resilienceConfig.MaxRetries, err = strconv.Atoi(utils.GetEnv("EXCHANGE_MAX_RETRIES", "2")); err != nil {
+		log.Fatalf("Invalid EXCHANGE_MAX_RETRIES: %v", err)
+	}
+	if resilienceConfig.FailureThreshold, err = strconv.Atoi(utils.GetEnv("EXCHANGE_CIRCUIT_FAILURE_THRESHOLD", "5")); err != nil {
+		log.Fatalf("Invalid EXCHANGE_CIRCUIT_FAILURE_THRESHOLD: %v", err)
+	}
+	if resilienceConfig.OpenTimeout, err = time.ParseDuration(utils.GetEnv("EXCHANGE_CIRCUIT_OPEN_TIMEOUT", "30s")); err != nil {
+		log.Fatalf("Invalid EXCHANGE_CIRCUIT_OPEN_TIMEOUT: %v", err)
+	}
+
+	// bitFlyer lists recent orders in every state, so an order that is not found after a failure can be resubmitted;
+	// GMO Coin only lists open orders, so its orders are never resubmitted
+	bitflyerResilienceConfig := resilienceConfig
+	bitflyerResilienceConfig.ResubmitOrders = true
+	resilientBitflyerClient := client.NewResilientClient(bitflyerClient, bitflyerResilienceConfig)
+	resilientGMOCoinClient := client.NewResilientClient(gmocoinClient, resilienceConfig)
+
+	// Serve tickers from a cache shared by every request (TICKER_CACHE_TTL=0 disables it); while an exchange
+	// is down the last ticker is served, flagged stale, for up to TICKER_CACHE_MAX_STALE
+	tickerCacheConfig := client.DefaultTickerCacheConfig()
+	if tickerCacheConfig.TTL, err = time.ParseDuration(utils.GetEnv("TICKER_CACHE_TTL", "2s")); err != nil {
+		log.Fatalf("Invalid TICKER_CACHE_TTL: %v", err)
+	}
+	if tickerCacheConfig.MaxStale, err = time.ParseDuration(utils.GetEnv("TICKER_CACHE_MAX_STALE", "1m")); err != nil {
+		log.Fatalf("Invalid TICKER_CACHE_MAX_STALE: %v", err)
+	}
+	var cachedBitflyerClient, cachedGMOCoinClient client.CryptoExchangeClient = resilientBitflyerClient, resilientGMOCoinClient
+	if tickerCacheConfig.TTL > 0 {
+		cachedBitflyerClient = client.NewTickerCache(resilientBitflyerClient, tickerCacheConfig)
+		cachedGMOCoinClient = client.NewTickerCache(resilientGMOCoinClient, tickerCacheConfig)
+	}
+
+	// No order reaches an exchange while the trading halt (kill switch) is engaged
+	tradingHaltRepo := repository.NewMySQLTradingHaltRepository(db)
+	cachedBitflyerClient = client.NewHaltGuard(cachedBitflyerClient, tradingHaltRepo)
+	cachedGMOCoinClient = client.NewHaltGuard(cachedGMOCoinClient, tradingHaltRepo)
+
+	// Route every pair to its exchange (EXCHANGE_ROUTES, e.g. "ETH_JPY=gmocoin"); other pairs use bitFlyer
+	exchangeRoutes, err := client.ParseExchangeRoutes(utils.GetEnv("EXCHANGE_ROUTES", ""))
+	if err != nil {
+		log.Fatalf("Invalid EXCHANGE_ROUTES: %v", err)
+	}
+	exchangeClient, err := client.NewExchangeRouter(map[string]client.CryptoExchangeClient{
+		cachedBitflyerClient.Name(): cachedBitflyerClient,
+		cachedGMOCoinClient.Name():  cachedGMOCoinClient,
+	}, exchangeRoutes, bitflyerClient.Name())
+	if err != nil {
+		log.Fatalf("Invalid EXCHANGE_ROUTES: %v", err)
+	}
+	for productCode, exchange := range exchangeRoutes {
+		log.Printf("Exchange route: %s -> %s", productCode, exchange)
+	}
+
+	// Load the listed cryptocurrencies (ASSET_CATALOG_FILE replaces the built-in Bitcoin and Ethereum)
+	assets, err := model.LoadAssetCatalog(utils.GetEnv("ASSET_CATALOG_FILE", ""))
+	if err != nil {
+		log.Fatalf("Failed to load asset catalog: %v", err)
+	}
+
+	// Load trading rules of every listed pair (PRODUCT_OVERRIDES_FILE adds or corrects pairs). If the exchange
+	// cannot be reached, the server starts without products and orders are rejected until they are loaded.
+	productOverridesFile := utils.GetEnv("PRODUCT_OVERRIDES_FILE", "")
+	productRefreshInterval, err := time.ParseDuration(utils.GetEnv("PRODUCT_REFRESH_INTERVAL", "1h"))
+	if err != nil || productRefreshInterval <= 0 {
+		log.Fatalf("Invalid PRODUCT_REFRESH_INTERVAL: %q", utils.GetEnv("PRODUCT_REFRESH_INTERVAL", "1h"))
+	}
+	products, err := service.LoadProductRegistry(context.Background(), exchangeClient, assets, productOverridesFile)
+	if err != nil {
+		log.Printf("Warning: failed to load products, orders are rejected until they are loaded: %v", err)
+		products = service.NewProductRegistry(nil)
+	}
+	for _, product := range products.Products() {
+		log.Printf("Product: %s on %s (tick %g, lot %g, min %g)", product.Symbol, product.Exchange, product.TickSize, product.LotStep, product.MinSize)
+	}
+
 	// Initialize repositories
 	cryptoRepo := repository.NewMySQLCryptoRepository(db)
 	orderRepo := repository.NewOrderRepository(db)
-	tradeHistoryRepo := repository.NewMySQLTradeHistoryRepository(db)
+	parentOrderRepo := repository.NewParentOrderRepository(db)
+	tradeHistoryRepo := repository.NewMySQLTradeHistoryRepository(db, assets)
 
 	// Initialize services
-	cryptoService := service.NewCryptoService(cryptoRepo, exchangeClient)
-	orderService := service.NewOrderService(exchangeClient, orderRepo)
-	tradeHistoryService := service.NewTradeHistoryService(tradeHistoryRepo)
+	cryptoService := service.NewCryptoService(cryptoRepo, exchangeClient, products, assets)
+	// The market page fetches up to MARKET_DATA_WORKERS pairs at once and gives up on slow pairs after MARKET_DATA_TIMEOUT
+	marketDataWorkers, err := strconv.Atoi(utils.GetEnv("MARKET_DATA_WORKERS", "4"))
+	if err != nil || marketDataWorkers <= 0 {
+		log.Fatalf("Invalid MARKET_DATA_WORKERS: %q", utils.GetEnv("MARKET_DATA_WORKERS", "4"))
+	}
+	marketDataTimeout, err := time.ParseDuration(utils.GetEnv("MARKET_DATA_TIMEOUT", "5s"))
+	if err != nil || marketDataTimeout <= 0 {
+		log.Fatalf("Invalid MARKET_DATA_TIMEOUT: %q", utils.GetEnv("MARKET_DATA_TIMEOUT", "5s"))
+	}
+	cryptoService.SetMarketDataLimits(marketDataWorkers, marketDataTimeout)
+	cryptoService.SetTradingHalts(tradingHaltRepo)
+	orderService := service.NewOrderService(exchangeClient, orderRepo, products)
+	// Orders posted with an Idempotency-Key are placed at most once per key within IDEMPOTENCY_KEY_TTL
+	idempotencyKeyTTL, err := time.ParseDuration(utils.GetEnv("IDEMPOTENCY_KEY_TTL", "24h"))
+	if err != nil || idempotencyKeyTTL <= 0 {
+		log.Fatalf("Invalid IDEMPOTENCY_KEY_TTL: %q", utils.GetEnv("IDEMPOTENCY_KEY_TTL", "24h"))
+	}
+	orderService.SetIdempotencyKeys(repository.NewMySQLIdempotencyRepository(db), idempotencyKeyTTL)
+	// Orders, special order legs and take-profit orders are checked against the pre-trade risk limits (RISK_*)
+	// before they are sent
+	riskConfig, err := service.LoadRiskConfigFromEnv(assets)
+	if err != nil {
+		log.Fatalf("Invalid risk limits: %v", err)
+	}
+	riskEngine := service.NewRiskEngine(service.NewRiskRules(riskConfig, orderRepo)...)
+	orderService.SetRiskEngine(riskEngine)
+	// Orders, special orders and take-profit orders share the queues of the accounts, so that funds and the open
+	// order and daily volume limits are never counted twice
+	orderPipeline := service.NewOrderPipeline(orderRepo)
+	orderService.SetOrderPipeline(orderPipeline)
+	// Every order is journaled before it is sent; the recovery saves the ones a previous run failed to save
+	orderJournalRepo := repository.NewMySQLOrderJournalRepository(db)
+	orderService.SetOrderJournal(orderJournalRepo)
+	orderService.SetTradingHalts(tradingHaltRepo)
+	orderJournalRecovery := service.NewOrderJournalRecovery(exchangeClient, orderRepo, orderJournalRepo)
+	parentOrderService := service.NewParentOrderService(exchangeClient, parentOrderRepo, products)
+	parentOrderService.SetRiskEngine(riskEngine)
+	parentOrderService.SetOrderPipeline(orderPipeline)
+	tradeHistoryService := service.NewTradeHistoryService(tradeHistoryRepo, assets)
+	tradingHaltService := service.NewTradingHaltService(exchangeClient, tradingHaltRepo, orderRepo, assets)
+
+	// Stop background workers and the server on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Refresh the trading rules in the background; they are retried sooner while none could be loaded
+	go products.Run(ctx, exchangeClient, assets, productOverridesFile, productRefreshInterval)
+
+	// Recover journaled orders in the background, so that an unreachable exchange does not delay startup
+	go func() {
+		result, err := orderJournalRecovery.Recover(ctx)
+		if err != nil {
+			log.Printf("Warning: order journal recovery failed: %v", err)
+			return
+		}
+		log.Printf("Order journal recovery finished (recorded: %d, not found: %d, unresolved: %d)", result.Recorded, result.NotFound, result.Unresolved)
+	}()
+
+	// Start order status synchronizer (ORDER_SYNC_INTERVAL=0 disables it)
+	syncInterval, err := time.ParseDuration(utils.GetEnv("ORDER_SYNC_INTERVAL", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid ORDER_SYNC_INTERVAL: %v", err)
+	}
+	if syncInterval > 0 {
+		orderSynchronizer := service.NewOrderSynchronizer(exchangeClient, orderRepo)
+		go orderSynchronizer.Run(ctx, syncInterval)
+		parentOrderSynchronizer := service.NewParentOrderSynchronizer(exchangeClient, parentOrderRepo)
+		go parentOrderSynchronizer.Run(ctx, syncInterval)
+		log.Printf("Order synchronizer started (interval: %s)", syncInterval)
+	} else {
+		log.Println("Order synchronizer disabled")
+	}
+
+	// Start take-profit engine (places a sell order for every filled buy order)
+	if utils.GetEnv("TAKE_PROFIT_ENABLED", "false") == "true" && syncInterval > 0 {
+		takeProfitConfig, err := service.ParseTakeProfitMarkups(utils.GetEnv("TAKE_PROFIT_MARKUPS", ""))
+		if err != nil {
+			log.Fatalf("Invalid TAKE_PROFIT_MARKUPS: %v", err)
+		}
+		takeProfitEngine := service.NewTakeProfitEngine(exchangeClient, orderRepo, takeProfitConfig, products)
+		takeProfitEngine.SetRiskEngine(riskEngine)
+		takeProfitEngine.SetOrderPipeline(orderPipeline)
+		go takeProfitEngine.Run(ctx, syncInterval)
+		log.Println("Take-profit engine started")
+	}
+
+	// Start price stream: bitFlyer pairs follow the realtime API (BITFLYER_REALTIME_ENABLED=false disables it),
+	// other pairs are polled every PRICE_STREAM_POLL_INTERVAL while someone is subscribed
+	marketDataStreams := map[string]client.MarketDataStream{}
+	if utils.GetEnv("BITFLYER_REALTIME_ENABLED", "true") == "true" {
+		realtimeClient := client.NewBitFlyerRealtimeClient(utils.GetEnv("BITFLYER_REALTIME_URL", client.BitFlyerRealtimeURL), client.DefaultRealtimeConfig())
+		go realtimeClient.Run(ctx)
+		marketDataStreams[bitflyerClient.Name()] = realtimeClient
+		log.Println("bitFlyer realtime client started")
+	}
+	pricePollInterval, err := time.ParseDuration(utils.GetEnv("PRICE_STREAM_POLL_INTERVAL", "5s"))
+	if err != nil || pricePollInterval <= 0 {
+		log.Fatalf("Invalid PRICE_STREAM_POLL_INTERVAL: %q", utils.GetEnv("PRICE_STREAM_POLL_INTERVAL", "5s"))
+	}
+	priceStreamKeepAlive, err := time.ParseDuration(utils.GetEnv("PRICE_STREAM_KEEP_ALIVE", "15s"))
+	if err != nil || priceStreamKeepAlive <= 0 {
+		log.Fatalf("Invalid PRICE_STREAM_KEEP_ALIVE: %q", utils.GetEnv("PRICE_STREAM_KEEP_ALIVE", "15s"))
+	}
+	priceStreamHub := service.NewPriceStreamHub(exchangeClient, marketDataStreams, assets, pricePollInterval)
+	go priceStreamHub.Run(ctx)
 
 	// Initialize handlers
 	cryptoHandler := handler.NewCryptoHandler(cryptoService)
 	orderHandler := handler.NewOrderHandler(orderService)
-	tradeHistoryHandler := handler.NewTradeHistoryHandler(tradeHistoryService)
+	parentOrderHandler := handler.NewParentOrderHandler(parentOrderService)
+	tradeHistoryHandler := handler.NewTradeHistoryHandler(tradeHistoryService, assets)
+	debugHandler := handler.NewDebugHandler(exchangeClient)
+	streamHandler := handler.NewStreamHandler(priceStreamHub, priceStreamKeepAlive)
+	tradingHaltHandler := handler.NewTradingHaltHandler(tradingHaltService)
 
 	// Initialize Echo
 	e := echo.New()
@@ -75,11 +290,12 @@ func main() {
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 
-	// CORS middleware - Allow all origins in development
+	// CORS middleware - Allow all origins in development, except on the admin routes
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		Skipper:      handler.IsAdminPath,
 		AllowOrigins: []string{"*"},
 		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept},
+		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, "Idempotency-Key"},
 	}))
 
 	// Routes
@@ -94,8 +310,13 @@ func main() {
 		}
 
 		// Order routes
+		api.GET("/orders", orderHandler.ListOrders)
 		api.POST("/orders", orderHandler.CreateOrder)
+		api.POST("/orders/parent", parentOrderHandler.CreateParentOrder)
+		api.GET("/orders/:id", orderHandler.GetOrder)
+		api.DELETE("/orders/:id", orderHandler.CancelOrder)
 		api.GET("/balance", orderHandler.GetBalance)
+		api.GET("/balances", orderHandler.GetBalances)
 
 		// Trade History routes
 		tradeHistory := api.Group("/trade-history")
@@ -107,10 +328,25 @@ func main() {
 			tradeHistory.GET("/transactions", tradeHistoryHandler.GetTradeTransactions)
 		}
 
+		// Admin routes, closed unless ADMIN_TOKEN is set
+		adminToken := utils.GetEnv("ADMIN_TOKEN", "")
+		if adminToken == "" {
+			log.Printf("Warning: ADMIN_TOKEN is not set, the admin routes reject every request")
+		}
+		admin := api.Group("/admin", handler.AdminAuth(adminToken))
+		{
+			admin.GET("/trading-halt", tradingHaltHandler.GetTradingHalt)
+			admin.PUT("/trading-halt", tradingHaltHandler.UpdateTradingHalt)
+		}
+
+		// Server-Sent Events routes
+		api.GET("/stream/prices", streamHandler.GetPriceStream)
+
 		// Debug route to test if routing works
 		api.GET("/debug", func(c echo.Context) error {
 			return c.JSON(200, map[string]string{"message": "Debug endpoint works"})
 		})
+		api.GET("/debug/rate-limits", debugHandler.GetRateLimits)
 	}
 
 	// Log registered routes
@@ -123,7 +359,18 @@ func main() {
 	port := utils.GetEnv("SERVER_PORT", "8080")
 	log.Printf("Starting server on 0.0.0.0:%s", port)
 	log.Printf("Local: http://localhost:%s", port)
-	if err := e.Start(fmt.Sprintf("0.0.0.0:%s", port)); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	go func() {
+		if err := e.Start(fmt.Sprintf("0.0.0.0:%s", port)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to shut down server gracefully: %v", err)
 	}
 }
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+	"github.com/crypto-trading-connector/backend/internal/service"
+	"github.com/crypto-trading-connector/backend/pkg/database"
+	"github.com/crypto-trading-connector/backend/utils"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	engage := flag.Bool("engage", false, "Halt trading: no order is sent until the halt is released")
+	release := flag.Bool("release", false, "Release the trading halt")
+	reason := flag.String("reason", "", "Reason of the halt, shown in rejected orders")
+	cancelOpenOrders := flag.Bool("cancel", false, "Cancel the open orders of every listed pair on its exchange")
+	flag.Parse()
+
+	if *engage && *release {
+		log.Fatal("Error: -engage and -release cannot be used together")
+	}
+	if *cancelOpenOrders && !*engage && !*release {
+		log.Fatal("Error: -cancel requires -engage or -release")
+	}
+
+	// Load .env file
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, using environment variables")
+	}
+
+	// Connect to database; the halt is shared with the server through it
+	db, err := database.Connect(database.LoadConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Error: failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Open orders are cancelled on the exchange each pair is routed to (EXCHANGE_ROUTES), as in the server
+	bitflyerClient := client.NewBitFlyerClientWithAuth(
+		utils.GetEnv("BITFLYER_API_URL", "https://api.bitflyer.com"),
+		utils.GetEnv("BITFLYER_API_KEY", ""),
+		utils.GetEnv("BITFLYER_API_SECRET", ""),
+	)
+	gmocoinClient := client.NewGMOCoinClientWithAuth(
+		utils.GetEnv("GMOCOIN_API_URL", "https://api.coin.z.com"),
+		utils.GetEnv("GMOCOIN_API_KEY", ""),
+		utils.GetEnv("GMOCOIN_API_SECRET", ""),
+	)
+	exchangeRoutes, err := client.ParseExchangeRoutes(utils.GetEnv("EXCHANGE_ROUTES", ""))
+	if err != nil {
+		log.Fatalf("Invalid EXCHANGE_ROUTES: %v", err)
+	}
+	exchangeClient, err := client.NewExchangeRouter(map[string]client.CryptoExchangeClient{
+		bitflyerClient.Name(): bitflyerClient,
+		gmocoinClient.Name():  gmocoinClient,
+	}, exchangeRoutes, bitflyerClient.Name())
+	if err != nil {
+		log.Fatalf("Invalid EXCHANGE_ROUTES: %v", err)
+	}
+
+	assets, err := model.LoadAssetCatalog(utils.GetEnv("ASSET_CATALOG_FILE", ""))
+	if err != nil {
+		log.Fatalf("Error: failed to load asset catalog: %v", err)
+	}
+
+	tradingHaltService := service.NewTradingHaltService(exchangeClient, repository.NewMySQLTradingHaltRepository(db), repository.NewOrderRepository(db), assets)
+	ctx := context.Background()
+
+	if !*engage && !*release {
+		halt, err := tradingHaltService.GetTradingHalt(ctx)
+		if err != nil {
+			log.Fatalf("❌ Failed to get trading halt: %v", err)
+		}
+		printTradingHalt(halt)
+		return
+	}
+
+	req := &generated.UpdateTradingHaltRequest{
+		Halted:           *engage,
+		Reason:           reason,
+		CancelOpenOrders: cancelOpenOrders,
+	}
+	response, err := tradingHaltService.UpdateTradingHalt(ctx, req)
+	if err != nil {
+		log.Fatalf("❌ Failed to update trading halt: %v", err)
+	}
+	printTradingHalt(&response.TradingHalt)
+
+	if response.Cancellations == nil {
+		return
+	}
+	failed := 0
+	for _, cancellation := range *response.Cancellations {
+		if cancellation.Cancelled {
+			fmt.Printf("   ✅ Open %s orders cancelled\n", cancellation.Pair)
+			continue
+		}
+		failed++
+		fmt.Printf("   ❌ Failed to cancel open %s orders: %s\n", cancellation.Pair, *cancellation.Message)
+	}
+	if failed > 0 {
+		log.Fatalf("❌ Failed to cancel the open orders of %d pair(s)", failed)
+	}
+}
+
+// printTradingHalt prints the state of the trading halt
+func printTradingHalt(halt *generated.TradingHalt) {
+	if !halt.Halted {
+		fmt.Println("🟢 Trading is active")
+	} else if halt.Reason != nil {
+		fmt.Printf("🛑 Trading is halted: %s\n", *halt.Reason)
+	} else {
+		fmt.Println("🛑 Trading is halted")
+	}
+	if halt.UpdatedAt != nil {
+		fmt.Printf("   Updated at: %s\n", halt.UpdatedAt.Format(time.RFC3339))
+	}
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -9,7 +10,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/crypto-trading-connector/backend/internal/model"
@@ -21,6 +24,15 @@ type BitFlyerClient struct {
 	apiKey    string
 	apiSecret string
 	client    *http.Client
+	limiter   *RateLimiter
+}
+
+// bitFlyerRateLimits are the request budgets published by bitFlyer. Order endpoints
+// (sending and cancelling orders) also count against the private budget.
+var bitFlyerRateLimits = map[RateLimitCategory]RateLimit{
+	RateLimitPublic:  {Requests: 500, Period: 5 * time.Minute, Burst: 20},
+	RateLimitPrivate: {Requests: 500, Period: 5 * time.Minute, Burst: 20},
+	RateLimitOrder:   {Requests: 300, Period: 5 * time.Minute, Burst: 10},
 }
 
 // NewBitFlyerClient creates a new bitFlyer API client
@@ -30,6 +42,7 @@ func NewBitFlyerClient(baseURL string) *BitFlyerClient {
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		limiter: NewRateLimiter("bitflyer", bitFlyerRateLimits, defaultRateLimitMaxWait),
 	}
 }
 
@@ -42,14 +55,46 @@ func NewBitFlyerClientWithAuth(baseURL, apiKey, apiSecret string) *BitFlyerClien
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		limiter: NewRateLimiter("bitflyer", bitFlyerRateLimits, defaultRateLimitMaxWait),
 	}
 }
 
-// GetTicker retrieves ticker information for a specific product
-func (c *BitFlyerClient) GetTicker(productCode string) (*model.TickerResponse, error) {
-	url := fmt.Sprintf("%s/v1/ticker?product_code=%s", c.baseURL, productCode)
+// Name returns the identifier of bitFlyer
+func (c *BitFlyerClient) Name() string {
+	return "bitflyer"
+}
+
+// SetRateLimitMaxWait changes how long a call may be queued for the request budget before it is rejected
+func (c *BitFlyerClient) SetRateLimitMaxWait(maxWait time.Duration) {
+	c.limiter.SetMaxWait(maxWait)
+}
+
+// RateLimitBudgets returns the current request budget of the public, private and order endpoints
+func (c *BitFlyerClient) RateLimitBudgets() []RateLimitBudget {
+	return c.limiter.Budgets()
+}
+
+// bitFlyerProductRules are the trading rules of bitFlyer spot products.
+// getmarkets only lists product codes, so the rules published by bitFlyer are kept here;
+// products missing from this table can be added with the product override file.
+var bitFlyerProductRules = map[string]model.Product{
+	"BTC_JPY": {TickSize: 1, LotStep: 0.00000001, MinSize: 0.001, PricePrecision: 0},
+	"ETH_JPY": {TickSize: 1, LotStep: 0.00000001, MinSize: 0.01, PricePrecision: 0},
+}
 
-	resp, err := c.client.Get(url)
+// bitFlyerTimestampLayout is the layout of timestamps returned by bitFlyer (UTC without a zone designator)
+const bitFlyerTimestampLayout = "2006-01-02T15:04:05.999999999"
+
+// GetTicker retrieves ticker information for a specific symbol
+func (c *BitFlyerClient) GetTicker(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+	url := fmt.Sprintf("%s/v1/ticker?product_code=%s", c.baseURL, bitFlyerProductCode(symbol))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req, RateLimitPublic)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call bitFlyer API: %w", err)
 	}
@@ -57,70 +102,127 @@ func (c *BitFlyerClient) GetTicker(productCode string) (*model.TickerResponse, e
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("bitFlyer API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, decodeBitFlyerError(resp.StatusCode, body)
 	}
 
-	var ticker model.TickerResponse
+	var ticker model.BitFlyerTickerResponse
 	if err := json.NewDecoder(resp.Body).Decode(&ticker); err != nil {
 		return nil, fmt.Errorf("failed to decode ticker response: %w", err)
 	}
 
-	return &ticker, nil
+	return toTicker(symbol, &ticker), nil
+}
+
+// GetProducts retrieves the spot products listed by bitFlyer getmarkets API together with their trading rules
+func (c *BitFlyerClient) GetProducts(ctx context.Context) ([]model.Product, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/getmarkets", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req, RateLimitPublic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call bitFlyer API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, decodeBitFlyerError(resp.StatusCode, body)
+	}
+
+	var markets []model.BitFlyerMarket
+	if err := json.NewDecoder(resp.Body).Decode(&markets); err != nil {
+		return nil, fmt.Errorf("failed to decode markets response: %w", err)
+	}
+
+	products := []model.Product{}
+	for _, market := range markets {
+		rules, ok := bitFlyerProductRules[market.ProductCode]
+		if !ok || market.MarketType != "Spot" {
+			continue
+		}
+		rules.Symbol = model.SymbolFromProductCode(market.ProductCode)
+		rules.Exchange = c.Name()
+		products = append(products, rules)
+	}
+
+	return products, nil
 }
 
 // GetBalance retrieves JPY balance from bitFlyer API
-func (c *BitFlyerClient) GetBalance() (float64, error) {
+func (c *BitFlyerClient) GetBalance(ctx context.Context) (float64, error) {
+	balances, err := c.GetBalances(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Find JPY balance
+	for _, balance := range balances {
+		if balance.Currency == "JPY" {
+			return balance.Available, nil
+		}
+	}
+
+	return 0, fmt.Errorf("JPY balance not found")
+}
+
+// GetBalances retrieves the balance of every currency (JPY, BTC, ETH, ...) from bitFlyer API
+func (c *BitFlyerClient) GetBalances(ctx context.Context) ([]model.Balance, error) {
 	path := "/v1/me/getbalance"
 	method := "GET"
 	body := ""
 
-	req, err := c.createAuthenticatedRequest(method, path, body)
+	req, err := c.createAuthenticatedRequest(ctx, method, path, body)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req, RateLimitPrivate)
 	if err != nil {
-		return 0, fmt.Errorf("failed to call bitFlyer API: %w", err)
+		return nil, fmt.Errorf("failed to call bitFlyer API: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("bitFlyer API returned status %d: %s", resp.StatusCode, string(respBody))
+		return nil, decodeBitFlyerError(resp.StatusCode, respBody)
 	}
 
 	var balances []model.BitFlyerBalance
 	if err := json.NewDecoder(resp.Body).Decode(&balances); err != nil {
-		return 0, fmt.Errorf("failed to decode balance response: %w", err)
+		return nil, fmt.Errorf("failed to decode balance response: %w", err)
 	}
 
-	// Find JPY balance
+	result := make([]model.Balance, 0, len(balances))
 	for _, balance := range balances {
-		if balance.CurrencyCode == "JPY" {
-			return balance.Available, nil
-		}
+		result = append(result, model.Balance{
+			Currency:  balance.CurrencyCode,
+			Exchange:  c.Name(),
+			Amount:    balance.Amount,
+			Available: balance.Available,
+		})
 	}
-
-	return 0, fmt.Errorf("JPY balance not found")
+	return result, nil
 }
 
 // SendOrder sends an order to bitFlyer API
-func (c *BitFlyerClient) SendOrder(req *model.BitFlyerOrderRequest) (*model.BitFlyerOrderResponse, error) {
+// bitFlyer has no client order IDs, so ClientOrderID is only echoed back in the response
+func (c *BitFlyerClient) SendOrder(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
 	path := "/v1/me/sendchildorder"
 	method := "POST"
 
-	bodyBytes, err := json.Marshal(req)
+	bodyBytes, err := json.Marshal(toBitFlyerOrderRequest(req))
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal order request: %w", err)
 	}
 
-	httpReq, err := c.createAuthenticatedRequest(method, path, string(bodyBytes))
+	httpReq, err := c.createAuthenticatedRequest(ctx, method, path, string(bodyBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.client.Do(httpReq)
+	resp, err := c.do(httpReq, RateLimitOrder)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call bitFlyer API: %w", err)
 	}
@@ -128,7 +230,7 @@ func (c *BitFlyerClient) SendOrder(req *model.BitFlyerOrderRequest) (*model.BitF
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("bitFlyer API returned status %d: %s", resp.StatusCode, string(respBody))
+		return nil, decodeBitFlyerError(resp.StatusCode, respBody)
 	}
 
 	var orderResp model.BitFlyerOrderResponse
@@ -136,11 +238,232 @@ func (c *BitFlyerClient) SendOrder(req *model.BitFlyerOrderRequest) (*model.BitF
 		return nil, fmt.Errorf("failed to decode order response: %w", err)
 	}
 
-	return &orderResp, nil
+	return &model.OrderResponse{
+		OrderID:       orderResp.ChildOrderAcceptanceID,
+		ClientOrderID: req.ClientOrderID,
+	}, nil
+}
+
+// GetChildOrders retrieves child orders from bitFlyer API
+func (c *BitFlyerClient) GetChildOrders(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+	params := url.Values{}
+	params.Set("product_code", query.ProductCode)
+	if query.OrderID != "" {
+		params.Set("child_order_acceptance_id", query.OrderID)
+	}
+	if query.State != "" {
+		params.Set("child_order_state", string(query.State))
+	}
+	if query.ParentOrderID != "" {
+		params.Set("parent_order_id", query.ParentOrderID)
+	}
+	if query.Count > 0 {
+		params.Set("count", strconv.Itoa(query.Count))
+	}
+
+	// The query string is part of the signed path for authenticated GET requests
+	path := "/v1/me/getchildorders?" + params.Encode()
+
+	req, err := c.createAuthenticatedRequest(ctx, "GET", path, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req, RateLimitPrivate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call bitFlyer API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, decodeBitFlyerError(resp.StatusCode, respBody)
+	}
+
+	var orders []model.BitFlyerChildOrder
+	if err := json.NewDecoder(resp.Body).Decode(&orders); err != nil {
+		return nil, fmt.Errorf("failed to decode child orders response: %w", err)
+	}
+
+	childOrders := make([]model.ChildOrder, 0, len(orders))
+	for i := range orders {
+		childOrders = append(childOrders, toChildOrder(&orders[i]))
+	}
+	return childOrders, nil
+}
+
+// SendParentOrder sends a special order (IFD, OCO or IFDOCO) to bitFlyer API
+func (c *BitFlyerClient) SendParentOrder(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error) {
+	path := "/v1/me/sendparentorder"
+	method := "POST"
+
+	parentReq := &model.BitFlyerParentOrderRequest{
+		OrderMethod:    req.Method,
+		MinuteToExpire: req.MinuteToExpire,
+		TimeInForce:    req.TimeInForce,
+		Parameters:     make([]model.BitFlyerParentOrderParameter, 0, len(req.Legs)),
+	}
+	for _, leg := range req.Legs {
+		parentReq.Parameters = append(parentReq.Parameters, model.BitFlyerParentOrderParameter{
+			ProductCode:   leg.ProductCode,
+			ConditionType: leg.ConditionType,
+			Side:          leg.Side,
+			Size:          leg.Size,
+			Price:         leg.Price,
+			TriggerPrice:  leg.TriggerPrice,
+			Offset:        leg.Offset,
+		})
+	}
+
+	bodyBytes, err := json.Marshal(parentReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal parent order request: %w", err)
+	}
+
+	httpReq, err := c.createAuthenticatedRequest(ctx, method, path, string(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(httpReq, RateLimitOrder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call bitFlyer API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, decodeBitFlyerError(resp.StatusCode, respBody)
+	}
+
+	var parentResp model.BitFlyerParentOrderResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parentResp); err != nil {
+		return nil, fmt.Errorf("failed to decode parent order response: %w", err)
+	}
+
+	return &model.ParentOrderResponse{AcceptanceID: parentResp.ParentOrderAcceptanceID}, nil
+}
+
+// GetParentOrders retrieves parent orders from bitFlyer API
+func (c *BitFlyerClient) GetParentOrders(ctx context.Context, query *model.ParentOrderQuery) ([]model.ParentOrder, error) {
+	params := url.Values{}
+	params.Set("product_code", query.ProductCode)
+	if query.State != "" {
+		params.Set("parent_order_state", string(query.State))
+	}
+	if query.Count > 0 {
+		params.Set("count", strconv.Itoa(query.Count))
+	}
+
+	// The query string is part of the signed path for authenticated GET requests
+	path := "/v1/me/getparentorders?" + params.Encode()
+
+	req, err := c.createAuthenticatedRequest(ctx, "GET", path, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req, RateLimitPrivate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call bitFlyer API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, decodeBitFlyerError(resp.StatusCode, respBody)
+	}
+
+	var orders []model.BitFlyerParentOrder
+	if err := json.NewDecoder(resp.Body).Decode(&orders); err != nil {
+		return nil, fmt.Errorf("failed to decode parent orders response: %w", err)
+	}
+
+	parentOrders := make([]model.ParentOrder, 0, len(orders))
+	for _, order := range orders {
+		parentOrders = append(parentOrders, model.ParentOrder{
+			AcceptanceID:    order.ParentOrderAcceptanceID,
+			ExchangeOrderID: order.ParentOrderID,
+			ProductCode:     order.ProductCode,
+			State:           model.OrderState(order.ParentOrderState),
+		})
+	}
+	return parentOrders, nil
+}
+
+// CancelOrder cancels an order on bitFlyer by its child order acceptance ID
+func (c *BitFlyerClient) CancelOrder(ctx context.Context, productCode, orderID string) error {
+	return c.postWithoutResponse(ctx, "/v1/me/cancelchildorder", &model.BitFlyerCancelOrderRequest{
+		ProductCode:            productCode,
+		ChildOrderAcceptanceID: orderID,
+	})
+}
+
+// CancelAllOrders cancels all open orders for the product on bitFlyer
+func (c *BitFlyerClient) CancelAllOrders(ctx context.Context, productCode string) error {
+	return c.postWithoutResponse(ctx, "/v1/me/cancelallchildorders", &model.BitFlyerCancelAllOrdersRequest{
+		ProductCode: productCode,
+	})
+}
+
+// postWithoutResponse sends an authenticated POST request to an endpoint that returns an empty body on success
+func (c *BitFlyerClient) postWithoutResponse(ctx context.Context, path string, payload any) error {
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := c.createAuthenticatedRequest(ctx, "POST", path, string(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req, RateLimitOrder)
+	if err != nil {
+		return fmt.Errorf("failed to call bitFlyer API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return decodeBitFlyerError(resp.StatusCode, respBody)
+	}
+
+	return nil
+}
+
+// do sends a request once the request budget of its rate limit category allows it.
+// A call over budget, or rejected by bitFlyer with HTTP 429, returns a RateLimitError.
+func (c *BitFlyerClient) do(req *http.Request, category RateLimitCategory) (*http.Response, error) {
+	// Orders count against both the private and the order budget, and private headers report the private budget
+	categories := []RateLimitCategory{category}
+	headerCategory := category
+	if category == RateLimitOrder {
+		categories = []RateLimitCategory{RateLimitPrivate, RateLimitOrder}
+		headerCategory = RateLimitPrivate
+	}
+
+	if err := c.limiter.Wait(req.Context(), categories...); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.limiter.Observe(resp, category, headerCategory)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		resp.Body.Close()
+		return nil, c.limiter.rateLimitError(category)
+	}
+
+	return resp, nil
 }
 
 // createAuthenticatedRequest creates an HTTP request with bitFlyer API authentication headers
-func (c *BitFlyerClient) createAuthenticatedRequest(method, path, body string) (*http.Request, error) {
+// The request is bound to ctx so that cancellation and deadlines of the caller abort the call
+func (c *BitFlyerClient) createAuthenticatedRequest(ctx context.Context, method, path, body string) (*http.Request, error) {
 	url := c.baseURL + path
 	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
 
@@ -148,9 +471,9 @@ func (c *BitFlyerClient) createAuthenticatedRequest(method, path, body string) (
 	var req *http.Request
 	var err error
 	if body != "" {
-		req, err = http.NewRequest(method, url, bytes.NewBufferString(body))
+		req, err = http.NewRequestWithContext(ctx, method, url, bytes.NewBufferString(body))
 	} else {
-		req, err = http.NewRequest(method, url, nil)
+		req, err = http.NewRequestWithContext(ctx, method, url, nil)
 	}
 	if err != nil {
 		return nil, err
@@ -173,26 +496,76 @@ func (c *BitFlyerClient) createAuthenticatedRequest(method, path, body string) (
 	return req, nil
 }
 
+// bitFlyerProductCode converts a symbol to a bitFlyer product code (BTC/JPY -> BTC_JPY)
+func bitFlyerProductCode(symbol model.Symbol) string {
+	return symbol.ProductCode()
+}
+
+// toBitFlyerOrderRequest converts an order to the sendchildorder request format
+func toBitFlyerOrderRequest(req *model.OrderRequest) *model.BitFlyerOrderRequest {
+	orderReq := &model.BitFlyerOrderRequest{
+		ProductCode:    bitFlyerProductCode(req.Symbol),
+		ChildOrderType: string(req.Type),
+		Side:           string(req.Side),
+		Size:           req.Size,
+		TimeInForce:    string(req.TimeInForce),
+	}
+	// Price must not be sent for MARKET orders
+	if req.Type != model.OrderTypeMarket {
+		orderReq.Price = req.Price
+	}
+	return orderReq
+}
+
+// toTicker converts a bitFlyer ticker to the exchange-agnostic model
+func toTicker(symbol model.Symbol, ticker *model.BitFlyerTickerResponse) *model.Ticker {
+	timestamp, err := parseBitFlyerTimestamp(ticker.Timestamp)
+	if err != nil {
+		timestamp = time.Now()
+	}
+
+	return &model.Ticker{
+		Symbol:    symbol,
+		Last:      ticker.Ltp,
+		Bid:       ticker.BestBid,
+		Ask:       ticker.BestAsk,
+		BidSize:   ticker.BestBidSize,
+		AskSize:   ticker.BestAskSize,
+		Volume:    ticker.Volume,
+		Timestamp: timestamp,
+	}
+}
+
+// toChildOrder converts a bitFlyer child order to the exchange-agnostic model. Orders are identified by their
+// acceptance ID, which SendOrder returns.
+func toChildOrder(order *model.BitFlyerChildOrder) model.ChildOrder {
+	// A date that cannot be parsed is left zero, so the order is never taken for one just placed
+	orderDate, _ := parseBitFlyerTimestamp(order.ChildOrderDate)
+
+	return model.ChildOrder{
+		OrderID:         order.ChildOrderAcceptanceID,
+		ProductCode:     order.ProductCode,
+		Side:            model.OrderSide(order.Side),
+		Type:            model.OrderType(order.ChildOrderType),
+		Price:           order.Price,
+		AveragePrice:    order.AveragePrice,
+		Size:            order.Size,
+		OutstandingSize: order.OutstandingSize,
+		CancelSize:      order.CancelSize,
+		ExecutedSize:    order.ExecutedSize,
+		State:           model.OrderState(order.ChildOrderState),
+		OrderDate:       orderDate,
+	}
+}
+
+// parseBitFlyerTimestamp parses a timestamp of bitFlyer, which is in UTC with a trailing Z in Realtime API only
+func parseBitFlyerTimestamp(value string) (time.Time, error) {
+	return time.ParseInLocation(bitFlyerTimestampLayout, strings.TrimSuffix(value, "Z"), time.UTC)
+}
+
 // createSignature creates HMAC-SHA256 signature for bitFlyer API authentication
 func (c *BitFlyerClient) createSignature(text string) string {
 	mac := hmac.New(sha256.New, []byte(c.apiSecret))
 	mac.Write([]byte(text))
 	return hex.EncodeToString(mac.Sum(nil))
 }
-
-// RoundPrice rounds the price according to the product code
-// BTC_JPY: rounds to nearest million (1,000,000)
-// ETH_JPY: rounds to nearest ten thousand (10,000)
-func (c *BitFlyerClient) RoundPrice(price float64, productCode string) float64 {
-	switch productCode {
-	case "BTC_JPY":
-		// Round to nearest million
-		return float64(int(price/1000000)) * 1000000
-	case "ETH_JPY":
-		// Round to nearest ten thousand
-		return float64(int(price/10000)) * 10000
-	default:
-		// Default: round to nearest integer
-		return float64(int(price))
-	}
-}
@@ -1,52 +1,125 @@
 package client
 
-import "github.com/crypto-trading-connector/backend/internal/model"
+import (
+	"context"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
 
 // MockBitFlyerClient is a mock implementation of CryptoExchangeClient for testing
 type MockBitFlyerClient struct {
-	GetTickerFunc  func(productCode string) (*model.TickerResponse, error)
-	GetBalanceFunc func() (float64, error)
-	SendOrderFunc  func(req *model.BitFlyerOrderRequest) (*model.BitFlyerOrderResponse, error)
+	NameFunc            func() string
+	GetTickerFunc       func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error)
+	GetProductsFunc     func(ctx context.Context) ([]model.Product, error)
+	GetBalanceFunc      func(ctx context.Context) (float64, error)
+	GetBalancesFunc     func(ctx context.Context) ([]model.Balance, error)
+	SendOrderFunc       func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error)
+	GetChildOrdersFunc  func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error)
+	SendParentOrderFunc func(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error)
+	GetParentOrdersFunc func(ctx context.Context, query *model.ParentOrderQuery) ([]model.ParentOrder, error)
+	CancelOrderFunc     func(ctx context.Context, productCode, orderID string) error
+	CancelAllOrdersFunc func(ctx context.Context, productCode string) error
+}
+
+// Name calls the mock function if set, otherwise returns "bitflyer"
+func (m *MockBitFlyerClient) Name() string {
+	if m.NameFunc != nil {
+		return m.NameFunc()
+	}
+	return "bitflyer"
 }
 
 // GetTicker calls the mock function if set, otherwise returns default values
-func (m *MockBitFlyerClient) GetTicker(productCode string) (*model.TickerResponse, error) {
+func (m *MockBitFlyerClient) GetTicker(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
 	if m.GetTickerFunc != nil {
-		return m.GetTickerFunc(productCode)
+		return m.GetTickerFunc(ctx, symbol)
 	}
-	return &model.TickerResponse{
-		ProductCode: productCode,
-		Ltp:         10000000.0,
+	return &model.Ticker{
+		Symbol: symbol,
+		Last:   10000000.0,
+	}, nil
+}
+
+// GetProducts calls the mock function if set, otherwise returns the BTC/JPY and ETH/JPY rules of bitFlyer
+func (m *MockBitFlyerClient) GetProducts(ctx context.Context) ([]model.Product, error) {
+	if m.GetProductsFunc != nil {
+		return m.GetProductsFunc(ctx)
+	}
+	return []model.Product{
+		{Symbol: "BTC/JPY", Exchange: m.Name(), TickSize: 1, LotStep: 0.00000001, MinSize: 0.001},
+		{Symbol: "ETH/JPY", Exchange: m.Name(), TickSize: 1, LotStep: 0.00000001, MinSize: 0.01},
 	}, nil
 }
 
 // GetBalance calls the mock function if set, otherwise returns default balance
-func (m *MockBitFlyerClient) GetBalance() (float64, error) {
+func (m *MockBitFlyerClient) GetBalance(ctx context.Context) (float64, error) {
 	if m.GetBalanceFunc != nil {
-		return m.GetBalanceFunc()
+		return m.GetBalanceFunc(ctx)
 	}
 	return 1000000.0, nil // Default: 1,000,000 JPY
 }
 
+// GetBalances calls the mock function if set, otherwise returns default balances
+func (m *MockBitFlyerClient) GetBalances(ctx context.Context) ([]model.Balance, error) {
+	if m.GetBalancesFunc != nil {
+		return m.GetBalancesFunc(ctx)
+	}
+	return []model.Balance{
+		{Currency: "JPY", Exchange: m.Name(), Amount: 1000000.0, Available: 1000000.0},
+		{Currency: "BTC", Exchange: m.Name(), Amount: 0.01, Available: 0.01},
+		{Currency: "ETH", Exchange: m.Name(), Amount: 0.1, Available: 0.1},
+	}, nil
+}
+
 // SendOrder calls the mock function if set, otherwise returns default response
-func (m *MockBitFlyerClient) SendOrder(req *model.BitFlyerOrderRequest) (*model.BitFlyerOrderResponse, error) {
+func (m *MockBitFlyerClient) SendOrder(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
 	if m.SendOrderFunc != nil {
-		return m.SendOrderFunc(req)
+		return m.SendOrderFunc(ctx, req)
 	}
-	return &model.BitFlyerOrderResponse{
-		ChildOrderAcceptanceID: "TEST_ORDER_123",
+	return &model.OrderResponse{
+		OrderID:       "TEST_ORDER_123",
+		ClientOrderID: req.ClientOrderID,
 	}, nil
 }
 
-// RoundPrice rounds the price according to the product code
-func (m *MockBitFlyerClient) RoundPrice(price float64, productCode string) float64 {
-	// Use the same logic as the real client
-	switch productCode {
-	case "BTC_JPY":
-		return float64(int(price/1000000)) * 1000000
-	case "ETH_JPY":
-		return float64(int(price/10000)) * 10000
-	default:
-		return float64(int(price))
+// GetChildOrders calls the mock function if set, otherwise returns no orders
+func (m *MockBitFlyerClient) GetChildOrders(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+	if m.GetChildOrdersFunc != nil {
+		return m.GetChildOrdersFunc(ctx, query)
+	}
+	return []model.ChildOrder{}, nil
+}
+
+// SendParentOrder calls the mock function if set, otherwise returns default response
+func (m *MockBitFlyerClient) SendParentOrder(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error) {
+	if m.SendParentOrderFunc != nil {
+		return m.SendParentOrderFunc(ctx, req)
+	}
+	return &model.ParentOrderResponse{
+		AcceptanceID: "TEST_PARENT_ORDER_123",
+	}, nil
+}
+
+// GetParentOrders calls the mock function if set, otherwise returns no orders
+func (m *MockBitFlyerClient) GetParentOrders(ctx context.Context, query *model.ParentOrderQuery) ([]model.ParentOrder, error) {
+	if m.GetParentOrdersFunc != nil {
+		return m.GetParentOrdersFunc(ctx, query)
+	}
+	return []model.ParentOrder{}, nil
+}
+
+// CancelOrder calls the mock function if set, otherwise succeeds
+func (m *MockBitFlyerClient) CancelOrder(ctx context.Context, productCode, orderID string) error {
+	if m.CancelOrderFunc != nil {
+		return m.CancelOrderFunc(ctx, productCode, orderID)
+	}
+	return nil
+}
+
+// CancelAllOrders calls the mock function if set, otherwise succeeds
+func (m *MockBitFlyerClient) CancelAllOrders(ctx context.Context, productCode string) error {
+	if m.CancelAllOrdersFunc != nil {
+		return m.CancelAllOrdersFunc(ctx, productCode)
 	}
+	return nil
 }
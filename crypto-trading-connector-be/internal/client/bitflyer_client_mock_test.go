@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -10,7 +11,7 @@ import (
 func TestMockBitFlyerClient_GetBalance(t *testing.T) {
 	t.Run("returns default balance when no mock function set", func(t *testing.T) {
 		mock := &MockBitFlyerClient{}
-		balance, err := mock.GetBalance()
+		balance, err := mock.GetBalance(context.Background())
 
 		if err != nil {
 			t.Errorf("expected no error, got %v", err)
@@ -23,12 +24,12 @@ func TestMockBitFlyerClient_GetBalance(t *testing.T) {
 	t.Run("calls custom mock function when set", func(t *testing.T) {
 		expectedBalance := 2500000.0
 		mock := &MockBitFlyerClient{
-			GetBalanceFunc: func() (float64, error) {
+			GetBalanceFunc: func(ctx context.Context) (float64, error) {
 				return expectedBalance, nil
 			},
 		}
 
-		balance, err := mock.GetBalance()
+		balance, err := mock.GetBalance(context.Background())
 
 		if err != nil {
 			t.Errorf("expected no error, got %v", err)
@@ -41,12 +42,12 @@ func TestMockBitFlyerClient_GetBalance(t *testing.T) {
 	t.Run("returns error when mock function returns error", func(t *testing.T) {
 		expectedError := errors.New("balance fetch failed")
 		mock := &MockBitFlyerClient{
-			GetBalanceFunc: func() (float64, error) {
+			GetBalanceFunc: func(ctx context.Context) (float64, error) {
 				return 0, expectedError
 			},
 		}
 
-		balance, err := mock.GetBalance()
+		balance, err := mock.GetBalance(context.Background())
 
 		if err == nil {
 			t.Error("expected error, got nil")
@@ -63,69 +64,69 @@ func TestMockBitFlyerClient_GetBalance(t *testing.T) {
 func TestMockBitFlyerClient_SendOrder(t *testing.T) {
 	t.Run("returns default response when no mock function set", func(t *testing.T) {
 		mock := &MockBitFlyerClient{}
-		req := &model.BitFlyerOrderRequest{
-			ProductCode:    "BTC_JPY",
-			ChildOrderType: "LIMIT",
-			Side:           "BUY",
-			Price:          10000000,
-			Size:           0.001,
+		req := &model.OrderRequest{
+			Symbol: "BTC/JPY",
+			Type:   model.OrderTypeLimit,
+			Side:   model.OrderSideBuy,
+			Price:  10000000,
+			Size:   0.001,
 		}
 
-		resp, err := mock.SendOrder(req)
+		resp, err := mock.SendOrder(context.Background(), req)
 
 		if err != nil {
 			t.Errorf("expected no error, got %v", err)
 		}
-		if resp.ChildOrderAcceptanceID != "TEST_ORDER_123" {
-			t.Errorf("expected order ID TEST_ORDER_123, got %s", resp.ChildOrderAcceptanceID)
+		if resp.OrderID != "TEST_ORDER_123" {
+			t.Errorf("expected order ID TEST_ORDER_123, got %s", resp.OrderID)
 		}
 	})
 
 	t.Run("calls custom mock function when set", func(t *testing.T) {
 		expectedOrderID := "CUSTOM_ORDER_456"
 		mock := &MockBitFlyerClient{
-			SendOrderFunc: func(req *model.BitFlyerOrderRequest) (*model.BitFlyerOrderResponse, error) {
-				return &model.BitFlyerOrderResponse{
-					ChildOrderAcceptanceID: expectedOrderID,
+			SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+				return &model.OrderResponse{
+					OrderID: expectedOrderID,
 				}, nil
 			},
 		}
 
-		req := &model.BitFlyerOrderRequest{
-			ProductCode:    "BTC_JPY",
-			ChildOrderType: "LIMIT",
-			Side:           "BUY",
-			Price:          10000000,
-			Size:           0.001,
+		req := &model.OrderRequest{
+			Symbol: "BTC/JPY",
+			Type:   model.OrderTypeLimit,
+			Side:   model.OrderSideBuy,
+			Price:  10000000,
+			Size:   0.001,
 		}
 
-		resp, err := mock.SendOrder(req)
+		resp, err := mock.SendOrder(context.Background(), req)
 
 		if err != nil {
 			t.Errorf("expected no error, got %v", err)
 		}
-		if resp.ChildOrderAcceptanceID != expectedOrderID {
-			t.Errorf("expected order ID %s, got %s", expectedOrderID, resp.ChildOrderAcceptanceID)
+		if resp.OrderID != expectedOrderID {
+			t.Errorf("expected order ID %s, got %s", expectedOrderID, resp.OrderID)
 		}
 	})
 
 	t.Run("returns error when mock function returns error", func(t *testing.T) {
 		expectedError := errors.New("order submission failed")
 		mock := &MockBitFlyerClient{
-			SendOrderFunc: func(req *model.BitFlyerOrderRequest) (*model.BitFlyerOrderResponse, error) {
+			SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
 				return nil, expectedError
 			},
 		}
 
-		req := &model.BitFlyerOrderRequest{
-			ProductCode:    "BTC_JPY",
-			ChildOrderType: "LIMIT",
-			Side:           "BUY",
-			Price:          10000000,
-			Size:           0.001,
+		req := &model.OrderRequest{
+			Symbol: "BTC/JPY",
+			Type:   model.OrderTypeLimit,
+			Side:   model.OrderSideBuy,
+			Price:  10000000,
+			Size:   0.001,
 		}
 
-		resp, err := mock.SendOrder(req)
+		resp, err := mock.SendOrder(context.Background(), req)
 
 		if err == nil {
 			t.Error("expected error, got nil")
@@ -142,16 +143,16 @@ func TestMockBitFlyerClient_SendOrder(t *testing.T) {
 func TestMockBitFlyerClient_GetTicker(t *testing.T) {
 	t.Run("returns default ticker when no mock function set", func(t *testing.T) {
 		mock := &MockBitFlyerClient{}
-		ticker, err := mock.GetTicker("BTC_JPY")
+		ticker, err := mock.GetTicker(context.Background(), "BTC/JPY")
 
 		if err != nil {
 			t.Errorf("expected no error, got %v", err)
 		}
-		if ticker.ProductCode != "BTC_JPY" {
-			t.Errorf("expected product code BTC_JPY, got %s", ticker.ProductCode)
+		if ticker.Symbol != "BTC/JPY" {
+			t.Errorf("expected symbol BTC/JPY, got %s", ticker.Symbol)
 		}
-		if ticker.Ltp != 10000000.0 {
-			t.Errorf("expected LTP 10000000.0, got %f", ticker.Ltp)
+		if ticker.Last != 10000000.0 {
+			t.Errorf("expected last price 10000000.0, got %f", ticker.Last)
 		}
 	})
 }
@@ -0,0 +1,530 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+func TestBitFlyerClient_GetTicker_ContextCancellation(t *testing.T) {
+	released := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Block until the client gives up on the request
+		<-r.Context().Done()
+		close(released)
+	}))
+	defer server.Close()
+
+	c := NewBitFlyerClient(server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.GetTicker(ctx, "BTC/JPY")
+
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected call to return promptly after deadline, took %v", elapsed)
+	}
+
+	select {
+	case <-released:
+	case <-time.After(2 * time.Second):
+		t.Error("expected outbound request to be cancelled on the server side")
+	}
+}
+
+func TestBitFlyerClient_GetTicker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/ticker" || r.URL.Query().Get("product_code") != "ETH_JPY" {
+			t.Errorf("unexpected request %s", r.URL.String())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"product_code": "ETH_JPY",
+			"timestamp": "2015-07-08T02:50:59.97",
+			"tick_id": 3579,
+			"best_bid": 480000,
+			"best_ask": 481000,
+			"best_bid_size": 0.1,
+			"best_ask_size": 0.2,
+			"ltp": 480500,
+			"volume": 16819.26,
+			"volume_by_product": 6819.26
+		}`))
+	}))
+	defer server.Close()
+
+	c := NewBitFlyerClient(server.URL)
+
+	ticker, err := c.GetTicker(context.Background(), "ETH/JPY")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := model.Ticker{
+		Symbol:    "ETH/JPY",
+		Last:      480500,
+		Bid:       480000,
+		Ask:       481000,
+		BidSize:   0.1,
+		AskSize:   0.2,
+		Volume:    16819.26,
+		Timestamp: time.Date(2015, 7, 8, 2, 50, 59, 970000000, time.UTC),
+	}
+	if *ticker != expected {
+		t.Errorf("expected %+v, got %+v", expected, *ticker)
+	}
+}
+
+func TestBitFlyerClient_SendOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *model.OrderRequest
+		expected map[string]any
+	}{
+		{
+			name: "limit order",
+			req:  &model.OrderRequest{Symbol: "BTC/JPY", Side: model.OrderSideSell, Type: model.OrderTypeLimit, TimeInForce: model.TimeInForceGTC, Price: 15000000, Size: 0.001, ClientOrderID: "client-1"},
+			expected: map[string]any{
+				"product_code": "BTC_JPY", "child_order_type": "LIMIT", "side": "SELL",
+				"price": float64(15000000), "size": 0.001, "time_in_force": "GTC",
+			},
+		},
+		{
+			name: "market order without price",
+			req:  &model.OrderRequest{Symbol: "ETH/JPY", Side: model.OrderSideBuy, Type: model.OrderTypeMarket, Price: 480000, Size: 0.01, ClientOrderID: "client-1"},
+			expected: map[string]any{
+				"product_code": "ETH_JPY", "child_order_type": "MARKET", "side": "BUY", "size": 0.01,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/me/sendchildorder" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+					t.Errorf("failed to decode request body: %v", err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"child_order_acceptance_id": "JRF20150707-050237-639234"}`))
+			}))
+			defer server.Close()
+
+			c := NewBitFlyerClientWithAuth(server.URL, "key", "secret")
+
+			resp, err := c.SendOrder(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if resp.OrderID != "JRF20150707-050237-639234" || resp.ClientOrderID != "client-1" {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+			if len(gotBody) != len(tt.expected) {
+				t.Errorf("expected body %v, got %v", tt.expected, gotBody)
+			}
+			for key, value := range tt.expected {
+				if gotBody[key] != value {
+					t.Errorf("expected %s=%v, got %v", key, value, gotBody[key])
+				}
+			}
+		})
+	}
+}
+
+func TestBitFlyerClient_GetBalances(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/me/getbalance" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("ACCESS-KEY") != "key" || r.Header.Get("ACCESS-SIGN") == "" {
+			t.Error("expected authentication headers to be set")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"currency_code": "JPY", "amount": 1024078, "available": 508000},
+			{"currency_code": "BTC", "amount": 10.24, "available": 4.12},
+			{"currency_code": "ETH", "amount": 20.48, "available": 16.38}
+		]`))
+	}))
+	defer server.Close()
+
+	c := NewBitFlyerClientWithAuth(server.URL, "key", "secret")
+
+	balances, err := c.GetBalances(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(balances) != 3 {
+		t.Fatalf("expected 3 balances, got %d", len(balances))
+	}
+	if balances[1].Currency != "BTC" || balances[1].Amount != 10.24 || balances[1].Available != 4.12 {
+		t.Errorf("unexpected BTC balance: %+v", balances[1])
+	}
+
+	jpy, err := c.GetBalance(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if jpy != 508000 {
+		t.Errorf("expected available JPY 508000, got %f", jpy)
+	}
+}
+
+func TestBitFlyerClient_CancelOrder(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := NewBitFlyerClientWithAuth(server.URL, "key", "secret")
+
+	if err := c.CancelOrder(context.Background(), "BTC_JPY", "JRF20150707-050237-639234"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotPath != "/v1/me/cancelchildorder" {
+		t.Errorf("unexpected path %s", gotPath)
+	}
+	if gotBody["product_code"] != "BTC_JPY" || gotBody["child_order_acceptance_id"] != "JRF20150707-050237-639234" {
+		t.Errorf("unexpected request body: %v", gotBody)
+	}
+
+	if err := c.CancelAllOrders(context.Background(), "ETH_JPY"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotPath != "/v1/me/cancelallchildorders" {
+		t.Errorf("unexpected path %s", gotPath)
+	}
+	if gotBody["product_code"] != "ETH_JPY" {
+		t.Errorf("unexpected request body: %v", gotBody)
+	}
+}
+
+func TestBitFlyerClient_CancelOrder_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":-111,"error_message":"Order not found"}`))
+	}))
+	defer server.Close()
+
+	c := NewBitFlyerClientWithAuth(server.URL, "key", "secret")
+
+	if err := c.CancelOrder(context.Background(), "BTC_JPY", "JRF-X"); err == nil {
+		t.Error("expected error for non-200 response, got nil")
+	}
+}
+
+func TestBitFlyerClient_ErrorDecoding(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		kind       error
+		expected   string
+	}{
+		{
+			name:       "insufficient funds",
+			statusCode: http.StatusBadRequest,
+			body:       `{"status":-200,"error_message":"Insufficient funds","data":null}`,
+			kind:       ErrInsufficientFunds,
+			expected:   "insufficient balance: bitFlyer API error -200: Insufficient funds",
+		},
+		{
+			name:       "minimum order size",
+			statusCode: http.StatusBadRequest,
+			body:       `{"status":-110,"error_message":"The minimum order size is 0.001 BTC.","data":null}`,
+			kind:       ErrInvalidSize,
+			expected:   "bitFlyer API error -110",
+		},
+		{
+			name:       "unknown code classified by message",
+			statusCode: http.StatusBadRequest,
+			body:       `{"status":-106,"error_message":"The price is invalid.","data":null}`,
+			kind:       ErrInvalidPrice,
+			expected:   "bitFlyer API error -106",
+		},
+		{
+			name:       "invalid signature",
+			statusCode: http.StatusUnauthorized,
+			body:       `{"status":-501,"error_message":"Invalid signature","data":null}`,
+			kind:       ErrAuthFailed,
+			expected:   "bitFlyer API error -501",
+		},
+		{
+			name:       "non-JSON error response",
+			statusCode: http.StatusServiceUnavailable,
+			body:       `Service Unavailable`,
+			expected:   "bitFlyer API returned status 503: Service Unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			c := NewBitFlyerClientWithAuth(server.URL, "key", "secret")
+
+			_, err := c.SendOrder(context.Background(), &model.OrderRequest{Symbol: "BTC/JPY", Side: model.OrderSideBuy, Type: model.OrderTypeLimit, Price: 14000000, Size: 0.001})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.statusCode {
+				t.Fatalf("expected APIError with status %d, got %v", tt.statusCode, err)
+			}
+			if apiErr.Kind != tt.kind {
+				t.Errorf("expected kind %v, got %v", tt.kind, apiErr.Kind)
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestBitFlyerClient_SendOrder_CancelledContext(t *testing.T) {
+	var called atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called.Store(true)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := NewBitFlyerClientWithAuth(server.URL, "key", "secret")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.SendOrder(ctx, &model.OrderRequest{
+		Symbol: "BTC/JPY",
+		Type:   model.OrderTypeLimit,
+		Side:   model.OrderSideBuy,
+		Price:  10000000,
+		Size:   0.001,
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if called.Load() {
+		t.Error("expected no request to reach the exchange after cancellation")
+	}
+}
+
+func TestBitFlyerClient_GetChildOrders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/me/getchildorders" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("product_code") != "BTC_JPY" || query.Get("child_order_acceptance_id") != "JRF-1" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if query.Has("count") || query.Has("child_order_state") {
+			t.Errorf("expected unset filters to be omitted, got %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{
+			"id": 138398,
+			"child_order_id": "JOR20150707-084555-022523",
+			"product_code": "BTC_JPY",
+			"side": "BUY",
+			"child_order_type": "LIMIT",
+			"price": 30000,
+			"average_price": 30000,
+			"size": 0.1,
+			"child_order_state": "ACTIVE",
+			"child_order_date": "2015-07-07T08:45:53",
+			"child_order_acceptance_id": "JRF-1",
+			"outstanding_size": 0.07,
+			"cancel_size": 0,
+			"executed_size": 0.03,
+			"total_commission": 0
+		}]`))
+	}))
+	defer server.Close()
+
+	c := NewBitFlyerClientWithAuth(server.URL, "key", "secret")
+
+	orders, err := c.GetChildOrders(context.Background(), &model.ChildOrderQuery{
+		ProductCode: "BTC_JPY",
+		OrderID:     "JRF-1",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(orders) != 1 {
+		t.Fatalf("expected 1 order, got %d", len(orders))
+	}
+	if orders[0].OrderID != "JRF-1" || orders[0].State != model.OrderStateActive || orders[0].ExecutedSize != 0.03 || orders[0].OutstandingSize != 0.07 {
+		t.Errorf("unexpected order: %+v", orders[0])
+	}
+	// bitFlyer omits the zone of its UTC order dates
+	if !orders[0].OrderDate.Equal(time.Date(2015, 7, 7, 8, 45, 53, 0, time.UTC)) {
+		t.Errorf("unexpected order date: %v", orders[0].OrderDate)
+	}
+}
+
+func TestBitFlyerClient_SendParentOrder(t *testing.T) {
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/me/sendparentorder" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"parent_order_acceptance_id": "JRF20150925-060559-396699"}`))
+	}))
+	defer server.Close()
+
+	c := NewBitFlyerClientWithAuth(server.URL, "key", "secret")
+
+	resp, err := c.SendParentOrder(context.Background(), &model.ParentOrderRequest{
+		Method:         model.ParentOrderMethodIFDOCO,
+		MinuteToExpire: 10000,
+		TimeInForce:    "GTC",
+		Legs: []model.ParentOrderLeg{
+			{ProductCode: "BTC_JPY", Side: "BUY", ConditionType: model.ConditionTypeLimit, Size: 0.001, Price: 14000000},
+			{ProductCode: "BTC_JPY", Side: "SELL", ConditionType: model.ConditionTypeLimit, Size: 0.001, Price: 14200000},
+			{ProductCode: "BTC_JPY", Side: "SELL", ConditionType: model.ConditionTypeStopLimit, Size: 0.001, Price: 13790000, TriggerPrice: 13800000},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.AcceptanceID != "JRF20150925-060559-396699" {
+		t.Errorf("unexpected acceptance ID %s", resp.AcceptanceID)
+	}
+
+	if gotBody["order_method"] != "IFDOCO" || gotBody["minute_to_expire"] != float64(10000) || gotBody["time_in_force"] != "GTC" {
+		t.Errorf("unexpected request body: %v", gotBody)
+	}
+	parameters, ok := gotBody["parameters"].([]any)
+	if !ok || len(parameters) != 3 {
+		t.Fatalf("expected 3 parameters, got %v", gotBody["parameters"])
+	}
+	entry := parameters[0].(map[string]any)
+	if entry["condition_type"] != "LIMIT" || entry["price"] != float64(14000000) || entry["trigger_price"] != nil {
+		t.Errorf("unexpected entry parameter: %v", entry)
+	}
+	stop := parameters[2].(map[string]any)
+	if stop["condition_type"] != "STOP_LIMIT" || stop["trigger_price"] != float64(13800000) || stop["offset"] != nil {
+		t.Errorf("unexpected stop parameter: %v", stop)
+	}
+}
+
+func TestBitFlyerClient_GetParentOrders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/me/getparentorders" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("product_code") != "BTC_JPY" || query.Get("count") != "100" || query.Has("parent_order_state") {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{
+			"id": 138398,
+			"parent_order_id": "JCO20150925-055934-201013",
+			"product_code": "BTC_JPY",
+			"side": "BUY",
+			"parent_order_type": "IFDOCO",
+			"price": 30000,
+			"average_price": 30000,
+			"size": 0.1,
+			"parent_order_state": "COMPLETED",
+			"expire_date": "2015-10-25T05:59:34",
+			"parent_order_date": "2015-09-25T05:59:34",
+			"parent_order_acceptance_id": "JRF20150925-055934-201013",
+			"outstanding_size": 0,
+			"cancel_size": 0,
+			"executed_size": 0.1,
+			"total_commission": 0
+		}]`))
+	}))
+	defer server.Close()
+
+	c := NewBitFlyerClientWithAuth(server.URL, "key", "secret")
+
+	orders, err := c.GetParentOrders(context.Background(), &model.ParentOrderQuery{
+		ProductCode: "BTC_JPY",
+		Count:       100,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(orders) != 1 {
+		t.Fatalf("expected 1 order, got %d", len(orders))
+	}
+	if orders[0].ExchangeOrderID != "JCO20150925-055934-201013" || orders[0].State != "COMPLETED" {
+		t.Errorf("unexpected order: %+v", orders[0])
+	}
+}
+
+func TestBitFlyerClient_GetProducts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/getmarkets" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"product_code": "BTC_JPY", "market_type": "Spot"},
+			{"product_code": "XRP_JPY", "market_type": "Spot"},
+			{"product_code": "ETH_JPY", "market_type": "Spot"},
+			{"product_code": "FX_BTC_JPY", "market_type": "FX"},
+			{"product_code": "BTCJPY28MAR2025", "alias": "BTCJPY_MAT3M", "market_type": "Futures"}
+		]`))
+	}))
+	defer server.Close()
+
+	c := NewBitFlyerClient(server.URL)
+
+	products, err := c.GetProducts(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Only spot products with known trading rules are returned
+	expected := []model.Product{
+		{Symbol: "BTC/JPY", Exchange: "bitflyer", TickSize: 1, LotStep: 0.00000001, MinSize: 0.001},
+		{Symbol: "ETH/JPY", Exchange: "bitflyer", TickSize: 1, LotStep: 0.00000001, MinSize: 0.01},
+	}
+	if len(products) != len(expected) {
+		t.Fatalf("expected %+v, got %+v", expected, products)
+	}
+	for i := range expected {
+		if products[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], products[i])
+		}
+	}
+}
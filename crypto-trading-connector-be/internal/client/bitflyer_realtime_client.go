@@ -0,0 +1,479 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"golang.org/x/net/websocket"
+)
+
+// BitFlyerRealtimeURL is the endpoint of bitFlyer Realtime API (JSON-RPC 2.0 over WebSocket)
+const BitFlyerRealtimeURL = "wss://ws.lightstream.bitflyer.com/json-rpc"
+
+// bitFlyerRealtimeOrigin is sent as the Origin header of the WebSocket handshake
+const bitFlyerRealtimeOrigin = "https://lightning.bitflyer.com"
+
+// Channel name prefixes of bitFlyer Realtime API; the product code follows (e.g. "lightning_ticker_BTC_JPY")
+const (
+	bitFlyerTickerChannel        = "lightning_ticker_"
+	bitFlyerExecutionsChannel    = "lightning_executions_"
+	bitFlyerBoardSnapshotChannel = "lightning_board_snapshot_"
+	bitFlyerBoardChannel         = "lightning_board_"
+)
+
+// RealtimeConfig configures the connection of a realtime market data client
+type RealtimeConfig struct {
+	HeartbeatTimeout  time.Duration // The connection is dropped when nothing is received for this long
+	MinReconnectDelay time.Duration // Delay before reconnecting, doubled for every attempt that fails
+	MaxReconnectDelay time.Duration // Upper bound of the reconnect delay
+}
+
+// DefaultRealtimeConfig returns the connection settings used by the server
+func DefaultRealtimeConfig() RealtimeConfig {
+	return RealtimeConfig{
+		HeartbeatTimeout:  30 * time.Second,
+		MinReconnectDelay: time.Second,
+		MaxReconnectDelay: 30 * time.Second,
+	}
+}
+
+// BitFlyerRealtimeClient implements MarketDataStream with bitFlyer Realtime API.
+// Run keeps one WebSocket connection open, reconnecting with backoff when it drops or goes silent,
+// and subscribes to the channels needed by the current subscriptions on every connection.
+type BitFlyerRealtimeClient struct {
+	url    string
+	config RealtimeConfig
+	done   chan struct{} // Closed when Run returns
+
+	mu            sync.Mutex
+	conn          *websocket.Conn // nil while disconnected
+	nextID        int
+	channels      map[string]int // Number of subscriptions per channel
+	subscriptions map[*realtimeSubscription]struct{}
+	books         map[model.Symbol]*orderBook
+	stopped       bool
+}
+
+// realtimeSubscription is a subscriber of one or more channels
+type realtimeSubscription struct {
+	symbol   model.Symbol
+	channels []string
+	book     bool             // The subscription reads the local order book of the symbol
+	deliver  func(update any) // Called with *model.Ticker, []model.Execution or *orderBook; must not block
+	close    func()
+}
+
+// jsonRPCRequest is a request to bitFlyer Realtime API
+type jsonRPCRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+	ID      int    `json:"id"`
+}
+
+// jsonRPCMessage is a response or a notification from bitFlyer Realtime API
+type jsonRPCMessage struct {
+	ID     *int                  `json:"id"`
+	Method string                `json:"method"`
+	Params *channelMessageParams `json:"params"`
+	Error  *jsonRPCError         `json:"error"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// channelMessageParams are the params of a channelMessage notification
+type channelMessageParams struct {
+	Channel string          `json:"channel"`
+	Message json.RawMessage `json:"message"`
+}
+
+type channelParams struct {
+	Channel string `json:"channel"`
+}
+
+// NewBitFlyerRealtimeClient creates a realtime client for the given endpoint (normally BitFlyerRealtimeURL).
+// Nothing is received until Run is started.
+func NewBitFlyerRealtimeClient(url string, config RealtimeConfig) *BitFlyerRealtimeClient {
+	return &BitFlyerRealtimeClient{
+		url:           url,
+		config:        config,
+		done:          make(chan struct{}),
+		channels:      map[string]int{},
+		subscriptions: map[*realtimeSubscription]struct{}{},
+		books:         map[model.Symbol]*orderBook{},
+	}
+}
+
+// Connected reports whether the WebSocket connection is currently open
+func (c *BitFlyerRealtimeClient) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn != nil
+}
+
+// Run connects to bitFlyer and delivers pushed messages to subscribers until ctx is cancelled.
+// Lost connections are reestablished with exponential backoff. When Run returns, the channels
+// of every subscription are closed and new subscriptions fail with ErrStreamClosed.
+func (c *BitFlyerRealtimeClient) Run(ctx context.Context) {
+	defer c.stop()
+
+	delay := c.config.MinReconnectDelay
+	for {
+		received, err := c.connect(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if received {
+			delay = c.config.MinReconnectDelay
+		}
+
+		log.Printf("Warning: bitFlyer realtime connection lost: %v; reconnecting in %s", err, delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, c.config.MaxReconnectDelay)
+	}
+}
+
+// connect opens a connection, subscribes to the channels in use and reads messages until the
+// connection fails. received reports whether anything arrived before it did.
+func (c *BitFlyerRealtimeClient) connect(ctx context.Context) (received bool, err error) {
+	config, err := websocket.NewConfig(c.url, bitFlyerRealtimeOrigin)
+	if err != nil {
+		return false, fmt.Errorf("invalid realtime API URL: %w", err)
+	}
+	conn, err := config.DialContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+	stopClosing := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stopClosing()
+
+	c.mu.Lock()
+	c.conn = conn
+	channels := make([]string, 0, len(c.channels))
+	for channel := range c.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	for _, channel := range channels {
+		c.sendLocked("subscribe", channel)
+	}
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.conn = nil
+		// Diffs pushed while disconnected are lost, so every book waits for a new snapshot
+		for _, book := range c.books {
+			book.reset()
+		}
+		c.mu.Unlock()
+	}()
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(c.config.HeartbeatTimeout)); err != nil {
+			return received, err
+		}
+
+		var data []byte
+		if err := websocket.Message.Receive(conn, &data); err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return received, fmt.Errorf("no message received for %s", c.config.HeartbeatTimeout)
+			}
+			return received, err
+		}
+		received = true
+		c.handleMessage(data)
+	}
+}
+
+// sendLocked sends a subscribe or unsubscribe request if connected; otherwise the request is
+// made on the next connection. c.mu must be held.
+func (c *BitFlyerRealtimeClient) sendLocked(method, channel string) {
+	if c.conn == nil {
+		return
+	}
+
+	c.nextID++
+	req := jsonRPCRequest{JSONRPC: "2.0", Method: method, Params: channelParams{Channel: channel}, ID: c.nextID}
+	_ = c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := websocket.JSON.Send(c.conn, req); err != nil {
+		// Closing the connection makes Run reconnect and subscribe again
+		log.Printf("Warning: failed to %s bitFlyer realtime channel %s: %v", method, channel, err)
+		c.conn.Close()
+	}
+}
+
+func (c *BitFlyerRealtimeClient) handleMessage(data []byte) {
+	var msg jsonRPCMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Printf("Warning: failed to decode bitFlyer realtime message: %v", err)
+		return
+	}
+
+	switch {
+	case msg.Error != nil:
+		id := 0
+		if msg.ID != nil {
+			id = *msg.ID
+		}
+		log.Printf("Warning: bitFlyer realtime request %d failed: %d %s", id, msg.Error.Code, msg.Error.Message)
+	case msg.Method == "channelMessage" && msg.Params != nil:
+		c.handleChannelMessage(msg.Params)
+	}
+}
+
+// handleChannelMessage decodes a message pushed on a channel and delivers it to the subscribers of the channel
+func (c *BitFlyerRealtimeClient) handleChannelMessage(params *channelMessageParams) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var update any
+	switch channel := params.Channel; {
+	case strings.HasPrefix(channel, bitFlyerTickerChannel):
+		var ticker model.BitFlyerTickerResponse
+		if err := json.Unmarshal(params.Message, &ticker); err != nil {
+			log.Printf("Warning: failed to decode %s message: %v", channel, err)
+			return
+		}
+		update = toTicker(channelSymbol(channel, bitFlyerTickerChannel), &ticker)
+
+	case strings.HasPrefix(channel, bitFlyerExecutionsChannel):
+		var executions []model.BitFlyerExecution
+		if err := json.Unmarshal(params.Message, &executions); err != nil {
+			log.Printf("Warning: failed to decode %s message: %v", channel, err)
+			return
+		}
+		update = toExecutions(channelSymbol(channel, bitFlyerExecutionsChannel), executions)
+
+	case strings.HasPrefix(channel, bitFlyerBoardSnapshotChannel), strings.HasPrefix(channel, bitFlyerBoardChannel):
+		var board model.BitFlyerBoard
+		if err := json.Unmarshal(params.Message, &board); err != nil {
+			log.Printf("Warning: failed to decode %s message: %v", channel, err)
+			return
+		}
+
+		snapshot := strings.HasPrefix(channel, bitFlyerBoardSnapshotChannel)
+		prefix := bitFlyerBoardChannel
+		if snapshot {
+			prefix = bitFlyerBoardSnapshotChannel
+		}
+		book, ok := c.books[channelSymbol(channel, prefix)]
+		if !ok {
+			return
+		}
+		if snapshot {
+			book.applySnapshot(&board)
+		} else if !book.applyDiff(&board) {
+			return
+		}
+		update = book
+
+	default:
+		return
+	}
+
+	for sub := range c.subscriptions {
+		for _, channel := range sub.channels {
+			if channel == params.Channel {
+				sub.deliver(update)
+			}
+		}
+	}
+}
+
+// SubscribeTicker delivers every ticker update of a trading pair
+func (c *BitFlyerRealtimeClient) SubscribeTicker(ctx context.Context, symbol model.Symbol) (<-chan model.Ticker, error) {
+	updates := make(chan model.Ticker, 1)
+	err := c.subscribe(ctx, &realtimeSubscription{
+		symbol:   symbol,
+		channels: []string{bitFlyerTickerChannel + bitFlyerProductCode(symbol)},
+		deliver: func(update any) {
+			offer(updates, *update.(*model.Ticker))
+		},
+		close: func() { close(updates) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// SubscribeExecutions delivers the trades executed on a trading pair
+func (c *BitFlyerRealtimeClient) SubscribeExecutions(ctx context.Context, symbol model.Symbol) (<-chan []model.Execution, error) {
+	updates := make(chan []model.Execution, 64)
+	err := c.subscribe(ctx, &realtimeSubscription{
+		symbol:   symbol,
+		channels: []string{bitFlyerExecutionsChannel + bitFlyerProductCode(symbol)},
+		deliver: func(update any) {
+			offer(updates, update.([]model.Execution))
+		},
+		close: func() { close(updates) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// SubscribeOrderBook delivers the best depth levels of the local order book of a trading pair.
+// The book is built from the board snapshot channel and updated with the board channel.
+func (c *BitFlyerRealtimeClient) SubscribeOrderBook(ctx context.Context, symbol model.Symbol, depth int) (<-chan model.OrderBook, error) {
+	updates := make(chan model.OrderBook, 1)
+	productCode := bitFlyerProductCode(symbol)
+	err := c.subscribe(ctx, &realtimeSubscription{
+		symbol:   symbol,
+		channels: []string{bitFlyerBoardSnapshotChannel + productCode, bitFlyerBoardChannel + productCode},
+		book:     true,
+		deliver: func(update any) {
+			offer(updates, *update.(*orderBook).snapshot(depth))
+		},
+		close: func() { close(updates) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// OrderBook returns the best depth levels of the local order book of a trading pair
+func (c *BitFlyerRealtimeClient) OrderBook(symbol model.Symbol, depth int) (*model.OrderBook, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	book, ok := c.books[symbol]
+	if !ok || !book.synced {
+		return nil, false
+	}
+	return book.snapshot(depth), true
+}
+
+// subscribe registers a subscription, subscribing to its channels not in use yet,
+// and removes it again when ctx is done
+func (c *BitFlyerRealtimeClient) subscribe(ctx context.Context, sub *realtimeSubscription) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopped {
+		return ErrStreamClosed
+	}
+
+	c.subscriptions[sub] = struct{}{}
+	for _, channel := range sub.channels {
+		c.channels[channel]++
+		if c.channels[channel] == 1 {
+			c.sendLocked("subscribe", channel)
+		}
+	}
+	if sub.book {
+		book, ok := c.books[sub.symbol]
+		if !ok {
+			c.books[sub.symbol] = newOrderBook(sub.symbol)
+		} else if book.synced {
+			sub.deliver(book)
+		}
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.unsubscribe(sub)
+		case <-c.done:
+		}
+	}()
+	return nil
+}
+
+// unsubscribe removes a subscription, unsubscribing from the channels no longer in use
+func (c *BitFlyerRealtimeClient) unsubscribe(sub *realtimeSubscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.subscriptions[sub]; !ok {
+		return
+	}
+	delete(c.subscriptions, sub)
+
+	for _, channel := range sub.channels {
+		c.channels[channel]--
+		if c.channels[channel] == 0 {
+			delete(c.channels, channel)
+			c.sendLocked("unsubscribe", channel)
+		}
+	}
+	if _, inUse := c.channels[bitFlyerBoardSnapshotChannel+bitFlyerProductCode(sub.symbol)]; sub.book && !inUse {
+		delete(c.books, sub.symbol)
+	}
+	sub.close()
+}
+
+// stop closes every subscription once Run has returned
+func (c *BitFlyerRealtimeClient) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stopped = true
+	close(c.done)
+	for sub := range c.subscriptions {
+		sub.close()
+	}
+	c.subscriptions = map[*realtimeSubscription]struct{}{}
+}
+
+// channelSymbol returns the trading pair of a channel name
+func channelSymbol(channel, prefix string) model.Symbol {
+	return model.SymbolFromProductCode(strings.TrimPrefix(channel, prefix))
+}
+
+// toExecutions converts executions pushed by bitFlyer to the exchange-agnostic model
+func toExecutions(symbol model.Symbol, executions []model.BitFlyerExecution) []model.Execution {
+	converted := make([]model.Execution, 0, len(executions))
+	for _, execution := range executions {
+		timestamp, err := parseBitFlyerTimestamp(execution.ExecDate)
+		if err != nil {
+			timestamp = time.Now()
+		}
+		converted = append(converted, model.Execution{
+			ID:        execution.ID,
+			Symbol:    symbol,
+			Side:      model.OrderSide(execution.Side),
+			Price:     execution.Price,
+			Size:      execution.Size,
+			Timestamp: timestamp,
+		})
+	}
+	return converted
+}
+
+// offer sends an update without blocking, dropping the oldest buffered update of a slow subscriber.
+// Updates are only sent with c.mu held, so the loop ends as soon as a slot has been freed.
+func offer[T any](updates chan T, update T) {
+	for {
+		select {
+		case updates <- update:
+			return
+		default:
+		}
+		select {
+		case <-updates:
+		default:
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"golang.org/x/net/websocket"
+)
+
+// realtimeStandIn is a local stand-in for bitFlyer Realtime API that hands every connection to the test
+type realtimeStandIn struct {
+	server *httptest.Server
+	conns  chan *standInConn
+}
+
+// standInConn is a connection accepted by the stand-in. Subscribe and unsubscribe requests are
+// acknowledged and forwarded to requests; messages are pushed by the test.
+type standInConn struct {
+	ws       *websocket.Conn
+	requests chan standInRequest
+}
+
+type standInRequest struct {
+	Method string        `json:"method"`
+	Params channelParams `json:"params"`
+	ID     int           `json:"id"`
+}
+
+func newRealtimeStandIn(t *testing.T) *realtimeStandIn {
+	s := &realtimeStandIn{conns: make(chan *standInConn, 10)}
+	s.server = httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		conn := &standInConn{ws: ws, requests: make(chan standInRequest, 10)}
+		s.conns <- conn
+		for {
+			var req standInRequest
+			if err := websocket.JSON.Receive(ws, &req); err != nil {
+				return
+			}
+			_ = websocket.JSON.Send(ws, map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": true})
+			conn.requests <- req
+		}
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *realtimeStandIn) url() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+func (s *realtimeStandIn) accept(t *testing.T) *standInConn {
+	t.Helper()
+	select {
+	case conn := <-s.conns:
+		return conn
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the client to connect")
+		return nil
+	}
+}
+
+// expect waits for the next requests and checks that they (un)subscribe the channels in any order
+func (c *standInConn) expect(t *testing.T, method string, channels ...string) {
+	t.Helper()
+	pending := map[string]bool{}
+	for _, channel := range channels {
+		pending[channel] = true
+	}
+	for len(pending) > 0 {
+		select {
+		case req := <-c.requests:
+			if req.Method != method || !pending[req.Params.Channel] {
+				t.Fatalf("expected %s %v, got %s %s", method, channels, req.Method, req.Params.Channel)
+			}
+			delete(pending, req.Params.Channel)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected %s %v", method, channels)
+		}
+	}
+}
+
+func (c *standInConn) push(t *testing.T, channel, message string) {
+	t.Helper()
+	notification := map[string]any{
+		"jsonrpc": "2.0",
+		"method":  "channelMessage",
+		"params":  map[string]any{"channel": channel, "message": json.RawMessage(message)},
+	}
+	if err := websocket.JSON.Send(c.ws, notification); err != nil {
+		t.Fatalf("failed to push message: %v", err)
+	}
+}
+
+func receiveUpdate[T any](t *testing.T, updates <-chan T) T {
+	t.Helper()
+	select {
+	case update, ok := <-updates:
+		if !ok {
+			t.Fatal("expected an update, got a closed channel")
+		}
+		return update
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected an update")
+	}
+	var zero T
+	return zero
+}
+
+// runRealtimeClient runs the client until the test ends and returns a channel closed when Run returns
+func runRealtimeClient(t *testing.T, c *BitFlyerRealtimeClient) (cancel context.CancelFunc, stopped <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return cancel, done
+}
+
+func testRealtimeConfig() RealtimeConfig {
+	return RealtimeConfig{
+		HeartbeatTimeout:  time.Second,
+		MinReconnectDelay: 10 * time.Millisecond,
+		MaxReconnectDelay: 50 * time.Millisecond,
+	}
+}
+
+func TestBitFlyerRealtimeClient_TickerAndExecutions(t *testing.T) {
+	standIn := newRealtimeStandIn(t)
+	c := NewBitFlyerRealtimeClient(standIn.url(), testRealtimeConfig())
+
+	// Subscriptions made before connecting are sent once connected
+	tickers, err := c.SubscribeTicker(context.Background(), "BTC/JPY")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	runRealtimeClient(t, c)
+	conn := standIn.accept(t)
+	conn.expect(t, "subscribe", "lightning_ticker_BTC_JPY")
+
+	conn.push(t, "lightning_ticker_BTC_JPY", `{"product_code":"BTC_JPY","timestamp":"2019-04-11T05:14:12.3739915Z","best_bid":15000000,"best_ask":15001000,"ltp":15000500,"volume":1200.5}`)
+	ticker := receiveUpdate(t, tickers)
+	if ticker.Symbol != "BTC/JPY" || ticker.Last != 15000500 || ticker.Bid != 15000000 || ticker.Ask != 15001000 {
+		t.Errorf("unexpected ticker: %+v", ticker)
+	}
+	if want := time.Date(2019, 4, 11, 5, 14, 12, 373991500, time.UTC); !ticker.Timestamp.Equal(want) {
+		t.Errorf("expected timestamp %v, got %v", want, ticker.Timestamp)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	executions, err := c.SubscribeExecutions(ctx, "ETH/JPY")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	conn.expect(t, "subscribe", "lightning_executions_ETH_JPY")
+
+	conn.push(t, "lightning_executions_ETH_JPY", `[{"id":39361,"side":"SELL","price":500000,"size":0.01,"exec_date":"2015-07-07T10:44:33.547Z"},{"id":39362,"side":"BUY","price":500100,"size":0.2,"exec_date":"2015-07-07T10:44:33.6Z"}]`)
+	batch := receiveUpdate(t, executions)
+	if len(batch) != 2 || batch[0].ID != 39361 || batch[0].Side != model.OrderSideSell || batch[1].Size != 0.2 || batch[1].Symbol != "ETH/JPY" {
+		t.Errorf("unexpected executions: %+v", batch)
+	}
+
+	// Cancelling the last subscriber unsubscribes from the channel and closes the subscription
+	cancel()
+	conn.expect(t, "unsubscribe", "lightning_executions_ETH_JPY")
+	if _, ok := <-executions; ok {
+		t.Error("expected the execution channel to be closed")
+	}
+}
+
+func TestBitFlyerRealtimeClient_OrderBook(t *testing.T) {
+	standIn := newRealtimeStandIn(t)
+	c := NewBitFlyerRealtimeClient(standIn.url(), testRealtimeConfig())
+	runRealtimeClient(t, c)
+	conn := standIn.accept(t)
+
+	books, err := c.SubscribeOrderBook(context.Background(), "BTC/JPY", 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	conn.expect(t, "subscribe", "lightning_board_snapshot_BTC_JPY", "lightning_board_BTC_JPY")
+
+	// A diff before the first snapshot is ignored
+	conn.push(t, "lightning_board_BTC_JPY", `{"mid_price":100,"bids":[{"price":97,"size":5}],"asks":[]}`)
+	conn.push(t, "lightning_board_snapshot_BTC_JPY", `{"mid_price":100.5,"bids":[{"price":100,"size":1},{"price":98,"size":3},{"price":99,"size":2}],"asks":[{"price":102,"size":2},{"price":101,"size":1}]}`)
+	book := receiveUpdate(t, books)
+	want := []model.OrderBookLevel{{Price: 100, Size: 1}, {Price: 99, Size: 2}}
+	if book.MidPrice != 100.5 || !equalLevels(book.Bids, want) {
+		t.Errorf("expected best bids %v, got %+v", want, book)
+	}
+
+	// Levels with a size of 0 are removed
+	conn.push(t, "lightning_board_BTC_JPY", `{"mid_price":100.75,"bids":[{"price":100,"size":0},{"price":99.5,"size":1}],"asks":[{"price":101,"size":0.5}]}`)
+	book = receiveUpdate(t, books)
+	if want := []model.OrderBookLevel{{Price: 99.5, Size: 1}, {Price: 99, Size: 2}}; !equalLevels(book.Bids, want) {
+		t.Errorf("expected bids %v, got %v", want, book.Bids)
+	}
+	if want := []model.OrderBookLevel{{Price: 101, Size: 0.5}, {Price: 102, Size: 2}}; !equalLevels(book.Asks, want) {
+		t.Errorf("expected asks %v, got %v", want, book.Asks)
+	}
+
+	full, ok := c.OrderBook("BTC/JPY", 0)
+	if !ok || len(full.Bids) != 3 || len(full.Asks) != 2 || full.MidPrice != 100.75 {
+		t.Errorf("expected the whole book, got %+v, %v", full, ok)
+	}
+	if _, ok := c.OrderBook("ETH/JPY", 0); ok {
+		t.Error("expected no book for a pair without subscription")
+	}
+}
+
+func TestBitFlyerRealtimeClient_Reconnect(t *testing.T) {
+	standIn := newRealtimeStandIn(t)
+	config := testRealtimeConfig()
+	config.HeartbeatTimeout = 200 * time.Millisecond
+	c := NewBitFlyerRealtimeClient(standIn.url(), config)
+	cancel, stopped := runRealtimeClient(t, c)
+
+	tickers, err := c.SubscribeTicker(context.Background(), "BTC/JPY")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	books, err := c.SubscribeOrderBook(context.Background(), "BTC/JPY", 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	conn := standIn.accept(t)
+	conn.expect(t, "subscribe", "lightning_ticker_BTC_JPY", "lightning_board_snapshot_BTC_JPY", "lightning_board_BTC_JPY")
+	conn.push(t, "lightning_board_snapshot_BTC_JPY", `{"mid_price":100,"bids":[{"price":99,"size":1}],"asks":[{"price":101,"size":1}]}`)
+	receiveUpdate(t, books)
+
+	// The server drops the connection: the client reconnects and subscribes again
+	conn.ws.Close()
+	conn = standIn.accept(t)
+	conn.expect(t, "subscribe", "lightning_ticker_BTC_JPY", "lightning_board_snapshot_BTC_JPY", "lightning_board_BTC_JPY")
+	if _, ok := c.OrderBook("BTC/JPY", 1); ok {
+		t.Error("expected the book to wait for a new snapshot after reconnecting")
+	}
+
+	conn.push(t, "lightning_ticker_BTC_JPY", `{"product_code":"BTC_JPY","timestamp":"2019-04-11T05:14:12.3739915Z","ltp":15000500}`)
+	if ticker := receiveUpdate(t, tickers); ticker.Last != 15000500 {
+		t.Errorf("unexpected ticker after reconnecting: %+v", ticker)
+	}
+
+	// The connection goes silent: the client gives up on it after the heartbeat timeout
+	conn = standIn.accept(t)
+	conn.expect(t, "subscribe", "lightning_ticker_BTC_JPY", "lightning_board_snapshot_BTC_JPY", "lightning_board_BTC_JPY")
+
+	// Stopping the client closes the subscriptions
+	cancel()
+	<-stopped
+	if _, ok := <-tickers; ok {
+		t.Error("expected the ticker channel to be closed")
+	}
+	if _, err := c.SubscribeTicker(context.Background(), "BTC/JPY"); !errors.Is(err, ErrStreamClosed) {
+		t.Errorf("expected ErrStreamClosed, got %v", err)
+	}
+}
+
+func equalLevels(got, want []model.OrderBookLevel) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,17 +1,52 @@
 package client
 
-import "github.com/crypto-trading-connector/backend/internal/model"
+import (
+	"context"
+	"errors"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
 
 // CryptoExchangeClient defines the common interface for all cryptocurrency exchange APIs
 // This interface allows the application to support multiple exchanges (bitFlyer, Coinbase, Binance, etc.)
+// Every method takes a context.Context so that cancellation and deadlines of the caller
+// (e.g. an HTTP request from the frontend) propagate to the outbound exchange call.
 type CryptoExchangeClient interface {
+	// Name returns the identifier of the exchange recorded in the exchange column of orders (e.g., "bitflyer")
+	Name() string
+
 	// GetTicker retrieves current ticker information for a specific trading pair
-	// productCode: Trading pair identifier (e.g., "BTC_JPY", "ETH_JPY")
-	GetTicker(productCode string) (*model.TickerResponse, error)
+	// symbol: Exchange-agnostic trading pair (e.g., "BTC/JPY", "ETH/JPY")
+	GetTicker(ctx context.Context, symbol model.Symbol) (*model.Ticker, error)
+
+	// GetProducts retrieves the trading rules (tick size, lot step, minimum size) of every spot product on the exchange
+	GetProducts(ctx context.Context) ([]model.Product, error)
 
 	// GetBalance retrieves the available balance in the base currency (JPY)
-	GetBalance() (float64, error)
+	GetBalance(ctx context.Context) (float64, error)
+
+	// GetBalances retrieves the total amount and available amount of every currency held on the exchange
+	GetBalances(ctx context.Context) ([]model.Balance, error)
 
 	// SendOrder submits a new order to the exchange
-	SendOrder(req *model.BitFlyerOrderRequest) (*model.BitFlyerOrderResponse, error)
+	// The exchange-agnostic request is translated to the exchange's own format by each client
+	SendOrder(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error)
+
+	// GetChildOrders retrieves orders placed on the exchange together with their live execution state
+	GetChildOrders(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error)
+
+	// SendParentOrder submits a special order (IFD, OCO or IFDOCO) whose legs are managed by the exchange
+	SendParentOrder(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error)
+
+	// GetParentOrders retrieves special orders placed on the exchange together with their state
+	GetParentOrders(ctx context.Context, query *model.ParentOrderQuery) ([]model.ParentOrder, error)
+
+	// CancelOrder cancels an open order identified by the order ID returned from SendOrder
+	CancelOrder(ctx context.Context, productCode, orderID string) error
+
+	// CancelAllOrders cancels every open order for a specific trading pair
+	CancelAllOrders(ctx context.Context, productCode string) error
 }
+
+// ErrNotSupported is returned by exchange clients for operations the exchange does not offer
+var ErrNotSupported = errors.New("not supported by this exchange")
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// Kinds of exchange API errors. An APIError unwraps to one of them, so callers can classify
+// failures with errors.Is regardless of the exchange.
+var (
+	ErrInsufficientFunds = errors.New("insufficient balance")
+	ErrInvalidPrice      = errors.New("invalid price")
+	ErrInvalidSize       = errors.New("invalid amount")
+	ErrAuthFailed        = errors.New("exchange authentication failed")
+	ErrMaintenance       = errors.New("exchange under maintenance")
+)
+
+// APIError is an error response of an exchange API
+type APIError struct {
+	Exchange   string // Display name of the exchange (e.g. "bitFlyer")
+	StatusCode int    // HTTP status
+	Code       string // Error code of the exchange (e.g. "-200", "ERR-201"); empty if the body had none
+	Message    string // Error message of the exchange, or the raw body if it could not be decoded
+	Kind       error  // ErrInsufficientFunds, ErrRateLimited, ...; nil if the error is not classified
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("%s API returned status %d: %s", e.Exchange, e.StatusCode, e.Message)
+	if e.Code != "" {
+		message = fmt.Sprintf("%s API error %s: %s", e.Exchange, e.Code, e.Message)
+	}
+	if e.Kind != nil {
+		message = e.Kind.Error() + ": " + message
+	}
+	return message
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Kind
+}
+
+// bitFlyerErrorKinds classifies the error codes of bitFlyer API
+var bitFlyerErrorKinds = map[int]error{
+	-200: ErrInsufficientFunds, // Insufficient funds
+	-205: ErrInsufficientFunds, // Margin amount is insufficient for this order
+	-110: ErrInvalidSize,       // The minimum order size is ...
+	-500: ErrAuthFailed,        // Key not found
+}
+
+// decodeBitFlyerError converts an error response of bitFlyer API to an APIError
+// bitFlyer reports errors as {"status": -200, "error_message": "Insufficient funds"}
+func decodeBitFlyerError(statusCode int, body []byte) error {
+	var errResp model.BitFlyerErrorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Status == 0 {
+		return &APIError{Exchange: "bitFlyer", StatusCode: statusCode, Message: string(body), Kind: classifyAPIError(statusCode, "")}
+	}
+
+	kind, ok := bitFlyerErrorKinds[errResp.Status]
+	if !ok {
+		kind = classifyAPIError(statusCode, errResp.ErrorMessage)
+	}
+	return &APIError{
+		Exchange:   "bitFlyer",
+		StatusCode: statusCode,
+		Code:       strconv.Itoa(errResp.Status),
+		Message:    errResp.ErrorMessage,
+		Kind:       kind,
+	}
+}
+
+// classifyAPIError classifies an error without a known error code by its HTTP status and message.
+// The message is empty when the body could not be decoded, so that error pages are not misread.
+func classifyAPIError(statusCode int, message string) error {
+	message = strings.ToLower(message)
+	switch {
+	case statusCode == http.StatusTooManyRequests || strings.Contains(message, "over api limit") || strings.Contains(message, "too many"):
+		return ErrRateLimited
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden ||
+		strings.Contains(message, "signature") || strings.Contains(message, "api key") || strings.Contains(message, "permission"):
+		return ErrAuthFailed
+	case strings.Contains(message, "maintenance"):
+		return ErrMaintenance
+	case strings.Contains(message, "insufficient"):
+		return ErrInsufficientFunds
+	case strings.Contains(message, "price"):
+		return ErrInvalidPrice
+	case strings.Contains(message, "size") || strings.Contains(message, "amount"):
+		return ErrInvalidSize
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// defaultExchangeRouteKey is the route key of pairs without their own route
+const defaultExchangeRouteKey = "*"
+
+// SymbolRouter is implemented by clients that dispatch each trading pair to a different exchange
+type SymbolRouter interface {
+	// ClientFor returns the client of the exchange the symbol is traded on
+	ClientFor(symbol model.Symbol) CryptoExchangeClient
+}
+
+// ForSymbol returns the client of the exchange the symbol is traded on.
+// Callers that need the balance or name of that exchange use it instead of the router itself.
+func ForSymbol(c CryptoExchangeClient, symbol model.Symbol) CryptoExchangeClient {
+	if router, ok := c.(SymbolRouter); ok {
+		return router.ClientFor(symbol)
+	}
+	return c
+}
+
+// ExchangeRouter implements CryptoExchangeClient by dispatching every call to the exchange
+// configured for its trading pair. Name is that of the default exchange; GetBalance and
+// GetBalances cover the accounts on every exchange.
+type ExchangeRouter struct {
+	defaultClient CryptoExchangeClient
+	routes        map[string]CryptoExchangeClient // Keyed by product code (e.g. "ETH_JPY")
+}
+
+// NewExchangeRouter creates a router from exchange clients keyed by name and routes from
+// ParseExchangeRoutes. Pairs without a route use the "*" route, or defaultExchange if there is none.
+func NewExchangeRouter(clients map[string]CryptoExchangeClient, routes map[string]string, defaultExchange string) (*ExchangeRouter, error) {
+	if name, ok := routes[defaultExchangeRouteKey]; ok {
+		defaultExchange = name
+	}
+
+	defaultClient, ok := clients[defaultExchange]
+	if !ok {
+		return nil, fmt.Errorf("unknown exchange %q for pairs without a route", defaultExchange)
+	}
+
+	router := &ExchangeRouter{
+		defaultClient: defaultClient,
+		routes:        make(map[string]CryptoExchangeClient),
+	}
+	for productCode, name := range routes {
+		if productCode == defaultExchangeRouteKey {
+			continue
+		}
+		routed, ok := clients[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown exchange %q for %s", name, productCode)
+		}
+		router.routes[productCode] = routed
+	}
+
+	return router, nil
+}
+
+// ParseExchangeRoutes parses a comma-separated list of exchange routes keyed by product code,
+// e.g. "*=bitflyer,ETH_JPY=gmocoin"
+func ParseExchangeRoutes(spec string) (map[string]string, error) {
+	routes := make(map[string]string)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if !found || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid exchange route %q: expected PAIR=EXCHANGE", entry)
+		}
+
+		// Accept both BTC_JPY and BTC/JPY
+		if key != defaultExchangeRouteKey {
+			key = model.Symbol(strings.ToUpper(key)).ProductCode()
+		}
+		routes[key] = strings.ToLower(value)
+	}
+
+	return routes, nil
+}
+
+// ClientFor returns the client of the exchange the symbol is routed to
+func (r *ExchangeRouter) ClientFor(symbol model.Symbol) CryptoExchangeClient {
+	return r.clientForProduct(symbol.ProductCode())
+}
+
+// clientForProduct returns the client of the exchange the product code is routed to
+func (r *ExchangeRouter) clientForProduct(productCode string) CryptoExchangeClient {
+	if routed, ok := r.routes[productCode]; ok {
+		return routed
+	}
+	return r.defaultClient
+}
+
+// Name returns the name of the default exchange
+func (r *ExchangeRouter) Name() string {
+	return r.defaultClient.Name()
+}
+
+// GetTicker retrieves the ticker from the exchange of the symbol
+func (r *ExchangeRouter) GetTicker(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+	return r.ClientFor(symbol).GetTicker(ctx, symbol)
+}
+
+// GetProducts retrieves the products of every exchange, keeping each product only from the exchange it is routed to
+func (r *ExchangeRouter) GetProducts(ctx context.Context) ([]model.Product, error) {
+	products := []model.Product{}
+	for _, exchangeClient := range r.clients() {
+		exchangeProducts, err := exchangeClient.GetProducts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get products from %s: %w", exchangeClient.Name(), err)
+		}
+		for _, product := range exchangeProducts {
+			if r.ClientFor(product.Symbol) == exchangeClient {
+				products = append(products, product)
+			}
+		}
+	}
+
+	return products, nil
+}
+
+// RateLimitBudgets returns the request budgets of every exchange client that schedules its requests
+func (r *ExchangeRouter) RateLimitBudgets() []RateLimitBudget {
+	budgets := []RateLimitBudget{}
+	for _, exchangeClient := range r.clients() {
+		if reporter, ok := exchangeClient.(RateLimitReporter); ok {
+			budgets = append(budgets, reporter.RateLimitBudgets()...)
+		}
+	}
+	return budgets
+}
+
+// clients returns every distinct exchange client, starting with the default one
+func (r *ExchangeRouter) clients() []CryptoExchangeClient {
+	clients := []CryptoExchangeClient{r.defaultClient}
+	for _, routed := range r.routes {
+		if !containsClient(clients, routed) {
+			clients = append(clients, routed)
+		}
+	}
+	return clients
+}
+
+// containsClient reports whether clients already holds exchangeClient
+func containsClient(clients []CryptoExchangeClient, exchangeClient CryptoExchangeClient) bool {
+	for _, c := range clients {
+		if c == exchangeClient {
+			return true
+		}
+	}
+	return false
+}
+
+// GetBalance retrieves the available JPY balance summed over every exchange
+func (r *ExchangeRouter) GetBalance(ctx context.Context) (float64, error) {
+	total := 0.0
+	for _, exchangeClient := range r.clients() {
+		balance, err := exchangeClient.GetBalance(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get balance from %s: %w", exchangeClient.Name(), err)
+		}
+		total += balance
+	}
+	return total, nil
+}
+
+// GetBalances retrieves the balances held on every exchange, each tagged with the exchange holding it.
+// A currency held on several exchanges is listed once per exchange.
+func (r *ExchangeRouter) GetBalances(ctx context.Context) ([]model.Balance, error) {
+	balances := []model.Balance{}
+	for _, exchangeClient := range r.clients() {
+		exchangeBalances, err := exchangeClient.GetBalances(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get balances from %s: %w", exchangeClient.Name(), err)
+		}
+		for _, balance := range exchangeBalances {
+			balance.Exchange = exchangeClient.Name()
+			balances = append(balances, balance)
+		}
+	}
+	return balances, nil
+}
+
+// SendOrder sends the order to the exchange of its symbol
+func (r *ExchangeRouter) SendOrder(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+	return r.ClientFor(req.Symbol).SendOrder(ctx, req)
+}
+
+// GetChildOrders retrieves orders from the exchange of the queried product
+func (r *ExchangeRouter) GetChildOrders(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+	return r.clientForProduct(query.ProductCode).GetChildOrders(ctx, query)
+}
+
+// SendParentOrder sends the special order to the exchange of its first leg
+func (r *ExchangeRouter) SendParentOrder(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error) {
+	productCode := ""
+	if len(req.Legs) > 0 {
+		productCode = req.Legs[0].ProductCode
+	}
+	return r.clientForProduct(productCode).SendParentOrder(ctx, req)
+}
+
+// GetParentOrders retrieves special orders from the exchange of the queried product
+func (r *ExchangeRouter) GetParentOrders(ctx context.Context, query *model.ParentOrderQuery) ([]model.ParentOrder, error) {
+	return r.clientForProduct(query.ProductCode).GetParentOrders(ctx, query)
+}
+
+// CancelOrder cancels the order on the exchange of the product
+func (r *ExchangeRouter) CancelOrder(ctx context.Context, productCode, orderID string) error {
+	return r.clientForProduct(productCode).CancelOrder(ctx, productCode, orderID)
+}
+
+// CancelAllOrders cancels every open order of the product on its exchange
+func (r *ExchangeRouter) CancelAllOrders(ctx context.Context, productCode string) error {
+	return r.clientForProduct(productCode).CancelAllOrders(ctx, productCode)
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+func TestParseExchangeRoutes(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{name: "empty", spec: "", expected: map[string]string{}},
+		{
+			name:     "default and pairs",
+			spec:     "*=bitflyer, ETH/JPY=GMOCoin ,xrp_jpy=gmocoin",
+			expected: map[string]string{"*": "bitflyer", "ETH_JPY": "gmocoin", "XRP_JPY": "gmocoin"},
+		},
+		{name: "missing exchange", spec: "ETH_JPY=", wantErr: true},
+		{name: "missing separator", spec: "ETH_JPY", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routes, err := ParseExchangeRoutes(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %v", routes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(routes) != len(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, routes)
+			}
+			for key, value := range tt.expected {
+				if routes[key] != value {
+					t.Errorf("expected %s=%s, got %s", key, value, routes[key])
+				}
+			}
+		})
+	}
+}
+
+func TestExchangeRouter_RoutesByPair(t *testing.T) {
+	var calls []string
+	newMock := func(name string) *MockBitFlyerClient {
+		return &MockBitFlyerClient{
+			NameFunc: func() string { return name },
+			SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+				calls = append(calls, name+":SendOrder:"+string(req.Symbol))
+				return &model.OrderResponse{OrderID: name}, nil
+			},
+			CancelOrderFunc: func(ctx context.Context, productCode, orderID string) error {
+				calls = append(calls, name+":CancelOrder:"+productCode)
+				return nil
+			},
+		}
+	}
+	bitflyer := newMock("bitflyer")
+	gmocoin := newMock("gmocoin")
+
+	router, err := NewExchangeRouter(
+		map[string]CryptoExchangeClient{"bitflyer": bitflyer, "gmocoin": gmocoin},
+		map[string]string{"ETH_JPY": "gmocoin"},
+		"bitflyer",
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, _ = router.SendOrder(context.Background(), &model.OrderRequest{Symbol: "ETH/JPY"})
+	_, _ = router.SendOrder(context.Background(), &model.OrderRequest{Symbol: "BTC/JPY"})
+	_ = router.CancelOrder(context.Background(), "ETH_JPY", "1")
+
+	expected := []string{"gmocoin:SendOrder:ETH/JPY", "bitflyer:SendOrder:BTC/JPY", "gmocoin:CancelOrder:ETH_JPY"}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("expected call %d to be %s, got %s", i, expected[i], calls[i])
+		}
+	}
+
+	if ForSymbol(router, "ETH/JPY").Name() != "gmocoin" || ForSymbol(router, "BTC/JPY").Name() != "bitflyer" {
+		t.Error("expected ForSymbol to return the routed exchange")
+	}
+	if ForSymbol(gmocoin, "BTC/JPY") != CryptoExchangeClient(gmocoin) {
+		t.Error("expected ForSymbol to return a plain client as is")
+	}
+}
+
+func TestExchangeRouter_GetProducts(t *testing.T) {
+	bitflyer := &MockBitFlyerClient{}
+	gmocoin := &MockBitFlyerClient{
+		NameFunc: func() string { return "gmocoin" },
+		GetProductsFunc: func(ctx context.Context) ([]model.Product, error) {
+			return []model.Product{
+				{Symbol: "BTC/JPY", Exchange: "gmocoin", TickSize: 1, LotStep: 0.0001, MinSize: 0.0001},
+				{Symbol: "ETH/JPY", Exchange: "gmocoin", TickSize: 1, LotStep: 0.0001, MinSize: 0.01},
+			}, nil
+		},
+	}
+
+	router, err := NewExchangeRouter(
+		map[string]CryptoExchangeClient{"bitflyer": bitflyer, "gmocoin": gmocoin},
+		map[string]string{"ETH_JPY": "gmocoin"},
+		"bitflyer",
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	products, err := router.GetProducts(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Each pair comes from the exchange it is routed to
+	exchanges := map[model.Symbol]string{}
+	for _, product := range products {
+		exchanges[product.Symbol] = product.Exchange
+	}
+	if len(products) != 2 || exchanges["BTC/JPY"] != "bitflyer" || exchanges["ETH/JPY"] != "gmocoin" {
+		t.Errorf("unexpected products: %+v", products)
+	}
+}
+
+func TestExchangeRouter_GetBalances(t *testing.T) {
+	bitflyer := &MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return []model.Balance{
+				{Currency: "JPY", Amount: 100000, Available: 80000},
+				{Currency: "BTC", Amount: 0.01, Available: 0.01},
+			}, nil
+		},
+		GetBalanceFunc: func(ctx context.Context) (float64, error) {
+			return 80000, nil
+		},
+	}
+	gmocoin := &MockBitFlyerClient{
+		NameFunc: func() string { return "gmocoin" },
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return []model.Balance{
+				{Currency: "JPY", Amount: 50000, Available: 50000},
+				{Currency: "ETH", Amount: 0.5, Available: 0.3},
+			}, nil
+		},
+		GetBalanceFunc: func(ctx context.Context) (float64, error) {
+			return 50000, nil
+		},
+	}
+
+	router, err := NewExchangeRouter(
+		map[string]CryptoExchangeClient{"bitflyer": bitflyer, "gmocoin": gmocoin},
+		map[string]string{"ETH_JPY": "gmocoin"},
+		"bitflyer",
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Every exchange is listed, each balance tagged with the exchange holding it
+	balances, err := router.GetBalances(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []model.Balance{
+		{Currency: "JPY", Exchange: "bitflyer", Amount: 100000, Available: 80000},
+		{Currency: "BTC", Exchange: "bitflyer", Amount: 0.01, Available: 0.01},
+		{Currency: "JPY", Exchange: "gmocoin", Amount: 50000, Available: 50000},
+		{Currency: "ETH", Exchange: "gmocoin", Amount: 0.5, Available: 0.3},
+	}
+	if len(balances) != len(expected) {
+		t.Fatalf("expected balances %+v, got %+v", expected, balances)
+	}
+	for i := range expected {
+		if balances[i] != expected[i] {
+			t.Errorf("expected balance %d to be %+v, got %+v", i, expected[i], balances[i])
+		}
+	}
+
+	// The JPY balances of every exchange are summed
+	balance, err := router.GetBalance(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if balance != 130000 {
+		t.Errorf("expected 130000, got %f", balance)
+	}
+
+	gmocoin.GetBalancesFunc = func(ctx context.Context) ([]model.Balance, error) {
+		return nil, errors.New("connection refused")
+	}
+	if _, err := router.GetBalances(context.Background()); err == nil {
+		t.Error("expected an error when an exchange cannot be reached")
+	}
+}
+
+func TestNewExchangeRouter_UnknownExchange(t *testing.T) {
+	clients := map[string]CryptoExchangeClient{"bitflyer": &MockBitFlyerClient{}}
+
+	if _, err := NewExchangeRouter(clients, map[string]string{"ETH_JPY": "coincheck"}, "bitflyer"); err == nil {
+		t.Error("expected error for a route to an unknown exchange, got nil")
+	}
+	if _, err := NewExchangeRouter(clients, map[string]string{"*": "gmocoin"}, "bitflyer"); err == nil {
+		t.Error("expected error for an unknown default exchange, got nil")
+	}
+}
@@ -0,0 +1,517 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// GMOCoinClient implements CryptoExchangeClient for GMO Coin spot trading
+// GMO Coin has no special orders, so SendParentOrder and GetParentOrders return ErrNotSupported.
+type GMOCoinClient struct {
+	baseURL   string
+	apiKey    string
+	apiSecret string
+	client    *http.Client
+}
+
+// NewGMOCoinClient creates a new GMO Coin API client
+func NewGMOCoinClient(baseURL string) *GMOCoinClient {
+	return &GMOCoinClient{
+		baseURL: baseURL,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// NewGMOCoinClientWithAuth creates a new GMO Coin API client with authentication
+func NewGMOCoinClientWithAuth(baseURL, apiKey, apiSecret string) *GMOCoinClient {
+	return &GMOCoinClient{
+		baseURL:   baseURL,
+		apiKey:    apiKey,
+		apiSecret: apiSecret,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+const (
+	// gmoCoinActiveOrdersMaxCount is the maximum page size of the activeOrders API
+	gmoCoinActiveOrdersMaxCount = 100
+	// gmoCoinQuote is the quote currency of every GMO Coin spot symbol
+	gmoCoinQuote = "JPY"
+)
+
+// gmoCoinErrorKinds classifies the error codes of GMO Coin API
+var gmoCoinErrorKinds = map[string]error{
+	"ERR-201":  ErrInsufficientFunds, // Insufficient funds
+	"ERR-208":  ErrInsufficientFunds, // Exceeds the available amount
+	"ERR-5003": ErrRateLimited,       // Requests are too many
+	"ERR-5010": ErrAuthFailed,        // Invalid signature
+	"ERR-5011": ErrAuthFailed,        // Invalid API key
+	"ERR-5201": ErrMaintenance,       // Regular maintenance
+	"ERR-5202": ErrMaintenance,       // Emergency maintenance
+}
+
+// gmoCoinTimeInForces maps time in force to the GMO Coin equivalent
+var gmoCoinTimeInForces = map[model.TimeInForce]string{
+	model.TimeInForceGTC: "FAS", // Fill And Store
+	model.TimeInForceIOC: "FAK", // Fill And Kill
+	model.TimeInForceFOK: "FOK", // Fill Or Kill
+}
+
+// Name returns the identifier of GMO Coin
+func (c *GMOCoinClient) Name() string {
+	return "gmocoin"
+}
+
+// GetTicker retrieves ticker information for a specific symbol
+func (c *GMOCoinClient) GetTicker(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+	gmoSymbol, err := gmoCoinSymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("symbol", gmoSymbol)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/public/v1/ticker?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var tickers []model.GMOCoinTicker
+	if err := c.do(req, &tickers); err != nil {
+		return nil, err
+	}
+
+	for _, ticker := range tickers {
+		if ticker.Symbol == gmoSymbol {
+			return toGMOCoinTicker(symbol, &ticker), nil
+		}
+	}
+
+	return nil, fmt.Errorf("ticker for %s not found in GMO Coin response", symbol)
+}
+
+// GetProducts retrieves the trading rules of every spot symbol from GMO Coin symbols API
+func (c *GMOCoinClient) GetProducts(ctx context.Context) ([]model.Product, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/public/v1/symbols", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var symbols []model.GMOCoinSymbol
+	if err := c.do(req, &symbols); err != nil {
+		return nil, err
+	}
+
+	products := []model.Product{}
+	for _, symbol := range symbols {
+		// Leverage symbols such as BTC_JPY are not traded by this client
+		if strings.Contains(symbol.Symbol, "_") {
+			continue
+		}
+		products = append(products, model.Product{
+			Symbol:         model.Symbol(symbol.Symbol + "/" + gmoCoinQuote),
+			Exchange:       c.Name(),
+			TickSize:       symbol.TickSize,
+			LotStep:        symbol.SizeStep,
+			MinSize:        symbol.MinOrderSize,
+			PricePrecision: model.DecimalPlaces(symbol.TickSize),
+		})
+	}
+
+	return products, nil
+}
+
+// GetBalance retrieves JPY balance from GMO Coin API
+func (c *GMOCoinClient) GetBalance(ctx context.Context) (float64, error) {
+	balances, err := c.GetBalances(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Find JPY balance
+	for _, balance := range balances {
+		if balance.Currency == "JPY" {
+			return balance.Available, nil
+		}
+	}
+
+	return 0, fmt.Errorf("JPY balance not found")
+}
+
+// GetBalances retrieves the balance of every currency from GMO Coin API
+func (c *GMOCoinClient) GetBalances(ctx context.Context) ([]model.Balance, error) {
+	req, err := c.createAuthenticatedRequest(ctx, http.MethodGet, "/v1/account/assets", nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var assets []model.GMOCoinAsset
+	if err := c.do(req, &assets); err != nil {
+		return nil, err
+	}
+
+	balances := make([]model.Balance, 0, len(assets))
+	for _, asset := range assets {
+		balances = append(balances, model.Balance{
+			Currency:  asset.Symbol,
+			Exchange:  c.Name(),
+			Amount:    asset.Amount,
+			Available: asset.Available,
+		})
+	}
+
+	return balances, nil
+}
+
+// SendOrder sends an order to GMO Coin API
+// GMO Coin spot orders have no client order IDs, so ClientOrderID is only echoed back in the response
+func (c *GMOCoinClient) SendOrder(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+	orderReq, err := toGMOCoinOrderRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := json.Marshal(orderReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal order request: %w", err)
+	}
+
+	httpReq, err := c.createAuthenticatedRequest(ctx, http.MethodPost, "/v1/order", nil, string(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// The order API returns the new order ID as the data string
+	var orderID string
+	if err := c.do(httpReq, &orderID); err != nil {
+		return nil, err
+	}
+
+	return &model.OrderResponse{
+		OrderID:       orderID,
+		ClientOrderID: req.ClientOrderID,
+	}, nil
+}
+
+// GetChildOrders retrieves orders from GMO Coin API
+// A query with OrderID looks up that order in any state; otherwise only
+// active orders are returned because GMO Coin has no listing of recent closed orders.
+func (c *GMOCoinClient) GetChildOrders(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+	if query.ParentOrderID != "" {
+		return nil, fmt.Errorf("child orders of parent orders are %w", ErrNotSupported)
+	}
+
+	params := url.Values{}
+	path := "/v1/activeOrders"
+	if query.OrderID != "" {
+		path = "/v1/orders"
+		params.Set("orderId", query.OrderID)
+	} else {
+		gmoSymbol, err := gmoCoinSymbol(model.SymbolFromProductCode(query.ProductCode))
+		if err != nil {
+			return nil, err
+		}
+		params.Set("symbol", gmoSymbol)
+
+		count := gmoCoinActiveOrdersMaxCount
+		if query.Count > 0 && query.Count < count {
+			count = query.Count
+		}
+		params.Set("count", strconv.Itoa(count))
+	}
+
+	req, err := c.createAuthenticatedRequest(ctx, http.MethodGet, path, params, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// activeOrders returns an empty object instead of an empty list when nothing is open
+	var list model.GMOCoinOrderList
+	if err := c.do(req, &list); err != nil {
+		return nil, err
+	}
+
+	orders := make([]model.ChildOrder, 0, len(list.List))
+	for _, order := range list.List {
+		childOrder := toGMOCoinChildOrder(&order)
+		if query.State != "" && childOrder.State != query.State {
+			continue
+		}
+		orders = append(orders, childOrder)
+	}
+
+	return orders, nil
+}
+
+// SendParentOrder is not supported because GMO Coin spot trading has no special orders
+func (c *GMOCoinClient) SendParentOrder(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error) {
+	return nil, fmt.Errorf("special orders are %w", ErrNotSupported)
+}
+
+// GetParentOrders is not supported because GMO Coin spot trading has no special orders
+func (c *GMOCoinClient) GetParentOrders(ctx context.Context, query *model.ParentOrderQuery) ([]model.ParentOrder, error) {
+	return nil, fmt.Errorf("special orders are %w", ErrNotSupported)
+}
+
+// CancelOrder cancels an order on GMO Coin by its order ID
+func (c *GMOCoinClient) CancelOrder(ctx context.Context, productCode, orderID string) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid GMO Coin order ID: %s", orderID)
+	}
+
+	return c.postWithoutResponse(ctx, "/v1/cancelOrder", &model.GMOCoinCancelOrderRequest{OrderID: id})
+}
+
+// CancelAllOrders cancels all open orders for the product on GMO Coin
+func (c *GMOCoinClient) CancelAllOrders(ctx context.Context, productCode string) error {
+	gmoSymbol, err := gmoCoinSymbol(model.SymbolFromProductCode(productCode))
+	if err != nil {
+		return err
+	}
+
+	return c.postWithoutResponse(ctx, "/v1/cancelBulkOrder", &model.GMOCoinCancelBulkOrderRequest{
+		Symbols: []string{gmoSymbol},
+	})
+}
+
+// postWithoutResponse sends an authenticated POST request whose response data is not needed
+func (c *GMOCoinClient) postWithoutResponse(ctx context.Context, path string, payload any) error {
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := c.createAuthenticatedRequest(ctx, http.MethodPost, path, nil, string(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	return c.do(req, nil)
+}
+
+// do sends the request and decodes the data of the response envelope into out
+// GMO Coin reports most errors with HTTP 200 and a non-zero status, so both are checked
+func (c *GMOCoinClient) do(req *http.Request, out any) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call GMO Coin API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read GMO Coin response: %w", err)
+	}
+
+	var envelope model.GMOCoinResponse
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return &APIError{Exchange: "GMO Coin", StatusCode: resp.StatusCode, Message: string(respBody), Kind: classifyAPIError(resp.StatusCode, "")}
+		}
+		return fmt.Errorf("failed to decode GMO Coin response: %w", err)
+	}
+
+	if envelope.Status != 0 || resp.StatusCode != http.StatusOK {
+		return decodeGMOCoinError(resp.StatusCode, &envelope)
+	}
+
+	if out == nil || len(envelope.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("failed to decode GMO Coin response data: %w", err)
+	}
+
+	return nil
+}
+
+// decodeGMOCoinError converts the error messages of a GMO Coin response to an APIError
+// Error codes without a known kind are classified by their message
+func decodeGMOCoinError(statusCode int, envelope *model.GMOCoinResponse) error {
+	if len(envelope.Messages) == 0 {
+		return &APIError{
+			Exchange:   "GMO Coin",
+			StatusCode: statusCode,
+			Message:    fmt.Sprintf("response status %d", envelope.Status),
+			Kind:       classifyAPIError(statusCode, ""),
+		}
+	}
+
+	first := envelope.Messages[0]
+	details := []string{first.MessageString}
+	for _, message := range envelope.Messages[1:] {
+		details = append(details, message.MessageCode+": "+message.MessageString)
+	}
+
+	kind, ok := gmoCoinErrorKinds[first.MessageCode]
+	if !ok {
+		kind = classifyAPIError(statusCode, first.MessageString)
+	}
+	return &APIError{
+		Exchange:   "GMO Coin",
+		StatusCode: statusCode,
+		Code:       first.MessageCode,
+		Message:    strings.Join(details, "; "),
+		Kind:       kind,
+	}
+}
+
+// createAuthenticatedRequest creates an HTTP request to the private API with GMO Coin authentication headers
+// The request is bound to ctx so that cancellation and deadlines of the caller abort the call
+func (c *GMOCoinClient) createAuthenticatedRequest(ctx context.Context, method, path string, params url.Values, body string) (*http.Request, error) {
+	url := c.baseURL + "/private" + path
+	if len(params) > 0 {
+		url += "?" + params.Encode()
+	}
+	// GMO Coin expects the timestamp in milliseconds
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	// Create request
+	var req *http.Request
+	var err error
+	if body != "" {
+		req, err = http.NewRequestWithContext(ctx, method, url, bytes.NewBufferString(body))
+	} else {
+		req, err = http.NewRequestWithContext(ctx, method, url, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Add authentication headers if credentials are provided
+	if c.apiKey != "" && c.apiSecret != "" {
+		// Create signature: HMAC-SHA256(timestamp + method + path + body, secret)
+		// Unlike bitFlyer, the signed path excludes the /private prefix and the query string
+		text := timestamp + method + path + body
+		signature := c.createSignature(text)
+
+		req.Header.Set("API-KEY", c.apiKey)
+		req.Header.Set("API-TIMESTAMP", timestamp)
+		req.Header.Set("API-SIGN", signature)
+	}
+
+	return req, nil
+}
+
+// createSignature creates HMAC-SHA256 signature for GMO Coin API authentication
+func (c *GMOCoinClient) createSignature(text string) string {
+	mac := hmac.New(sha256.New, []byte(c.apiSecret))
+	mac.Write([]byte(text))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// gmoCoinSymbol converts a symbol to a GMO Coin spot symbol (BTC/JPY -> BTC)
+// Symbols with an underscore such as BTC_JPY are leverage products on GMO Coin
+func gmoCoinSymbol(symbol model.Symbol) (string, error) {
+	if symbol.Base() == "" || symbol.Quote() != gmoCoinQuote {
+		return "", fmt.Errorf("unsupported symbol on GMO Coin: %s", symbol)
+	}
+	return symbol.Base(), nil
+}
+
+// toGMOCoinOrderRequest converts an order to the GMO Coin order request format
+func toGMOCoinOrderRequest(req *model.OrderRequest) (*model.GMOCoinOrderRequest, error) {
+	gmoSymbol, err := gmoCoinSymbol(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	orderReq := &model.GMOCoinOrderRequest{
+		Symbol:        gmoSymbol,
+		Side:          string(req.Side),
+		ExecutionType: string(req.Type),
+		Size:          req.Size,
+	}
+	// Price and time in force must not be sent for MARKET orders
+	if req.Type != model.OrderTypeMarket {
+		orderReq.Price = req.Price
+		if req.TimeInForce != "" {
+			timeInForce, ok := gmoCoinTimeInForces[req.TimeInForce]
+			if !ok {
+				return nil, fmt.Errorf("unsupported time in force on GMO Coin: %s", req.TimeInForce)
+			}
+			orderReq.TimeInForce = timeInForce
+		}
+	}
+	return orderReq, nil
+}
+
+// toGMOCoinTicker converts a GMO Coin ticker to the exchange-agnostic model
+// GMO Coin does not report the size available at the best prices
+func toGMOCoinTicker(symbol model.Symbol, ticker *model.GMOCoinTicker) *model.Ticker {
+	timestamp, err := time.Parse(time.RFC3339Nano, ticker.Timestamp)
+	if err != nil {
+		timestamp = time.Now()
+	}
+
+	return &model.Ticker{
+		Symbol:    symbol,
+		Last:      ticker.Last,
+		Bid:       ticker.Bid,
+		Ask:       ticker.Ask,
+		Volume:    ticker.Volume,
+		Timestamp: timestamp,
+	}
+}
+
+// toGMOCoinChildOrder converts a GMO Coin order to the exchange-agnostic model
+func toGMOCoinChildOrder(order *model.GMOCoinOrder) model.ChildOrder {
+	state := gmoCoinOrderState(order.Status)
+	// A date that cannot be parsed is left zero, so the order is never taken for one just placed
+	orderDate, _ := time.Parse(time.RFC3339Nano, order.Timestamp)
+
+	childOrder := model.ChildOrder{
+		OrderID:      strconv.FormatInt(order.OrderID, 10),
+		ProductCode:  order.Symbol + "_" + gmoCoinQuote,
+		Side:         model.OrderSide(order.Side),
+		Type:         model.OrderType(order.ExecutionType),
+		Price:        order.Price,
+		Size:         order.Size,
+		ExecutedSize: order.ExecutedSize,
+		State:        state,
+		OrderDate:    orderDate,
+	}
+	if state == model.OrderStateActive {
+		childOrder.OutstandingSize = order.Size - order.ExecutedSize
+	} else if state == model.OrderStateCanceled || state == model.OrderStateExpired {
+		childOrder.CancelSize = order.Size - order.ExecutedSize
+	}
+	return childOrder
+}
+
+// gmoCoinOrderState maps a GMO Coin order status to the exchange-agnostic order state
+func gmoCoinOrderState(status string) model.OrderState {
+	switch status {
+	case "EXECUTED":
+		return model.OrderStateCompleted
+	case "CANCELED":
+		return model.OrderStateCanceled
+	case "EXPIRED":
+		return model.OrderStateExpired
+	default:
+		// WAITING, ORDERED, MODIFYING and CANCELLING orders may still execute
+		return model.OrderStateActive
+	}
+}
@@ -0,0 +1,368 @@
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// verifyGMOCoinSignature checks the authentication headers of a private API request
+func verifyGMOCoinSignature(t *testing.T, r *http.Request, body string) {
+	t.Helper()
+
+	if r.Header.Get("API-KEY") != "key" {
+		t.Errorf("expected API-KEY header, got %q", r.Header.Get("API-KEY"))
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(r.Header.Get("API-TIMESTAMP") + r.Method + strings.TrimPrefix(r.URL.Path, "/private") + body))
+	if expected := hex.EncodeToString(mac.Sum(nil)); r.Header.Get("API-SIGN") != expected {
+		t.Errorf("unexpected API-SIGN %q, expected %q", r.Header.Get("API-SIGN"), expected)
+	}
+}
+
+func TestGMOCoinClient_GetTicker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/public/v1/ticker" || r.URL.Query().Get("symbol") != "ETH" {
+			t.Errorf("unexpected request %s", r.URL.String())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"status": 0,
+			"data": [{
+				"ask": "481000",
+				"bid": "480000",
+				"high": "490000",
+				"last": "480500",
+				"low": "470000",
+				"symbol": "ETH",
+				"timestamp": "2018-03-30T12:34:56.789Z",
+				"volume": "194.8484"
+			}],
+			"responsetime": "2018-03-30T12:34:56.789Z"
+		}`))
+	}))
+	defer server.Close()
+
+	c := NewGMOCoinClient(server.URL)
+
+	ticker, err := c.GetTicker(context.Background(), "ETH/JPY")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := model.Ticker{
+		Symbol:    "ETH/JPY",
+		Last:      480500,
+		Bid:       480000,
+		Ask:       481000,
+		Volume:    194.8484,
+		Timestamp: time.Date(2018, 3, 30, 12, 34, 56, 789000000, time.UTC),
+	}
+	if *ticker != expected {
+		t.Errorf("expected %+v, got %+v", expected, *ticker)
+	}
+}
+
+func TestGMOCoinClient_GetProducts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/public/v1/symbols" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"status": 0,
+			"data": [
+				{"symbol": "BTC", "minOrderSize": "0.0001", "maxOrderSize": "5", "sizeStep": "0.0001", "tickSize": "1", "takerFee": "0.0005", "makerFee": "-0.0001"},
+				{"symbol": "XRP", "minOrderSize": "1", "maxOrderSize": "50000", "sizeStep": "1", "tickSize": "0.001", "takerFee": "0.0005", "makerFee": "-0.0001"},
+				{"symbol": "BTC_JPY", "minOrderSize": "0.01", "maxOrderSize": "5", "sizeStep": "0.01", "tickSize": "1", "takerFee": "0", "makerFee": "0"}
+			],
+			"responsetime": "2022-12-15T19:22:23.792Z"
+		}`))
+	}))
+	defer server.Close()
+
+	c := NewGMOCoinClient(server.URL)
+
+	products, err := c.GetProducts(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Leverage symbols are skipped
+	expected := []model.Product{
+		{Symbol: "BTC/JPY", Exchange: "gmocoin", TickSize: 1, LotStep: 0.0001, MinSize: 0.0001, PricePrecision: 0},
+		{Symbol: "XRP/JPY", Exchange: "gmocoin", TickSize: 0.001, LotStep: 1, MinSize: 1, PricePrecision: 3},
+	}
+	if len(products) != len(expected) {
+		t.Fatalf("expected %+v, got %+v", expected, products)
+	}
+	for i := range expected {
+		if products[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], products[i])
+		}
+	}
+}
+
+func TestGMOCoinClient_GetTicker_UnsupportedSymbol(t *testing.T) {
+	c := NewGMOCoinClient("http://127.0.0.1:0")
+
+	if _, err := c.GetTicker(context.Background(), "BTC/USD"); err == nil {
+		t.Error("expected error for a non-JPY symbol, got nil")
+	}
+}
+
+func TestGMOCoinClient_SendOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *model.OrderRequest
+		expected map[string]any
+	}{
+		{
+			name: "limit order",
+			req:  &model.OrderRequest{Symbol: "BTC/JPY", Side: model.OrderSideSell, Type: model.OrderTypeLimit, TimeInForce: model.TimeInForceGTC, Price: 15000000, Size: 0.001, ClientOrderID: "client-1"},
+			expected: map[string]any{
+				"symbol": "BTC", "side": "SELL", "executionType": "LIMIT",
+				"timeInForce": "FAS", "price": "15000000", "size": "0.001",
+			},
+		},
+		{
+			name: "market order without price",
+			req:  &model.OrderRequest{Symbol: "ETH/JPY", Side: model.OrderSideBuy, Type: model.OrderTypeMarket, TimeInForce: model.TimeInForceGTC, Price: 480000, Size: 0.01, ClientOrderID: "client-1"},
+			expected: map[string]any{
+				"symbol": "ETH", "side": "BUY", "executionType": "MARKET", "size": "0.01",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/private/v1/order" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				body, _ := io.ReadAll(r.Body)
+				verifyGMOCoinSignature(t, r, string(body))
+				if err := json.Unmarshal(body, &gotBody); err != nil {
+					t.Errorf("failed to decode request body: %v", err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"status": 0, "data": "637000", "responsetime": "2019-03-19T02:15:06.108Z"}`))
+			}))
+			defer server.Close()
+
+			c := NewGMOCoinClientWithAuth(server.URL, "key", "secret")
+
+			resp, err := c.SendOrder(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if resp.OrderID != "637000" || resp.ClientOrderID != "client-1" {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+			if len(gotBody) != len(tt.expected) {
+				t.Errorf("expected body %v, got %v", tt.expected, gotBody)
+			}
+			for key, value := range tt.expected {
+				if gotBody[key] != value {
+					t.Errorf("expected %s=%v, got %v", key, value, gotBody[key])
+				}
+			}
+		})
+	}
+}
+
+func TestGMOCoinClient_ErrorDecoding(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   string
+	}{
+		{
+			name:       "error status with HTTP 200",
+			statusCode: http.StatusOK,
+			body:       `{"status": 1, "messages": [{"message_code": "ERR-5003", "message_string": "Requests are too many."}], "responsetime": "2019-03-19T02:15:06.108Z"}`,
+			expected:   "GMO Coin API error ERR-5003: Requests are too many.",
+		},
+		{
+			name:       "insufficient funds",
+			statusCode: http.StatusOK,
+			body:       `{"status": 1, "messages": [{"message_code": "ERR-201", "message_string": "Trading margin is insufficient."}], "responsetime": "2019-03-19T02:15:06.108Z"}`,
+			expected:   "insufficient balance: GMO Coin API error ERR-201",
+		},
+		{
+			name:       "non-JSON error response",
+			statusCode: http.StatusServiceUnavailable,
+			body:       `Service Unavailable`,
+			expected:   "GMO Coin API returned status 503",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			c := NewGMOCoinClientWithAuth(server.URL, "key", "secret")
+
+			_, err := c.SendOrder(context.Background(), &model.OrderRequest{Symbol: "BTC/JPY", Side: model.OrderSideBuy, Type: model.OrderTypeLimit, Price: 14000000, Size: 0.001})
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestGMOCoinClient_GetBalances(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/private/v1/account/assets" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		verifyGMOCoinSignature(t, r, "")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"status": 0,
+			"data": [
+				{"amount": "1024078", "available": "508000", "conversionRate": "1", "symbol": "JPY"},
+				{"amount": "10.24", "available": "4.12", "conversionRate": "14000000", "symbol": "BTC"}
+			],
+			"responsetime": "2019-03-19T02:15:06.055Z"
+		}`))
+	}))
+	defer server.Close()
+
+	c := NewGMOCoinClientWithAuth(server.URL, "key", "secret")
+
+	balances, err := c.GetBalances(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(balances) != 2 {
+		t.Fatalf("expected 2 balances, got %d", len(balances))
+	}
+	if balances[1].Currency != "BTC" || balances[1].Amount != 10.24 || balances[1].Available != 4.12 {
+		t.Errorf("unexpected BTC balance: %+v", balances[1])
+	}
+
+	jpy, err := c.GetBalance(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if jpy != 508000 {
+		t.Errorf("expected available JPY 508000, got %f", jpy)
+	}
+}
+
+func TestGMOCoinClient_GetChildOrders(t *testing.T) {
+	var gotPath, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.RawQuery
+		verifyGMOCoinSignature(t, r, "")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"status": 0,
+			"data": {
+				"list": [
+					{"orderId": 223456789, "rootOrderId": 223456789, "symbol": "BTC", "side": "BUY", "orderType": "NORMAL", "executionType": "LIMIT", "settleType": "OPEN", "size": "0.02", "executedSize": "0.005", "price": "14000000", "losscutPrice": "0", "status": "ORDERED", "timeInForce": "FAS", "timestamp": "2020-10-14T20:18:59.343Z"},
+					{"orderId": 223456790, "rootOrderId": 223456790, "symbol": "BTC", "side": "SELL", "orderType": "NORMAL", "executionType": "LIMIT", "settleType": "OPEN", "size": "0.01", "executedSize": "0.01", "price": "15000000", "losscutPrice": "0", "status": "EXECUTED", "timeInForce": "FAS", "timestamp": "2020-10-14T20:19:59.343Z"}
+				]
+			},
+			"responsetime": "2020-10-14T20:20:00.000Z"
+		}`))
+	}))
+	defer server.Close()
+
+	c := NewGMOCoinClientWithAuth(server.URL, "key", "secret")
+
+	orders, err := c.GetChildOrders(context.Background(), &model.ChildOrderQuery{ProductCode: "BTC_JPY", Count: 50})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotPath != "/private/v1/activeOrders" || gotQuery != "count=50&symbol=BTC" {
+		t.Errorf("unexpected request %s?%s", gotPath, gotQuery)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("expected 2 orders, got %d", len(orders))
+	}
+
+	active := orders[0]
+	if active.OrderID != "223456789" || active.ProductCode != "BTC_JPY" || active.State != "ACTIVE" ||
+		active.ExecutedSize != 0.005 || active.OutstandingSize != 0.015 {
+		t.Errorf("unexpected active order: %+v", active)
+	}
+	if orders[1].State != "COMPLETED" || orders[1].Side != "SELL" || orders[1].Price != 15000000 {
+		t.Errorf("unexpected executed order: %+v", orders[1])
+	}
+
+	orders, err = c.GetChildOrders(context.Background(), &model.ChildOrderQuery{ProductCode: "BTC_JPY", OrderID: "223456790"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotPath != "/private/v1/orders" || gotQuery != "orderId=223456790" {
+		t.Errorf("unexpected request %s?%s", gotPath, gotQuery)
+	}
+	if len(orders) != 2 {
+		t.Errorf("expected 2 orders, got %d", len(orders))
+	}
+}
+
+func TestGMOCoinClient_CancelOrder(t *testing.T) {
+	var gotPath, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		verifyGMOCoinSignature(t, r, gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": 0, "responsetime": "2019-03-19T01:07:24.557Z"}`))
+	}))
+	defer server.Close()
+
+	c := NewGMOCoinClientWithAuth(server.URL, "key", "secret")
+
+	if err := c.CancelOrder(context.Background(), "BTC_JPY", "223456789"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotPath != "/private/v1/cancelOrder" || gotBody != `{"orderId":223456789}` {
+		t.Errorf("unexpected request %s %s", gotPath, gotBody)
+	}
+
+	if err := c.CancelAllOrders(context.Background(), "ETH_JPY"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotPath != "/private/v1/cancelBulkOrder" || gotBody != `{"symbols":["ETH"]}` {
+		t.Errorf("unexpected request %s %s", gotPath, gotBody)
+	}
+
+	if err := c.CancelOrder(context.Background(), "BTC_JPY", "JRF-NOT-GMO"); err == nil {
+		t.Error("expected error for a non-numeric order ID, got nil")
+	}
+}
+
+func TestGMOCoinClient_ParentOrdersNotSupported(t *testing.T) {
+	c := NewGMOCoinClientWithAuth("http://127.0.0.1:0", "key", "secret")
+
+	if _, err := c.SendParentOrder(context.Background(), &model.ParentOrderRequest{Method: "IFD"}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+	if _, err := c.GetParentOrders(context.Background(), &model.ParentOrderQuery{ProductCode: "BTC_JPY"}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+}
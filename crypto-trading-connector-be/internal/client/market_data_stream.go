@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// MarketDataStream delivers market data pushed by an exchange in real time.
+// Subscriptions survive reconnections of the underlying connection. Their channels are
+// closed when ctx is done or the stream stops. A subscriber that falls behind loses the
+// oldest updates instead of blocking the stream.
+type MarketDataStream interface {
+	// SubscribeTicker delivers every ticker update of a trading pair
+	SubscribeTicker(ctx context.Context, symbol model.Symbol) (<-chan model.Ticker, error)
+
+	// SubscribeExecutions delivers the trades executed on a trading pair, in batches as pushed by the exchange
+	SubscribeExecutions(ctx context.Context, symbol model.Symbol) (<-chan []model.Execution, error)
+
+	// SubscribeOrderBook delivers the best depth levels on each side of the local order book of a
+	// trading pair every time it changes
+	SubscribeOrderBook(ctx context.Context, symbol model.Symbol, depth int) (<-chan model.OrderBook, error)
+
+	// OrderBook returns the best depth levels on each side of the local order book of a trading pair.
+	// ok is false while the pair has no order book subscription or its first snapshot has not arrived.
+	OrderBook(symbol model.Symbol, depth int) (book *model.OrderBook, ok bool)
+}
+
+// ErrStreamClosed is returned when subscribing to a market data stream that has stopped
+var ErrStreamClosed = errors.New("market data stream is closed")
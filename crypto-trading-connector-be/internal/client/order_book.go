@@ -0,0 +1,95 @@
+package client
+
+import (
+	"sort"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// orderBook is a local copy of the order book of a trading pair, rebuilt from a snapshot
+// and kept up to date with the diffs pushed by the exchange
+type orderBook struct {
+	symbol    model.Symbol
+	midPrice  float64
+	bids      map[float64]float64 // Size by price
+	asks      map[float64]float64
+	synced    bool // A snapshot has been applied since the book was created or reset
+	updatedAt time.Time
+}
+
+func newOrderBook(symbol model.Symbol) *orderBook {
+	return &orderBook{
+		symbol: symbol,
+		bids:   map[float64]float64{},
+		asks:   map[float64]float64{},
+	}
+}
+
+// applySnapshot replaces the whole book
+func (b *orderBook) applySnapshot(board *model.BitFlyerBoard) {
+	b.bids = map[float64]float64{}
+	b.asks = map[float64]float64{}
+	b.synced = true
+	b.applyDiff(board)
+}
+
+// applyDiff updates the changed levels; a size of 0 removes the level.
+// Diffs are ignored until the first snapshot, since the book they apply to is unknown.
+func (b *orderBook) applyDiff(board *model.BitFlyerBoard) bool {
+	if !b.synced {
+		return false
+	}
+
+	applyLevels(b.bids, board.Bids)
+	applyLevels(b.asks, board.Asks)
+	if board.MidPrice > 0 {
+		b.midPrice = board.MidPrice
+	}
+	b.updatedAt = time.Now()
+	return true
+}
+
+// reset discards the book until the next snapshot, e.g. after a reconnection in which diffs were missed
+func (b *orderBook) reset() {
+	b.synced = false
+}
+
+// snapshot returns the best depth levels on each side; depth <= 0 returns every level
+func (b *orderBook) snapshot(depth int) *model.OrderBook {
+	return &model.OrderBook{
+		Symbol:    b.symbol,
+		MidPrice:  b.midPrice,
+		Bids:      sortedLevels(b.bids, depth, true),
+		Asks:      sortedLevels(b.asks, depth, false),
+		Timestamp: b.updatedAt,
+	}
+}
+
+func applyLevels(levels map[float64]float64, changes []model.BitFlyerBoardLevel) {
+	for _, change := range changes {
+		if change.Size == 0 {
+			delete(levels, change.Price)
+		} else {
+			levels[change.Price] = change.Size
+		}
+	}
+}
+
+func sortedLevels(levels map[float64]float64, depth int, descending bool) []model.OrderBookLevel {
+	sorted := make([]model.OrderBookLevel, 0, len(levels))
+	for price, size := range levels {
+		sorted = append(sorted, model.OrderBookLevel{Price: price, Size: size})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if descending {
+			return sorted[i].Price > sorted[j].Price
+		}
+		return sorted[i].Price < sorted[j].Price
+	})
+
+	if depth > 0 && len(sorted) > depth {
+		sorted = sorted[:depth]
+	}
+	return sorted
+}
@@ -0,0 +1,275 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimitCategory is a group of exchange endpoints sharing a request budget
+type RateLimitCategory string
+
+const (
+	RateLimitPublic  RateLimitCategory = "public"
+	RateLimitPrivate RateLimitCategory = "private"
+	RateLimitOrder   RateLimitCategory = "order"
+)
+
+// defaultRateLimitMaxWait is how long a call may be queued before it is rejected
+const defaultRateLimitMaxWait = 5 * time.Second
+
+// defaultRateLimitBackoff blocks a category after HTTP 429 when the response has no reset time
+const defaultRateLimitBackoff = 10 * time.Second
+
+// ErrRateLimited is wrapped by RateLimitError, so callers can test for it with errors.Is
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitError reports a call rejected because it would exceed the request budget of the exchange,
+// either by the client-side limiter or by the exchange itself (HTTP 429)
+type RateLimitError struct {
+	Exchange   string
+	Category   RateLimitCategory
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s on %s %s API: retry after %s", ErrRateLimited, e.Exchange, e.Category, e.RetryAfter.Round(time.Millisecond))
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// RateLimit is the request budget of a category
+type RateLimit struct {
+	Requests int           // Requests allowed per period
+	Period   time.Duration // Length of the exchange's rate limit window
+	Burst    int           // Requests that may be sent back to back
+}
+
+// RateLimitBudget is a snapshot of the request budget of a category
+type RateLimitBudget struct {
+	Exchange     string
+	Category     RateLimitCategory
+	Requests     int           // Configured requests per period
+	Period       time.Duration // Configured period
+	Available    float64       // Requests that can be sent right now without queueing (negative while calls are queued)
+	Remaining    *int          // Remaining requests reported by the exchange, nil until a response carried them
+	ResetAt      *time.Time    // Reset of the exchange's window, nil until a response carried it
+	BlockedUntil *time.Time    // Set while the exchange reported an exhausted budget or returned HTTP 429
+}
+
+// RateLimitReporter is implemented by clients that schedule their requests with a RateLimiter
+type RateLimitReporter interface {
+	RateLimitBudgets() []RateLimitBudget
+}
+
+// RateLimiter schedules requests to an exchange with a token bucket per category.
+// Calls are queued until a token is available, and rejected with a RateLimitError when
+// they would wait longer than maxWait. The rate limit headers of every response adapt the
+// buckets: the remaining budget is paced over the rest of the window, and an exhausted
+// budget blocks the category until the window resets.
+type RateLimiter struct {
+	exchange string
+
+	mu      sync.Mutex
+	maxWait time.Duration
+	buckets map[RateLimitCategory]*rateBucket
+}
+
+// rateBucket is the token bucket of a category together with the state reported by the exchange
+type rateBucket struct {
+	config       RateLimit
+	limiter      *rate.Limiter
+	remaining    *int
+	resetAt      *time.Time
+	blockedUntil time.Time
+}
+
+// NewRateLimiter creates a rate limiter for the categories of an exchange.
+// Categories without a limit are not throttled.
+func NewRateLimiter(exchange string, limits map[RateLimitCategory]RateLimit, maxWait time.Duration) *RateLimiter {
+	l := &RateLimiter{
+		exchange: exchange,
+		maxWait:  maxWait,
+		buckets:  make(map[RateLimitCategory]*rateBucket, len(limits)),
+	}
+	for category, config := range limits {
+		l.buckets[category] = &rateBucket{
+			config:  config,
+			limiter: rate.NewLimiter(config.rate(), config.Burst),
+		}
+	}
+	return l
+}
+
+// rate returns the configured refill rate in requests per second
+func (r RateLimit) rate() rate.Limit {
+	return rate.Limit(float64(r.Requests) / r.Period.Seconds())
+}
+
+// SetMaxWait changes how long a call may be queued before it is rejected
+func (l *RateLimiter) SetMaxWait(maxWait time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxWait = maxWait
+}
+
+// Wait blocks until a request of every category may be sent, or returns a RateLimitError
+// without waiting if that would take longer than maxWait
+func (l *RateLimiter) Wait(ctx context.Context, categories ...RateLimitCategory) error {
+	for _, category := range categories {
+		if err := l.wait(ctx, category); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wait takes a token from the bucket of a category
+func (l *RateLimiter) wait(ctx context.Context, category RateLimitCategory) error {
+	l.mu.Lock()
+	bucket, ok := l.buckets[category]
+	if !ok {
+		l.mu.Unlock()
+		return nil
+	}
+
+	// While the category is blocked, reserve the token for the time the block ends
+	now := time.Now()
+	at := now
+	if bucket.blockedUntil.After(now) {
+		at = bucket.blockedUntil
+	}
+	reservation := bucket.limiter.ReserveN(at, 1)
+	delay := reservation.DelayFrom(now)
+	if !reservation.OK() || delay > l.maxWait {
+		reservation.CancelAt(now)
+		l.mu.Unlock()
+		return &RateLimitError{Exchange: l.exchange, Category: category, RetryAfter: delay}
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		reservation.Cancel()
+		return ctx.Err()
+	}
+}
+
+// Observe adapts the buckets to the rate limit headers of a response. headerCategory is the
+// bucket the X-RateLimit-* headers apply to; X-OrderRequest-RateLimit-* always apply to orders.
+// After HTTP 429 the category of the request is blocked until the window resets.
+func (l *RateLimiter) Observe(resp *http.Response, category, headerCategory RateLimitCategory) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.observeHeaders(headerCategory, resp.Header, "X-RateLimit-", now)
+	l.observeHeaders(RateLimitOrder, resp.Header, "X-OrderRequest-RateLimit-", now)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if bucket, ok := l.buckets[category]; ok {
+			blockedUntil := now.Add(defaultRateLimitBackoff)
+			if bucket.resetAt != nil && bucket.resetAt.After(now) {
+				blockedUntil = *bucket.resetAt
+			}
+			bucket.blockedUntil = blockedUntil
+		}
+	}
+}
+
+// observeHeaders applies the Remaining/Reset/Period headers with a prefix to the bucket of a category
+func (l *RateLimiter) observeHeaders(category RateLimitCategory, header http.Header, prefix string, now time.Time) {
+	bucket, ok := l.buckets[category]
+	if !ok {
+		return
+	}
+
+	remaining, err := strconv.Atoi(header.Get(prefix + "Remaining"))
+	if err != nil {
+		return
+	}
+
+	// Reset is a Unix time; fall back to Period, the seconds until the window resets
+	resetAt := now.Add(bucket.config.Period)
+	if reset, err := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64); err == nil {
+		resetAt = time.Unix(reset, 0)
+	} else if period, err := strconv.Atoi(header.Get(prefix + "Period")); err == nil {
+		resetAt = now.Add(time.Duration(period) * time.Second)
+	}
+
+	bucket.remaining = &remaining
+	bucket.resetAt = &resetAt
+
+	if remaining <= 0 {
+		bucket.blockedUntil = resetAt
+		return
+	}
+
+	// Spread the remaining requests over the rest of the window when that is slower than the configured rate
+	limit := bucket.config.rate()
+	if window := resetAt.Sub(now); window > 0 {
+		if paced := rate.Limit(float64(remaining) / window.Seconds()); paced < limit {
+			limit = paced
+		}
+	}
+	bucket.limiter.SetLimitAt(now, limit)
+}
+
+// rateLimitError returns the error of a call the exchange rejected with HTTP 429
+func (l *RateLimiter) rateLimitError(category RateLimitCategory) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	retryAfter := defaultRateLimitBackoff
+	if bucket, ok := l.buckets[category]; ok {
+		retryAfter = time.Until(bucket.blockedUntil)
+	}
+	return &RateLimitError{Exchange: l.exchange, Category: category, RetryAfter: retryAfter}
+}
+
+// Budgets returns a snapshot of the budget of every category ordered by category
+func (l *RateLimiter) Budgets() []RateLimitBudget {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	budgets := make([]RateLimitBudget, 0, len(l.buckets))
+	for category, bucket := range l.buckets {
+		budget := RateLimitBudget{
+			Exchange:  l.exchange,
+			Category:  category,
+			Requests:  bucket.config.Requests,
+			Period:    bucket.config.Period,
+			Available: bucket.limiter.TokensAt(now),
+			Remaining: bucket.remaining,
+			ResetAt:   bucket.resetAt,
+		}
+		if bucket.blockedUntil.After(now) {
+			blockedUntil := bucket.blockedUntil
+			budget.BlockedUntil = &blockedUntil
+		}
+		budgets = append(budgets, budget)
+	}
+	sort.Slice(budgets, func(i, j int) bool {
+		return budgets[i].Category < budgets[j].Category
+	})
+
+	return budgets
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_QueuesThenRejects(t *testing.T) {
+	// One request every 100ms without burst; calls may wait up to 150ms
+	limiter := NewRateLimiter("test", map[RateLimitCategory]RateLimit{
+		RateLimitPublic: {Requests: 10, Period: time.Second, Burst: 1},
+	}, 150*time.Millisecond)
+
+	start := time.Now()
+	if err := limiter.Wait(context.Background(), RateLimitPublic); err != nil {
+		t.Fatalf("expected first call to pass, got %v", err)
+	}
+	if err := limiter.Wait(context.Background(), RateLimitPublic); err != nil {
+		t.Fatalf("expected second call to be queued, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("expected second call to wait for a token, waited %s", elapsed)
+	}
+
+	// Two calls ahead in the queue push the wait over the limit
+	go func() { _ = limiter.Wait(context.Background(), RateLimitPublic) }()
+	time.Sleep(10 * time.Millisecond)
+	err := limiter.Wait(context.Background(), RateLimitPublic)
+
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected RateLimitError, got %v", err)
+	}
+	if rateLimitErr.Category != RateLimitPublic || rateLimitErr.RetryAfter <= 150*time.Millisecond {
+		t.Errorf("unexpected error: %+v", rateLimitErr)
+	}
+
+	// Categories without a limit are not throttled
+	if err := limiter.Wait(context.Background(), RateLimitOrder); err != nil {
+		t.Errorf("expected unlimited category to pass, got %v", err)
+	}
+}
+
+func TestRateLimiter_ObserveHeaders(t *testing.T) {
+	limiter := NewRateLimiter("test", map[RateLimitCategory]RateLimit{
+		RateLimitPrivate: {Requests: 500, Period: 5 * time.Minute, Burst: 20},
+		RateLimitOrder:   {Requests: 300, Period: 5 * time.Minute, Burst: 10},
+	}, time.Second)
+
+	reset := time.Now().Add(time.Hour).Unix()
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", "120")
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+	header.Set("X-OrderRequest-RateLimit-Remaining", "0")
+	header.Set("X-OrderRequest-RateLimit-Period", "3600")
+	limiter.Observe(&http.Response{StatusCode: http.StatusOK, Header: header}, RateLimitOrder, RateLimitPrivate)
+
+	budgets := limiter.Budgets()
+	if len(budgets) != 2 || budgets[0].Category != RateLimitOrder || budgets[1].Category != RateLimitPrivate {
+		t.Fatalf("unexpected budgets: %+v", budgets)
+	}
+
+	order, private := budgets[0], budgets[1]
+	if private.Remaining == nil || *private.Remaining != 120 || private.ResetAt == nil || private.ResetAt.Unix() != reset {
+		t.Errorf("expected private budget from X-RateLimit headers, got %+v", private)
+	}
+	if private.BlockedUntil != nil {
+		t.Errorf("expected private budget not to be blocked, got %v", private.BlockedUntil)
+	}
+	if order.Remaining == nil || *order.Remaining != 0 || order.BlockedUntil == nil {
+		t.Errorf("expected exhausted order budget to be blocked, got %+v", order)
+	}
+
+	// The order budget stays blocked until the window resets
+	err := limiter.Wait(context.Background(), RateLimitPrivate, RateLimitOrder)
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.Category != RateLimitOrder || rateLimitErr.RetryAfter < 59*time.Minute {
+		t.Errorf("expected order budget to be rejected until reset, got %v", err)
+	}
+}
+
+func TestBitFlyerClient_RateLimited(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Period", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := NewBitFlyerClient(server.URL)
+
+	_, err := c.GetTicker(context.Background(), "BTC/JPY")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected rate limit error for HTTP 429, got %v", err)
+	}
+
+	// The public budget is blocked for the rest of the window without calling bitFlyer
+	_, err = c.GetTicker(context.Background(), "BTC/JPY")
+	if !errors.Is(err, ErrRateLimited) || calls != 1 {
+		t.Errorf("expected call to be rejected locally, got %v after %d calls", err, calls)
+	}
+
+	budgets := c.RateLimitBudgets()
+	if len(budgets) != 3 || budgets[2].Category != RateLimitPublic || budgets[2].BlockedUntil == nil {
+		t.Errorf("expected blocked public budget, got %+v", budgets)
+	}
+}
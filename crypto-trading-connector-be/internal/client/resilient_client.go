@@ -0,0 +1,401 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// ErrExchangeUnavailable is wrapped by ExchangeUnavailableError, so callers can test for it with errors.Is
+var ErrExchangeUnavailable = errors.New("exchange unavailable")
+
+// ErrOrderAmbiguous is returned when orders matching a failed order are found on the exchange but none of
+// them can be identified as that order by its client order ID
+var ErrOrderAmbiguous = errors.New("order cannot be identified on the exchange")
+
+// ExchangeUnavailableError reports a call that failed because the exchange could not be reached,
+// either because its circuit breaker is open or because every retry failed
+type ExchangeUnavailableError struct {
+	Exchange   string
+	RetryAfter time.Duration // Time until the circuit breaker lets a call through again; zero when it is closed
+	Err        error         // Last failure of the call; nil when the circuit breaker rejected it
+}
+
+func (e *ExchangeUnavailableError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", ErrExchangeUnavailable, e.Exchange, e.Err)
+	}
+	return fmt.Sprintf("%s: %s circuit breaker is open, retry after %s", ErrExchangeUnavailable, e.Exchange, e.RetryAfter.Round(time.Millisecond))
+}
+
+func (e *ExchangeUnavailableError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrExchangeUnavailable}
+	}
+	return []error{ErrExchangeUnavailable, e.Err}
+}
+
+// ResilienceConfig configures the retries and the circuit breaker of a ResilientClient
+type ResilienceConfig struct {
+	MaxRetries       int           // Retries after the first attempt of a read or an order
+	BaseDelay        time.Duration // Backoff before the first retry, doubled for every further retry
+	MaxDelay         time.Duration // Upper bound of the backoff
+	FailureThreshold int           // Consecutive transient failures that open the circuit breaker
+	OpenTimeout      time.Duration // How long the circuit breaker stays open before a trial call is let through
+
+	// ResubmitOrders allows sending an order again when the lookup after a failed SendOrder does not
+	// find it. Only enable it for exchanges whose GetChildOrders without an order ID lists recent
+	// orders in every state; otherwise a filled order would be missed and placed twice.
+	ResubmitOrders bool
+}
+
+// DefaultResilienceConfig returns the retry and circuit breaker settings used by the server
+func DefaultResilienceConfig() ResilienceConfig {
+	return ResilienceConfig{
+		MaxRetries:       2,
+		BaseDelay:        200 * time.Millisecond,
+		MaxDelay:         2 * time.Second,
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+// orderLookupCount is the number of recent orders searched for an order whose SendOrder failed
+const orderLookupCount = 20
+
+// orderLookupClockSkew allows for the difference between our clock and the order date of the exchange
+const orderLookupClockSkew = 5 * time.Second
+
+// ResilientClient decorates a CryptoExchangeClient with retries and a circuit breaker.
+// Reads are retried with jittered exponential backoff after transient failures (network errors
+// and HTTP 5xx). A failed SendOrder is never sent again blindly: the recent orders of the pair are
+// looked up first, and the order is only resubmitted if no order like it is found and ResubmitOrders is set.
+// Special orders and cancellations are not retried. After FailureThreshold consecutive transient
+// failures the circuit breaker opens and every call fails fast with an ExchangeUnavailableError.
+type ResilientClient struct {
+	next    CryptoExchangeClient
+	config  ResilienceConfig
+	breaker *circuitBreaker
+}
+
+// NewResilientClient wraps an exchange client with retries and a circuit breaker
+func NewResilientClient(next CryptoExchangeClient, config ResilienceConfig) *ResilientClient {
+	return &ResilientClient{
+		next:    next,
+		config:  config,
+		breaker: &circuitBreaker{threshold: config.FailureThreshold, openTimeout: config.OpenTimeout},
+	}
+}
+
+// Name returns the name of the wrapped exchange
+func (c *ResilientClient) Name() string {
+	return c.next.Name()
+}
+
+// RateLimitBudgets returns the request budgets of the wrapped client, if it schedules its requests
+func (c *ResilientClient) RateLimitBudgets() []RateLimitBudget {
+	if reporter, ok := c.next.(RateLimitReporter); ok {
+		return reporter.RateLimitBudgets()
+	}
+	return nil
+}
+
+// GetTicker retrieves the ticker, retrying transient failures
+func (c *ResilientClient) GetTicker(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+	return retry(ctx, c, func() (*model.Ticker, error) {
+		return c.next.GetTicker(ctx, symbol)
+	})
+}
+
+// GetProducts retrieves the products, retrying transient failures
+func (c *ResilientClient) GetProducts(ctx context.Context) ([]model.Product, error) {
+	return retry(ctx, c, func() ([]model.Product, error) {
+		return c.next.GetProducts(ctx)
+	})
+}
+
+// GetBalance retrieves the JPY balance, retrying transient failures
+func (c *ResilientClient) GetBalance(ctx context.Context) (float64, error) {
+	return retry(ctx, c, func() (float64, error) {
+		return c.next.GetBalance(ctx)
+	})
+}
+
+// GetBalances retrieves the balances, retrying transient failures
+func (c *ResilientClient) GetBalances(ctx context.Context) ([]model.Balance, error) {
+	return retry(ctx, c, func() ([]model.Balance, error) {
+		return c.next.GetBalances(ctx)
+	})
+}
+
+// GetChildOrders retrieves orders, retrying transient failures
+func (c *ResilientClient) GetChildOrders(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+	return retry(ctx, c, func() ([]model.ChildOrder, error) {
+		return c.next.GetChildOrders(ctx, query)
+	})
+}
+
+// GetParentOrders retrieves special orders, retrying transient failures
+func (c *ResilientClient) GetParentOrders(ctx context.Context, query *model.ParentOrderQuery) ([]model.ParentOrder, error) {
+	return retry(ctx, c, func() ([]model.ParentOrder, error) {
+		return c.next.GetParentOrders(ctx, query)
+	})
+}
+
+// SendOrder sends the order. After a transient failure the order may still have reached the exchange,
+// so the recent orders of the pair are searched for it before it is sent again. An order is only taken
+// as the one sent if its client order ID matches; when orders like it are found but cannot be told apart
+// from other orders that way, the state of the order is unknown and it is neither taken nor resubmitted.
+func (c *ResilientClient) SendOrder(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+	firstSentAt := time.Now()
+
+	for attempt := 0; ; attempt++ {
+		resp, err := once(c, func() (*model.OrderResponse, error) {
+			return c.next.SendOrder(ctx, req)
+		})
+		if err == nil || !isTransient(err) {
+			return resp, err
+		}
+
+		if waitErr := c.backoff(ctx, attempt); waitErr != nil {
+			return nil, c.unavailable(fmt.Errorf("%w (order state unknown: %v)", err, waitErr))
+		}
+
+		placed, similar, lookupErr := findOrderCandidates(ctx, c, req, firstSentAt)
+		if lookupErr != nil {
+			return nil, c.unavailable(fmt.Errorf("%w (order state unknown: lookup failed: %v)", err, lookupErr))
+		}
+		if placed == nil && len(similar) > 0 {
+			return nil, c.unavailable(fmt.Errorf("%w (order state unknown: %d similar orders found: %v)", err, len(similar), ErrOrderAmbiguous))
+		}
+		if placed != nil {
+			return &model.OrderResponse{
+				OrderID:       placed.OrderID,
+				ClientOrderID: req.ClientOrderID,
+			}, nil
+		}
+		if !c.config.ResubmitOrders || attempt >= c.config.MaxRetries {
+			return nil, c.unavailable(fmt.Errorf("%w (order not found on the exchange, not resubmitted)", err))
+		}
+	}
+}
+
+// FindPlacedOrder searches the recent orders of the pair for one matching the request placed since sentAt.
+// The order with the client order ID of the request is returned; otherwise a single order matching the request
+// is, and ErrOrderAmbiguous is returned if several do. It returns nil if there is none.
+func FindPlacedOrder(ctx context.Context, c CryptoExchangeClient, req *model.OrderRequest, sentAt time.Time) (*model.ChildOrder, error) {
+	placed, similar, err := findOrderCandidates(ctx, c, req, sentAt)
+	if err != nil || placed != nil {
+		return placed, err
+	}
+
+	switch len(similar) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &similar[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %d similar orders found", ErrOrderAmbiguous, len(similar))
+	}
+}
+
+// findOrderCandidates searches the recent orders of the pair for the ones matching the request placed since
+// sentAt. The order carrying the client order ID of the request is returned as placed; the matching orders that
+// carry no client order ID, which cannot be told apart, are returned as similar.
+func findOrderCandidates(ctx context.Context, c CryptoExchangeClient, req *model.OrderRequest, sentAt time.Time) (*model.ChildOrder, []model.ChildOrder, error) {
+	orders, err := c.GetChildOrders(ctx, &model.ChildOrderQuery{
+		ProductCode: req.Symbol.ProductCode(),
+		Count:       orderLookupCount,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var similar []model.ChildOrder
+	for i := range orders {
+		if !matchesOrderRequest(&orders[i], req, sentAt) {
+			continue
+		}
+		switch orders[i].ClientOrderID {
+		case "":
+			similar = append(similar, orders[i])
+		case req.ClientOrderID:
+			return &orders[i], nil, nil
+		}
+	}
+	return nil, similar, nil
+}
+
+// matchesOrderRequest reports whether an order on the exchange was placed by the request since sentAt
+func matchesOrderRequest(order *model.ChildOrder, req *model.OrderRequest, sentAt time.Time) bool {
+	if order.Side != req.Side || order.Type != req.Type || !floatEquals(order.Size, req.Size) {
+		return false
+	}
+	if req.Type == model.OrderTypeLimit && !floatEquals(order.Price, req.Price) {
+		return false
+	}
+
+	return !order.OrderDate.IsZero() && !order.OrderDate.Before(sentAt.Add(-orderLookupClockSkew))
+}
+
+// floatEquals compares prices and sizes that went through JSON
+func floatEquals(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// SendParentOrder sends the special order without retries
+func (c *ResilientClient) SendParentOrder(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error) {
+	return once(c, func() (*model.ParentOrderResponse, error) {
+		return c.next.SendParentOrder(ctx, req)
+	})
+}
+
+// CancelOrder cancels the order without retries
+func (c *ResilientClient) CancelOrder(ctx context.Context, productCode, orderID string) error {
+	_, err := once(c, func() (struct{}, error) {
+		return struct{}{}, c.next.CancelOrder(ctx, productCode, orderID)
+	})
+	return err
+}
+
+// CancelAllOrders cancels every open order of the product without retries
+func (c *ResilientClient) CancelAllOrders(ctx context.Context, productCode string) error {
+	_, err := once(c, func() (struct{}, error) {
+		return struct{}{}, c.next.CancelAllOrders(ctx, productCode)
+	})
+	return err
+}
+
+// retry calls an idempotent read until it succeeds, fails with a non-transient error or runs out of retries
+func retry[T any](ctx context.Context, c *ResilientClient, call func() (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := once(c, call)
+		if err == nil || !isTransient(err) {
+			return result, err
+		}
+		if attempt >= c.config.MaxRetries {
+			return result, c.unavailable(err)
+		}
+		if waitErr := c.backoff(ctx, attempt); waitErr != nil {
+			return result, waitErr
+		}
+	}
+}
+
+// once makes a single call if the circuit breaker allows it and records its outcome
+func once[T any](c *ResilientClient, call func() (T, error)) (T, error) {
+	if retryAfter, ok := c.breaker.allow(); !ok {
+		var zero T
+		return zero, &ExchangeUnavailableError{Exchange: c.next.Name(), RetryAfter: retryAfter}
+	}
+
+	result, err := call()
+	c.breaker.record(err)
+	return result, err
+}
+
+// backoff sleeps before the next attempt: half of the exponential delay plus a random jitter up to the other half
+func (c *ResilientClient) backoff(ctx context.Context, attempt int) error {
+	delay := c.config.BaseDelay << attempt
+	if delay > c.config.MaxDelay || delay <= 0 {
+		delay = c.config.MaxDelay
+	}
+	if delay > 1 {
+		delay = delay/2 + rand.N(delay/2)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// unavailable wraps the last transient failure of a call
+func (c *ResilientClient) unavailable(err error) error {
+	var unavailable *ExchangeUnavailableError
+	if errors.As(err, &unavailable) {
+		return err
+	}
+	return &ExchangeUnavailableError{Exchange: c.next.Name(), Err: err}
+}
+
+// isTransient reports whether a call failed because the exchange could not be reached or had an
+// internal error, so that it may succeed when retried. Rate limits are handled by the rate limiter.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrRateLimited) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 && apiErr.Kind == nil
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// circuitBreaker counts consecutive transient failures. Once they reach the threshold it is open and
+// rejects calls until openTimeout has passed; then a single trial call decides whether it closes again.
+type circuitBreaker struct {
+	threshold   int
+	openTimeout time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool // A trial call is in flight while half-open
+}
+
+// allow reports whether a call may be made, or how long until the circuit breaker lets one through
+func (b *circuitBreaker) allow() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return 0, true
+	}
+	if wait := time.Until(b.openUntil); wait > 0 {
+		return wait, false
+	}
+	if b.probing {
+		return b.openTimeout, false
+	}
+	b.probing = true
+	return 0, true
+}
+
+// record updates the failure count with the outcome of a call. Maintenance counts as a failure
+// without being retried; other errors show that the exchange is reachable and close the circuit breaker.
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	switch {
+	case isTransient(err) || errors.Is(err, ErrMaintenance):
+		b.failures++
+		if b.threshold > 0 && b.failures >= b.threshold {
+			b.openUntil = time.Now().Add(b.openTimeout)
+		}
+	case err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)):
+		// The caller gave up; this says nothing about the exchange
+	default:
+		b.failures = 0
+	}
+}
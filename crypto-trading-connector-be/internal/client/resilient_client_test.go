@@ -0,0 +1,247 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// testResilienceConfig retries quickly so that tests do not sleep for long
+func testResilienceConfig() ResilienceConfig {
+	return ResilienceConfig{
+		MaxRetries:       2,
+		BaseDelay:        time.Millisecond,
+		MaxDelay:         5 * time.Millisecond,
+		FailureThreshold: 3,
+		OpenTimeout:      50 * time.Millisecond,
+	}
+}
+
+func TestResilientClient_RetriesTransientReads(t *testing.T) {
+	calls := 0
+	mock := &MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			calls++
+			if calls < 3 {
+				return nil, &APIError{Exchange: "bitFlyer", StatusCode: 503, Message: "Service Unavailable"}
+			}
+			return &model.Ticker{Symbol: symbol, Last: 15000000}, nil
+		},
+	}
+
+	ticker, err := NewResilientClient(mock, testResilienceConfig()).GetTicker(context.Background(), "BTC/JPY")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 3 || ticker.Last != 15000000 {
+		t.Errorf("expected the third attempt to succeed, got %d calls and %+v", calls, ticker)
+	}
+}
+
+func TestResilientClient_DoesNotRetryClientErrors(t *testing.T) {
+	calls := 0
+	mock := &MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			calls++
+			return nil, &APIError{Exchange: "bitFlyer", StatusCode: 400, Code: "-106", Message: "The price is invalid.", Kind: ErrInvalidPrice}
+		},
+	}
+
+	_, err := NewResilientClient(mock, testResilienceConfig()).GetTicker(context.Background(), "BTC/JPY")
+	if !errors.Is(err, ErrInvalidPrice) || errors.Is(err, ErrExchangeUnavailable) {
+		t.Fatalf("expected the client error as is, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestResilientClient_CircuitBreaker(t *testing.T) {
+	failing := true
+	calls := 0
+	mock := &MockBitFlyerClient{
+		GetBalanceFunc: func(ctx context.Context) (float64, error) {
+			calls++
+			if failing {
+				return 0, &APIError{Exchange: "bitFlyer", StatusCode: 500, Message: "Internal Server Error"}
+			}
+			return 1000, nil
+		},
+	}
+	resilient := NewResilientClient(mock, testResilienceConfig())
+
+	// Three failed attempts open the circuit breaker
+	_, err := resilient.GetBalance(context.Background())
+	var unavailable *ExchangeUnavailableError
+	if !errors.As(err, &unavailable) || unavailable.Err == nil {
+		t.Fatalf("expected ExchangeUnavailableError with the last failure, got %v", err)
+	}
+
+	// Calls fail fast while it is open
+	_, err = resilient.GetBalance(context.Background())
+	if !errors.As(err, &unavailable) || unavailable.Err != nil || unavailable.RetryAfter <= 0 {
+		t.Fatalf("expected open circuit breaker error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected no call while the circuit breaker is open, got %d calls", calls)
+	}
+
+	// A successful trial call closes it again
+	time.Sleep(60 * time.Millisecond)
+	failing = false
+	if balance, err := resilient.GetBalance(context.Background()); err != nil || balance != 1000 {
+		t.Fatalf("expected trial call to succeed, got %v, %v", balance, err)
+	}
+	if _, err := resilient.GetBalance(context.Background()); err != nil {
+		t.Errorf("expected closed circuit breaker, got %v", err)
+	}
+}
+
+func TestResilientClient_SendOrder(t *testing.T) {
+	timeout := &APIError{Exchange: "bitFlyer", StatusCode: 504, Message: "Gateway Timeout"}
+	req := &model.OrderRequest{Symbol: "BTC/JPY", Side: model.OrderSideBuy, Type: model.OrderTypeLimit, Price: 15000000, Size: 0.001, ClientOrderID: "client-1"}
+	recentOrder := func(orderID, clientOrderID string) model.ChildOrder {
+		return model.ChildOrder{
+			Side: "BUY", Type: "LIMIT", Price: 15000000, Size: 0.001,
+			OrderDate:     time.Now(),
+			OrderID:       orderID,
+			ClientOrderID: clientOrderID,
+		}
+	}
+
+	tests := []struct {
+		name            string
+		placed          []model.ChildOrder // Recent orders like the failed one on the exchange
+		resubmit        bool
+		wantOrderID     string
+		wantSendCalls   int
+		wantUnavailable bool
+		wantAmbiguous   bool
+	}{
+		{
+			name:          "order found by client order ID after failure",
+			placed:        []model.ChildOrder{recentOrder("JRF-OTHER", "client-2"), recentOrder("JRF-PLACED", "client-1")},
+			resubmit:      true,
+			wantOrderID:   "JRF-PLACED",
+			wantSendCalls: 1,
+		},
+		{
+			name:            "order without client order ID is not taken",
+			placed:          []model.ChildOrder{recentOrder("JRF-PLACED", "")},
+			resubmit:        true,
+			wantSendCalls:   1,
+			wantUnavailable: true,
+			wantAmbiguous:   true,
+		},
+		{
+			name:            "two identical orders are not told apart",
+			placed:          []model.ChildOrder{recentOrder("JRF-PLACED", ""), recentOrder("JRF-MANUAL", "")},
+			resubmit:        true,
+			wantSendCalls:   1,
+			wantUnavailable: true,
+			wantAmbiguous:   true,
+		},
+		{name: "order not found is resubmitted", resubmit: true, wantOrderID: "JRF-RESUBMITTED", wantSendCalls: 2},
+		{
+			name:          "order of another client is not taken",
+			placed:        []model.ChildOrder{recentOrder("JRF-OTHER", "client-2")},
+			resubmit:      true,
+			wantOrderID:   "JRF-RESUBMITTED",
+			wantSendCalls: 2,
+		},
+		{name: "order not found without resubmission", wantSendCalls: 1, wantUnavailable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sendCalls := 0
+			mock := &MockBitFlyerClient{
+				SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+					sendCalls++
+					if sendCalls == 1 {
+						return nil, timeout
+					}
+					return &model.OrderResponse{OrderID: "JRF-RESUBMITTED"}, nil
+				},
+				GetChildOrdersFunc: func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+					if query.ProductCode != "BTC_JPY" || query.OrderID != "" {
+						t.Errorf("unexpected lookup query: %+v", query)
+					}
+					orders := []model.ChildOrder{
+						// Same order placed earlier by someone else
+						{Side: "BUY", Type: "LIMIT", Price: 15000000, Size: 0.001, OrderDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), OrderID: "JRF-OLD"},
+					}
+					return append(orders, tt.placed...), nil
+				},
+			}
+			config := testResilienceConfig()
+			config.ResubmitOrders = tt.resubmit
+
+			resp, err := NewResilientClient(mock, config).SendOrder(context.Background(), req)
+			if tt.wantUnavailable {
+				if !errors.Is(err, ErrExchangeUnavailable) || !errors.As(err, new(*APIError)) {
+					t.Fatalf("expected ExchangeUnavailableError wrapping the failure, got %v", err)
+				}
+				if got := strings.Contains(err.Error(), ErrOrderAmbiguous.Error()); got != tt.wantAmbiguous {
+					t.Errorf("expected ambiguous order %v, got %v", tt.wantAmbiguous, err)
+				}
+			} else if err != nil || resp.OrderID != tt.wantOrderID {
+				t.Fatalf("expected order %s, got %+v, %v", tt.wantOrderID, resp, err)
+			}
+			if sendCalls != tt.wantSendCalls {
+				t.Errorf("expected %d SendOrder calls, got %d", tt.wantSendCalls, sendCalls)
+			}
+		})
+	}
+}
+
+func TestFindPlacedOrder(t *testing.T) {
+	req := &model.OrderRequest{Symbol: "BTC/JPY", Side: model.OrderSideBuy, Type: model.OrderTypeLimit, Price: 15000000, Size: 0.001, ClientOrderID: "client-1"}
+	sentAt := time.Now()
+	order := func(orderID string) model.ChildOrder {
+		return model.ChildOrder{Side: "BUY", Type: "LIMIT", Price: 15000000, Size: 0.001, OrderDate: sentAt, OrderID: orderID}
+	}
+
+	tests := []struct {
+		name          string
+		orders        []model.ChildOrder
+		wantOrderID   string
+		wantAmbiguous bool
+	}{
+		{name: "no order", orders: nil},
+		{name: "single similar order", orders: []model.ChildOrder{order("JRF-1")}, wantOrderID: "JRF-1"},
+		{name: "two identical orders", orders: []model.ChildOrder{order("JRF-1"), order("JRF-2")}, wantAmbiguous: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockBitFlyerClient{
+				GetChildOrdersFunc: func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+					return tt.orders, nil
+				},
+			}
+
+			placed, err := FindPlacedOrder(context.Background(), mock, req, sentAt)
+			if tt.wantAmbiguous {
+				if !errors.Is(err, ErrOrderAmbiguous) || placed != nil {
+					t.Fatalf("expected ErrOrderAmbiguous, got %+v, %v", placed, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			gotOrderID := ""
+			if placed != nil {
+				gotOrderID = placed.OrderID
+			}
+			if gotOrderID != tt.wantOrderID {
+				t.Errorf("expected order %q, got %q", tt.wantOrderID, gotOrderID)
+			}
+		})
+	}
+}
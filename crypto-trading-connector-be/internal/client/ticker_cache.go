@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// TickerCacheConfig configures a TickerCache
+type TickerCacheConfig struct {
+	TTL      time.Duration // How long a ticker is served without asking the exchange again
+	MaxStale time.Duration // How long past its TTL a ticker is still served, marked stale, while the exchange fails
+}
+
+// DefaultTickerCacheConfig returns the cache settings used by the server and the CLIs
+func DefaultTickerCacheConfig() TickerCacheConfig {
+	return TickerCacheConfig{
+		TTL:      2 * time.Second,
+		MaxStale: time.Minute,
+	}
+}
+
+// tickerFetchTimeout bounds a shared ticker fetch, which does not stop when one of its callers gives up
+const tickerFetchTimeout = 15 * time.Second
+
+// TickerCache decorates a CryptoExchangeClient with a ticker cache; every other call goes to the
+// wrapped client. Concurrent calls for an expired symbol share one request to the exchange.
+// When that request fails, the last ticker is returned with Stale set for up to MaxStale past its
+// TTL, and the exchange is not asked again for the symbol until another TTL has passed.
+type TickerCache struct {
+	CryptoExchangeClient
+	config TickerCacheConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[model.Symbol]*tickerEntry
+}
+
+// tickerEntry is the cached ticker of a symbol
+type tickerEntry struct {
+	ticker    *model.Ticker // Last ticker received; nil until a fetch succeeds
+	fetchedAt time.Time     // When ticker was received
+	checkedAt time.Time     // When the exchange was last asked, successfully or not
+	err       error         // Error of the last request; nil if it succeeded
+	fetch     *tickerFetch  // Fetch in progress; nil if there is none
+}
+
+// tickerFetch is a request to the exchange shared by every caller waiting for it
+type tickerFetch struct {
+	done   chan struct{}
+	ticker *model.Ticker
+	err    error
+}
+
+// NewTickerCache wraps an exchange client with a ticker cache
+func NewTickerCache(next CryptoExchangeClient, config TickerCacheConfig) *TickerCache {
+	return &TickerCache{
+		CryptoExchangeClient: next,
+		config:               config,
+		now:                  time.Now,
+		entries:              map[model.Symbol]*tickerEntry{},
+	}
+}
+
+// RateLimitBudgets returns the request budgets of the wrapped client, if it schedules its requests
+func (c *TickerCache) RateLimitBudgets() []RateLimitBudget {
+	if reporter, ok := c.CryptoExchangeClient.(RateLimitReporter); ok {
+		return reporter.RateLimitBudgets()
+	}
+	return nil
+}
+
+// GetTicker returns the cached ticker of the symbol, fetching it if it has expired
+func (c *TickerCache) GetTicker(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[symbol]
+	if !ok {
+		entry = &tickerEntry{}
+		c.entries[symbol] = entry
+	}
+
+	now := c.now()
+	if !entry.checkedAt.IsZero() && now.Sub(entry.checkedAt) < c.config.TTL {
+		// Fresh, or the last refresh failed less than a TTL ago
+		ticker, err := c.served(entry, now), entry.err
+		c.mu.Unlock()
+		if ticker == nil {
+			return nil, err
+		}
+		return ticker, nil
+	}
+
+	fetch := entry.fetch
+	if fetch == nil {
+		fetch = &tickerFetch{done: make(chan struct{})}
+		entry.fetch = fetch
+		go c.refresh(ctx, symbol, entry, fetch)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-fetch.done:
+	}
+	if fetch.err == nil {
+		ticker := *fetch.ticker
+		return &ticker, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ticker := c.served(entry, c.now()); ticker != nil {
+		return ticker, nil
+	}
+	return nil, fetch.err
+}
+
+// refresh fetches the ticker from the exchange on behalf of every caller waiting for fetch.
+// The request is detached from the cancellation of the caller that started it.
+func (c *TickerCache) refresh(ctx context.Context, symbol model.Symbol, entry *tickerEntry, fetch *tickerFetch) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tickerFetchTimeout)
+	defer cancel()
+	ticker, err := c.CryptoExchangeClient.GetTicker(ctx, symbol)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	entry.checkedAt = now
+	entry.err = err
+	entry.fetch = nil
+	if err == nil {
+		entry.ticker = ticker
+		entry.fetchedAt = now
+	}
+
+	fetch.ticker, fetch.err = ticker, err
+	close(fetch.done)
+}
+
+// served returns a copy of the cached ticker, marked stale if it is past its TTL, or nil if it is
+// too old to be served. c.mu must be held.
+func (c *TickerCache) served(entry *tickerEntry, now time.Time) *model.Ticker {
+	if entry.ticker == nil {
+		return nil
+	}
+
+	age := now.Sub(entry.fetchedAt)
+	if age >= c.config.TTL+c.config.MaxStale {
+		return nil
+	}
+	ticker := *entry.ticker
+	ticker.Stale = age >= c.config.TTL
+	return &ticker
+}
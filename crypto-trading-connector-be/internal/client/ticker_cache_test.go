@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+func TestTickerCache_CoalescesConcurrentCalls(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	mock := &MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			calls.Add(1)
+			<-release
+			return &model.Ticker{Symbol: symbol, Last: 15000000}, nil
+		},
+	}
+	cache := NewTickerCache(mock, TickerCacheConfig{TTL: time.Minute, MaxStale: time.Minute})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ticker, err := cache.GetTicker(context.Background(), "BTC/JPY"); err != nil || ticker.Last != 15000000 {
+				t.Errorf("expected the shared ticker, got %+v, %v", ticker, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// Within the TTL the cached ticker is served
+	if _, err := cache.GetTicker(context.Background(), "BTC/JPY"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 call to the exchange, got %d", calls.Load())
+	}
+}
+
+func TestTickerCache_ServesStaleTickerWhileExchangeFails(t *testing.T) {
+	failure := &ExchangeUnavailableError{Exchange: "bitflyer", RetryAfter: time.Second}
+	failing := false
+	calls := 0
+	mock := &MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			calls++
+			if failing {
+				return nil, failure
+			}
+			return &model.Ticker{Symbol: symbol, Last: float64(calls)}, nil
+		},
+	}
+	cache := NewTickerCache(mock, TickerCacheConfig{TTL: 2 * time.Second, MaxStale: 10 * time.Second})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	if ticker, err := cache.GetTicker(context.Background(), "BTC/JPY"); err != nil || ticker.Last != 1 || ticker.Stale {
+		t.Fatalf("expected a fresh ticker, got %+v, %v", ticker, err)
+	}
+
+	// The refresh fails: the last ticker is served, flagged stale
+	failing = true
+	now = now.Add(3 * time.Second)
+	ticker, err := cache.GetTicker(context.Background(), "BTC/JPY")
+	if err != nil || ticker.Last != 1 || !ticker.Stale {
+		t.Fatalf("expected the stale ticker, got %+v, %v", ticker, err)
+	}
+
+	// The failing exchange is not asked again until the TTL has passed
+	if _, err := cache.GetTicker(context.Background(), "BTC/JPY"); err != nil || calls != 2 {
+		t.Errorf("expected the stale ticker without a call, got %v after %d calls", err, calls)
+	}
+
+	// Past MaxStale the error is returned
+	now = now.Add(10 * time.Second)
+	if _, err := cache.GetTicker(context.Background(), "BTC/JPY"); !errors.Is(err, ErrExchangeUnavailable) {
+		t.Errorf("expected the exchange error, got %v", err)
+	}
+
+	// The exchange recovers
+	failing = false
+	now = now.Add(3 * time.Second)
+	if ticker, err := cache.GetTicker(context.Background(), "BTC/JPY"); err != nil || ticker.Last != 4 || ticker.Stale {
+		t.Errorf("expected a fresh ticker, got %+v, %v", ticker, err)
+	}
+}
+
+func TestTickerCache_CancelledCallerDoesNotFailOthers(t *testing.T) {
+	release := make(chan struct{})
+	mock := &MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			<-release
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return &model.Ticker{Symbol: symbol, Last: 15000000}, nil
+		},
+	}
+	cache := NewTickerCache(mock, DefaultTickerCacheConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := cache.GetTicker(ctx, "BTC/JPY")
+		first <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	second := make(chan error, 1)
+	go func() {
+		_, err := cache.GetTicker(context.Background(), "BTC/JPY")
+		second <- err
+	}()
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancelled caller to give up, got %v", err)
+	}
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("expected the other caller to get the ticker, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// ErrTradingHalted is returned for orders while the trading halt is engaged
+var ErrTradingHalted = errors.New("trading halted")
+
+// TradingHaltSource reports the state of the trading halt (repository.TradingHaltRepository)
+type TradingHaltSource interface {
+	GetTradingHalt(ctx context.Context) (*model.TradingHalt, error)
+}
+
+// HaltGuard decorates a CryptoExchangeClient so that no order is sent while the trading halt is engaged.
+// The halt is read before every order, so engaging it from another process takes effect at once.
+// Cancellations and every other call go to the wrapped client.
+type HaltGuard struct {
+	CryptoExchangeClient
+	halts TradingHaltSource
+}
+
+// NewHaltGuard wraps an exchange client with the trading halt
+func NewHaltGuard(next CryptoExchangeClient, halts TradingHaltSource) *HaltGuard {
+	return &HaltGuard{
+		CryptoExchangeClient: next,
+		halts:                halts,
+	}
+}
+
+// RateLimitBudgets returns the request budgets of the wrapped client, if it schedules its requests
+func (c *HaltGuard) RateLimitBudgets() []RateLimitBudget {
+	if reporter, ok := c.CryptoExchangeClient.(RateLimitReporter); ok {
+		return reporter.RateLimitBudgets()
+	}
+	return nil
+}
+
+// SendOrder sends the order unless trading is halted
+func (c *HaltGuard) SendOrder(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+	if err := CheckTradingHalt(ctx, c.halts); err != nil {
+		return nil, err
+	}
+	return c.CryptoExchangeClient.SendOrder(ctx, req)
+}
+
+// SendParentOrder sends the special order unless trading is halted
+func (c *HaltGuard) SendParentOrder(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error) {
+	if err := CheckTradingHalt(ctx, c.halts); err != nil {
+		return nil, err
+	}
+	return c.CryptoExchangeClient.SendParentOrder(ctx, req)
+}
+
+// CheckTradingHalt returns an error wrapping ErrTradingHalted if the halt is engaged. Orders are not sent
+// either when the halt cannot be read.
+func CheckTradingHalt(ctx context.Context, halts TradingHaltSource) error {
+	halt, err := halts.GetTradingHalt(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check trading halt, order not sent: %w", err)
+	}
+	if !halt.Halted {
+		return nil
+	}
+	if halt.Reason != nil && *halt.Reason != "" {
+		return fmt.Errorf("%w: %s", ErrTradingHalted, *halt.Reason)
+	}
+	return ErrTradingHalted
+}
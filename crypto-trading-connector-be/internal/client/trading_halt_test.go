@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// fakeTradingHalts returns a fixed trading halt
+type fakeTradingHalts struct {
+	halt *model.TradingHalt
+	err  error
+}
+
+func (f *fakeTradingHalts) GetTradingHalt(ctx context.Context) (*model.TradingHalt, error) {
+	return f.halt, f.err
+}
+
+func TestHaltGuard(t *testing.T) {
+	sent, cancelled := 0, 0
+	mock := &MockBitFlyerClient{
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			sent++
+			return &model.OrderResponse{OrderID: "JRF20240101-000000-000001"}, nil
+		},
+		CancelAllOrdersFunc: func(ctx context.Context, productCode string) error {
+			cancelled++
+			return nil
+		},
+	}
+	reason := "investigating unexpected fills"
+	halts := &fakeTradingHalts{halt: &model.TradingHalt{Halted: true, Reason: &reason}}
+	guard := NewHaltGuard(mock, halts)
+	req := &model.OrderRequest{Symbol: "BTC/JPY", Side: model.OrderSideBuy, Type: model.OrderTypeLimit, Price: 10000000, Size: 0.001}
+
+	// Engaged: orders are rejected, cancellations go through
+	if _, err := guard.SendOrder(context.Background(), req); !errors.Is(err, ErrTradingHalted) {
+		t.Fatalf("expected ErrTradingHalted, got %v", err)
+	}
+	if _, err := guard.SendParentOrder(context.Background(), &model.ParentOrderRequest{}); !errors.Is(err, ErrTradingHalted) {
+		t.Fatalf("expected ErrTradingHalted, got %v", err)
+	}
+	if err := guard.CancelAllOrders(context.Background(), "BTC_JPY"); err != nil || cancelled != 1 {
+		t.Fatalf("expected the cancellation to be sent, got %v", err)
+	}
+
+	// The halt cannot be read: orders are not sent
+	halts.halt, halts.err = nil, errors.New("connection refused")
+	if _, err := guard.SendOrder(context.Background(), req); err == nil || errors.Is(err, ErrTradingHalted) {
+		t.Fatalf("expected the lookup error, got %v", err)
+	}
+	if sent != 0 {
+		t.Fatalf("expected no order sent, got %d", sent)
+	}
+
+	// Released
+	halts.halt, halts.err = &model.TradingHalt{}, nil
+	if _, err := guard.SendOrder(context.Background(), req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sent != 1 {
+		t.Errorf("expected 1 order sent, got %d", sent)
+	}
+}
@@ -5,8 +5,10 @@ package generated
 
 import (
 	"time"
+)
 
-	openapi_types "github.com/oapi-codegen/runtime/types"
+const (
+	AdminTokenScopes = "adminToken.Scopes"
 )
 
 // Defines values for BalanceCurrency.
@@ -25,40 +27,90 @@ const (
 
 // Defines values for CreateOrderRequestOrderType.
 const (
-	CreateOrderRequestOrderTypeLimit CreateOrderRequestOrderType = "limit"
+	CreateOrderRequestOrderTypeLimit  CreateOrderRequestOrderType = "limit"
+	CreateOrderRequestOrderTypeMarket CreateOrderRequestOrderType = "market"
+)
+
+// Defines values for CreateOrderRequestSide.
+const (
+	CreateOrderRequestSideBUY  CreateOrderRequestSide = "BUY"
+	CreateOrderRequestSideSELL CreateOrderRequestSide = "SELL"
+)
+
+// Defines values for CreateParentOrderRequestMethod.
+const (
+	CreateParentOrderRequestMethodIFD    CreateParentOrderRequestMethod = "IFD"
+	CreateParentOrderRequestMethodIFDOCO CreateParentOrderRequestMethod = "IFDOCO"
+	CreateParentOrderRequestMethodOCO    CreateParentOrderRequestMethod = "OCO"
 )
 
-// Defines values for CreateOrderRequestPair.
+// Defines values for CreateParentOrderRequestTimeInForce.
 const (
-	CreateOrderRequestPairBTCJPY CreateOrderRequestPair = "BTC/JPY"
-	CreateOrderRequestPairETHJPY CreateOrderRequestPair = "ETH/JPY"
+	FOK CreateParentOrderRequestTimeInForce = "FOK"
+	GTC CreateParentOrderRequestTimeInForce = "GTC"
+	IOC CreateParentOrderRequestTimeInForce = "IOC"
 )
 
 // Defines values for ErrorResponseError.
 const (
-	BADREQUEST          ErrorResponseError = "BAD_REQUEST"
-	INSUFFICIENTBALANCE ErrorResponseError = "INSUFFICIENT_BALANCE"
-	INTERNALERROR       ErrorResponseError = "INTERNAL_ERROR"
-	INTERNALSERVERERROR ErrorResponseError = "INTERNAL_SERVER_ERROR"
-	INVALIDAMOUNT       ErrorResponseError = "INVALID_AMOUNT"
-	INVALIDFILTER       ErrorResponseError = "INVALID_FILTER"
-	INVALIDPAGINATION   ErrorResponseError = "INVALID_PAGINATION"
-	INVALIDPRICE        ErrorResponseError = "INVALID_PRICE"
-	INVALIDREQUEST      ErrorResponseError = "INVALID_REQUEST"
-	NOTFOUND            ErrorResponseError = "NOT_FOUND"
-	UNAUTHORIZED        ErrorResponseError = "UNAUTHORIZED"
-	UNSUPPORTEDPAIR     ErrorResponseError = "UNSUPPORTED_PAIR"
+	BADREQUEST           ErrorResponseError = "BAD_REQUEST"
+	EXCHANGEUNAVAILABLE  ErrorResponseError = "EXCHANGE_UNAVAILABLE"
+	IDEMPOTENCYKEYREUSED ErrorResponseError = "IDEMPOTENCY_KEY_REUSED"
+	INSUFFICIENTBALANCE  ErrorResponseError = "INSUFFICIENT_BALANCE"
+	INTERNALERROR        ErrorResponseError = "INTERNAL_ERROR"
+	INTERNALSERVERERROR  ErrorResponseError = "INTERNAL_SERVER_ERROR"
+	INVALIDAMOUNT        ErrorResponseError = "INVALID_AMOUNT"
+	INVALIDFILTER        ErrorResponseError = "INVALID_FILTER"
+	INVALIDPAGINATION    ErrorResponseError = "INVALID_PAGINATION"
+	INVALIDPRICE         ErrorResponseError = "INVALID_PRICE"
+	INVALIDREQUEST       ErrorResponseError = "INVALID_REQUEST"
+	NOTFOUND             ErrorResponseError = "NOT_FOUND"
+	ORDERINPROGRESS      ErrorResponseError = "ORDER_IN_PROGRESS"
+	ORDERNOTCANCELLABLE  ErrorResponseError = "ORDER_NOT_CANCELLABLE"
+	RATELIMITED          ErrorResponseError = "RATE_LIMITED"
+	RISKDAILYVOLUME      ErrorResponseError = "RISK_DAILY_VOLUME"
+	RISKMAXNOTIONAL      ErrorResponseError = "RISK_MAX_NOTIONAL"
+	RISKMAXOPENORDERS    ErrorResponseError = "RISK_MAX_OPEN_ORDERS"
+	RISKPAIRNOTALLOWED   ErrorResponseError = "RISK_PAIR_NOT_ALLOWED"
+	RISKPRICEBAND        ErrorResponseError = "RISK_PRICE_BAND"
+	TRADINGHALTED        ErrorResponseError = "TRADING_HALTED"
+	UNAUTHORIZED         ErrorResponseError = "UNAUTHORIZED"
+	UNSUPPORTEDPAIR      ErrorResponseError = "UNSUPPORTED_PAIR"
+)
+
+// Defines values for MarketAssetStatusStatus.
+const (
+	MarketAssetStatusStatusNOCHART     MarketAssetStatusStatus = "NO_CHART"
+	MarketAssetStatusStatusOK          MarketAssetStatusStatus = "OK"
+	MarketAssetStatusStatusSTALE       MarketAssetStatusStatus = "STALE"
+	MarketAssetStatusStatusUNAVAILABLE MarketAssetStatusStatus = "UNAVAILABLE"
+)
+
+// Defines values for OrderDetailSide.
+const (
+	OrderDetailSideBUY  OrderDetailSide = "BUY"
+	OrderDetailSideSELL OrderDetailSide = "SELL"
+)
+
+// Defines values for OrderDetailStatus.
+const (
+	OrderDetailStatusCANCELLED       OrderDetailStatus = "CANCELLED"
+	OrderDetailStatusEXPIRED         OrderDetailStatus = "EXPIRED"
+	OrderDetailStatusFILLED          OrderDetailStatus = "FILLED"
+	OrderDetailStatusPARTIALLYFILLED OrderDetailStatus = "PARTIALLY_FILLED"
+	OrderDetailStatusUNFILLED        OrderDetailStatus = "UNFILLED"
 )
 
 // Defines values for OrderOrderType.
 const (
-	OrderOrderTypeLimit OrderOrderType = "limit"
+	OrderOrderTypeLimit  OrderOrderType = "limit"
+	OrderOrderTypeMarket OrderOrderType = "market"
 )
 
-// Defines values for OrderPair.
+// Defines values for OrderSide.
 const (
-	OrderPairBTCJPY OrderPair = "BTC/JPY"
-	OrderPairETHJPY OrderPair = "ETH/JPY"
+	OrderSideBUY  OrderSide = "BUY"
+	OrderSideSELL OrderSide = "SELL"
 )
 
 // Defines values for OrderStatus.
@@ -69,16 +121,47 @@ const (
 	Pending   OrderStatus = "pending"
 )
 
-// Defines values for TradeStatisticsPeriod.
+// Defines values for ParentOrderMethod.
 const (
-	TradeStatisticsPeriodAll    TradeStatisticsPeriod = "all"
-	TradeStatisticsPeriodN7days TradeStatisticsPeriod = "7days"
+	ParentOrderMethodIFD    ParentOrderMethod = "IFD"
+	ParentOrderMethodIFDOCO ParentOrderMethod = "IFDOCO"
+	ParentOrderMethodOCO    ParentOrderMethod = "OCO"
+)
+
+// Defines values for ParentOrderStatus.
+const (
+	ParentOrderStatusACTIVE    ParentOrderStatus = "ACTIVE"
+	ParentOrderStatusCANCELLED ParentOrderStatus = "CANCELLED"
+	ParentOrderStatusCOMPLETED ParentOrderStatus = "COMPLETED"
+	ParentOrderStatusEXPIRED   ParentOrderStatus = "EXPIRED"
 )
 
-// Defines values for TransactionCryptocurrency.
+// Defines values for ParentOrderLegConditionType.
 const (
-	Bitcoin  TransactionCryptocurrency = "Bitcoin"
-	Ethereum TransactionCryptocurrency = "Ethereum"
+	LIMIT     ParentOrderLegConditionType = "LIMIT"
+	MARKET    ParentOrderLegConditionType = "MARKET"
+	STOP      ParentOrderLegConditionType = "STOP"
+	STOPLIMIT ParentOrderLegConditionType = "STOP_LIMIT"
+	TRAIL     ParentOrderLegConditionType = "TRAIL"
+)
+
+// Defines values for ParentOrderLegSide.
+const (
+	ParentOrderLegSideBUY  ParentOrderLegSide = "BUY"
+	ParentOrderLegSideSELL ParentOrderLegSide = "SELL"
+)
+
+// Defines values for RateLimitBudgetCategory.
+const (
+	RateLimitBudgetCategoryOrder   RateLimitBudgetCategory = "order"
+	RateLimitBudgetCategoryPrivate RateLimitBudgetCategory = "private"
+	RateLimitBudgetCategoryPublic  RateLimitBudgetCategory = "public"
+)
+
+// Defines values for TradeStatisticsPeriod.
+const (
+	TradeStatisticsPeriodAll    TradeStatisticsPeriod = "all"
+	TradeStatisticsPeriodN7days TradeStatisticsPeriod = "7days"
 )
 
 // Defines values for TransactionOrderType.
@@ -95,47 +178,75 @@ const (
 	GetCryptoChartParamsPeriodN7d  GetCryptoChartParamsPeriod = "7d"
 )
 
-// Defines values for GetTradeStatisticsParamsAssetFilter.
-const (
-	GetTradeStatisticsParamsAssetFilterAll GetTradeStatisticsParamsAssetFilter = "all"
-	GetTradeStatisticsParamsAssetFilterBTC GetTradeStatisticsParamsAssetFilter = "BTC"
-	GetTradeStatisticsParamsAssetFilterETH GetTradeStatisticsParamsAssetFilter = "ETH"
-)
-
 // Defines values for GetTradeStatisticsParamsTimeFilter.
 const (
 	GetTradeStatisticsParamsTimeFilterAll    GetTradeStatisticsParamsTimeFilter = "all"
 	GetTradeStatisticsParamsTimeFilterN7days GetTradeStatisticsParamsTimeFilter = "7days"
 )
 
-// Defines values for GetTradeTransactionsParamsAssetFilter.
-const (
-	GetTradeTransactionsParamsAssetFilterAll GetTradeTransactionsParamsAssetFilter = "all"
-	GetTradeTransactionsParamsAssetFilterBTC GetTradeTransactionsParamsAssetFilter = "BTC"
-	GetTradeTransactionsParamsAssetFilterETH GetTradeTransactionsParamsAssetFilter = "ETH"
-)
-
 // Defines values for GetTradeTransactionsParamsTimeFilter.
 const (
 	All    GetTradeTransactionsParamsTimeFilter = "all"
 	N7days GetTradeTransactionsParamsTimeFilter = "7days"
 )
 
+// Defines values for ListOrdersParamsSide.
+const (
+	ListOrdersParamsSideBUY  ListOrdersParamsSide = "BUY"
+	ListOrdersParamsSideSELL ListOrdersParamsSide = "SELL"
+)
+
+// Defines values for ListOrdersParamsStatus.
+const (
+	ListOrdersParamsStatusCANCELLED       ListOrdersParamsStatus = "CANCELLED"
+	ListOrdersParamsStatusEXPIRED         ListOrdersParamsStatus = "EXPIRED"
+	ListOrdersParamsStatusFILLED          ListOrdersParamsStatus = "FILLED"
+	ListOrdersParamsStatusPARTIALLYFILLED ListOrdersParamsStatus = "PARTIALLY_FILLED"
+	ListOrdersParamsStatusUNFILLED        ListOrdersParamsStatus = "UNFILLED"
+)
+
 // Balance defines model for Balance.
 type Balance struct {
-	// AvailableBalance Available balance in JPY
+	// AvailableBalance Available balance in JPY, summed over every exchange
 	AvailableBalance float64 `json:"availableBalance"`
 
 	// Currency Currency code
 	Currency BalanceCurrency `json:"currency"`
 
+	// Holdings Crypto holdings other than JPY
+	Holdings *[]CurrencyBalance `json:"holdings,omitempty"`
+
 	// Timestamp Unix timestamp of the balance snapshot
 	Timestamp int64 `json:"timestamp"`
+
+	// TradingHalted Whether trading is halted; orders are rejected until it is released
+	TradingHalted bool `json:"tradingHalted"`
 }
 
 // BalanceCurrency Currency code
 type BalanceCurrency string
 
+// BalancesResponse defines model for BalancesResponse.
+type BalancesResponse struct {
+	// Balances Balances of every currency held on every exchange, one entry per exchange
+	Balances []CurrencyBalance `json:"balances"`
+
+	// Timestamp Unix timestamp of the balance snapshot
+	Timestamp int64 `json:"timestamp"`
+}
+
+// CancelOrderResponse defines model for CancelOrderResponse.
+type CancelOrderResponse struct {
+	// OrderId Exchange order ID of the cancelled order
+	OrderId string `json:"orderId"`
+
+	// Pair Trading pair
+	Pair string `json:"pair"`
+
+	// Status Order status after cancellation
+	Status string `json:"status"`
+}
+
 // ChartDataPoint defines model for ChartDataPoint.
 type ChartDataPoint struct {
 	// Day Day label (Mon, Tue, Wed, etc.)
@@ -159,24 +270,48 @@ type ChartResponsePeriod string
 
 // CreateOrderRequest defines model for CreateOrderRequest.
 type CreateOrderRequest struct {
-	// Amount Amount of cryptocurrency to buy
+	// Amount Amount of cryptocurrency to buy or sell
 	Amount float64 `json:"amount"`
 
-	// OrderType Order type (currently only limit orders supported)
+	// OrderType Order type
 	OrderType CreateOrderRequestOrderType `json:"orderType"`
 
 	// Pair Trading pair
-	Pair CreateOrderRequestPair `json:"pair"`
+	Pair string `json:"pair"`
 
-	// Price Limit price in JPY
-	Price float64 `json:"price"`
+	// Price Limit price in JPY (required for limit orders, ignored for market orders)
+	Price float64 `json:"price,omitempty"`
+
+	// Side Order side (defaults to BUY)
+	Side CreateOrderRequestSide `json:"side,omitempty"`
 }
 
-// CreateOrderRequestOrderType Order type (currently only limit orders supported)
+// CreateOrderRequestOrderType Order type
 type CreateOrderRequestOrderType string
 
-// CreateOrderRequestPair Trading pair
-type CreateOrderRequestPair string
+// CreateOrderRequestSide Order side (defaults to BUY)
+type CreateOrderRequestSide string
+
+// CreateParentOrderRequest defines model for CreateParentOrderRequest.
+type CreateParentOrderRequest struct {
+	// Legs Order conditions in exchange order (IFD and OCO take 2 legs, IFDOCO takes 3)
+	Legs []ParentOrderLeg `json:"legs"`
+
+	// Method Special order method
+	Method CreateParentOrderRequestMethod `json:"method"`
+
+	// MinuteToExpire Minutes until the order expires (exchange default when omitted)
+	MinuteToExpire *int `json:"minuteToExpire,omitempty"`
+
+	// TimeInForce Execution condition (exchange default when omitted)
+	TimeInForce *CreateParentOrderRequestTimeInForce `json:"timeInForce,omitempty"`
+}
+
+// CreateParentOrderRequestMethod Special order method
+type CreateParentOrderRequestMethod string
+
+// CreateParentOrderRequestTimeInForce Execution condition (exchange default when omitted)
+type CreateParentOrderRequestTimeInForce string
 
 // CryptoData defines model for CryptoData.
 type CryptoData struct {
@@ -204,10 +339,31 @@ type CryptoData struct {
 	// Pair Trading pair
 	Pair string `json:"pair"`
 
+	// Product Trading rules of the pair on the exchange it is traded on
+	Product *ProductInfo `json:"product,omitempty"`
+
+	// Stale Present and true when currentPrice is a cached price served because the exchange could not be reached
+	Stale *bool `json:"stale,omitempty"`
+
 	// Symbol Trading symbol
 	Symbol string `json:"symbol"`
 }
 
+// CurrencyBalance defines model for CurrencyBalance.
+type CurrencyBalance struct {
+	// Amount Total amount held, including amounts reserved by open orders
+	Amount float64 `json:"amount"`
+
+	// Available Amount available for new orders
+	Available float64 `json:"available"`
+
+	// Currency Currency code (e.g., JPY, BTC, ETH)
+	Currency string `json:"currency"`
+
+	// Exchange Exchange the currency is held on
+	Exchange string `json:"exchange"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	// Details Additional error details (optional)
@@ -223,13 +379,41 @@ type ErrorResponse struct {
 // ErrorResponseError Error type
 type ErrorResponseError string
 
+// MarketAssetStatus defines model for MarketAssetStatus.
+type MarketAssetStatus struct {
+	// Id Cryptocurrency identifier
+	Id string `json:"id"`
+
+	// Message Why the data is incomplete; absent when the status is OK
+	Message *string `json:"message,omitempty"`
+
+	// Status OK: price and chart are up to date.
+	// STALE: the price is the last one received before the exchange became unreachable. If the chart could
+	// not be loaded either, chartData is empty and the message tells both.
+	// NO_CHART: the price is up to date but the chart could not be loaded; chartData is empty.
+	// UNAVAILABLE: the price could not be retrieved; the cryptocurrency is left out of data.
+	Status MarketAssetStatusStatus `json:"status"`
+}
+
+// MarketAssetStatusStatus OK: price and chart are up to date.
+// STALE: the price is the last one received before the exchange became unreachable.
+// NO_CHART: the price is up to date but the chart could not be loaded; chartData is empty.
+// UNAVAILABLE: the price could not be retrieved; the cryptocurrency is left out of data.
+type MarketAssetStatusStatus string
+
 // MarketResponse defines model for MarketResponse.
 type MarketResponse struct {
-	// Data Array of cryptocurrency market data
+	// Data Array of cryptocurrency market data; cryptocurrencies whose price could not be retrieved are left out
 	Data []CryptoData `json:"data"`
 
+	// Statuses Status of every listed cryptocurrency, in listing order
+	Statuses []MarketAssetStatus `json:"statuses"`
+
 	// Timestamp Unix timestamp of the response
 	Timestamp int64 `json:"timestamp"`
+
+	// TradingHalted Whether trading is halted; orders are rejected until it is released
+	TradingHalted bool `json:"tradingHalted"`
 }
 
 // Order defines model for Order.
@@ -240,21 +424,24 @@ type Order struct {
 	// CreatedAt Order creation timestamp
 	CreatedAt time.Time `json:"createdAt"`
 
-	// EstimatedTotal Estimated total cost in JPY (price * amount)
+	// EstimatedTotal Estimated total in JPY (price * amount)
 	EstimatedTotal float64 `json:"estimatedTotal"`
 
-	// OrderId Unique order identifier
-	OrderId openapi_types.UUID `json:"orderId"`
+	// OrderId Exchange order ID (child order acceptance ID), used by GET and DELETE /orders/{orderId}
+	OrderId string `json:"orderId"`
 
 	// OrderType Order type
 	OrderType OrderOrderType `json:"orderType"`
 
 	// Pair Trading pair
-	Pair OrderPair `json:"pair"`
+	Pair string `json:"pair"`
 
-	// Price Limit price in JPY
+	// Price Limit price in JPY (estimated from the ticker for market orders)
 	Price float64 `json:"price"`
 
+	// Side Order side
+	Side OrderSide `json:"side"`
+
 	// Status Order status
 	Status OrderStatus `json:"status"`
 
@@ -265,12 +452,75 @@ type Order struct {
 // OrderOrderType Order type
 type OrderOrderType string
 
-// OrderPair Trading pair
-type OrderPair string
+// OrderSide Order side
+type OrderSide string
 
 // OrderStatus Order status
 type OrderStatus string
 
+// OrderDetail defines model for OrderDetail.
+type OrderDetail struct {
+	// Amount Order size in cryptocurrency
+	Amount float64 `json:"amount"`
+
+	// AveragePrice Average execution price reported by the exchange
+	AveragePrice *float64 `json:"averagePrice,omitempty"`
+
+	// CreatedAt Order creation timestamp
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Exchange Exchange the order was placed on
+	Exchange string `json:"exchange"`
+
+	// ExchangeState Raw order state reported by the exchange (e.g., ACTIVE, COMPLETED)
+	ExchangeState *string `json:"exchangeState,omitempty"`
+
+	// ExecutedSize Size already executed on the exchange
+	ExecutedSize *float64 `json:"executedSize,omitempty"`
+
+	// OrderId Exchange order ID (child order acceptance ID)
+	OrderId string `json:"orderId"`
+
+	// OutstandingSize Size still resting on the exchange
+	OutstandingSize *float64 `json:"outstandingSize,omitempty"`
+
+	// Pair Trading pair
+	Pair string `json:"pair"`
+
+	// ParentOrderId Buy order ID this sell order was placed against
+	ParentOrderId *string `json:"parentOrderId,omitempty"`
+
+	// Price Limit price in JPY
+	Price float64 `json:"price"`
+
+	// Side Order side
+	Side OrderDetailSide `json:"side"`
+
+	// Status Order status
+	Status OrderDetailStatus `json:"status"`
+
+	// UpdatedAt Order last update timestamp
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// OrderDetailSide Order side
+type OrderDetailSide string
+
+// OrderDetailStatus Order status
+type OrderDetailStatus string
+
+// OrderListResponse defines model for OrderListResponse.
+type OrderListResponse struct {
+	// HasNext Whether there are more orders after this page
+	HasNext bool `json:"hasNext"`
+
+	// NextCursor Cursor to pass to fetch the next page
+	NextCursor *string `json:"nextCursor,omitempty"`
+
+	// Orders Array of orders, newest first
+	Orders []OrderDetail `json:"orders"`
+}
+
 // Pagination defines model for Pagination.
 type Pagination struct {
 	// CurrentPage Current page number
@@ -286,6 +536,154 @@ type Pagination struct {
 	TotalPages int `json:"total_pages"`
 }
 
+// PairCancellation defines model for PairCancellation.
+type PairCancellation struct {
+	// Cancelled Whether the exchange accepted the request to cancel every open order of the pair
+	Cancelled bool `json:"cancelled"`
+
+	// Message Why the orders could not be cancelled
+	Message *string `json:"message,omitempty"`
+	Pair    string  `json:"pair"`
+}
+
+// ParentOrder defines model for ParentOrder.
+type ParentOrder struct {
+	// CreatedAt Order creation timestamp
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Legs Order conditions of the special order
+	Legs []ParentOrderLeg `json:"legs"`
+
+	// Method Special order method
+	Method ParentOrderMethod `json:"method"`
+
+	// Pair Trading pair
+	Pair string `json:"pair"`
+
+	// ParentOrderId Exchange parent order ID (parent order acceptance ID)
+	ParentOrderId string `json:"parentOrderId"`
+
+	// Status Parent order status
+	Status ParentOrderStatus `json:"status"`
+}
+
+// ParentOrderMethod Special order method
+type ParentOrderMethod string
+
+// ParentOrderStatus Parent order status
+type ParentOrderStatus string
+
+// ParentOrderLeg defines model for ParentOrderLeg.
+type ParentOrderLeg struct {
+	// ConditionType Execution condition of the leg
+	ConditionType ParentOrderLegConditionType `json:"conditionType"`
+
+	// Offset Trailing width in JPY (required for TRAIL)
+	Offset *float64 `json:"offset,omitempty"`
+
+	// Pair Trading pair
+	Pair string `json:"pair"`
+
+	// Price Limit price in JPY (required for LIMIT and STOP_LIMIT)
+	Price *float64 `json:"price,omitempty"`
+
+	// Side Order side
+	Side ParentOrderLegSide `json:"side"`
+
+	// Size Order size in cryptocurrency
+	Size float64 `json:"size"`
+
+	// TriggerPrice Trigger price in JPY (required for STOP and STOP_LIMIT)
+	TriggerPrice *float64 `json:"triggerPrice,omitempty"`
+}
+
+// ParentOrderLegConditionType Execution condition of the leg
+type ParentOrderLegConditionType string
+
+// ParentOrderLegSide Order side
+type ParentOrderLegSide string
+
+// PriceUpdate Latest price of a cryptocurrency pushed by the price stream
+type PriceUpdate struct {
+	// Ask Best ask price in JPY
+	Ask float64 `json:"ask"`
+
+	// Bid Best bid price in JPY
+	Bid float64 `json:"bid"`
+
+	// Id Unique identifier for the cryptocurrency
+	Id string `json:"id"`
+
+	// Pair Trading pair
+	Pair string `json:"pair"`
+
+	// Price Last traded price in JPY
+	Price float64 `json:"price"`
+
+	// Symbol Trading symbol
+	Symbol string `json:"symbol"`
+
+	// Timestamp Unix timestamp of the price in milliseconds
+	Timestamp int64 `json:"timestamp"`
+}
+
+// ProductInfo Trading rules of the pair on the exchange it is traded on
+type ProductInfo struct {
+	// Exchange Exchange the pair is traded on
+	Exchange string `json:"exchange"`
+
+	// LotStep Smallest order size increment
+	LotStep float64 `json:"lotStep"`
+
+	// MinSize Minimum order size
+	MinSize float64 `json:"minSize"`
+
+	// PricePrecision Number of decimal places of prices
+	PricePrecision int `json:"pricePrecision"`
+
+	// TickSize Smallest price increment in JPY
+	TickSize float64 `json:"tickSize"`
+}
+
+// RateLimitBudget defines model for RateLimitBudget.
+type RateLimitBudget struct {
+	// Available Requests that can be sent right now without queueing (negative while calls are queued)
+	Available float64 `json:"available"`
+
+	// BlockedUntil Calls are held until this time after the exchange reported an exhausted budget or returned 429
+	BlockedUntil *time.Time `json:"blockedUntil,omitempty"`
+
+	// Category Group of endpoints sharing the budget (orders also count against private)
+	Category RateLimitBudgetCategory `json:"category"`
+
+	// Exchange Exchange the budget applies to
+	Exchange string `json:"exchange"`
+
+	// PeriodSeconds Configured period in seconds
+	PeriodSeconds int `json:"periodSeconds"`
+
+	// Remaining Remaining requests reported by the exchange in its rate limit headers
+	Remaining *int `json:"remaining,omitempty"`
+
+	// Requests Configured requests per period
+	Requests int `json:"requests"`
+
+	// ResetAt Time the exchange's rate limit window resets
+	ResetAt *time.Time `json:"resetAt,omitempty"`
+}
+
+// RateLimitBudgetCategory Group of endpoints sharing the budget (orders also count against private)
+type RateLimitBudgetCategory string
+
+// RateLimitResponse defines model for RateLimitResponse.
+type RateLimitResponse struct {
+	// Data Request budget of every rate limit category
+	Data []RateLimitBudget `json:"data"`
+
+	// Timestamp Unix timestamp of the response
+	Timestamp int64 `json:"timestamp"`
+}
+
 // TradeStatistics defines model for TradeStatistics.
 type TradeStatistics struct {
 	// ExecutionCount Total number of executed trades
@@ -304,6 +702,18 @@ type TradeStatistics struct {
 // TradeStatisticsPeriod Time period for the statistics
 type TradeStatisticsPeriod string
 
+// TradingHalt defines model for TradingHalt.
+type TradingHalt struct {
+	// Halted Whether trading is halted
+	Halted bool `json:"halted"`
+
+	// Reason Why trading was halted
+	Reason *string `json:"reason,omitempty"`
+
+	// UpdatedAt When the halt was last engaged or released; absent if it never was
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
 // Transaction defines model for Transaction.
 type Transaction struct {
 	// Amount Amount of cryptocurrency traded
@@ -316,7 +726,7 @@ type Transaction struct {
 	BuyPrice float64 `json:"buy_price"`
 
 	// Cryptocurrency Cryptocurrency name
-	Cryptocurrency TransactionCryptocurrency `json:"cryptocurrency"`
+	Cryptocurrency string `json:"cryptocurrency"`
 
 	// Id Unique transaction identifier
 	Id string `json:"id"`
@@ -337,9 +747,6 @@ type Transaction struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
-// TransactionCryptocurrency Cryptocurrency name
-type TransactionCryptocurrency string
-
 // TransactionOrderType Order type (always sell for completed trades)
 type TransactionOrderType string
 
@@ -351,6 +758,32 @@ type TransactionLogResponse struct {
 	Transactions []Transaction `json:"transactions"`
 }
 
+// UpdateTradingHaltRequest defines model for UpdateTradingHaltRequest.
+type UpdateTradingHaltRequest struct {
+	// CancelOpenOrders Also cancel the open orders of every listed pair on its exchange
+	CancelOpenOrders *bool `json:"cancelOpenOrders,omitempty"`
+
+	// Halted true engages the halt, false releases it
+	Halted bool `json:"halted"`
+
+	// Reason Why trading is halted or released
+	Reason *string `json:"reason,omitempty"`
+}
+
+// UpdateTradingHaltResponse defines model for UpdateTradingHaltResponse.
+type UpdateTradingHaltResponse struct {
+	// Cancellations Result of cancelling the open orders of each pair, when cancelOpenOrders was set
+	Cancellations *[]PairCancellation `json:"cancellations,omitempty"`
+	TradingHalt   TradingHalt         `json:"tradingHalt"`
+}
+
+// CreateOrderParams defines parameters for CreateOrder.
+type CreateOrderParams struct {
+	// IdempotencyKey Client-chosen key identifying the order (e.g. a UUID). Keys expire after a configured
+	// window (24 hours by default); within it, reusing a key with a different body fails with 422.
+	IdempotencyKey *string `json:"Idempotency-Key,omitempty"`
+}
+
 // GetCryptoChartParams defines parameters for GetCryptoChart.
 type GetCryptoChartParams struct {
 	// Period Time period for chart data
@@ -360,25 +793,28 @@ type GetCryptoChartParams struct {
 // GetCryptoChartParamsPeriod defines parameters for GetCryptoChart.
 type GetCryptoChartParamsPeriod string
 
+// GetPriceStreamParams defines parameters for GetPriceStream.
+type GetPriceStreamParams struct {
+	// Ids Comma-separated cryptocurrency IDs to stream; every listed cryptocurrency if omitted
+	Ids *string `form:"ids,omitempty" json:"ids,omitempty"`
+}
+
 // GetTradeStatisticsParams defines parameters for GetTradeStatistics.
 type GetTradeStatisticsParams struct {
 	// AssetFilter Filter by cryptocurrency asset
-	AssetFilter *GetTradeStatisticsParamsAssetFilter `form:"asset_filter,omitempty" json:"asset_filter,omitempty"`
+	AssetFilter *string `form:"asset_filter,omitempty" json:"asset_filter,omitempty"`
 
 	// TimeFilter Filter by time period
 	TimeFilter *GetTradeStatisticsParamsTimeFilter `form:"time_filter,omitempty" json:"time_filter,omitempty"`
 }
 
-// GetTradeStatisticsParamsAssetFilter defines parameters for GetTradeStatistics.
-type GetTradeStatisticsParamsAssetFilter string
-
 // GetTradeStatisticsParamsTimeFilter defines parameters for GetTradeStatistics.
 type GetTradeStatisticsParamsTimeFilter string
 
 // GetTradeTransactionsParams defines parameters for GetTradeTransactions.
 type GetTradeTransactionsParams struct {
 	// AssetFilter Filter by cryptocurrency asset
-	AssetFilter *GetTradeTransactionsParamsAssetFilter `form:"asset_filter,omitempty" json:"asset_filter,omitempty"`
+	AssetFilter *string `form:"asset_filter,omitempty" json:"asset_filter,omitempty"`
 
 	// TimeFilter Filter by time period
 	TimeFilter *GetTradeTransactionsParamsTimeFilter `form:"time_filter,omitempty" json:"time_filter,omitempty"`
@@ -390,11 +826,44 @@ type GetTradeTransactionsParams struct {
 	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
-// GetTradeTransactionsParamsAssetFilter defines parameters for GetTradeTransactions.
-type GetTradeTransactionsParamsAssetFilter string
-
 // GetTradeTransactionsParamsTimeFilter defines parameters for GetTradeTransactions.
 type GetTradeTransactionsParamsTimeFilter string
 
+// ListOrdersParams defines parameters for ListOrders.
+type ListOrdersParams struct {
+	// Pair Filter by trading pair
+	Pair *string `form:"pair,omitempty" json:"pair,omitempty"`
+
+	// Side Filter by order side
+	Side *ListOrdersParamsSide `form:"side,omitempty" json:"side,omitempty"`
+
+	// Status Filter by order status
+	Status *ListOrdersParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// From Only orders created at or after this time
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Only orders created before this time
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+
+	// Cursor Cursor returned by the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit Number of orders per page
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListOrdersParamsSide defines parameters for ListOrders.
+type ListOrdersParamsSide string
+
+// ListOrdersParamsStatus defines parameters for ListOrders.
+type ListOrdersParamsStatus string
+
 // CreateOrderJSONRequestBody defines body for CreateOrder for application/json ContentType.
 type CreateOrderJSONRequestBody = CreateOrderRequest
+
+// CreateParentOrderJSONRequestBody defines body for CreateParentOrder for application/json ContentType.
+type CreateParentOrderJSONRequestBody = CreateParentOrderRequest
+
+// UpdateTradingHaltJSONRequestBody defines body for UpdateTradingHalt for application/json ContentType.
+type UpdateTradingHaltJSONRequestBody = UpdateTradingHaltRequest
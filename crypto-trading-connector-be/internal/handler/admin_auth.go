@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/labstack/echo/v4"
+)
+
+// adminPathPrefix is the path of the admin routes, which are only served to callers holding the admin token
+const adminPathPrefix = "/api/v1/admin"
+
+// AdminAuth returns a middleware letting through the requests carrying "Authorization: Bearer <token>".
+// Every request is rejected when token is empty, so that the admin routes are closed until a token is set.
+func AdminAuth(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			provided, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if token == "" || !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				return handleError(c, http.StatusUnauthorized, generated.UNAUTHORIZED, "A valid admin token is required")
+			}
+			return next(c)
+		}
+	}
+}
+
+// IsAdminPath reports whether a request is for an admin route
+func IsAdminPath(c echo.Context) bool {
+	return strings.HasPrefix(c.Request().URL.Path, adminPathPrefix+"/")
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		expected      int
+	}{
+		{name: "valid token", token: "s3cret", authorization: "Bearer s3cret", expected: http.StatusOK},
+		{name: "missing token", token: "s3cret", authorization: "", expected: http.StatusUnauthorized},
+		{name: "wrong token", token: "s3cret", authorization: "Bearer guess", expected: http.StatusUnauthorized},
+		{name: "wrong scheme", token: "s3cret", authorization: "Basic s3cret", expected: http.StatusUnauthorized},
+		{name: "no token configured", token: "", authorization: "Bearer ", expected: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.GET("/api/v1/admin/trading-halt", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}, AdminAuth(tt.token))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/trading-halt", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expected {
+				t.Errorf("expected status %d, got %d", tt.expected, rec.Code)
+			}
+		})
+	}
+}
+
+func TestIsAdminPath_SkipsWildcardCORS(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		Skipper:      IsAdminPath,
+		AllowOrigins: []string{"*"},
+	}))
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/api/v1/admin/trading-halt", ok)
+	e.GET("/api/v1/orders", ok)
+
+	for path, allowed := range map[string]bool{"/api/v1/admin/trading-halt": false, "/api/v1/orders": true} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(echo.HeaderOrigin, "https://evil.example")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get(echo.HeaderAccessControlAllowOrigin) != ""; got != allowed {
+			t.Errorf("%s: expected cross-origin access %v, got %v", path, allowed, got)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/crypto-trading-connector/backend/internal/generated"
@@ -22,9 +23,9 @@ func NewCryptoHandler(service service.CryptoService) *CryptoHandler {
 
 // GetMarketData handles GET /api/v1/crypto/market
 func (h *CryptoHandler) GetMarketData(c echo.Context) error {
-	marketData, err := h.service.GetMarketData()
+	marketData, err := h.service.GetMarketData(c.Request().Context())
 	if err != nil {
-		return handleError(c, http.StatusInternalServerError, generated.INTERNALSERVERERROR, err.Error())
+		return handleServiceError(c, err, err.Error())
 	}
 
 	return c.JSON(http.StatusOK, marketData)
@@ -40,13 +41,12 @@ func (h *CryptoHandler) GetCryptoByID(c echo.Context) error {
 	// Get optional period parameter (defaults to 7d in service layer)
 	period := c.QueryParam("period")
 
-	cryptoData, err := h.service.GetCryptoByID(id, period)
+	cryptoData, err := h.service.GetCryptoByID(c.Request().Context(), id, period)
 	if err != nil {
-		// Check if it's a not found error
-		if err.Error() == "cryptocurrency not found: "+id {
+		if errors.Is(err, service.ErrCryptoNotFound) {
 			return handleError(c, http.StatusNotFound, generated.NOTFOUND, "Cryptocurrency not found")
 		}
-		return handleError(c, http.StatusInternalServerError, generated.INTERNALSERVERERROR, err.Error())
+		return handleServiceError(c, err, err.Error())
 	}
 
 	return c.JSON(http.StatusOK, cryptoData)
@@ -62,13 +62,12 @@ func (h *CryptoHandler) GetChartData(c echo.Context) error {
 	period := c.QueryParam("period")
 	// Default period is handled in service layer
 
-	chartData, err := h.service.GetChartData(id, period)
+	chartData, err := h.service.GetChartData(c.Request().Context(), id, period)
 	if err != nil {
-		// Check if it's a not found error
-		if err.Error() == "cryptocurrency not found: "+id {
+		if errors.Is(err, service.ErrCryptoNotFound) {
 			return handleError(c, http.StatusNotFound, generated.NOTFOUND, "Cryptocurrency not found")
 		}
-		return handleError(c, http.StatusInternalServerError, generated.INTERNALSERVERERROR, err.Error())
+		return handleServiceError(c, err, err.Error())
 	}
 
 	return c.JSON(http.StatusOK, chartData)
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/labstack/echo/v4"
+)
+
+// DebugHandler handles HTTP requests for debug endpoints
+type DebugHandler struct {
+	rateLimits client.RateLimitReporter
+}
+
+// NewDebugHandler creates a new debug handler reporting the request budgets of the exchange clients
+func NewDebugHandler(rateLimits client.RateLimitReporter) *DebugHandler {
+	return &DebugHandler{
+		rateLimits: rateLimits,
+	}
+}
+
+// GetRateLimits handles GET /api/v1/debug/rate-limits
+func (h *DebugHandler) GetRateLimits(c echo.Context) error {
+	budgets := h.rateLimits.RateLimitBudgets()
+
+	response := generated.RateLimitResponse{
+		Data:      make([]generated.RateLimitBudget, 0, len(budgets)),
+		Timestamp: time.Now().Unix(),
+	}
+	for _, budget := range budgets {
+		response.Data = append(response.Data, generated.RateLimitBudget{
+			Exchange:      budget.Exchange,
+			Category:      generated.RateLimitBudgetCategory(budget.Category),
+			Requests:      budget.Requests,
+			PeriodSeconds: int(budget.Period.Seconds()),
+			Available:     budget.Available,
+			Remaining:     budget.Remaining,
+			ResetAt:       budget.ResetAt,
+			BlockedUntil:  budget.BlockedUntil,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
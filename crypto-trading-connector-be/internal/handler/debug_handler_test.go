@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/labstack/echo/v4"
+)
+
+// stubRateLimitReporter returns fixed request budgets
+type stubRateLimitReporter []client.RateLimitBudget
+
+func (s stubRateLimitReporter) RateLimitBudgets() []client.RateLimitBudget {
+	return s
+}
+
+func TestDebugHandler_GetRateLimits(t *testing.T) {
+	remaining := 480
+	handler := NewDebugHandler(stubRateLimitReporter{
+		{Exchange: "bitflyer", Category: client.RateLimitPrivate, Requests: 500, Period: 5 * time.Minute, Available: 18.5, Remaining: &remaining},
+	})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/debug/rate-limits", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.GetRateLimits(c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var response generated.RateLimitResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(response.Data) != 1 {
+		t.Fatalf("expected 1 budget, got %d", len(response.Data))
+	}
+
+	budget := response.Data[0]
+	if budget.Exchange != "bitflyer" || budget.Category != generated.RateLimitBudgetCategoryPrivate || budget.PeriodSeconds != 300 ||
+		budget.Available != 18.5 || budget.Remaining == nil || *budget.Remaining != 480 || budget.BlockedUntil != nil {
+		t.Errorf("unexpected budget: %+v", budget)
+	}
+}
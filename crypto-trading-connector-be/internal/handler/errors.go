@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// errorResponse maps an error returned by a service to the HTTP status and error code of the response.
+// Domain errors of the service package and exchange errors of the client package are both recognized;
+// ok is false for any other error, which is an internal error.
+func errorResponse(err error) (status int, code generated.ErrorResponseError, ok bool) {
+	var validationErr *service.ValidationError
+
+	switch {
+	case errors.Is(err, client.ErrRateLimited):
+		return http.StatusTooManyRequests, generated.RATELIMITED, true
+	case errors.Is(err, client.ErrExchangeUnavailable), errors.Is(err, client.ErrMaintenance):
+		return http.StatusServiceUnavailable, generated.EXCHANGEUNAVAILABLE, true
+	case errors.Is(err, client.ErrAuthFailed):
+		return http.StatusUnauthorized, generated.UNAUTHORIZED, true
+	case errors.Is(err, service.ErrInsufficientBalance), errors.Is(err, client.ErrInsufficientFunds):
+		return http.StatusPaymentRequired, generated.INSUFFICIENTBALANCE, true
+	case errors.Is(err, service.ErrTradingHalted):
+		return http.StatusLocked, generated.TRADINGHALTED, true
+	case errors.Is(err, service.ErrRiskPairNotAllowed):
+		return http.StatusForbidden, generated.RISKPAIRNOTALLOWED, true
+	case errors.Is(err, service.ErrRiskMaxNotional):
+		return http.StatusForbidden, generated.RISKMAXNOTIONAL, true
+	case errors.Is(err, service.ErrRiskPriceBand):
+		return http.StatusForbidden, generated.RISKPRICEBAND, true
+	case errors.Is(err, service.ErrRiskMaxOpenOrders):
+		return http.StatusForbidden, generated.RISKMAXOPENORDERS, true
+	case errors.Is(err, service.ErrRiskDailyVolume):
+		return http.StatusForbidden, generated.RISKDAILYVOLUME, true
+	case errors.Is(err, service.ErrInvalidPrice), errors.Is(err, client.ErrInvalidPrice):
+		return http.StatusBadRequest, generated.INVALIDPRICE, true
+	case errors.Is(err, service.ErrInvalidAmount), errors.Is(err, client.ErrInvalidSize):
+		return http.StatusBadRequest, generated.INVALIDAMOUNT, true
+	case errors.Is(err, service.ErrUnsupportedPair):
+		return http.StatusBadRequest, generated.UNSUPPORTEDPAIR, true
+	case errors.Is(err, service.ErrInvalidFilter):
+		return http.StatusBadRequest, generated.INVALIDFILTER, true
+	case errors.Is(err, service.ErrInvalidPagination):
+		return http.StatusBadRequest, generated.INVALIDPAGINATION, true
+	case errors.As(err, &validationErr), errors.Is(err, client.ErrNotSupported):
+		return http.StatusBadRequest, generated.INVALIDREQUEST, true
+	case errors.Is(err, service.ErrOrderNotFound), errors.Is(err, service.ErrCryptoNotFound):
+		return http.StatusNotFound, generated.NOTFOUND, true
+	case errors.Is(err, service.ErrOrderNotCancellable):
+		return http.StatusConflict, generated.ORDERNOTCANCELLABLE, true
+	case errors.Is(err, service.ErrOrderInProgress):
+		return http.StatusConflict, generated.ORDERINPROGRESS, true
+	case errors.Is(err, service.ErrIdempotencyKeyReused):
+		return http.StatusUnprocessableEntity, generated.IDEMPOTENCYKEYREUSED, true
+	}
+
+	return 0, "", false
+}
+
+// handleServiceError writes the error response of a service error, using internalMessage
+// for internal errors so that their details are not exposed
+func handleServiceError(c echo.Context, err error, internalMessage string) error {
+	if status, code, ok := errorResponse(err); ok {
+		return handleError(c, status, code, err.Error())
+	}
+	return handleError(c, http.StatusInternalServerError, generated.INTERNALSERVERERROR, internalMessage)
+}
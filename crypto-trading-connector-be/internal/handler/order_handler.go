@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
-	"strings"
+	"strconv"
+	"time"
 
 	"github.com/crypto-trading-connector/backend/internal/generated"
 	"github.com/crypto-trading-connector/backend/internal/service"
@@ -33,8 +35,14 @@ func (h *OrderHandler) CreateOrder(c echo.Context) error {
 		return handleError(c, http.StatusBadRequest, generated.INVALIDREQUEST, err.Error())
 	}
 
+	// A retried request carries the Idempotency-Key of the first one
+	params := &generated.CreateOrderParams{}
+	if key := c.Request().Header.Get("Idempotency-Key"); key != "" {
+		params.IdempotencyKey = &key
+	}
+
 	// Create order
-	order, err := h.orderService.CreateOrder(&req)
+	order, err := h.orderService.CreateOrder(c.Request().Context(), params, &req)
 	if err != nil {
 		return h.handleOrderError(c, err)
 	}
@@ -42,51 +50,154 @@ func (h *OrderHandler) CreateOrder(c echo.Context) error {
 	return c.JSON(http.StatusCreated, order)
 }
 
+// ListOrders handles GET /api/v1/orders
+func (h *OrderHandler) ListOrders(c echo.Context) error {
+	params, err := parseListOrdersParams(c)
+	if err != nil {
+		return h.handleListOrdersError(c, err)
+	}
+
+	orders, err := h.orderService.ListOrders(c.Request().Context(), params)
+	if err != nil {
+		return h.handleListOrdersError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, orders)
+}
+
+// GetOrder handles GET /api/v1/orders/:id
+func (h *OrderHandler) GetOrder(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return handleError(c, http.StatusBadRequest, generated.BADREQUEST, "order ID is required")
+	}
+
+	order, err := h.orderService.GetOrder(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			return handleError(c, http.StatusNotFound, generated.NOTFOUND, "Order not found")
+		}
+		return handleServiceError(c, err, "Failed to get order")
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// CancelOrder handles DELETE /api/v1/orders/:id
+func (h *OrderHandler) CancelOrder(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return handleError(c, http.StatusBadRequest, generated.BADREQUEST, "order ID is required")
+	}
+
+	result, err := h.orderService.CancelOrder(c.Request().Context(), id)
+	if err != nil {
+		return h.handleCancelOrderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
 // GetBalance handles GET /api/v1/balance
 func (h *OrderHandler) GetBalance(c echo.Context) error {
-	balance, err := h.orderService.GetBalance()
+	balance, err := h.orderService.GetBalance(c.Request().Context())
 	if err != nil {
-		return handleError(c, http.StatusInternalServerError, generated.INTERNALSERVERERROR, "Failed to get balance")
+		return handleServiceError(c, err, "Failed to get balance")
 	}
 
 	return c.JSON(http.StatusOK, balance)
 }
 
+// GetBalances handles GET /api/v1/balances
+func (h *OrderHandler) GetBalances(c echo.Context) error {
+	balances, err := h.orderService.GetBalances(c.Request().Context())
+	if err != nil {
+		return handleServiceError(c, err, "Failed to get balances")
+	}
+
+	return c.JSON(http.StatusOK, balances)
+}
+
 // validateCreateOrderRequest validates the create order request
 func validateCreateOrderRequest(req *generated.CreateOrderRequest) error {
-	if req.Price <= 0 {
+	if req.OrderType != generated.CreateOrderRequestOrderTypeLimit && req.OrderType != generated.CreateOrderRequestOrderTypeMarket {
+		return echo.NewHTTPError(http.StatusBadRequest, "unsupported order type")
+	}
+	if req.OrderType == generated.CreateOrderRequestOrderTypeLimit && req.Price <= 0 {
 		return echo.NewHTTPError(http.StatusBadRequest, "price must be greater than 0")
 	}
+	if req.Side != "" && req.Side != generated.CreateOrderRequestSideBUY && req.Side != generated.CreateOrderRequestSideSELL {
+		return echo.NewHTTPError(http.StatusBadRequest, "unsupported side")
+	}
 	if req.Amount <= 0 {
 		return echo.NewHTTPError(http.StatusBadRequest, "amount must be greater than 0")
 	}
-	if req.Pair != generated.CreateOrderRequestPairBTCJPY && req.Pair != generated.CreateOrderRequestPairETHJPY {
-		return echo.NewHTTPError(http.StatusBadRequest, "unsupported pair")
-	}
-	if req.OrderType != generated.CreateOrderRequestOrderTypeLimit {
-		return echo.NewHTTPError(http.StatusBadRequest, "unsupported order type")
-	}
 	return nil
 }
 
-// handleOrderError handles errors from order service
-func (h *OrderHandler) handleOrderError(c echo.Context, err error) error {
-	errMsg := err.Error()
+// parseListOrdersParams reads the query parameters of GET /api/v1/orders
+func parseListOrdersParams(c echo.Context) (*generated.ListOrdersParams, error) {
+	params := &generated.ListOrdersParams{}
 
-	// Check for specific error types
-	if strings.Contains(errMsg, "insufficient balance") {
-		return handleError(c, http.StatusPaymentRequired, generated.INSUFFICIENTBALANCE, errMsg)
+	if pair := c.QueryParam("pair"); pair != "" {
+		params.Pair = &pair
 	}
-	if strings.Contains(errMsg, "invalid price") {
-		return handleError(c, http.StatusBadRequest, generated.INVALIDPRICE, errMsg)
+
+	if side := c.QueryParam("side"); side != "" {
+		s := generated.ListOrdersParamsSide(side)
+		params.Side = &s
 	}
-	if strings.Contains(errMsg, "invalid amount") {
-		return handleError(c, http.StatusBadRequest, generated.INVALIDAMOUNT, errMsg)
+
+	if status := c.QueryParam("status"); status != "" {
+		s := generated.ListOrdersParamsStatus(status)
+		params.Status = &s
 	}
-	if strings.Contains(errMsg, "unsupported pair") {
-		return handleError(c, http.StatusBadRequest, generated.UNSUPPORTEDPAIR, errMsg)
+
+	if from := c.QueryParam("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, &service.ValidationError{Kind: service.ErrInvalidFilter, Message: "invalid time filter: from must be an RFC 3339 timestamp"}
+		}
+		params.From = &t
+	}
+
+	if to := c.QueryParam("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, &service.ValidationError{Kind: service.ErrInvalidFilter, Message: "invalid time filter: to must be an RFC 3339 timestamp"}
+		}
+		params.To = &t
 	}
 
-	// Default to internal server error
-	return handleError(c, http.StatusInternalServerError, generated.INTERNALSERVERERROR, "Failed to create order")
+	if cursor := c.QueryParam("cursor"); cursor != "" {
+		params.Cursor = &cursor
+	}
+
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return nil, &service.ValidationError{Kind: service.ErrInvalidPagination, Message: "invalid limit: " + limitStr}
+		}
+		params.Limit = &limit
+	}
+
+	return params, nil
+}
+
+// handleOrderError handles errors from order service
+func (h *OrderHandler) handleOrderError(c echo.Context, err error) error {
+	return handleServiceError(c, err, "Failed to create order")
+}
+
+// handleCancelOrderError handles errors from order cancellation
+func (h *OrderHandler) handleCancelOrderError(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrOrderNotFound) {
+		return handleError(c, http.StatusNotFound, generated.NOTFOUND, "Order not found")
+	}
+	return handleServiceError(c, err, "Failed to cancel order")
+}
+
+// handleListOrdersError handles errors from order listing
+func (h *OrderHandler) handleListOrdersError(c echo.Context, err error) error {
+	return handleServiceError(c, err, "Failed to list orders")
 }
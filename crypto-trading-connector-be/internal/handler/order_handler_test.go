@@ -1,45 +1,80 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/crypto-trading-connector/backend/internal/client"
 	"github.com/crypto-trading-connector/backend/internal/generated"
-	"github.com/google/uuid"
+	"github.com/crypto-trading-connector/backend/internal/service"
 	"github.com/labstack/echo/v4"
-	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 // MockOrderService is a mock implementation of OrderService for testing
 type MockOrderService struct {
-	CreateOrderFunc func(req *generated.CreateOrderRequest) (*generated.Order, error)
-	GetBalanceFunc  func() (*generated.Balance, error)
+	CreateOrderFunc func(ctx context.Context, params *generated.CreateOrderParams, req *generated.CreateOrderRequest) (*generated.Order, error)
+	GetBalanceFunc  func(ctx context.Context) (*generated.Balance, error)
+	GetBalancesFunc func(ctx context.Context) (*generated.BalancesResponse, error)
+	CancelOrderFunc func(ctx context.Context, orderID string) (*generated.CancelOrderResponse, error)
+	ListOrdersFunc  func(ctx context.Context, params *generated.ListOrdersParams) (*generated.OrderListResponse, error)
+	GetOrderFunc    func(ctx context.Context, orderID string) (*generated.OrderDetail, error)
 }
 
-func (m *MockOrderService) CreateOrder(req *generated.CreateOrderRequest) (*generated.Order, error) {
+func (m *MockOrderService) CreateOrder(ctx context.Context, params *generated.CreateOrderParams, req *generated.CreateOrderRequest) (*generated.Order, error) {
 	if m.CreateOrderFunc != nil {
-		return m.CreateOrderFunc(req)
+		return m.CreateOrderFunc(ctx, params, req)
 	}
 	return nil, errors.New("not implemented")
 }
 
-func (m *MockOrderService) GetBalance() (*generated.Balance, error) {
+func (m *MockOrderService) GetBalance(ctx context.Context) (*generated.Balance, error) {
 	if m.GetBalanceFunc != nil {
-		return m.GetBalanceFunc()
+		return m.GetBalanceFunc(ctx)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockOrderService) GetBalances(ctx context.Context) (*generated.BalancesResponse, error) {
+	if m.GetBalancesFunc != nil {
+		return m.GetBalancesFunc(ctx)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockOrderService) CancelOrder(ctx context.Context, orderID string) (*generated.CancelOrderResponse, error) {
+	if m.CancelOrderFunc != nil {
+		return m.CancelOrderFunc(ctx, orderID)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockOrderService) ListOrders(ctx context.Context, params *generated.ListOrdersParams) (*generated.OrderListResponse, error) {
+	if m.ListOrdersFunc != nil {
+		return m.ListOrdersFunc(ctx, params)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockOrderService) GetOrder(ctx context.Context, orderID string) (*generated.OrderDetail, error) {
+	if m.GetOrderFunc != nil {
+		return m.GetOrderFunc(ctx, orderID)
 	}
 	return nil, errors.New("not implemented")
 }
 
 func TestOrderHandler_CreateOrder_Success(t *testing.T) {
 	mockService := &MockOrderService{
-		CreateOrderFunc: func(req *generated.CreateOrderRequest) (*generated.Order, error) {
+		CreateOrderFunc: func(ctx context.Context, params *generated.CreateOrderParams, req *generated.CreateOrderRequest) (*generated.Order, error) {
 			return &generated.Order{
-				OrderId:        openapi_types.UUID(uuid.New()),
-				Pair:           generated.OrderPairBTCJPY,
+				OrderId:        "JRF20240101-000000-000001",
+				Pair:           "BTC/JPY",
 				OrderType:      generated.OrderOrderTypeLimit,
 				Price:          14000000,
 				Amount:         0.001,
@@ -125,8 +160,8 @@ func TestOrderHandler_CreateOrder_InvalidPrice(t *testing.T) {
 
 func TestOrderHandler_CreateOrder_InsufficientBalance(t *testing.T) {
 	mockService := &MockOrderService{
-		CreateOrderFunc: func(req *generated.CreateOrderRequest) (*generated.Order, error) {
-			return nil, errors.New("insufficient balance: required 14000.00, available 10000.00")
+		CreateOrderFunc: func(ctx context.Context, params *generated.CreateOrderParams, req *generated.CreateOrderRequest) (*generated.Order, error) {
+			return nil, fmt.Errorf("%w: required 14000.00, available 10000.00", service.ErrInsufficientBalance)
 		},
 	}
 
@@ -150,9 +185,132 @@ func TestOrderHandler_CreateOrder_InsufficientBalance(t *testing.T) {
 	// Service error would be caught by Echo's middleware
 }
 
+func TestOrderHandler_CreateOrder_RateLimited(t *testing.T) {
+	mockService := &MockOrderService{
+		CreateOrderFunc: func(ctx context.Context, params *generated.CreateOrderParams, req *generated.CreateOrderRequest) (*generated.Order, error) {
+			return nil, fmt.Errorf("failed to send order to exchange: failed to call bitFlyer API: %w", &client.RateLimitError{Exchange: "bitflyer", Category: client.RateLimitOrder, RetryAfter: 12 * time.Second})
+		},
+	}
+
+	handler := NewOrderHandler(mockService)
+	e := echo.New()
+
+	reqBody := `{"pair": "BTC/JPY", "orderType": "limit", "price": 14000000, "amount": 0.001}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.CreateOrder(c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status %d, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+
+	var errResp generated.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if errResp.Error != generated.RATELIMITED {
+		t.Errorf("expected error %s, got %s", generated.RATELIMITED, errResp.Error)
+	}
+}
+
+func TestOrderHandler_CreateOrder_IdempotencyKey(t *testing.T) {
+	var gotKey *string
+	mockService := &MockOrderService{
+		CreateOrderFunc: func(ctx context.Context, params *generated.CreateOrderParams, req *generated.CreateOrderRequest) (*generated.Order, error) {
+			gotKey = params.IdempotencyKey
+			return nil, fmt.Errorf("%w: the order of key-1 is being placed or its state is unknown; check the order list", service.ErrOrderInProgress)
+		},
+	}
+
+	handler := NewOrderHandler(mockService)
+	e := echo.New()
+
+	reqBody := `{"pair": "BTC/JPY", "orderType": "limit", "price": 14000000, "amount": 0.001}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Idempotency-Key", "key-1")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.CreateOrder(c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if gotKey == nil || *gotKey != "key-1" {
+		t.Errorf("expected the idempotency key to be passed to the service, got %v", gotKey)
+	}
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, rec.Code)
+	}
+
+	var errResp generated.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if errResp.Error != generated.ORDERINPROGRESS {
+		t.Errorf("expected error code %s, got %s", generated.ORDERINPROGRESS, errResp.Error)
+	}
+}
+
+func TestOrderHandler_CreateOrder_RiskRejected(t *testing.T) {
+	tests := []struct {
+		kind     error
+		wantCode generated.ErrorResponseError
+	}{
+		{service.ErrRiskPairNotAllowed, generated.RISKPAIRNOTALLOWED},
+		{service.ErrRiskMaxNotional, generated.RISKMAXNOTIONAL},
+		{service.ErrRiskPriceBand, generated.RISKPRICEBAND},
+		{service.ErrRiskMaxOpenOrders, generated.RISKMAXOPENORDERS},
+		{service.ErrRiskDailyVolume, generated.RISKDAILYVOLUME},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.wantCode), func(t *testing.T) {
+			mockService := &MockOrderService{
+				CreateOrderFunc: func(ctx context.Context, params *generated.CreateOrderParams, req *generated.CreateOrderRequest) (*generated.Order, error) {
+					return nil, &service.RiskError{Rule: "test", Kind: tt.kind, Message: "rejected by a risk limit"}
+				},
+			}
+
+			handler := NewOrderHandler(mockService)
+			e := echo.New()
+
+			reqBody := `{"pair": "BTC/JPY", "orderType": "limit", "price": 14000000, "amount": 0.001}`
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if err := handler.CreateOrder(c); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("expected status %d, got %d", http.StatusForbidden, rec.Code)
+			}
+
+			var errResp generated.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if errResp.Error != tt.wantCode {
+				t.Errorf("expected error code %s, got %s", tt.wantCode, errResp.Error)
+			}
+		})
+	}
+}
+
 func TestOrderHandler_GetBalance_Success(t *testing.T) {
 	mockService := &MockOrderService{
-		GetBalanceFunc: func() (*generated.Balance, error) {
+		GetBalanceFunc: func(ctx context.Context) (*generated.Balance, error) {
 			return &generated.Balance{
 				AvailableBalance: 1540200,
 				Currency:         generated.JPY,
@@ -188,7 +346,7 @@ func TestOrderHandler_GetBalance_Success(t *testing.T) {
 
 func TestOrderHandler_GetBalance_Error(t *testing.T) {
 	mockService := &MockOrderService{
-		GetBalanceFunc: func() (*generated.Balance, error) {
+		GetBalanceFunc: func(ctx context.Context) (*generated.Balance, error) {
 			return nil, errors.New("balance fetch error")
 		},
 	}
@@ -205,6 +363,176 @@ func TestOrderHandler_GetBalance_Error(t *testing.T) {
 	// Service error would be caught by Echo's middleware
 }
 
+func TestOrderHandler_GetBalance_ExchangeUnavailable(t *testing.T) {
+	mockService := &MockOrderService{
+		GetBalanceFunc: func(ctx context.Context) (*generated.Balance, error) {
+			return nil, fmt.Errorf("failed to get balance from exchange: %w", &client.ExchangeUnavailableError{Exchange: "bitflyer", RetryAfter: 25 * time.Second})
+		},
+	}
+
+	handler := NewOrderHandler(mockService)
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/balance", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.GetBalance(c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+
+	var errResp generated.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if errResp.Error != generated.EXCHANGEUNAVAILABLE {
+		t.Errorf("expected error %s, got %s", generated.EXCHANGEUNAVAILABLE, errResp.Error)
+	}
+}
+
+func TestOrderHandler_GetBalances_Success(t *testing.T) {
+	mockService := &MockOrderService{
+		GetBalancesFunc: func(ctx context.Context) (*generated.BalancesResponse, error) {
+			return &generated.BalancesResponse{
+				Balances: []generated.CurrencyBalance{
+					{Currency: "JPY", Amount: 1540200, Available: 1500000},
+					{Currency: "BTC", Amount: 0.015, Available: 0.01},
+				},
+				Timestamp: 1704067200,
+			}, nil
+		},
+	}
+
+	handler := NewOrderHandler(mockService)
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/balances", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.GetBalances(c)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+
+	var response generated.BalancesResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Errorf("failed to unmarshal response: %v", err)
+	}
+	if len(response.Balances) != 2 {
+		t.Fatalf("expected 2 balances, got %d", len(response.Balances))
+	}
+	if response.Balances[1].Currency != "BTC" || response.Balances[1].Available != 0.01 {
+		t.Errorf("unexpected BTC balance: %+v", response.Balances[1])
+	}
+}
+
+func TestOrderHandler_GetBalances_Error(t *testing.T) {
+	mockService := &MockOrderService{
+		GetBalancesFunc: func(ctx context.Context) (*generated.BalancesResponse, error) {
+			return nil, errors.New("balance fetch error")
+		},
+	}
+
+	handler := NewOrderHandler(mockService)
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/balances", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	_ = handler.GetBalances(c)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", rec.Code)
+	}
+}
+
+func TestOrderHandler_CancelOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		serviceErr   error
+		expectedCode int
+		expectedType generated.ErrorResponseError
+	}{
+		{
+			name:         "cancelled successfully",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "order not found",
+			serviceErr:   fmt.Errorf("%w: JRF-UNKNOWN", service.ErrOrderNotFound),
+			expectedCode: http.StatusNotFound,
+			expectedType: generated.NOTFOUND,
+		},
+		{
+			name:         "order already filled",
+			serviceErr:   fmt.Errorf("%w: status is FILLED", service.ErrOrderNotCancellable),
+			expectedCode: http.StatusConflict,
+			expectedType: generated.ORDERNOTCANCELLABLE,
+		},
+		{
+			name:         "exchange error",
+			serviceErr:   errors.New("failed to cancel order on exchange: timeout"),
+			expectedCode: http.StatusInternalServerError,
+			expectedType: generated.INTERNALSERVERERROR,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedID string
+			mockService := &MockOrderService{
+				CancelOrderFunc: func(ctx context.Context, orderID string) (*generated.CancelOrderResponse, error) {
+					receivedID = orderID
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &generated.CancelOrderResponse{OrderId: orderID, Pair: "BTC/JPY", Status: "cancelled"}, nil
+				},
+			}
+
+			handler := NewOrderHandler(mockService)
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/orders/JRF-123", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("JRF-123")
+
+			err := handler.CancelOrder(c)
+
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if receivedID != "JRF-123" {
+				t.Errorf("expected order ID JRF-123 to be passed to service, got %q", receivedID)
+			}
+			if rec.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if tt.serviceErr != nil {
+				var errResp generated.ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if errResp.Error != tt.expectedType {
+					t.Errorf("expected error type %s, got %s", tt.expectedType, errResp.Error)
+				}
+			}
+		})
+	}
+}
+
 func TestValidateCreateOrderRequest(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -214,7 +542,7 @@ func TestValidateCreateOrderRequest(t *testing.T) {
 		{
 			name: "valid request",
 			req: &generated.CreateOrderRequest{
-				Pair:      generated.CreateOrderRequestPairBTCJPY,
+				Pair:      "BTC/JPY",
 				OrderType: generated.CreateOrderRequestOrderTypeLimit,
 				Price:     14000000,
 				Amount:    0.001,
@@ -224,7 +552,7 @@ func TestValidateCreateOrderRequest(t *testing.T) {
 		{
 			name: "zero price",
 			req: &generated.CreateOrderRequest{
-				Pair:      generated.CreateOrderRequestPairBTCJPY,
+				Pair:      "BTC/JPY",
 				OrderType: generated.CreateOrderRequestOrderTypeLimit,
 				Price:     0,
 				Amount:    0.001,
@@ -234,7 +562,7 @@ func TestValidateCreateOrderRequest(t *testing.T) {
 		{
 			name: "negative price",
 			req: &generated.CreateOrderRequest{
-				Pair:      generated.CreateOrderRequestPairBTCJPY,
+				Pair:      "BTC/JPY",
 				OrderType: generated.CreateOrderRequestOrderTypeLimit,
 				Price:     -100,
 				Amount:    0.001,
@@ -244,13 +572,44 @@ func TestValidateCreateOrderRequest(t *testing.T) {
 		{
 			name: "zero amount",
 			req: &generated.CreateOrderRequest{
-				Pair:      generated.CreateOrderRequestPairBTCJPY,
+				Pair:      "BTC/JPY",
 				OrderType: generated.CreateOrderRequestOrderTypeLimit,
 				Price:     14000000,
 				Amount:    0,
 			},
 			wantErr: true,
 		},
+		{
+			name: "market order without price",
+			req: &generated.CreateOrderRequest{
+				Pair:      "BTC/JPY",
+				Side:      generated.CreateOrderRequestSideSELL,
+				OrderType: generated.CreateOrderRequestOrderTypeMarket,
+				Amount:    0.001,
+			},
+			wantErr: false,
+		},
+		{
+			name: "unsupported side",
+			req: &generated.CreateOrderRequest{
+				Pair:      "BTC/JPY",
+				Side:      "HOLD",
+				OrderType: generated.CreateOrderRequestOrderTypeLimit,
+				Price:     14000000,
+				Amount:    0.001,
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported order type",
+			req: &generated.CreateOrderRequest{
+				Pair:      "BTC/JPY",
+				OrderType: "stop",
+				Price:     14000000,
+				Amount:    0.001,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -262,3 +621,121 @@ func TestValidateCreateOrderRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderHandler_ListOrders(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		serviceErr   error
+		expectedCode int
+		expectedType generated.ErrorResponseError
+	}{
+		{
+			name:         "listed successfully",
+			query:        "?pair=BTC/JPY&side=BUY&status=UNFILLED&from=2024-01-01T00:00:00Z&limit=10",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "malformed from",
+			query:        "?from=yesterday",
+			expectedCode: http.StatusBadRequest,
+			expectedType: generated.INVALIDFILTER,
+		},
+		{
+			name:         "non-numeric limit",
+			query:        "?limit=ten",
+			expectedCode: http.StatusBadRequest,
+			expectedType: generated.INVALIDPAGINATION,
+		},
+		{
+			name:         "invalid cursor",
+			query:        "?cursor=bogus",
+			serviceErr:   &service.ValidationError{Kind: service.ErrInvalidPagination, Message: "invalid cursor: bogus"},
+			expectedCode: http.StatusBadRequest,
+			expectedType: generated.INVALIDPAGINATION,
+		},
+		{
+			name:         "invalid side",
+			query:        "?side=HOLD",
+			serviceErr:   &service.ValidationError{Kind: service.ErrInvalidFilter, Message: "invalid side filter: HOLD. Valid values are: BUY, SELL"},
+			expectedCode: http.StatusBadRequest,
+			expectedType: generated.INVALIDFILTER,
+		},
+		{
+			name:         "database error",
+			serviceErr:   errors.New("failed to list orders: connection refused"),
+			expectedCode: http.StatusInternalServerError,
+			expectedType: generated.INTERNALSERVERERROR,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockOrderService{
+				ListOrdersFunc: func(ctx context.Context, params *generated.ListOrdersParams) (*generated.OrderListResponse, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					if params.Pair == nil || *params.Pair != "BTC/JPY" || params.Side == nil || *params.Side != generated.ListOrdersParamsSideBUY {
+						t.Errorf("unexpected params: %+v", params)
+					}
+					if params.Limit == nil || *params.Limit != 10 || params.From == nil {
+						t.Errorf("unexpected params: %+v", params)
+					}
+					return &generated.OrderListResponse{Orders: []generated.OrderDetail{{OrderId: "JRF-1"}}}, nil
+				},
+			}
+
+			handler := NewOrderHandler(mockService)
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/orders"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.ListOrders(c)
+
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if rec.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if tt.expectedType != "" {
+				var errResp generated.ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if errResp.Error != tt.expectedType {
+					t.Errorf("expected error type %s, got %s", tt.expectedType, errResp.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestOrderHandler_GetOrder_NotFound(t *testing.T) {
+	mockService := &MockOrderService{
+		GetOrderFunc: func(ctx context.Context, orderID string) (*generated.OrderDetail, error) {
+			return nil, fmt.Errorf("%w: %s", service.ErrOrderNotFound, orderID)
+		},
+	}
+
+	handler := NewOrderHandler(mockService)
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/JRF-404", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("JRF-404")
+
+	err := handler.GetOrder(c)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
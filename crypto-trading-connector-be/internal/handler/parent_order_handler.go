@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// ParentOrderHandler handles HTTP requests for special order endpoints
+type ParentOrderHandler struct {
+	parentOrderService service.ParentOrderService
+}
+
+// NewParentOrderHandler creates a new parent order handler
+func NewParentOrderHandler(parentOrderService service.ParentOrderService) *ParentOrderHandler {
+	return &ParentOrderHandler{
+		parentOrderService: parentOrderService,
+	}
+}
+
+// CreateParentOrder handles POST /api/v1/orders/parent
+func (h *ParentOrderHandler) CreateParentOrder(c echo.Context) error {
+	var req generated.CreateParentOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return handleError(c, http.StatusBadRequest, generated.BADREQUEST, "Invalid request body")
+	}
+
+	order, err := h.parentOrderService.CreateParentOrder(c.Request().Context(), &req)
+	if err != nil {
+		return h.handleParentOrderError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, order)
+}
+
+// handleParentOrderError handles errors from parent order service
+// Every rejected parameter of a special order is reported as INVALID_REQUEST
+func (h *ParentOrderHandler) handleParentOrderError(c echo.Context, err error) error {
+	status, code, ok := errorResponse(err)
+	if !ok {
+		return handleError(c, http.StatusInternalServerError, generated.INTERNALSERVERERROR, "Failed to create parent order")
+	}
+	if status == http.StatusBadRequest {
+		code = generated.INVALIDREQUEST
+	}
+	return handleError(c, status, code, err.Error())
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// MockParentOrderService is a mock implementation of ParentOrderService for testing
+type MockParentOrderService struct {
+	CreateParentOrderFunc func(ctx context.Context, req *generated.CreateParentOrderRequest) (*generated.ParentOrder, error)
+}
+
+func (m *MockParentOrderService) CreateParentOrder(ctx context.Context, req *generated.CreateParentOrderRequest) (*generated.ParentOrder, error) {
+	if m.CreateParentOrderFunc != nil {
+		return m.CreateParentOrderFunc(ctx, req)
+	}
+	return nil, errors.New("not implemented")
+}
+
+const ifdRequestBody = `{
+	"method": "IFD",
+	"legs": [
+		{"pair": "BTC/JPY", "side": "BUY", "conditionType": "LIMIT", "size": 0.001, "price": 14000000},
+		{"pair": "BTC/JPY", "side": "SELL", "conditionType": "LIMIT", "size": 0.001, "price": 14200000}
+	]
+}`
+
+func TestParentOrderHandler_CreateParentOrder_Success(t *testing.T) {
+	var received *generated.CreateParentOrderRequest
+	mockService := &MockParentOrderService{
+		CreateParentOrderFunc: func(ctx context.Context, req *generated.CreateParentOrderRequest) (*generated.ParentOrder, error) {
+			received = req
+			return &generated.ParentOrder{
+				ParentOrderId: "JRF20150925-060559-396699",
+				Method:        generated.ParentOrderMethodIFD,
+				Pair:          "BTC/JPY",
+				Legs:          req.Legs,
+				Status:        generated.ParentOrderStatusACTIVE,
+			}, nil
+		},
+	}
+
+	handler := NewParentOrderHandler(mockService)
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/parent", strings.NewReader(ifdRequestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.CreateParentOrder(c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if rec.Code != http.StatusCreated {
+		t.Errorf("expected status 201, got %d", rec.Code)
+	}
+	if received == nil || len(received.Legs) != 2 || received.Legs[1].Side != generated.ParentOrderLegSideSELL {
+		t.Fatalf("unexpected request passed to service: %+v", received)
+	}
+
+	var order generated.ParentOrder
+	if err := json.Unmarshal(rec.Body.Bytes(), &order); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if order.ParentOrderId != "JRF20150925-060559-396699" || order.Status != generated.ParentOrderStatusACTIVE {
+		t.Errorf("unexpected response: %+v", order)
+	}
+}
+
+func TestParentOrderHandler_CreateParentOrder_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+		expectedType generated.ErrorResponseError
+	}{
+		{name: "invalid legs", err: &service.ValidationError{Kind: service.ErrInvalidRequest, Message: "invalid legs: IFDOCO requires 3 legs"}, expectedCode: http.StatusBadRequest, expectedType: generated.INVALIDREQUEST},
+		{name: "unsupported pair", err: fmt.Errorf("%w (leg 1)", &service.ValidationError{Kind: service.ErrUnsupportedPair, Message: "unsupported pair: XRP/JPY"}), expectedCode: http.StatusBadRequest, expectedType: generated.INVALIDREQUEST},
+		{name: "insufficient balance", err: fmt.Errorf("%w: required 14000.00, available 10000.00", service.ErrInsufficientBalance), expectedCode: http.StatusPaymentRequired, expectedType: generated.INSUFFICIENTBALANCE},
+		{name: "exchange failure", err: errors.New("failed to send parent order to exchange: timeout"), expectedCode: http.StatusInternalServerError, expectedType: generated.INTERNALSERVERERROR},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockParentOrderService{
+				CreateParentOrderFunc: func(ctx context.Context, req *generated.CreateParentOrderRequest) (*generated.ParentOrder, error) {
+					return nil, tt.err
+				},
+			}
+
+			handler := NewParentOrderHandler(mockService)
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/parent", strings.NewReader(ifdRequestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			_ = handler.CreateParentOrder(c)
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			var resp generated.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.Error != tt.expectedType {
+				t.Errorf("expected error %s, got %s", tt.expectedType, resp.Error)
+			}
+		})
+	}
+}
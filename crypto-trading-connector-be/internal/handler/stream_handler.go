@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// StreamHandler handles Server-Sent Events endpoints
+type StreamHandler struct {
+	prices    service.PriceStream
+	keepAlive time.Duration
+}
+
+// NewStreamHandler creates a new stream handler. A keep-alive comment is sent on idle streams
+// every keepAlive, so that proxies and browsers do not drop the connection.
+func NewStreamHandler(prices service.PriceStream, keepAlive time.Duration) *StreamHandler {
+	return &StreamHandler{
+		prices:    prices,
+		keepAlive: keepAlive,
+	}
+}
+
+// GetPriceStream handles GET /api/v1/stream/prices
+func (h *StreamHandler) GetPriceStream(c echo.Context) error {
+	var ids []string
+	for _, id := range strings.Split(c.QueryParam("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	// The subscription ends when the client disconnects and the request context is cancelled
+	ctx := c.Request().Context()
+	updates, err := h.prices.Subscribe(ctx, ids)
+	if err != nil {
+		return handleServiceError(c, err, "Failed to subscribe to prices")
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no") // Disable response buffering of reverse proxies
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	keepAlive := time.NewTicker(h.keepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(update)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(res, "event: price\ndata: %s\n\n", data); err != nil {
+				return nil
+			}
+			keepAlive.Reset(h.keepAlive)
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+	}
+}
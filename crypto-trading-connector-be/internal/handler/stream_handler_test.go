@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// MockPriceStream is a mock implementation of PriceStream for testing
+type MockPriceStream struct {
+	SubscribeFunc func(ctx context.Context, ids []string) (<-chan generated.PriceUpdate, error)
+}
+
+func (m *MockPriceStream) Subscribe(ctx context.Context, ids []string) (<-chan generated.PriceUpdate, error) {
+	return m.SubscribeFunc(ctx, ids)
+}
+
+func TestStreamHandler_GetPriceStream(t *testing.T) {
+	var gotIDs []string
+	handler := NewStreamHandler(&MockPriceStream{
+		SubscribeFunc: func(ctx context.Context, ids []string) (<-chan generated.PriceUpdate, error) {
+			gotIDs = ids
+			updates := make(chan generated.PriceUpdate, 2)
+			updates <- generated.PriceUpdate{Id: "bitcoin", Symbol: "BTC", Pair: "BTC/JPY", Price: 15000000, Timestamp: 1704067200000}
+			updates <- generated.PriceUpdate{Id: "ethereum", Symbol: "ETH", Pair: "ETH/JPY", Price: 500000, Timestamp: 1704067200000}
+			close(updates)
+			return updates, nil
+		},
+	}, time.Minute)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stream/prices?ids=bitcoin,%20ethereum", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.GetPriceStream(c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(gotIDs, []string{"bitcoin", "ethereum"}) {
+		t.Errorf("expected IDs [bitcoin ethereum], got %v", gotIDs)
+	}
+	if contentType := rec.Header().Get(echo.HeaderContentType); contentType != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got %s", contentType)
+	}
+
+	events := strings.Split(strings.TrimSpace(rec.Body.String()), "\n\n")
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %q", rec.Body.String())
+	}
+	data, ok := strings.CutPrefix(events[0], "event: price\ndata: ")
+	if !ok {
+		t.Fatalf("expected a price event, got %q", events[0])
+	}
+	var update generated.PriceUpdate
+	if err := json.Unmarshal([]byte(data), &update); err != nil || update.Id != "bitcoin" || update.Price != 15000000 {
+		t.Errorf("unexpected event data %q: %v", data, err)
+	}
+}
+
+func TestStreamHandler_GetPriceStream_KeepAlive(t *testing.T) {
+	handler := NewStreamHandler(&MockPriceStream{
+		SubscribeFunc: func(ctx context.Context, ids []string) (<-chan generated.PriceUpdate, error) {
+			return make(chan generated.PriceUpdate), nil
+		},
+	}, 10*time.Millisecond)
+
+	// The client disconnects after a while
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stream/prices", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.GetPriceStream(c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if count := strings.Count(rec.Body.String(), ": keep-alive\n\n"); count < 2 {
+		t.Errorf("expected keep-alive comments, got %q", rec.Body.String())
+	}
+}
+
+func TestStreamHandler_GetPriceStream_UnknownID(t *testing.T) {
+	handler := NewStreamHandler(&MockPriceStream{
+		SubscribeFunc: func(ctx context.Context, ids []string) (<-chan generated.PriceUpdate, error) {
+			return nil, &service.ValidationError{Kind: service.ErrInvalidRequest, Message: "unknown cryptocurrency: dogecoin"}
+		},
+	}, time.Minute)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stream/prices?ids=dogecoin", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.GetPriceStream(c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "INVALID_REQUEST") {
+		t.Errorf("expected 400 INVALID_REQUEST, got %d %s", rec.Code, rec.Body.String())
+	}
+}
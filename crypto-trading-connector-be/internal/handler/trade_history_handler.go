@@ -3,8 +3,10 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
 	"github.com/crypto-trading-connector/backend/internal/service"
 	"github.com/labstack/echo/v4"
 )
@@ -12,12 +14,14 @@ import (
 // TradeHistoryHandler handles HTTP requests for trade history endpoints
 type TradeHistoryHandler struct {
 	tradeHistoryService service.TradeHistoryService
+	assets              *model.AssetCatalog
 }
 
 // NewTradeHistoryHandler creates a new trade history handler
-func NewTradeHistoryHandler(tradeHistoryService service.TradeHistoryService) *TradeHistoryHandler {
+func NewTradeHistoryHandler(tradeHistoryService service.TradeHistoryService, assets *model.AssetCatalog) *TradeHistoryHandler {
 	return &TradeHistoryHandler{
 		tradeHistoryService: tradeHistoryService,
+		assets:              assets,
 	}
 }
 
@@ -40,7 +44,7 @@ func (h *TradeHistoryHandler) GetTradeStatistics(c echo.Context) error {
 	}
 
 	// Get statistics from service
-	statistics, err := h.tradeHistoryService.GetTradeStatistics(assetFilter, timeFilter)
+	statistics, err := h.tradeHistoryService.GetTradeStatistics(c.Request().Context(), assetFilter, timeFilter)
 	if err != nil {
 		return h.handleTradeHistoryError(c, err)
 	}
@@ -86,7 +90,7 @@ func (h *TradeHistoryHandler) GetTradeTransactions(c echo.Context) error {
 	}
 
 	// Get transactions from service
-	response, err := h.tradeHistoryService.GetTradeTransactions(assetFilter, timeFilter, page, limit)
+	response, err := h.tradeHistoryService.GetTradeTransactions(c.Request().Context(), assetFilter, timeFilter, page, limit)
 	if err != nil {
 		return h.handleTradeHistoryError(c, err)
 	}
@@ -96,14 +100,9 @@ func (h *TradeHistoryHandler) GetTradeTransactions(c echo.Context) error {
 
 // validateFilters validates asset and time filter parameters
 func (h *TradeHistoryHandler) validateFilters(assetFilter, timeFilter string) error {
-	// Validate asset filter
-	validAssetFilters := map[string]bool{
-		"all": true,
-		"BTC": true,
-		"ETH": true,
-	}
-	if !validAssetFilters[assetFilter] {
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid asset filter: "+assetFilter+". Valid values are: all, BTC, ETH")
+	// Validate asset filter against the currency codes of the asset catalog
+	if _, ok := h.assets.ByCode(assetFilter); !ok && assetFilter != "all" {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid asset filter: "+assetFilter+". Valid values are: all, "+strings.Join(h.assets.Codes(), ", "))
 	}
 
 	// Validate time filter
@@ -136,38 +135,8 @@ func (h *TradeHistoryHandler) validatePagination(page, limit int) error {
 }
 
 // handleTradeHistoryError handles service errors and converts them to appropriate HTTP responses
+// Filter and pagination errors map to INVALID_FILTER and INVALID_PAGINATION; database errors are internal
 func (h *TradeHistoryHandler) handleTradeHistoryError(c echo.Context, err error) error {
-	// Check for specific error types
-	errMsg := err.Error()
-
-	// Filter validation errors
-	if contains(errMsg, "invalid asset filter") || contains(errMsg, "invalid time filter") {
-		return handleError(c, http.StatusBadRequest, generated.INVALIDFILTER, errMsg)
-	}
-
-	// Pagination validation errors
-	if contains(errMsg, "invalid page") || contains(errMsg, "invalid limit") {
-		return handleError(c, http.StatusBadRequest, generated.INVALIDPAGINATION, errMsg)
-	}
-
-	// Database or other internal errors
-	return handleError(c, http.StatusInternalServerError, generated.INTERNALSERVERERROR, "Internal server error")
-}
-
-// contains checks if a string contains a substring
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || (len(s) > len(substr) && 
-		(s[:len(substr)] == substr || s[len(s)-len(substr):] == substr || 
-		 indexOf(s, substr) >= 0)))
-}
-
-// indexOf returns the index of the first occurrence of substr in s, or -1 if not found
-func indexOf(s, substr string) int {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return i
-		}
-	}
-	return -1
+	return handleServiceError(c, err, "Internal server error")
 }
 
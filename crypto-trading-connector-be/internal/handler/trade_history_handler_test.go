@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -21,7 +23,7 @@ type MockTradeHistoryService struct {
 	mock.Mock
 }
 
-func (m *MockTradeHistoryService) GetTradeStatistics(assetFilter, timeFilter string) (*generated.TradeStatistics, error) {
+func (m *MockTradeHistoryService) GetTradeStatistics(ctx context.Context, assetFilter, timeFilter string) (*generated.TradeStatistics, error) {
 	args := m.Called(assetFilter, timeFilter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -29,7 +31,7 @@ func (m *MockTradeHistoryService) GetTradeStatistics(assetFilter, timeFilter str
 	return args.Get(0).(*generated.TradeStatistics), args.Error(1)
 }
 
-func (m *MockTradeHistoryService) GetTradeTransactions(assetFilter, timeFilter string, page, limit int) (*generated.TransactionLogResponse, error) {
+func (m *MockTradeHistoryService) GetTradeTransactions(ctx context.Context, assetFilter, timeFilter string, page, limit int) (*generated.TransactionLogResponse, error) {
 	args := m.Called(assetFilter, timeFilter, page, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -78,14 +80,14 @@ func TestTradeHistoryHandler_PaginationFilterMaintenance_Property(t *testing.T)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := new(MockTradeHistoryService)
-			handler := NewTradeHistoryHandler(mockService)
+			handler := NewTradeHistoryHandler(mockService, model.DefaultAssetCatalog())
 
 			// Create mock response
 			mockResponse := &generated.TransactionLogResponse{
 				Transactions: []generated.Transaction{
 					{
 						Id:             "1",
-						Cryptocurrency: generated.Bitcoin,
+						Cryptocurrency: "Bitcoin",
 						Timestamp:      time.Now(),
 						Profit:         25000.0,
 						OrderType:      generated.Sell,
@@ -145,7 +147,7 @@ func TestTradeHistoryHandler_PaginationFilterMaintenance_Property(t *testing.T)
 
 func TestTradeHistoryHandler_GetTradeStatistics_Success(t *testing.T) {
 	mockService := new(MockTradeHistoryService)
-	handler := NewTradeHistoryHandler(mockService)
+	handler := NewTradeHistoryHandler(mockService, model.DefaultAssetCatalog())
 
 	// Mock response
 	mockStats := &generated.TradeStatistics{
@@ -182,7 +184,7 @@ func TestTradeHistoryHandler_GetTradeStatistics_Success(t *testing.T) {
 
 func TestTradeHistoryHandler_GetTradeStatistics_WithFilters(t *testing.T) {
 	mockService := new(MockTradeHistoryService)
-	handler := NewTradeHistoryHandler(mockService)
+	handler := NewTradeHistoryHandler(mockService, model.DefaultAssetCatalog())
 
 	// Mock response
 	mockStats := &generated.TradeStatistics{
@@ -219,14 +221,14 @@ func TestTradeHistoryHandler_GetTradeStatistics_WithFilters(t *testing.T) {
 
 func TestTradeHistoryHandler_GetTradeTransactions_Success(t *testing.T) {
 	mockService := new(MockTradeHistoryService)
-	handler := NewTradeHistoryHandler(mockService)
+	handler := NewTradeHistoryHandler(mockService, model.DefaultAssetCatalog())
 
 	// Mock response
 	mockResponse := &generated.TransactionLogResponse{
 		Transactions: []generated.Transaction{
 			{
 				Id:             "1",
-				Cryptocurrency: generated.Bitcoin,
+				Cryptocurrency: "Bitcoin",
 				Timestamp:      time.Now(),
 				Profit:         25000.0,
 				OrderType:      generated.Sell,
@@ -272,7 +274,7 @@ func TestTradeHistoryHandler_GetTradeTransactions_Success(t *testing.T) {
 
 func TestTradeHistoryHandler_InvalidFilters(t *testing.T) {
 	mockService := new(MockTradeHistoryService)
-	handler := NewTradeHistoryHandler(mockService)
+	handler := NewTradeHistoryHandler(mockService, model.DefaultAssetCatalog())
 
 	tests := []struct {
 		name        string
@@ -340,7 +342,7 @@ func TestTradeHistoryHandler_InvalidFilters(t *testing.T) {
 
 func TestTradeHistoryHandler_InvalidPagination(t *testing.T) {
 	mockService := new(MockTradeHistoryService)
-	handler := NewTradeHistoryHandler(mockService)
+	handler := NewTradeHistoryHandler(mockService, model.DefaultAssetCatalog())
 
 	tests := []struct {
 		name        string
@@ -414,7 +416,7 @@ func TestTradeHistoryHandler_InvalidPagination(t *testing.T) {
 
 func TestTradeHistoryHandler_ServiceError(t *testing.T) {
 	mockService := new(MockTradeHistoryService)
-	handler := NewTradeHistoryHandler(mockService)
+	handler := NewTradeHistoryHandler(mockService, model.DefaultAssetCatalog())
 
 	// Mock service error
 	mockService.On("GetTradeStatistics", "all", "all").Return(nil, errors.New("database connection failed"))
@@ -442,7 +444,7 @@ func TestTradeHistoryHandler_ServiceError(t *testing.T) {
 
 func TestTradeHistoryHandler_DefaultValues(t *testing.T) {
 	mockService := new(MockTradeHistoryService)
-	handler := NewTradeHistoryHandler(mockService)
+	handler := NewTradeHistoryHandler(mockService, model.DefaultAssetCatalog())
 
 	// Mock service call with default values
 	mockService.On("GetTradeTransactions", "all", "all", 1, 10).Return(&generated.TransactionLogResponse{
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// TradingHaltHandler handles HTTP requests for the trading kill switch
+type TradingHaltHandler struct {
+	tradingHaltService service.TradingHaltService
+}
+
+// NewTradingHaltHandler creates a new trading halt handler
+func NewTradingHaltHandler(tradingHaltService service.TradingHaltService) *TradingHaltHandler {
+	return &TradingHaltHandler{
+		tradingHaltService: tradingHaltService,
+	}
+}
+
+// GetTradingHalt handles GET /api/v1/admin/trading-halt
+func (h *TradingHaltHandler) GetTradingHalt(c echo.Context) error {
+	halt, err := h.tradingHaltService.GetTradingHalt(c.Request().Context())
+	if err != nil {
+		return handleServiceError(c, err, "Failed to get trading halt")
+	}
+
+	return c.JSON(http.StatusOK, halt)
+}
+
+// UpdateTradingHalt handles PUT /api/v1/admin/trading-halt
+func (h *TradingHaltHandler) UpdateTradingHalt(c echo.Context) error {
+	var req generated.UpdateTradingHaltRequest
+	if err := c.Bind(&req); err != nil {
+		return handleError(c, http.StatusBadRequest, generated.BADREQUEST, "Invalid request body")
+	}
+
+	response, err := h.tradingHaltService.UpdateTradingHalt(c.Request().Context(), &req)
+	if err != nil {
+		return handleServiceError(c, err, "Failed to update trading halt")
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
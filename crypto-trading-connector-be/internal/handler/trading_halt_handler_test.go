@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/labstack/echo/v4"
+)
+
+// MockTradingHaltService is a mock implementation of TradingHaltService for testing
+type MockTradingHaltService struct {
+	GetTradingHaltFunc    func(ctx context.Context) (*generated.TradingHalt, error)
+	UpdateTradingHaltFunc func(ctx context.Context, req *generated.UpdateTradingHaltRequest) (*generated.UpdateTradingHaltResponse, error)
+}
+
+func (m *MockTradingHaltService) GetTradingHalt(ctx context.Context) (*generated.TradingHalt, error) {
+	if m.GetTradingHaltFunc != nil {
+		return m.GetTradingHaltFunc(ctx)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockTradingHaltService) UpdateTradingHalt(ctx context.Context, req *generated.UpdateTradingHaltRequest) (*generated.UpdateTradingHaltResponse, error) {
+	if m.UpdateTradingHaltFunc != nil {
+		return m.UpdateTradingHaltFunc(ctx, req)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func TestTradingHaltHandler_UpdateTradingHalt(t *testing.T) {
+	var received *generated.UpdateTradingHaltRequest
+	mockService := &MockTradingHaltService{
+		UpdateTradingHaltFunc: func(ctx context.Context, req *generated.UpdateTradingHaltRequest) (*generated.UpdateTradingHaltResponse, error) {
+			received = req
+			return &generated.UpdateTradingHaltResponse{
+				TradingHalt:   generated.TradingHalt{Halted: req.Halted, Reason: req.Reason},
+				Cancellations: &[]generated.PairCancellation{{Pair: "BTC/JPY", Cancelled: true}},
+			}, nil
+		},
+	}
+
+	handler := NewTradingHaltHandler(mockService)
+	e := echo.New()
+
+	body := `{"halted": true, "reason": "exchange incident", "cancelOpenOrders": true}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/trading-halt", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.UpdateTradingHalt(c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+	if received == nil || !received.Halted || received.CancelOpenOrders == nil || !*received.CancelOpenOrders {
+		t.Fatalf("unexpected request passed to service: %+v", received)
+	}
+
+	var response generated.UpdateTradingHaltResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !response.TradingHalt.Halted || response.Cancellations == nil || len(*response.Cancellations) != 1 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
+
+func TestTradingHaltHandler_GetTradingHalt_Error(t *testing.T) {
+	mockService := &MockTradingHaltService{
+		GetTradingHaltFunc: func(ctx context.Context) (*generated.TradingHalt, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	handler := NewTradingHaltHandler(mockService)
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/trading-halt", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.GetTradingHalt(c); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "connection refused") {
+		t.Errorf("expected the internal error not to be exposed, got %s", rec.Body.String())
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/crypto-trading-connector/backend/internal/generated"
 	"github.com/crypto-trading-connector/backend/internal/handler"
+	"github.com/crypto-trading-connector/backend/internal/model"
 	"github.com/crypto-trading-connector/backend/internal/repository"
 	"github.com/crypto-trading-connector/backend/internal/service"
 	"github.com/labstack/echo/v4"
@@ -32,9 +33,10 @@ func TestTradeHistoryIntegration_GetStatistics_Success(t *testing.T) {
 		WillReturnRows(rows)
 
 	// Setup components
-	repo := repository.NewMySQLTradeHistoryRepository(db)
-	svc := service.NewTradeHistoryService(repo)
-	h := handler.NewTradeHistoryHandler(svc)
+	assets := model.DefaultAssetCatalog()
+	repo := repository.NewMySQLTradeHistoryRepository(db, assets)
+	svc := service.NewTradeHistoryService(repo, assets)
+	h := handler.NewTradeHistoryHandler(svc, assets)
 
 	// Setup Echo
 	e := echo.New()
@@ -76,9 +78,10 @@ func TestTradeHistoryIntegration_GetStatistics_WithFilters(t *testing.T) {
 		WillReturnRows(rows)
 
 	// Setup components
-	repo := repository.NewMySQLTradeHistoryRepository(db)
-	svc := service.NewTradeHistoryService(repo)
-	h := handler.NewTradeHistoryHandler(svc)
+	assets := model.DefaultAssetCatalog()
+	repo := repository.NewMySQLTradeHistoryRepository(db, assets)
+	svc := service.NewTradeHistoryService(repo, assets)
+	h := handler.NewTradeHistoryHandler(svc, assets)
 
 	// Setup Echo
 	e := echo.New()
@@ -127,9 +130,10 @@ func TestTradeHistoryIntegration_GetTransactions_Success(t *testing.T) {
 		WillReturnRows(countRows)
 
 	// Setup components
-	repo := repository.NewMySQLTradeHistoryRepository(db)
-	svc := service.NewTradeHistoryService(repo)
-	h := handler.NewTradeHistoryHandler(svc)
+	assets := model.DefaultAssetCatalog()
+	repo := repository.NewMySQLTradeHistoryRepository(db, assets)
+	svc := service.NewTradeHistoryService(repo, assets)
+	h := handler.NewTradeHistoryHandler(svc, assets)
 
 	// Setup Echo
 	e := echo.New()
@@ -150,7 +154,7 @@ func TestTradeHistoryIntegration_GetTransactions_Success(t *testing.T) {
 
 	assert.Len(t, response.Transactions, 2)
 	assert.Equal(t, "tx1", response.Transactions[0].Id)
-	assert.Equal(t, generated.Bitcoin, response.Transactions[0].Cryptocurrency)
+	assert.Equal(t, "Bitcoin", response.Transactions[0].Cryptocurrency)
 	assert.Equal(t, 2000.0, response.Transactions[0].Profit)
 
 	// Verify pagination
@@ -184,9 +188,10 @@ func TestTradeHistoryIntegration_GetTransactions_Pagination(t *testing.T) {
 		WillReturnRows(countRows)
 
 	// Setup components
-	repo := repository.NewMySQLTradeHistoryRepository(db)
-	svc := service.NewTradeHistoryService(repo)
-	h := handler.NewTradeHistoryHandler(svc)
+	assets := model.DefaultAssetCatalog()
+	repo := repository.NewMySQLTradeHistoryRepository(db, assets)
+	svc := service.NewTradeHistoryService(repo, assets)
+	h := handler.NewTradeHistoryHandler(svc, assets)
 
 	// Setup Echo
 	e := echo.New()
@@ -225,9 +230,10 @@ func TestTradeHistoryIntegration_InvalidFilters(t *testing.T) {
 	defer db.Close()
 
 	// Setup components
-	repo := repository.NewMySQLTradeHistoryRepository(db)
-	svc := service.NewTradeHistoryService(repo)
-	h := handler.NewTradeHistoryHandler(svc)
+	assets := model.DefaultAssetCatalog()
+	repo := repository.NewMySQLTradeHistoryRepository(db, assets)
+	svc := service.NewTradeHistoryService(repo, assets)
+	h := handler.NewTradeHistoryHandler(svc, assets)
 
 	tests := []struct {
 		name           string
@@ -278,9 +284,10 @@ func TestTradeHistoryIntegration_DatabaseError(t *testing.T) {
 		WillReturnError(sql.ErrConnDone)
 
 	// Setup components
-	repo := repository.NewMySQLTradeHistoryRepository(db)
-	svc := service.NewTradeHistoryService(repo)
-	h := handler.NewTradeHistoryHandler(svc)
+	assets := model.DefaultAssetCatalog()
+	repo := repository.NewMySQLTradeHistoryRepository(db, assets)
+	svc := service.NewTradeHistoryService(repo, assets)
+	h := handler.NewTradeHistoryHandler(svc, assets)
 
 	// Setup Echo
 	e := echo.New()
@@ -0,0 +1,131 @@
+package model
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed assets.yaml
+var defaultAssetCatalog []byte
+
+// Asset is a cryptocurrency listed in the asset catalog
+type Asset struct {
+	ID        string `yaml:"id"`         // Identifier used in URLs (e.g. "bitcoin")
+	Name      string `yaml:"name"`       // Display name, also reported in the trade history (e.g. "Bitcoin")
+	Pair      Symbol `yaml:"pair"`       // Trading pair (e.g. "BTC/JPY")
+	Icon      string `yaml:"icon"`       // Icon character (e.g. "₿")
+	IconColor string `yaml:"icon_color"` // Icon color (e.g. "#f7931a")
+}
+
+// Code returns the currency code of the asset, used as the asset filter of the trade history (e.g. "BTC")
+func (a Asset) Code() string {
+	return a.Pair.Base()
+}
+
+// AssetCatalog is the list of cryptocurrencies the application handles.
+// Market data, chart lookups, trade history filters and order validation all read it.
+type AssetCatalog struct {
+	assets []Asset
+}
+
+// NewAssetCatalog creates an asset catalog, rejecting incomplete or duplicate entries
+func NewAssetCatalog(assets []Asset) (*AssetCatalog, error) {
+	ids := make(map[string]bool, len(assets))
+	codes := make(map[string]bool, len(assets))
+
+	for _, asset := range assets {
+		if asset.ID == "" || asset.Name == "" || asset.Code() == "" || asset.Pair.Quote() == "" {
+			return nil, fmt.Errorf("invalid asset %q: id, name and pair (BASE/QUOTE) are required", asset.ID)
+		}
+		if ids[asset.ID] {
+			return nil, fmt.Errorf("invalid asset %q: duplicate id", asset.ID)
+		}
+		// The currency code identifies the asset in trade history filters, so one pair per currency
+		if codes[asset.Code()] {
+			return nil, fmt.Errorf("invalid asset %q: duplicate currency %s", asset.ID, asset.Code())
+		}
+		ids[asset.ID] = true
+		codes[asset.Code()] = true
+	}
+
+	return &AssetCatalog{assets: assets}, nil
+}
+
+// LoadAssetCatalog loads the asset catalog from a YAML file, or the built-in catalog if path is empty
+func LoadAssetCatalog(path string) (*AssetCatalog, error) {
+	data := defaultAssetCatalog
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read asset catalog: %w", err)
+		}
+	}
+
+	var assets []Asset
+	if err := yaml.Unmarshal(data, &assets); err != nil {
+		return nil, fmt.Errorf("failed to parse asset catalog: %w", err)
+	}
+	for i := range assets {
+		assets[i].Pair = Symbol(strings.ToUpper(string(assets[i].Pair)))
+	}
+
+	return NewAssetCatalog(assets)
+}
+
+// DefaultAssetCatalog returns the built-in asset catalog (Bitcoin and Ethereum)
+func DefaultAssetCatalog() *AssetCatalog {
+	catalog, err := LoadAssetCatalog("")
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+// Assets returns every asset in catalog order
+func (c *AssetCatalog) Assets() []Asset {
+	return c.assets
+}
+
+// ByID returns the asset with the URL identifier (e.g. "bitcoin")
+func (c *AssetCatalog) ByID(id string) (Asset, bool) {
+	for _, asset := range c.assets {
+		if asset.ID == id {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+// ByCode returns the asset with the currency code (e.g. "BTC")
+func (c *AssetCatalog) ByCode(code string) (Asset, bool) {
+	for _, asset := range c.assets {
+		if asset.Code() == code {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+// ByPair returns the asset traded on the pair (e.g. "BTC/JPY")
+func (c *AssetCatalog) ByPair(pair Symbol) (Asset, bool) {
+	for _, asset := range c.assets {
+		if asset.Pair == pair {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Codes returns the currency codes of every asset in catalog order
+func (c *AssetCatalog) Codes() []string {
+	codes := make([]string, 0, len(c.assets))
+	for _, asset := range c.assets {
+		codes = append(codes, asset.Code())
+	}
+	return codes
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultAssetCatalog(t *testing.T) {
+	catalog := DefaultAssetCatalog()
+
+	bitcoin, ok := catalog.ByID("bitcoin")
+	if !ok || bitcoin.Name != "Bitcoin" || bitcoin.Pair != "BTC/JPY" || bitcoin.Code() != "BTC" {
+		t.Errorf("unexpected bitcoin asset: %+v", bitcoin)
+	}
+
+	ethereum, ok := catalog.ByCode("ETH")
+	if !ok || ethereum.ID != "ethereum" || ethereum.Icon != "Ξ" || ethereum.IconColor != "#627eea" {
+		t.Errorf("unexpected ethereum asset: %+v", ethereum)
+	}
+
+	if codes := catalog.Codes(); strings.Join(codes, ",") != "BTC,ETH" {
+		t.Errorf("expected codes BTC,ETH, got %v", codes)
+	}
+}
+
+func TestLoadAssetCatalog_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "assets.yaml")
+	err := os.WriteFile(path, []byte(`
+- id: bitcoin
+  name: Bitcoin
+  pair: BTC/JPY
+- id: ripple
+  name: XRP
+  pair: xrp/jpy
+  icon: "✕"
+  icon_color: "#23292f"
+`), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write asset catalog: %v", err)
+	}
+
+	catalog, err := LoadAssetCatalog(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	xrp, ok := catalog.ByPair("XRP/JPY")
+	if !ok || xrp.ID != "ripple" || xrp.Code() != "XRP" {
+		t.Errorf("unexpected XRP asset: %+v", xrp)
+	}
+	if _, ok := catalog.ByID("ethereum"); ok {
+		t.Error("expected the file to replace the built-in catalog")
+	}
+}
+
+func TestNewAssetCatalog_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		assets []Asset
+	}{
+		{name: "missing name", assets: []Asset{{ID: "bitcoin", Pair: "BTC/JPY"}}},
+		{name: "pair without quote", assets: []Asset{{ID: "bitcoin", Name: "Bitcoin", Pair: "BTC"}}},
+		{name: "duplicate id", assets: []Asset{
+			{ID: "bitcoin", Name: "Bitcoin", Pair: "BTC/JPY"},
+			{ID: "bitcoin", Name: "Ethereum", Pair: "ETH/JPY"},
+		}},
+		{name: "duplicate currency", assets: []Asset{
+			{ID: "bitcoin", Name: "Bitcoin", Pair: "BTC/JPY"},
+			{ID: "bitcoin-usd", Name: "Bitcoin", Pair: "BTC/USD"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAssetCatalog(tt.assets); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
@@ -1,18 +1,20 @@
 package model
 
+import "time"
+
 // PriceHistory represents a record from price_histories table
 // This is a database-specific model not defined in OpenAPI
 type PriceHistory struct {
 	ID            int
-	Datetime      string
+	Datetime      time.Time
 	ProductCode   string
 	Price         float64
-	PriceRatio24h *float64
+	PriceRatio24h *float64 // Price divided by the price 24 hours earlier (e.g. 1.05); nil if there was no sample then
 }
 
-// TickerResponse represents bitFlyer ticker API response
+// BitFlyerTickerResponse represents bitFlyer ticker API response
 // This is a bitFlyer-specific model not defined in OpenAPI
-type TickerResponse struct {
+type BitFlyerTickerResponse struct {
 	ProductCode     string  `json:"product_code"`
 	Timestamp       string  `json:"timestamp"`
 	TickID          int     `json:"tick_id"`
@@ -27,6 +29,31 @@ type TickerResponse struct {
 	VolumeByProduct float64 `json:"volume_by_product"`
 }
 
+// BitFlyerExecution represents an execution pushed on the lightning_executions channel of bitFlyer Realtime API
+type BitFlyerExecution struct {
+	ID                         int64   `json:"id"`
+	Side                       string  `json:"side"` // BUY, SELL, or empty for executions of an auction
+	Price                      float64 `json:"price"`
+	Size                       float64 `json:"size"`
+	ExecDate                   string  `json:"exec_date"`
+	BuyChildOrderAcceptanceID  string  `json:"buy_child_order_acceptance_id"`
+	SellChildOrderAcceptanceID string  `json:"sell_child_order_acceptance_id"`
+}
+
+// BitFlyerBoard represents the order book pushed on the lightning_board_snapshot and lightning_board channels
+// of bitFlyer Realtime API. The lightning_board channel only carries the levels that changed.
+type BitFlyerBoard struct {
+	MidPrice float64              `json:"mid_price"`
+	Bids     []BitFlyerBoardLevel `json:"bids"`
+	Asks     []BitFlyerBoardLevel `json:"asks"`
+}
+
+// BitFlyerBoardLevel represents one price of a bitFlyer order book; a size of 0 removes the price
+type BitFlyerBoardLevel struct {
+	Price float64 `json:"price"`
+	Size  float64 `json:"size"`
+}
+
 // BuyOrder represents a record from buy_orders table
 type BuyOrder struct {
 	ID          int     `db:"id"`
@@ -43,6 +70,13 @@ type BuyOrder struct {
 	Updatetime  string  `db:"updatetime"`
 }
 
+// BitFlyerMarket represents a market returned by bitFlyer getmarkets API
+type BitFlyerMarket struct {
+	ProductCode string `json:"product_code"`
+	Alias       string `json:"alias,omitempty"`
+	MarketType  string `json:"market_type"` // Spot, FX or Futures
+}
+
 // BitFlyerBalance represents balance response from bitFlyer API
 type BitFlyerBalance struct {
 	CurrencyCode string  `json:"currency_code"`
@@ -55,7 +89,7 @@ type BitFlyerOrderRequest struct {
 	ProductCode    string  `json:"product_code"`
 	ChildOrderType string  `json:"child_order_type"` // LIMIT or MARKET
 	Side           string  `json:"side"`             // BUY or SELL
-	Price          float64 `json:"price"`
+	Price          float64 `json:"price,omitempty"`  // Not sent for MARKET orders
 	Size           float64 `json:"size"`
 	TimeInForce    string  `json:"time_in_force,omitempty"` // GTC, IOC, FOK
 }
@@ -64,3 +98,102 @@ type BitFlyerOrderRequest struct {
 type BitFlyerOrderResponse struct {
 	ChildOrderAcceptanceID string `json:"child_order_acceptance_id"`
 }
+
+// BitFlyerErrorResponse represents the body of an error response from bitFlyer API
+type BitFlyerErrorResponse struct {
+	Status       int    `json:"status"` // Negative error code (e.g. -200)
+	ErrorMessage string `json:"error_message"`
+}
+
+// BitFlyerChildOrder represents a child order returned by bitFlyer getchildorders API
+type BitFlyerChildOrder struct {
+	ID                     int64   `json:"id"`
+	ChildOrderID           string  `json:"child_order_id"`
+	ProductCode            string  `json:"product_code"`
+	Side                   string  `json:"side"`
+	ChildOrderType         string  `json:"child_order_type"`
+	Price                  float64 `json:"price"`
+	AveragePrice           float64 `json:"average_price"`
+	Size                   float64 `json:"size"`
+	ChildOrderState        string  `json:"child_order_state"` // ACTIVE, COMPLETED, CANCELED, EXPIRED, REJECTED
+	ExpireDate             string  `json:"expire_date"`
+	ChildOrderDate         string  `json:"child_order_date"`
+	ChildOrderAcceptanceID string  `json:"child_order_acceptance_id"`
+	OutstandingSize        float64 `json:"outstanding_size"`
+	CancelSize             float64 `json:"cancel_size"`
+	ExecutedSize           float64 `json:"executed_size"`
+	TotalCommission        float64 `json:"total_commission"`
+}
+
+// BitFlyerChildOrderQuery represents query parameters for bitFlyer getchildorders API
+// Empty fields are omitted from the request
+type BitFlyerChildOrderQuery struct {
+	ProductCode            string
+	ChildOrderAcceptanceID string
+	ChildOrderState        string
+	ParentOrderID          string
+	Count                  int
+}
+
+// BitFlyerCancelOrderRequest represents cancel order request to bitFlyer API
+type BitFlyerCancelOrderRequest struct {
+	ProductCode            string `json:"product_code"`
+	ChildOrderAcceptanceID string `json:"child_order_acceptance_id"`
+}
+
+// BitFlyerCancelAllOrdersRequest represents cancel all orders request to bitFlyer API
+type BitFlyerCancelAllOrdersRequest struct {
+	ProductCode string `json:"product_code"`
+}
+
+// BitFlyerParentOrderParameter represents a single order condition of a bitFlyer parent order
+type BitFlyerParentOrderParameter struct {
+	ProductCode   string  `json:"product_code"`
+	ConditionType string  `json:"condition_type"` // LIMIT, MARKET, STOP, STOP_LIMIT, TRAIL
+	Side          string  `json:"side"`
+	Size          float64 `json:"size"`
+	Price         float64 `json:"price,omitempty"`
+	TriggerPrice  float64 `json:"trigger_price,omitempty"`
+	Offset        float64 `json:"offset,omitempty"`
+}
+
+// BitFlyerParentOrderRequest represents parent order request to bitFlyer API
+type BitFlyerParentOrderRequest struct {
+	OrderMethod    string                         `json:"order_method"` // IFD, OCO, IFDOCO
+	MinuteToExpire int                            `json:"minute_to_expire,omitempty"`
+	TimeInForce    string                         `json:"time_in_force,omitempty"`
+	Parameters     []BitFlyerParentOrderParameter `json:"parameters"`
+}
+
+// BitFlyerParentOrderResponse represents parent order response from bitFlyer API
+type BitFlyerParentOrderResponse struct {
+	ParentOrderAcceptanceID string `json:"parent_order_acceptance_id"`
+}
+
+// BitFlyerParentOrder represents a parent order returned by bitFlyer getparentorders API
+type BitFlyerParentOrder struct {
+	ID                      int64   `json:"id"`
+	ParentOrderID           string  `json:"parent_order_id"`
+	ProductCode             string  `json:"product_code"`
+	Side                    string  `json:"side"`
+	ParentOrderType         string  `json:"parent_order_type"`
+	Price                   float64 `json:"price"`
+	AveragePrice            float64 `json:"average_price"`
+	Size                    float64 `json:"size"`
+	ParentOrderState        string  `json:"parent_order_state"` // ACTIVE, COMPLETED, CANCELED, EXPIRED, REJECTED
+	ExpireDate              string  `json:"expire_date"`
+	ParentOrderDate         string  `json:"parent_order_date"`
+	ParentOrderAcceptanceID string  `json:"parent_order_acceptance_id"`
+	OutstandingSize         float64 `json:"outstanding_size"`
+	CancelSize              float64 `json:"cancel_size"`
+	ExecutedSize            float64 `json:"executed_size"`
+	TotalCommission         float64 `json:"total_commission"`
+}
+
+// BitFlyerParentOrderQuery represents query parameters for bitFlyer getparentorders API
+// Empty fields are omitted from the request
+type BitFlyerParentOrderQuery struct {
+	ProductCode      string
+	ParentOrderState string
+	Count            int
+}
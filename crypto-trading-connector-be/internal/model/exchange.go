@@ -0,0 +1,157 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Symbol is the exchange-agnostic identifier of a trading pair in BASE/QUOTE form (e.g. "BTC/JPY").
+// Each exchange client translates it to its own product code.
+type Symbol string
+
+// SymbolFromProductCode converts a product code stored in the database (e.g. "BTC_JPY") to a Symbol
+func SymbolFromProductCode(productCode string) Symbol {
+	return Symbol(strings.ReplaceAll(productCode, "_", "/"))
+}
+
+// Base returns the currency being traded (e.g. "BTC")
+func (s Symbol) Base() string {
+	base, _, _ := strings.Cut(string(s), "/")
+	return base
+}
+
+// Quote returns the currency the price is expressed in (e.g. "JPY")
+func (s Symbol) Quote() string {
+	_, quote, _ := strings.Cut(string(s), "/")
+	return quote
+}
+
+// ProductCode returns the BASE_QUOTE form stored in the product_code columns (e.g. "BTC_JPY")
+func (s Symbol) ProductCode() string {
+	return strings.ReplaceAll(string(s), "/", "_")
+}
+
+// OrderSide is the side of an order
+type OrderSide string
+
+const (
+	OrderSideBuy  OrderSide = "BUY"
+	OrderSideSell OrderSide = "SELL"
+)
+
+// OrderType is the execution type of an order
+type OrderType string
+
+const (
+	OrderTypeLimit  OrderType = "LIMIT"
+	OrderTypeMarket OrderType = "MARKET"
+)
+
+// TimeInForce is how long an order stays on the book
+type TimeInForce string
+
+const (
+	TimeInForceGTC TimeInForce = "GTC" // Good Till Cancelled
+	TimeInForceIOC TimeInForce = "IOC" // Immediate Or Cancel
+	TimeInForceFOK TimeInForce = "FOK" // Fill Or Kill
+)
+
+// OrderRequest represents an exchange-agnostic order
+type OrderRequest struct {
+	Symbol        Symbol
+	Side          OrderSide
+	Type          OrderType
+	TimeInForce   TimeInForce // Empty uses the exchange default
+	Price         float64     // Ignored for MARKET orders
+	Size          float64
+	ClientOrderID string // Sent to exchanges that support client order IDs
+}
+
+// OrderResponse represents the result of placing an order
+type OrderResponse struct {
+	OrderID       string // Exchange order ID used to look up and cancel the order
+	ClientOrderID string
+}
+
+// Ticker represents an exchange-agnostic snapshot of the best prices of a symbol
+type Ticker struct {
+	Symbol    Symbol
+	Last      float64 // Last traded price
+	Bid       float64 // Best bid
+	Ask       float64 // Best ask
+	BidSize   float64
+	AskSize   float64
+	Volume    float64 // 24h volume in the base currency
+	Timestamp time.Time
+	Stale     bool // Served from a cache because the exchange could not be reached
+}
+
+// Execution represents a trade executed on an exchange
+type Execution struct {
+	ID        int64
+	Symbol    Symbol
+	Side      OrderSide // Side of the taker order; empty for executions of an auction
+	Price     float64
+	Size      float64
+	Timestamp time.Time
+}
+
+// OrderBookLevel is the total size of the orders at one price
+type OrderBookLevel struct {
+	Price float64
+	Size  float64
+}
+
+// OrderBook represents the bids and asks of a symbol
+type OrderBook struct {
+	Symbol    Symbol
+	MidPrice  float64
+	Bids      []OrderBookLevel // Highest price first
+	Asks      []OrderBookLevel // Lowest price first
+	Timestamp time.Time        // Time the last update was received
+}
+
+// Balance represents the amount of a currency held on an exchange
+type Balance struct {
+	Currency  string
+	Exchange  string  // Name of the exchange holding it (e.g. "bitflyer")
+	Amount    float64 // Total, including the funds the exchange holds back for open orders
+	Available float64 // Free for new orders
+}
+
+// OrderState is the state of an order on an exchange
+type OrderState string
+
+const (
+	OrderStateActive    OrderState = "ACTIVE" // On the book; may be partially executed
+	OrderStateCompleted OrderState = "COMPLETED"
+	OrderStateCanceled  OrderState = "CANCELED"
+	OrderStateExpired   OrderState = "EXPIRED"
+	OrderStateRejected  OrderState = "REJECTED"
+)
+
+// ChildOrder represents an order placed on an exchange together with its live execution state
+type ChildOrder struct {
+	OrderID         string // Order ID returned from SendOrder
+	ClientOrderID   string // Client order ID sent with the order; empty if the exchange does not report one
+	ProductCode     string // BASE_QUOTE form (e.g. "BTC_JPY")
+	Side            OrderSide
+	Type            OrderType
+	Price           float64 // 0 for MARKET orders
+	AveragePrice    float64
+	Size            float64
+	OutstandingSize float64
+	CancelSize      float64
+	ExecutedSize    float64
+	State           OrderState
+	OrderDate       time.Time // Zero if the exchange did not report it
+}
+
+// ChildOrderQuery selects orders on an exchange. Empty fields are not filtered on.
+type ChildOrderQuery struct {
+	ProductCode   string
+	OrderID       string
+	State         OrderState
+	ParentOrderID string // Exchange order ID of the parent order whose legs are listed
+	Count         int
+}
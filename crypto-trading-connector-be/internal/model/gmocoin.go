@@ -0,0 +1,91 @@
+package model
+
+import "encoding/json"
+
+// GMOCoinResponse represents the envelope of every GMO Coin API response
+// Status is 0 on success; otherwise Messages describes the error
+type GMOCoinResponse struct {
+	Status       int              `json:"status"`
+	Data         json.RawMessage  `json:"data"`
+	Messages     []GMOCoinMessage `json:"messages"`
+	ResponseTime string           `json:"responsetime"`
+}
+
+// GMOCoinMessage represents an error message returned by GMO Coin API
+type GMOCoinMessage struct {
+	MessageCode   string `json:"message_code"`   // e.g. ERR-201
+	MessageString string `json:"message_string"` // Human readable description
+}
+
+// GMOCoinTicker represents ticker response from GMO Coin API
+// GMO Coin returns every number as a string
+type GMOCoinTicker struct {
+	Symbol    string  `json:"symbol"`
+	Ask       float64 `json:"ask,string"`
+	Bid       float64 `json:"bid,string"`
+	High      float64 `json:"high,string"`
+	Low       float64 `json:"low,string"`
+	Last      float64 `json:"last,string"`
+	Volume    float64 `json:"volume,string"`
+	Timestamp string  `json:"timestamp"`
+}
+
+// GMOCoinSymbol represents the trading rules of a symbol returned by GMO Coin symbols API
+type GMOCoinSymbol struct {
+	Symbol       string  `json:"symbol"`
+	MinOrderSize float64 `json:"minOrderSize,string"`
+	MaxOrderSize float64 `json:"maxOrderSize,string"`
+	SizeStep     float64 `json:"sizeStep,string"`
+	TickSize     float64 `json:"tickSize,string"`
+	TakerFee     float64 `json:"takerFee,string"`
+	MakerFee     float64 `json:"makerFee,string"`
+}
+
+// GMOCoinAsset represents a single currency of the assets response from GMO Coin API
+type GMOCoinAsset struct {
+	Symbol         string  `json:"symbol"`
+	Amount         float64 `json:"amount,string"`
+	Available      float64 `json:"available,string"`
+	ConversionRate float64 `json:"conversionRate,string"`
+}
+
+// GMOCoinOrderRequest represents order request to GMO Coin API
+type GMOCoinOrderRequest struct {
+	Symbol        string  `json:"symbol"`
+	Side          string  `json:"side"`                   // BUY or SELL
+	ExecutionType string  `json:"executionType"`          // LIMIT or MARKET
+	TimeInForce   string  `json:"timeInForce,omitempty"`  // FAS, FAK, FOK
+	Price         float64 `json:"price,string,omitempty"` // Not sent for MARKET orders
+	Size          float64 `json:"size,string"`
+}
+
+// GMOCoinOrder represents an order returned by GMO Coin orders and activeOrders API
+type GMOCoinOrder struct {
+	OrderID       int64   `json:"orderId"`
+	RootOrderID   int64   `json:"rootOrderId"`
+	Symbol        string  `json:"symbol"`
+	Side          string  `json:"side"`
+	OrderType     string  `json:"orderType"`
+	ExecutionType string  `json:"executionType"`
+	Size          float64 `json:"size,string"`
+	ExecutedSize  float64 `json:"executedSize,string"`
+	Price         float64 `json:"price,string"`
+	Status        string  `json:"status"` // WAITING, ORDERED, MODIFYING, CANCELLING, CANCELED, EXECUTED, EXPIRED
+	TimeInForce   string  `json:"timeInForce"`
+	Timestamp     string  `json:"timestamp"`
+}
+
+// GMOCoinOrderList represents the data of GMO Coin orders and activeOrders API responses
+type GMOCoinOrderList struct {
+	List []GMOCoinOrder `json:"list"`
+}
+
+// GMOCoinCancelOrderRequest represents cancel order request to GMO Coin API
+type GMOCoinCancelOrderRequest struct {
+	OrderID int64 `json:"orderId"`
+}
+
+// GMOCoinCancelBulkOrderRequest represents cancel bulk order request to GMO Coin API
+type GMOCoinCancelBulkOrderRequest struct {
+	Symbols []string `json:"symbols"`
+}
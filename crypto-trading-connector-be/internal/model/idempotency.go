@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Idempotency key statuses stored in the status column of order_idempotency_keys
+const (
+	// IdempotencyStatusInFlight is a key whose order is being placed, or whose order reached the
+	// exchange without a clear answer
+	IdempotencyStatusInFlight = "IN_FLIGHT"
+	// IdempotencyStatusCompleted is a key whose order was placed; Response holds the result
+	IdempotencyStatusCompleted = "COMPLETED"
+)
+
+// IdempotencyKey represents a record from order_idempotency_keys table. It is saved before the
+// order is sent to the exchange, so that a request repeating the key does not place it again.
+type IdempotencyKey struct {
+	Key           string
+	ClientOrderID string // Order ID generated for the request, independent of the exchange order ID
+	RequestHash   string // SHA-256 of the request body, to detect a key reused for another order
+	Status        string
+	OrderID       *string // Exchange order ID, once the order is placed
+	Response      []byte  // JSON of the order returned to the first request, once the order is placed
+	CreatedAt     time.Time
+	ExpiresAt     time.Time
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// Order statuses stored in the status column of buy_orders and sell_orders
+const (
+	OrderStatusUnfilled        = "UNFILLED"
+	OrderStatusPartiallyFilled = "PARTIALLY_FILLED"
+	OrderStatusFilled          = "FILLED"
+	OrderStatusCancelled       = "CANCELLED"
+	OrderStatusExpired         = "EXPIRED"
+
+	// Buy order statuses used while placing the take-profit sell order
+	OrderStatusSellOrderPending = "FILLED(SELL ORDER PENDING)"
+	OrderStatusSellOrderPlaced  = "FILLED(SELL ORDER PLACED)"
+)
+
+// OrderRecord represents an order stored in either buy_orders or sell_orders table
+type OrderRecord struct {
+	ID          int       `db:"id"`
+	OrderID     string    `db:"order_id"`
+	ParentID    *string   `db:"parentid"` // Only set for sell orders placed against a buy order
+	ProductCode string    `db:"product_code"`
+	Side        string    `db:"side"` // BUY or SELL
+	Price       float64   `db:"price"`
+	Size        float64   `db:"size"`
+	Exchange    string    `db:"exchange"`
+	Status      string    `db:"status"`
+	Timestamp   time.Time `db:"timestamp"`
+	Updatetime  time.Time `db:"updatetime"`
+}
+
+// OrderCursor identifies the position after which the next page of orders starts
+// Orders are sorted by timestamp (newest first) and then by order ID
+type OrderCursor struct {
+	Timestamp time.Time
+	OrderID   string
+}
+
+// OrderFilter represents filter and pagination parameters for order queries
+type OrderFilter struct {
+	ProductCode string   // e.g. BTC_JPY; empty means all products
+	Side        string   // BUY or SELL; empty means both
+	Statuses    []string // Database statuses; empty means all statuses
+	From        *time.Time
+	To          *time.Time
+	Cursor      *OrderCursor
+	Limit       int
+}
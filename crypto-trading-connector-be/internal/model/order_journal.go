@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Order journal statuses stored in the status column of order_journal
+const (
+	// OrderJournalStatusPending is an order about to be sent, or sent without an answer from the exchange
+	OrderJournalStatusPending = "PENDING"
+	// OrderJournalStatusAccepted is an order accepted by the exchange but not yet saved in buy_orders or sell_orders
+	OrderJournalStatusAccepted = "ACCEPTED"
+	// OrderJournalStatusRecorded is an order saved in buy_orders or sell_orders
+	OrderJournalStatusRecorded = "RECORDED"
+	// OrderJournalStatusFailed is an order the exchange did not accept
+	OrderJournalStatusFailed = "FAILED"
+	// OrderJournalStatusNotFound is a pending order the recovery could not find on the exchange. It was most likely
+	// never placed, but exchanges that only list open orders (GMO Coin) hide orders that were filled since.
+	OrderJournalStatusNotFound = "NOT_FOUND"
+)
+
+// OrderJournalEntry represents a record from order_journal table. It is saved before an order is sent to
+// the exchange and updated with the exchange order ID once accepted, so that an order placed while the
+// database could not record it is found again by the recovery at startup.
+type OrderJournalEntry struct {
+	ClientOrderID string
+	Exchange      string
+	ProductCode   string
+	Side          string
+	OrderType     string
+	Price         float64 // Limit price sent to the exchange; 0 for market orders
+	RecordedPrice float64 // Price saved in buy_orders or sell_orders: the limit price, or the estimate of a market order
+	Size          float64
+	Status        string
+	OrderID       *string // Exchange order ID, once accepted
+	Message       *string // Why the order failed or could not be recorded
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// OrderRequest returns the request the entry was sent with
+func (e *OrderJournalEntry) OrderRequest() *OrderRequest {
+	return &OrderRequest{
+		Symbol:        SymbolFromProductCode(e.ProductCode),
+		Side:          OrderSide(e.Side),
+		Type:          OrderType(e.OrderType),
+		TimeInForce:   TimeInForceGTC,
+		Price:         e.Price,
+		Size:          e.Size,
+		ClientOrderID: e.ClientOrderID,
+	}
+}
@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+// Special order methods supported by parent orders
+const (
+	ParentOrderMethodIFD    = "IFD"    // If Done: the second leg is placed once the first fills
+	ParentOrderMethodOCO    = "OCO"    // One Cancels the Other: the first leg to fill cancels the other
+	ParentOrderMethodIFDOCO = "IFDOCO" // IFD whose follow-up is an OCO of the second and third legs
+)
+
+// Execution conditions of a parent order leg
+const (
+	ConditionTypeLimit     = "LIMIT"
+	ConditionTypeMarket    = "MARKET"
+	ConditionTypeStop      = "STOP"
+	ConditionTypeStopLimit = "STOP_LIMIT"
+	ConditionTypeTrail     = "TRAIL"
+)
+
+// Parent order statuses stored in the status column of parent_orders
+const (
+	ParentOrderStatusActive    = "ACTIVE"
+	ParentOrderStatusCompleted = "COMPLETED"
+	ParentOrderStatusCancelled = "CANCELLED"
+	ParentOrderStatusExpired   = "EXPIRED"
+)
+
+// ParentOrderLeg represents a single order condition of a special order
+type ParentOrderLeg struct {
+	ProductCode   string  `json:"product_code"`
+	Side          string  `json:"side"` // BUY or SELL
+	ConditionType string  `json:"condition_type"`
+	Size          float64 `json:"size"`
+	Price         float64 `json:"price,omitempty"`         // LIMIT and STOP_LIMIT
+	TriggerPrice  float64 `json:"trigger_price,omitempty"` // STOP and STOP_LIMIT
+	Offset        float64 `json:"offset,omitempty"`        // TRAIL
+}
+
+// ParentOrderRequest represents an exchange-agnostic IFD / OCO / IFDOCO order
+type ParentOrderRequest struct {
+	Method         string
+	Legs           []ParentOrderLeg // In exchange order, e.g. entry first for IFD
+	MinuteToExpire int              // 0 uses the exchange default
+	TimeInForce    string           // GTC, IOC or FOK; empty uses the exchange default
+}
+
+// ParentOrderResponse represents the result of placing a parent order
+type ParentOrderResponse struct {
+	AcceptanceID string
+}
+
+// ParentOrder represents a parent order placed on an exchange together with its state
+type ParentOrder struct {
+	AcceptanceID    string // Returned from SendParentOrder
+	ExchangeOrderID string // Assigned once the exchange has processed the order; lists its legs
+	ProductCode     string
+	State           OrderState
+}
+
+// ParentOrderQuery selects parent orders on an exchange. Empty fields are not filtered on.
+type ParentOrderQuery struct {
+	ProductCode string
+	State       OrderState
+	Count       int
+}
+
+// ParentOrderRecord represents a record from parent_orders table
+type ParentOrderRecord struct {
+	ID              int              `db:"id"`
+	AcceptanceID    string           `db:"parent_order_acceptance_id"`
+	ExchangeOrderID *string          `db:"parent_order_id"`
+	Method          string           `db:"order_method"`
+	ProductCode     string           `db:"product_code"`
+	Legs            []ParentOrderLeg `db:"legs"` // Stored as JSON
+	Exchange        string           `db:"exchange"`
+	Status          string           `db:"status"`
+	Timestamp       time.Time        `db:"timestamp"`
+	Updatetime      time.Time        `db:"updatetime"`
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// stepTolerance absorbs floating point error when prices and sizes are divided by their step
+const stepTolerance = 1e-9
+
+// Product holds the trading rules of a product on the exchange it is traded on
+type Product struct {
+	Symbol         Symbol
+	Exchange       string  // Exchange the product is traded on (e.g. "bitflyer")
+	TickSize       float64 // Smallest price increment
+	LotStep        float64 // Smallest size increment
+	MinSize        float64 // Minimum order size
+	PricePrecision int     // Number of decimal places of prices
+}
+
+// RoundPrice rounds a price to the tick size: down for buy orders and up for sell orders,
+// so that rounding never buys higher or sells lower than requested
+func (p Product) RoundPrice(price float64, side OrderSide) float64 {
+	if p.TickSize <= 0 {
+		return price
+	}
+
+	steps := price / p.TickSize
+	if side == OrderSideSell {
+		steps = math.Ceil(steps - stepTolerance)
+	} else {
+		steps = math.Floor(steps + stepTolerance)
+	}
+
+	// Multiplying by the tick size reintroduces floating point error, so cut it at the precision
+	scale := math.Pow(10, float64(p.PricePrecision))
+	return math.Round(steps*p.TickSize*scale) / scale
+}
+
+// IsLotMultiple reports whether a size is a whole number of lot steps
+func (p Product) IsLotMultiple(size float64) bool {
+	if p.LotStep <= 0 {
+		return true
+	}
+	steps := size / p.LotStep
+	return math.Abs(steps-math.Round(steps)) < stepTolerance*math.Max(1, steps)
+}
+
+// DecimalPlaces returns the number of decimal places of a step such as a tick size (0.001 -> 3)
+func DecimalPlaces(step float64) int {
+	formatted := strconv.FormatFloat(step, 'f', -1, 64)
+	_, decimals, found := strings.Cut(formatted, ".")
+	if !found {
+		return 0
+	}
+	return len(decimals)
+}
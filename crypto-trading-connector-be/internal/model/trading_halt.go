@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// TradingHalt represents the single row of trading_halt table: the kill switch that stops every order
+// sent by the server and the CLIs while it is engaged
+type TradingHalt struct {
+	Halted    bool
+	Reason    *string
+	UpdatedAt *time.Time // nil if the halt was never engaged or released
+}
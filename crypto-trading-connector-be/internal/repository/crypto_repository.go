@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -10,7 +11,7 @@ import (
 
 // CryptoRepository defines the interface for cryptocurrency data operations
 type CryptoRepository interface {
-	GetDailyAveragePrices(productCode string, days int) ([]generated.ChartDataPoint, error)
+	GetDailyAveragePrices(ctx context.Context, productCode string, days int) ([]generated.ChartDataPoint, error)
 }
 
 // MySQLCryptoRepository implements CryptoRepository with MySQL
@@ -26,7 +27,7 @@ func NewMySQLCryptoRepository(db *sql.DB) *MySQLCryptoRepository {
 }
 
 // GetDailyAveragePrices retrieves daily average prices for the specified product and number of days
-func (r *MySQLCryptoRepository) GetDailyAveragePrices(productCode string, days int) ([]generated.ChartDataPoint, error) {
+func (r *MySQLCryptoRepository) GetDailyAveragePrices(ctx context.Context, productCode string, days int) ([]generated.ChartDataPoint, error) {
 	query := `
 		SELECT 
 			DATE(datetime) as date,
@@ -38,7 +39,7 @@ func (r *MySQLCryptoRepository) GetDailyAveragePrices(productCode string, days i
 		ORDER BY date ASC
 	`
 
-	rows, err := r.db.Query(query, productCode, days)
+	rows, err := r.db.QueryContext(ctx, query, productCode, days)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query price histories: %w", err)
 	}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// IdempotencyRepository defines the interface for the idempotency keys of order requests
+type IdempotencyRepository interface {
+	ReserveIdempotencyKey(ctx context.Context, key *model.IdempotencyKey) (*model.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, key, orderID string, response []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+}
+
+// MySQLIdempotencyRepository implements IdempotencyRepository with MySQL
+type MySQLIdempotencyRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLIdempotencyRepository creates a new MySQL idempotency key repository
+func NewMySQLIdempotencyRepository(db *sql.DB) *MySQLIdempotencyRepository {
+	return &MySQLIdempotencyRepository{
+		db: db,
+	}
+}
+
+// ReserveIdempotencyKey saves the key as in flight. If the key is already saved and has not expired,
+// nothing is saved and the existing key is returned; otherwise it returns nil. Expired keys are deleted.
+func (r *MySQLIdempotencyRepository) ReserveIdempotencyKey(ctx context.Context, key *model.IdempotencyKey) (*model.IdempotencyKey, error) {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM order_idempotency_keys WHERE expires_at <= ?`, key.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	insert := `
+		INSERT IGNORE INTO order_idempotency_keys (idempotency_key, client_order_id, request_hash, status, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, insert, key.Key, key.ClientOrderID, key.RequestHash, model.IdempotencyStatusInFlight, key.CreatedAt, key.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save idempotency key: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 1 {
+		key.Status = model.IdempotencyStatusInFlight
+		return nil, nil
+	}
+
+	query := `
+		SELECT idempotency_key, client_order_id, request_hash, status, order_id, response, created_at, expires_at
+		FROM order_idempotency_keys
+		WHERE idempotency_key = ?
+	`
+
+	var existing model.IdempotencyKey
+	var orderID, response sql.NullString
+	err = r.db.QueryRowContext(ctx, query, key.Key).Scan(
+		&existing.Key,
+		&existing.ClientOrderID,
+		&existing.RequestHash,
+		&existing.Status,
+		&orderID,
+		&response,
+		&existing.CreatedAt,
+		&existing.ExpiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released by the request that held it since the insert
+		return nil, fmt.Errorf("failed to save idempotency key %s: released concurrently", key.Key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	if orderID.Valid {
+		existing.OrderID = &orderID.String
+	}
+	if response.Valid {
+		existing.Response = []byte(response.String)
+	}
+
+	return &existing, nil
+}
+
+// CompleteIdempotencyKey records the order placed for the key and the response returned for it
+func (r *MySQLIdempotencyRepository) CompleteIdempotencyKey(ctx context.Context, key, orderID string, response []byte) error {
+	query := `
+		UPDATE order_idempotency_keys
+		SET status = ?, order_id = ?, response = ?
+		WHERE idempotency_key = ?
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, model.IdempotencyStatusCompleted, orderID, string(response), key); err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey deletes an in-flight key whose order was not placed, so that the request can be retried
+func (r *MySQLIdempotencyRepository) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	query := `DELETE FROM order_idempotency_keys WHERE idempotency_key = ? AND status = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, key, model.IdempotencyStatusInFlight); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
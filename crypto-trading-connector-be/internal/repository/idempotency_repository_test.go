@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRepository_ReserveIdempotencyKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMySQLIdempotencyRepository(db)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	key := &model.IdempotencyKey{Key: "key-1", ClientOrderID: "5f0c2a3e-8d5b-4f0e-9a51-3c0b6a1f2e77", RequestHash: "abc", CreatedAt: now, ExpiresAt: now.Add(24 * time.Hour)}
+
+	mock.ExpectExec(`DELETE FROM order_idempotency_keys WHERE expires_at <= \?`).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT IGNORE INTO order_idempotency_keys`).
+		WithArgs("key-1", key.ClientOrderID, "abc", model.IdempotencyStatusInFlight, now, key.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	existing, err := repo.ReserveIdempotencyKey(context.Background(), key)
+
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+	assert.Equal(t, model.IdempotencyStatusInFlight, key.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepository_ReserveIdempotencyKey_Existing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMySQLIdempotencyRepository(db)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	key := &model.IdempotencyKey{Key: "key-1", ClientOrderID: "new", RequestHash: "abc", CreatedAt: now, ExpiresAt: now.Add(24 * time.Hour)}
+
+	mock.ExpectExec(`DELETE FROM order_idempotency_keys`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT IGNORE INTO order_idempotency_keys`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"idempotency_key", "client_order_id", "request_hash", "status", "order_id", "response", "created_at", "expires_at"}).
+		AddRow("key-1", "first", "abc", model.IdempotencyStatusCompleted, "JRF-1", `{"orderId":"first"}`, now.Add(-time.Hour), now.Add(23*time.Hour))
+	mock.ExpectQuery(`SELECT idempotency_key, client_order_id, request_hash, status, order_id, response, created_at, expires_at\s+FROM order_idempotency_keys\s+WHERE idempotency_key = \?`).
+		WithArgs("key-1").
+		WillReturnRows(rows)
+
+	existing, err := repo.ReserveIdempotencyKey(context.Background(), key)
+
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, "first", existing.ClientOrderID)
+	assert.Equal(t, model.IdempotencyStatusCompleted, existing.Status)
+	assert.Equal(t, "JRF-1", *existing.OrderID)
+	assert.JSONEq(t, `{"orderId":"first"}`, string(existing.Response))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// OrderJournalRepository defines the interface for the write-ahead journal of orders sent to exchanges
+type OrderJournalRepository interface {
+	SaveJournalEntry(ctx context.Context, entry *model.OrderJournalEntry) error
+	MarkJournalEntryAccepted(ctx context.Context, clientOrderID, orderID string) error
+	UpdateJournalEntryStatus(ctx context.Context, clientOrderID, status string, message *string) error
+	ListUnresolvedJournalEntries(ctx context.Context, before time.Time) ([]model.OrderJournalEntry, error)
+}
+
+// MySQLOrderJournalRepository implements OrderJournalRepository with MySQL
+type MySQLOrderJournalRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLOrderJournalRepository creates a new MySQL order journal repository
+func NewMySQLOrderJournalRepository(db *sql.DB) *MySQLOrderJournalRepository {
+	return &MySQLOrderJournalRepository{
+		db: db,
+	}
+}
+
+// SaveJournalEntry records an order as pending before it is sent
+func (r *MySQLOrderJournalRepository) SaveJournalEntry(ctx context.Context, entry *model.OrderJournalEntry) error {
+	query := `
+		INSERT INTO order_journal (client_order_id, exchange, product_code, side, order_type, price, recorded_price, size, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		entry.ClientOrderID,
+		entry.Exchange,
+		entry.ProductCode,
+		entry.Side,
+		entry.OrderType,
+		entry.Price,
+		entry.RecordedPrice,
+		entry.Size,
+		model.OrderJournalStatusPending,
+		entry.CreatedAt,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save order journal entry: %w", err)
+	}
+	entry.Status = model.OrderJournalStatusPending
+
+	return nil
+}
+
+// MarkJournalEntryAccepted records the exchange order ID of an accepted order
+func (r *MySQLOrderJournalRepository) MarkJournalEntryAccepted(ctx context.Context, clientOrderID, orderID string) error {
+	query := `
+		UPDATE order_journal
+		SET status = ?, order_id = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE client_order_id = ?
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, model.OrderJournalStatusAccepted, orderID, clientOrderID); err != nil {
+		return fmt.Errorf("failed to mark order journal entry accepted: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateJournalEntryStatus sets the status of an entry and why it was set
+func (r *MySQLOrderJournalRepository) UpdateJournalEntryStatus(ctx context.Context, clientOrderID, status string, message *string) error {
+	query := `
+		UPDATE order_journal
+		SET status = ?, message = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE client_order_id = ?
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, status, message, clientOrderID); err != nil {
+		return fmt.Errorf("failed to update order journal entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListUnresolvedJournalEntries returns the pending and accepted entries created before the given time, oldest first
+func (r *MySQLOrderJournalRepository) ListUnresolvedJournalEntries(ctx context.Context, before time.Time) ([]model.OrderJournalEntry, error) {
+	query := `
+		SELECT client_order_id, exchange, product_code, side, order_type, price, recorded_price, size, status, order_id, message, created_at, updated_at
+		FROM order_journal
+		WHERE status IN (?, ?) AND created_at < ?
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, model.OrderJournalStatusPending, model.OrderJournalStatusAccepted, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []model.OrderJournalEntry
+	for rows.Next() {
+		var entry model.OrderJournalEntry
+		var orderID, message sql.NullString
+		if err := rows.Scan(
+			&entry.ClientOrderID,
+			&entry.Exchange,
+			&entry.ProductCode,
+			&entry.Side,
+			&entry.OrderType,
+			&entry.Price,
+			&entry.RecordedPrice,
+			&entry.Size,
+			&entry.Status,
+			&orderID,
+			&message,
+			&entry.CreatedAt,
+			&entry.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan order journal entry: %w", err)
+		}
+		if orderID.Valid {
+			entry.OrderID = &orderID.String
+		}
+		if message.Valid {
+			entry.Message = &message.String
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get order journal entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderJournalRepository_SaveJournalEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMySQLOrderJournalRepository(db)
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectExec(`INSERT INTO order_journal \(client_order_id, exchange, product_code, side, order_type, price, recorded_price, size, status, created_at, updated_at\)`).
+		WithArgs("client-1", "bitflyer", "BTC_JPY", "BUY", "MARKET", 0.0, 14000000.0, 0.001, model.OrderJournalStatusPending, createdAt, createdAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	entry := &model.OrderJournalEntry{
+		ClientOrderID: "client-1",
+		Exchange:      "bitflyer",
+		ProductCode:   "BTC_JPY",
+		Side:          "BUY",
+		OrderType:     "MARKET",
+		RecordedPrice: 14000000,
+		Size:          0.001,
+		CreatedAt:     createdAt,
+	}
+	err = repo.SaveJournalEntry(context.Background(), entry)
+
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderJournalStatusPending, entry.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderJournalRepository_MarkJournalEntryAccepted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMySQLOrderJournalRepository(db)
+
+	mock.ExpectExec(`UPDATE order_journal\s+SET status = \?, order_id = \?`).
+		WithArgs(model.OrderJournalStatusAccepted, "JRF20240101-000000-000001", "client-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.MarkJournalEntryAccepted(context.Background(), "client-1", "JRF20240101-000000-000001")
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderJournalRepository_ListUnresolvedJournalEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMySQLOrderJournalRepository(db)
+
+	before := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	createdAt := before.Add(-time.Hour)
+	rows := sqlmock.NewRows([]string{"client_order_id", "exchange", "product_code", "side", "order_type", "price", "recorded_price", "size", "status", "order_id", "message", "created_at", "updated_at"}).
+		AddRow("client-1", "bitflyer", "BTC_JPY", "BUY", "LIMIT", 14000000.0, 14000000.0, 0.001, model.OrderJournalStatusPending, nil, nil, createdAt, createdAt).
+		AddRow("client-2", "gmocoin", "ETH_JPY", "SELL", "LIMIT", 500000.0, 500000.0, 0.1, model.OrderJournalStatusAccepted, "123456", nil, createdAt, createdAt)
+	mock.ExpectQuery(`FROM order_journal\s+WHERE status IN \(\?, \?\) AND created_at < \?\s+ORDER BY created_at ASC`).
+		WithArgs(model.OrderJournalStatusPending, model.OrderJournalStatusAccepted, before).
+		WillReturnRows(rows)
+
+	entries, err := repo.ListUnresolvedJournalEntries(context.Background(), before)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Nil(t, entries[0].OrderID)
+	assert.Equal(t, 0.001, entries[0].Size)
+	require.NotNil(t, entries[1].OrderID)
+	assert.Equal(t, "123456", *entries[1].OrderID)
+	assert.Equal(t, createdAt, entries[1].CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
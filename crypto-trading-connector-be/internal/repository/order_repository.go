@@ -1,19 +1,47 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/crypto-trading-connector/backend/internal/model"
 )
 
+// ErrOrderNotFound is returned when no order has the requested order ID
+var ErrOrderNotFound = errors.New("order not found")
+
 // OrderRepository defines the interface for order data access
 type OrderRepository interface {
-	SaveOrder(order *model.BuyOrder) error
-	GetOrderByID(orderID string) (*model.BuyOrder, error)
+	SaveOrder(ctx context.Context, order *model.BuyOrder) error
+	GetOrderByID(ctx context.Context, orderID string) (*model.BuyOrder, error)
+	UpdateOrderStatus(ctx context.Context, orderID, status string) error
+	ListOrders(ctx context.Context, filter *model.OrderFilter) ([]model.OrderRecord, error)
+	FindOrder(ctx context.Context, orderID string) (*model.OrderRecord, error)
+	ListOpenOrders(ctx context.Context) ([]model.OrderRecord, error)
+	TransitionOrderStatus(ctx context.Context, side, orderID, fromStatus, toStatus string) (bool, error)
+	ListBuyOrdersByStatus(ctx context.Context, status string) ([]model.BuyOrder, error)
+	SumBuyOrderSize(ctx context.Context, productCode string, since time.Time) (float64, error)
+	SaveSellOrder(ctx context.Context, order *model.SellOrder) error
+	GetSellOrderByParentID(ctx context.Context, parentID string) (*model.SellOrder, error)
 }
 
+// allOrdersQuery combines buy_orders and sell_orders into a single result set
+const allOrdersQuery = `
+	SELECT id, order_id, parentid, product_code, side, price, size, exchange, status, timestamp, updatetime
+	FROM (
+		SELECT id, order_id, NULL AS parentid, product_code, side, price, size, exchange, status, timestamp, updatetime
+		FROM buy_orders
+		UNION ALL
+		SELECT id, order_id, parentid, product_code, side, price, size, exchange, status, timestamp, updatetime
+		FROM sell_orders
+	) o
+	WHERE 1 = 1
+`
+
 // OrderRepositoryImpl implements OrderRepository
 type OrderRepositoryImpl struct {
 	db *sql.DB
@@ -27,7 +55,7 @@ func NewOrderRepository(db *sql.DB) *OrderRepositoryImpl {
 }
 
 // SaveOrder saves a buy order to the database
-func (r *OrderRepositoryImpl) SaveOrder(order *model.BuyOrder) error {
+func (r *OrderRepositoryImpl) SaveOrder(ctx context.Context, order *model.BuyOrder) error {
 	query := `
 		INSERT INTO buy_orders (
 			order_id, product_code, side, price, size, 
@@ -36,7 +64,8 @@ func (r *OrderRepositoryImpl) SaveOrder(order *model.BuyOrder) error {
 	`
 
 	now := time.Now()
-	_, err := r.db.Exec(
+	_, err := r.db.ExecContext(
+		ctx,
 		query,
 		order.OrderID,
 		order.ProductCode,
@@ -59,7 +88,7 @@ func (r *OrderRepositoryImpl) SaveOrder(order *model.BuyOrder) error {
 }
 
 // GetOrderByID retrieves an order by its order ID
-func (r *OrderRepositoryImpl) GetOrderByID(orderID string) (*model.BuyOrder, error) {
+func (r *OrderRepositoryImpl) GetOrderByID(ctx context.Context, orderID string) (*model.BuyOrder, error) {
 	query := `
 		SELECT id, order_id, product_code, side, price, size, 
 		       exchange, status, strategy, remarks, timestamp, updatetime
@@ -68,7 +97,7 @@ func (r *OrderRepositoryImpl) GetOrderByID(orderID string) (*model.BuyOrder, err
 	`
 
 	var order model.BuyOrder
-	err := r.db.QueryRow(query, orderID).Scan(
+	err := r.db.QueryRowContext(ctx, query, orderID).Scan(
 		&order.ID,
 		&order.OrderID,
 		&order.ProductCode,
@@ -84,7 +113,7 @@ func (r *OrderRepositoryImpl) GetOrderByID(orderID string) (*model.BuyOrder, err
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("order not found: %s", orderID)
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get order: %w", err)
@@ -92,3 +121,349 @@ func (r *OrderRepositoryImpl) GetOrderByID(orderID string) (*model.BuyOrder, err
 
 	return &order, nil
 }
+
+// ListBuyOrdersByStatus retrieves buy orders with the given status, oldest first.
+// Legs of special orders are excluded because the exchange places their exit orders.
+func (r *OrderRepositoryImpl) ListBuyOrdersByStatus(ctx context.Context, status string) ([]model.BuyOrder, error) {
+	query := `
+		SELECT id, order_id, product_code, side, price, size,
+		       exchange, status, strategy, remarks, timestamp, updatetime
+		FROM buy_orders
+		WHERE status = ? AND parent_order_id IS NULL
+		ORDER BY timestamp ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query buy orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := []model.BuyOrder{}
+	for rows.Next() {
+		var order model.BuyOrder
+		if err := rows.Scan(
+			&order.ID,
+			&order.OrderID,
+			&order.ProductCode,
+			&order.Side,
+			&order.Price,
+			&order.Size,
+			&order.Exchange,
+			&order.Status,
+			&order.Strategy,
+			&order.Remarks,
+			&order.Timestamp,
+			&order.Updatetime,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan buy order row: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating buy order rows: %w", err)
+	}
+
+	return orders, nil
+}
+
+// SumBuyOrderSize returns the total size of the buy orders of the product placed since the given time.
+// Cancelled and expired orders are left out, since they did not buy anything once their partial fills are
+// recorded as sell orders; open orders count with their full size.
+func (r *OrderRepositoryImpl) SumBuyOrderSize(ctx context.Context, productCode string, since time.Time) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(size), 0)
+		FROM buy_orders
+		WHERE product_code = ? AND timestamp >= ?
+		  AND (status IS NULL OR status NOT IN (?, ?))
+	`
+
+	var total float64
+	if err := r.db.QueryRowContext(ctx, query, productCode, since, model.OrderStatusCancelled, model.OrderStatusExpired).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to sum buy orders: %w", err)
+	}
+
+	return total, nil
+}
+
+// SaveSellOrder saves a sell order to the database
+func (r *OrderRepositoryImpl) SaveSellOrder(ctx context.Context, order *model.SellOrder) error {
+	query := `
+		INSERT INTO sell_orders (
+			parentid, order_id, product_code, side, price, size,
+			exchange, status, remarks, timestamp, updatetime
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	// Sell orders placed directly (not as a take-profit) have no parent buy order
+	var parentID any
+	if order.ParentID != "" {
+		parentID = order.ParentID
+	}
+
+	now := time.Now()
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		parentID,
+		order.OrderID,
+		order.ProductCode,
+		order.Side,
+		order.Price,
+		order.Size,
+		order.Exchange,
+		order.Status,
+		order.Remarks,
+		now,
+		now,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to save sell order: %w", err)
+	}
+
+	return nil
+}
+
+// GetSellOrderByParentID retrieves the sell order placed against a buy order.
+// It returns nil without an error if no sell order has been recorded yet.
+func (r *OrderRepositoryImpl) GetSellOrderByParentID(ctx context.Context, parentID string) (*model.SellOrder, error) {
+	query := `
+		SELECT id, parentid, order_id, product_code, side, price, size,
+		       exchange, status, remarks, timestamp, updatetime
+		FROM sell_orders
+		WHERE parentid = ?
+		LIMIT 1
+	`
+
+	var order model.SellOrder
+	err := r.db.QueryRowContext(ctx, query, parentID).Scan(
+		&order.ID,
+		&order.ParentID,
+		&order.OrderID,
+		&order.ProductCode,
+		&order.Side,
+		&order.Price,
+		&order.Size,
+		&order.Exchange,
+		&order.Status,
+		&order.Remarks,
+		&order.Timestamp,
+		&order.Updatetime,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sell order: %w", err)
+	}
+
+	return &order, nil
+}
+
+// UpdateOrderStatus updates the status of a buy order
+func (r *OrderRepositoryImpl) UpdateOrderStatus(ctx context.Context, orderID, status string) error {
+	query := `
+		UPDATE buy_orders
+		SET status = ?, updatetime = ?
+		WHERE order_id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, status, time.Now(), orderID)
+	if err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
+	}
+
+	return nil
+}
+
+// ListOrders retrieves buy and sell orders matching the filter, newest first
+func (r *OrderRepositoryImpl) ListOrders(ctx context.Context, filter *model.OrderFilter) ([]model.OrderRecord, error) {
+	query := allOrdersQuery
+	args := []any{}
+
+	if filter.ProductCode != "" {
+		query += " AND o.product_code = ?"
+		args = append(args, filter.ProductCode)
+	}
+
+	if filter.Side != "" {
+		query += " AND o.side = ?"
+		args = append(args, filter.Side)
+	}
+
+	if len(filter.Statuses) > 0 {
+		placeholders := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			placeholders[i] = "?"
+			args = append(args, status)
+		}
+		query += " AND o.status IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	if filter.From != nil {
+		query += " AND o.timestamp >= ?"
+		args = append(args, *filter.From)
+	}
+
+	if filter.To != nil {
+		query += " AND o.timestamp < ?"
+		args = append(args, *filter.To)
+	}
+
+	// Keyset pagination: continue strictly after the last order of the previous page
+	if filter.Cursor != nil {
+		query += " AND (o.timestamp < ? OR (o.timestamp = ? AND o.order_id < ?))"
+		args = append(args, filter.Cursor.Timestamp, filter.Cursor.Timestamp, filter.Cursor.OrderID)
+	}
+
+	query += " ORDER BY o.timestamp DESC, o.order_id DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := []model.OrderRecord{}
+	for rows.Next() {
+		order, err := scanOrderRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating order rows: %w", err)
+	}
+
+	return orders, nil
+}
+
+// FindOrder retrieves a buy or sell order by its order ID
+func (r *OrderRepositoryImpl) FindOrder(ctx context.Context, orderID string) (*model.OrderRecord, error) {
+	query := allOrdersQuery + " AND o.order_id = ? LIMIT 1"
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to get order: %w", err)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
+	}
+
+	return scanOrderRecord(rows)
+}
+
+// scanOrderRecord scans a row produced by allOrdersQuery
+func scanOrderRecord(rows *sql.Rows) (*model.OrderRecord, error) {
+	var (
+		order    model.OrderRecord
+		parentID sql.NullString
+		exchange sql.NullString
+		status   sql.NullString
+	)
+
+	if err := rows.Scan(
+		&order.ID,
+		&order.OrderID,
+		&parentID,
+		&order.ProductCode,
+		&order.Side,
+		&order.Price,
+		&order.Size,
+		&exchange,
+		&status,
+		&order.Timestamp,
+		&order.Updatetime,
+	); err != nil {
+		return nil, fmt.Errorf("failed to scan order row: %w", err)
+	}
+
+	if parentID.Valid {
+		order.ParentID = &parentID.String
+	}
+	order.Exchange = exchange.String
+	order.Status = status.String
+
+	return &order, nil
+}
+
+// ListOpenOrders retrieves buy and sell orders that may still be resting on the exchange
+func (r *OrderRepositoryImpl) ListOpenOrders(ctx context.Context) ([]model.OrderRecord, error) {
+	query := allOrdersQuery + " AND o.status IN (?, ?) ORDER BY o.timestamp ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, model.OrderStatusUnfilled, model.OrderStatusPartiallyFilled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := []model.OrderRecord{}
+	for rows.Next() {
+		order, err := scanOrderRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating order rows: %w", err)
+	}
+
+	return orders, nil
+}
+
+// TransitionOrderStatus changes the status of an order only if it still has fromStatus.
+// It returns false when the row was changed concurrently (e.g. cancelled via the API)
+// so that callers never overwrite a newer status.
+func (r *OrderRepositoryImpl) TransitionOrderStatus(ctx context.Context, side, orderID, fromStatus, toStatus string) (bool, error) {
+	table, err := orderTable(side)
+	if err != nil {
+		return false, err
+	}
+
+	query := `UPDATE ` + table + ` SET status = ?, updatetime = ? WHERE order_id = ? AND status = ?`
+
+	result, err := r.db.ExecContext(ctx, query, toStatus, time.Now(), orderID, fromStatus)
+	if err != nil {
+		return false, fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// orderTable returns the table that stores orders of the given side
+func orderTable(side string) (string, error) {
+	switch side {
+	case "BUY":
+		return "buy_orders", nil
+	case "SELL":
+		return "sell_orders", nil
+	default:
+		return "", fmt.Errorf("unknown order side: %s", side)
+	}
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_UpdateOrderStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOrderRepository(db)
+
+	mock.ExpectExec(`UPDATE buy_orders\s+SET status = \?, updatetime = \?\s+WHERE order_id = \?`).
+		WithArgs("CANCELLED", sqlmock.AnyArg(), "JRF-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdateOrderStatus(context.Background(), "JRF-1", "CANCELLED")
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_UpdateOrderStatus_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOrderRepository(db)
+
+	mock.ExpectExec(`UPDATE buy_orders`).
+		WithArgs("CANCELLED", sqlmock.AnyArg(), "JRF-404").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateOrderStatus(context.Background(), "JRF-404", "CANCELLED")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "order not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_ListOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOrderRepository(db)
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cursor := &model.OrderCursor{Timestamp: created, OrderID: "JRF-9"}
+
+	rows := sqlmock.NewRows([]string{"id", "order_id", "parentid", "product_code", "side", "price", "size", "exchange", "status", "timestamp", "updatetime"}).
+		AddRow(2, "JRF-SELL-1", "JRF-BUY-1", "BTC_JPY", "SELL", 15000000.0, 0.002, "bitflyer", "UNFILLED", created, created).
+		AddRow(1, "JRF-BUY-1", nil, "BTC_JPY", "BUY", 14000000.0, 0.002, "bitflyer", "FILLED", created, created)
+
+	mock.ExpectQuery(`FROM buy_orders\s+UNION ALL.*FROM sell_orders.*o\.product_code = \? AND o\.status IN \(\?, \?\).*ORDER BY o\.timestamp DESC, o\.order_id DESC LIMIT \?`).
+		WithArgs("BTC_JPY", "UNFILLED", "FILLED", created, created, "JRF-9", 21).
+		WillReturnRows(rows)
+
+	orders, err := repo.ListOrders(context.Background(), &model.OrderFilter{
+		ProductCode: "BTC_JPY",
+		Statuses:    []string{"UNFILLED", "FILLED"},
+		Cursor:      cursor,
+		Limit:       21,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	require.NotNil(t, orders[0].ParentID)
+	assert.Equal(t, "JRF-BUY-1", *orders[0].ParentID)
+	assert.Equal(t, "SELL", orders[0].Side)
+	assert.Nil(t, orders[1].ParentID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_FindOrder_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOrderRepository(db)
+
+	mock.ExpectQuery(`o\.order_id = \?`).
+		WithArgs("JRF-404").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	order, err := repo.FindOrder(context.Background(), "JRF-404")
+
+	require.Error(t, err)
+	assert.Nil(t, order)
+	assert.Contains(t, err.Error(), "order not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_TransitionOrderStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOrderRepository(db)
+
+	mock.ExpectExec(`UPDATE sell_orders SET status = \?, updatetime = \? WHERE order_id = \? AND status = \?`).
+		WithArgs("FILLED", sqlmock.AnyArg(), "JRF-SELL-1", "UNFILLED").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE buy_orders SET status = \?, updatetime = \? WHERE order_id = \? AND status = \?`).
+		WithArgs("FILLED", sqlmock.AnyArg(), "JRF-BUY-1", "UNFILLED").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	updated, err := repo.TransitionOrderStatus(context.Background(), "SELL", "JRF-SELL-1", "UNFILLED", "FILLED")
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	// The row was changed by someone else in the meantime
+	updated, err = repo.TransitionOrderStatus(context.Background(), "BUY", "JRF-BUY-1", "UNFILLED", "FILLED")
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	_, err = repo.TransitionOrderStatus(context.Background(), "HOLD", "JRF-1", "UNFILLED", "FILLED")
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_GetSellOrderByParentID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOrderRepository(db)
+
+	mock.ExpectQuery(`FROM sell_orders\s+WHERE parentid = \?`).
+		WithArgs("JRF-BUY-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	order, err := repo.GetSellOrderByParentID(context.Background(), "JRF-BUY-1")
+
+	require.NoError(t, err)
+	assert.Nil(t, order)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_SaveSellOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOrderRepository(db)
+
+	mock.ExpectExec(`INSERT INTO sell_orders`).
+		WithArgs("JRF-BUY-1", "JRF-SELL-1", "BTC_JPY", "SELL", 10200000.0, 0.001, "bitflyer", "UNFILLED", nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.SaveSellOrder(context.Background(), &model.SellOrder{
+		ParentID:    "JRF-BUY-1",
+		OrderID:     "JRF-SELL-1",
+		ProductCode: "BTC_JPY",
+		Side:        "SELL",
+		Price:       10200000,
+		Size:        0.001,
+		Exchange:    "bitflyer",
+		Status:      "UNFILLED",
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_SumBuyOrderSize(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOrderRepository(db)
+
+	since := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	// Cancelled and expired orders bought nothing
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(size\), 0\)\s+FROM buy_orders\s+WHERE product_code = \? AND timestamp >= \?\s+AND \(status IS NULL OR status NOT IN \(\?, \?\)\)`).
+		WithArgs("BTC_JPY", since, "CANCELLED", "EXPIRED").
+		WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(0.35))
+
+	total, err := repo.SumBuyOrderSize(context.Background(), "BTC_JPY", since)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0.35, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// ParentOrderRepository defines the interface for special order (IFD / OCO / IFDOCO) data access
+type ParentOrderRepository interface {
+	SaveParentOrder(ctx context.Context, order *model.ParentOrderRecord) error
+	ListActiveParentOrders(ctx context.Context) ([]model.ParentOrderRecord, error)
+	UpdateParentOrder(ctx context.Context, acceptanceID, exchangeOrderID, status string) error
+	ListChildOrders(ctx context.Context, acceptanceID string) ([]model.OrderRecord, error)
+	SaveChildOrder(ctx context.Context, acceptanceID string, child *model.OrderRecord) error
+}
+
+// ParentOrderRepositoryImpl implements ParentOrderRepository
+type ParentOrderRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewParentOrderRepository creates a new parent order repository
+func NewParentOrderRepository(db *sql.DB) *ParentOrderRepositoryImpl {
+	return &ParentOrderRepositoryImpl{
+		db: db,
+	}
+}
+
+// SaveParentOrder saves a parent order to the database
+func (r *ParentOrderRepositoryImpl) SaveParentOrder(ctx context.Context, order *model.ParentOrderRecord) error {
+	legs, err := json.Marshal(order.Legs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal parent order legs: %w", err)
+	}
+
+	query := `
+		INSERT INTO parent_orders (
+			parent_order_acceptance_id, order_method, product_code, legs,
+			exchange, status, timestamp, updatetime
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	now := time.Now()
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		order.AcceptanceID,
+		order.Method,
+		order.ProductCode,
+		string(legs),
+		order.Exchange,
+		order.Status,
+		now,
+		now,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to save parent order: %w", err)
+	}
+
+	return nil
+}
+
+// ListActiveParentOrders retrieves parent orders whose legs may still be triggered on the exchange
+func (r *ParentOrderRepositoryImpl) ListActiveParentOrders(ctx context.Context) ([]model.ParentOrderRecord, error) {
+	query := `
+		SELECT id, parent_order_acceptance_id, parent_order_id, order_method, product_code,
+		       legs, exchange, status, timestamp, updatetime
+		FROM parent_orders
+		WHERE status = ?
+		ORDER BY timestamp ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, model.ParentOrderStatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query parent orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := []model.ParentOrderRecord{}
+	for rows.Next() {
+		var (
+			order           model.ParentOrderRecord
+			exchangeOrderID sql.NullString
+			exchange        sql.NullString
+			legs            string
+		)
+		if err := rows.Scan(
+			&order.ID,
+			&order.AcceptanceID,
+			&exchangeOrderID,
+			&order.Method,
+			&order.ProductCode,
+			&legs,
+			&exchange,
+			&order.Status,
+			&order.Timestamp,
+			&order.Updatetime,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan parent order row: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(legs), &order.Legs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal legs of parent order %s: %w", order.AcceptanceID, err)
+		}
+		if exchangeOrderID.Valid {
+			order.ExchangeOrderID = &exchangeOrderID.String
+		}
+		order.Exchange = exchange.String
+
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating parent order rows: %w", err)
+	}
+
+	return orders, nil
+}
+
+// UpdateParentOrder records the exchange parent order ID and status of a parent order
+func (r *ParentOrderRepositoryImpl) UpdateParentOrder(ctx context.Context, acceptanceID, exchangeOrderID, status string) error {
+	query := `
+		UPDATE parent_orders
+		SET parent_order_id = ?, status = ?, updatetime = ?
+		WHERE parent_order_acceptance_id = ?
+	`
+
+	var orderID any
+	if exchangeOrderID != "" {
+		orderID = exchangeOrderID
+	}
+
+	result, err := r.db.ExecContext(ctx, query, orderID, status, time.Now(), acceptanceID)
+	if err != nil {
+		return fmt.Errorf("failed to update parent order: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("parent order not found: %s", acceptanceID)
+	}
+
+	return nil
+}
+
+// ListChildOrders retrieves the legs of a parent order already recorded in buy_orders and sell_orders
+func (r *ParentOrderRepositoryImpl) ListChildOrders(ctx context.Context, acceptanceID string) ([]model.OrderRecord, error) {
+	query := `
+		SELECT id, order_id, NULL AS parentid, product_code, side, price, size, exchange, status, timestamp, updatetime
+		FROM buy_orders
+		WHERE parent_order_id = ?
+		UNION ALL
+		SELECT id, order_id, parentid, product_code, side, price, size, exchange, status, timestamp, updatetime
+		FROM sell_orders
+		WHERE parent_order_id = ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, acceptanceID, acceptanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query child orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := []model.OrderRecord{}
+	for rows.Next() {
+		order, err := scanOrderRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating child order rows: %w", err)
+	}
+
+	return orders, nil
+}
+
+// SaveChildOrder records a triggered leg of a parent order in buy_orders or sell_orders.
+// A sell leg with ParentID set is paired with that buy leg in trade history.
+func (r *ParentOrderRepositoryImpl) SaveChildOrder(ctx context.Context, acceptanceID string, child *model.OrderRecord) error {
+	now := time.Now()
+
+	switch child.Side {
+	case "BUY":
+		query := `
+			INSERT INTO buy_orders (
+				order_id, product_code, side, price, size,
+				exchange, status, strategy, parent_order_id, timestamp, updatetime
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		_, err := r.db.ExecContext(ctx, query,
+			child.OrderID, child.ProductCode, child.Side, child.Price, child.Size,
+			child.Exchange, child.Status, 99, acceptanceID, now, now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save child order: %w", err)
+		}
+	case "SELL":
+		query := `
+			INSERT INTO sell_orders (
+				parentid, order_id, product_code, side, price, size,
+				exchange, status, parent_order_id, timestamp, updatetime
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		var parentID any
+		if child.ParentID != nil {
+			parentID = *child.ParentID
+		}
+		_, err := r.db.ExecContext(ctx, query,
+			parentID, child.OrderID, child.ProductCode, child.Side, child.Price, child.Size,
+			child.Exchange, child.Status, acceptanceID, now, now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save child order: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown order side: %s", child.Side)
+	}
+
+	return nil
+}
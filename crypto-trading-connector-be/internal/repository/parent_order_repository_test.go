@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParentOrderRepository_ListActiveParentOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewParentOrderRepository(db)
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "parent_order_acceptance_id", "parent_order_id", "order_method", "product_code", "legs", "exchange", "status", "timestamp", "updatetime"}).
+		AddRow(1, "JRF-PARENT-1", nil, "IFD", "BTC_JPY",
+			`[{"product_code":"BTC_JPY","side":"BUY","condition_type":"LIMIT","size":0.001,"price":14000000},{"product_code":"BTC_JPY","side":"SELL","condition_type":"STOP","size":0.001,"trigger_price":13500000}]`,
+			"bitflyer", "ACTIVE", created, created)
+
+	mock.ExpectQuery(`FROM parent_orders\s+WHERE status = \?`).
+		WithArgs("ACTIVE").
+		WillReturnRows(rows)
+
+	orders, err := repo.ListActiveParentOrders(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "JRF-PARENT-1", orders[0].AcceptanceID)
+	assert.Nil(t, orders[0].ExchangeOrderID)
+	require.Len(t, orders[0].Legs, 2)
+	assert.Equal(t, model.ConditionTypeStop, orders[0].Legs[1].ConditionType)
+	assert.Equal(t, 13500000.0, orders[0].Legs[1].TriggerPrice)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestParentOrderRepository_SaveChildOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewParentOrderRepository(db)
+
+	mock.ExpectExec(`INSERT INTO buy_orders`).
+		WithArgs("JRF-ENTRY", "BTC_JPY", "BUY", 14000000.0, 0.001, "bitflyer", "FILLED", 99, "JRF-PARENT-1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO sell_orders`).
+		WithArgs("JRF-ENTRY", "JRF-EXIT", "BTC_JPY", "SELL", 14200000.0, 0.001, "bitflyer", "UNFILLED", "JRF-PARENT-1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	entryID := "JRF-ENTRY"
+	err = repo.SaveChildOrder(context.Background(), "JRF-PARENT-1", &model.OrderRecord{
+		OrderID: "JRF-ENTRY", ProductCode: "BTC_JPY", Side: "BUY", Price: 14000000, Size: 0.001, Exchange: "bitflyer", Status: "FILLED",
+	})
+	require.NoError(t, err)
+
+	err = repo.SaveChildOrder(context.Background(), "JRF-PARENT-1", &model.OrderRecord{
+		OrderID: "JRF-EXIT", ParentID: &entryID, ProductCode: "BTC_JPY", Side: "SELL", Price: 14200000, Size: 0.001, Exchange: "bitflyer", Status: "UNFILLED",
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// PriceHistoryRepository defines the interface for recording price samples in price_histories
+type PriceHistoryRepository interface {
+	SavePriceHistory(ctx context.Context, history *model.PriceHistory) error
+	GetLatestPriceHistory(ctx context.Context, productCode string) (*model.PriceHistory, error)
+	FindClosestPriceHistory(ctx context.Context, productCode string, at time.Time, tolerance time.Duration) (*model.PriceHistory, error)
+}
+
+// MySQLPriceHistoryRepository implements PriceHistoryRepository with MySQL
+type MySQLPriceHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLPriceHistoryRepository creates a new MySQL price history repository
+func NewMySQLPriceHistoryRepository(db *sql.DB) *MySQLPriceHistoryRepository {
+	return &MySQLPriceHistoryRepository{
+		db: db,
+	}
+}
+
+// SavePriceHistory inserts a price sample
+func (r *MySQLPriceHistoryRepository) SavePriceHistory(ctx context.Context, history *model.PriceHistory) error {
+	query := `
+		INSERT INTO price_histories (datetime, product_code, price, price_ratio_24h)
+		VALUES (?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, history.Datetime, history.ProductCode, history.Price, history.PriceRatio24h)
+	if err != nil {
+		return fmt.Errorf("failed to save price history: %w", err)
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		history.ID = int(id)
+	}
+
+	return nil
+}
+
+// GetLatestPriceHistory returns the most recent sample of the product, or nil if there is none
+func (r *MySQLPriceHistoryRepository) GetLatestPriceHistory(ctx context.Context, productCode string) (*model.PriceHistory, error) {
+	query := `
+		SELECT id, datetime, product_code, price, price_ratio_24h
+		FROM price_histories
+		WHERE product_code = ?
+		ORDER BY datetime DESC
+		LIMIT 1
+	`
+
+	return r.queryPriceHistory(ctx, query, productCode)
+}
+
+// FindClosestPriceHistory returns the sample of the product taken closest to at, or nil if no sample was
+// taken within tolerance of it
+func (r *MySQLPriceHistoryRepository) FindClosestPriceHistory(ctx context.Context, productCode string, at time.Time, tolerance time.Duration) (*model.PriceHistory, error) {
+	query := `
+		SELECT id, datetime, product_code, price, price_ratio_24h
+		FROM price_histories
+		WHERE product_code = ?
+			AND datetime BETWEEN ? AND ?
+		ORDER BY ABS(TIMESTAMPDIFF(SECOND, datetime, ?)) ASC
+		LIMIT 1
+	`
+
+	return r.queryPriceHistory(ctx, query, productCode, at.Add(-tolerance), at.Add(tolerance), at)
+}
+
+// queryPriceHistory scans the single price history selected by query, or returns nil if there is none
+func (r *MySQLPriceHistoryRepository) queryPriceHistory(ctx context.Context, query string, args ...any) (*model.PriceHistory, error) {
+	var history model.PriceHistory
+	var ratio sql.NullFloat64
+
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+		&history.ID,
+		&history.Datetime,
+		&history.ProductCode,
+		&history.Price,
+		&ratio,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price history: %w", err)
+	}
+	if ratio.Valid {
+		history.PriceRatio24h = &ratio.Float64
+	}
+
+	return &history, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceHistoryRepository_SavePriceHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMySQLPriceHistoryRepository(db)
+
+	sampledAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ratio := 1.05
+	mock.ExpectExec(`INSERT INTO price_histories \(datetime, product_code, price, price_ratio_24h\)`).
+		WithArgs(sampledAt, "BTC_JPY", 10500000.0, &ratio).
+		WillReturnResult(sqlmock.NewResult(42, 1))
+
+	history := &model.PriceHistory{Datetime: sampledAt, ProductCode: "BTC_JPY", Price: 10500000, PriceRatio24h: &ratio}
+	err = repo.SavePriceHistory(context.Background(), history)
+
+	require.NoError(t, err)
+	assert.Equal(t, 42, history.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPriceHistoryRepository_FindClosestPriceHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMySQLPriceHistoryRepository(db)
+
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	sampledAt := at.Add(3 * time.Minute)
+	rows := sqlmock.NewRows([]string{"id", "datetime", "product_code", "price", "price_ratio_24h"}).
+		AddRow(7, sampledAt, "BTC_JPY", 10000000.0, nil)
+	mock.ExpectQuery(`SELECT id, datetime, product_code, price, price_ratio_24h\s+FROM price_histories\s+WHERE product_code = \?\s+AND datetime BETWEEN \? AND \?\s+ORDER BY ABS`).
+		WithArgs("BTC_JPY", at.Add(-30*time.Minute), at.Add(30*time.Minute), at).
+		WillReturnRows(rows)
+
+	history, err := repo.FindClosestPriceHistory(context.Background(), "BTC_JPY", at, 30*time.Minute)
+
+	require.NoError(t, err)
+	require.NotNil(t, history)
+	assert.Equal(t, 7, history.ID)
+	assert.Equal(t, sampledAt, history.Datetime)
+	assert.Nil(t, history.PriceRatio24h)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPriceHistoryRepository_GetLatestPriceHistory_None(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMySQLPriceHistoryRepository(db)
+
+	mock.ExpectQuery(`ORDER BY datetime DESC\s+LIMIT 1`).
+		WithArgs("ETH_JPY").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "datetime", "product_code", "price", "price_ratio_24h"}))
+
+	history, err := repo.GetLatestPriceHistory(context.Background(), "ETH_JPY")
+
+	require.NoError(t, err)
+	assert.Nil(t, history)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,35 +1,38 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
 )
 
 // TradeHistoryRepository defines the interface for trade history data access
 type TradeHistoryRepository interface {
-	GetTradeStatistics(assetFilter, timeFilter string) (*generated.TradeStatistics, error)
-	GetTradeTransactions(assetFilter, timeFilter string, page, limit int) (*generated.TransactionLogResponse, error)
-	GetTotalTransactionCount(assetFilter, timeFilter string) (int, error)
+	GetTradeStatistics(ctx context.Context, assetFilter, timeFilter string) (*generated.TradeStatistics, error)
+	GetTradeTransactions(ctx context.Context, assetFilter, timeFilter string, page, limit int) (*generated.TransactionLogResponse, error)
+	GetTotalTransactionCount(ctx context.Context, assetFilter, timeFilter string) (int, error)
 }
 
 // MySQLTradeHistoryRepository implements TradeHistoryRepository with MySQL
 type MySQLTradeHistoryRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	assets *model.AssetCatalog
 }
 
 // NewMySQLTradeHistoryRepository creates a new MySQL trade history repository
-func NewMySQLTradeHistoryRepository(db *sql.DB) *MySQLTradeHistoryRepository {
+func NewMySQLTradeHistoryRepository(db *sql.DB, assets *model.AssetCatalog) *MySQLTradeHistoryRepository {
 	return &MySQLTradeHistoryRepository{
-		db: db,
+		db:     db,
+		assets: assets,
 	}
 }
 
 // GetTradeStatistics retrieves aggregated trade statistics
-func (r *MySQLTradeHistoryRepository) GetTradeStatistics(assetFilter, timeFilter string) (*generated.TradeStatistics, error) {
+func (r *MySQLTradeHistoryRepository) GetTradeStatistics(ctx context.Context, assetFilter, timeFilter string) (*generated.TradeStatistics, error) {
 	// Base query for calculating profit with fee consideration (0.9989)
 	baseQuery := `
 		SELECT 
@@ -44,7 +47,7 @@ func (r *MySQLTradeHistoryRepository) GetTradeStatistics(assetFilter, timeFilter
 
 	// Add asset filter
 	if assetFilter != "all" {
-		productCode := getProductCodeFromAsset(assetFilter)
+		productCode := r.productCodeFromAsset(assetFilter)
 		baseQuery += " AND s.product_code = ?"
 		args = append(args, productCode)
 	}
@@ -57,7 +60,7 @@ func (r *MySQLTradeHistoryRepository) GetTradeStatistics(assetFilter, timeFilter
 	var executionCount int
 	var totalProfit float64
 
-	err := r.db.QueryRow(baseQuery, args...).Scan(&executionCount, &totalProfit)
+	err := r.db.QueryRowContext(ctx, baseQuery, args...).Scan(&executionCount, &totalProfit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trade statistics: %w", err)
 	}
@@ -84,7 +87,7 @@ func (r *MySQLTradeHistoryRepository) GetTradeStatistics(assetFilter, timeFilter
 }
 
 // GetTradeTransactions retrieves paginated trade transactions
-func (r *MySQLTradeHistoryRepository) GetTradeTransactions(assetFilter, timeFilter string, page, limit int) (*generated.TransactionLogResponse, error) {
+func (r *MySQLTradeHistoryRepository) GetTradeTransactions(ctx context.Context, assetFilter, timeFilter string, page, limit int) (*generated.TransactionLogResponse, error) {
 	offset := (page - 1) * limit
 
 	query := `
@@ -107,7 +110,7 @@ func (r *MySQLTradeHistoryRepository) GetTradeTransactions(assetFilter, timeFilt
 
 	// Add asset filter
 	if assetFilter != "all" {
-		productCode := getProductCodeFromAsset(assetFilter)
+		productCode := r.productCodeFromAsset(assetFilter)
 		query += " AND s.product_code = ?"
 		args = append(args, productCode)
 	}
@@ -120,7 +123,7 @@ func (r *MySQLTradeHistoryRepository) GetTradeTransactions(assetFilter, timeFilt
 	query += " ORDER BY s.updatetime DESC LIMIT ? OFFSET ?"
 	args = append(args, limit, offset)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query trade transactions: %w", err)
 	}
@@ -145,7 +148,7 @@ func (r *MySQLTradeHistoryRepository) GetTradeTransactions(assetFilter, timeFilt
 			return nil, fmt.Errorf("failed to scan transaction row: %w", err)
 		}
 
-		cryptocurrency := getCryptocurrencyFromProductCode(productCode)
+		cryptocurrency := r.cryptocurrencyFromProductCode(productCode)
 
 		transaction := generated.Transaction{
 			Id:             id,
@@ -168,7 +171,7 @@ func (r *MySQLTradeHistoryRepository) GetTradeTransactions(assetFilter, timeFilt
 	}
 
 	// Get total count for pagination
-	totalCount, err := r.GetTotalTransactionCount(assetFilter, timeFilter)
+	totalCount, err := r.GetTotalTransactionCount(ctx, assetFilter, timeFilter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total transaction count: %w", err)
 	}
@@ -190,7 +193,7 @@ func (r *MySQLTradeHistoryRepository) GetTradeTransactions(assetFilter, timeFilt
 }
 
 // GetTotalTransactionCount gets the total count of transactions for pagination
-func (r *MySQLTradeHistoryRepository) GetTotalTransactionCount(assetFilter, timeFilter string) (int, error) {
+func (r *MySQLTradeHistoryRepository) GetTotalTransactionCount(ctx context.Context, assetFilter, timeFilter string) (int, error) {
 	query := `
 		SELECT COUNT(*)
 		FROM sell_orders s
@@ -202,7 +205,7 @@ func (r *MySQLTradeHistoryRepository) GetTotalTransactionCount(assetFilter, time
 
 	// Add asset filter
 	if assetFilter != "all" {
-		productCode := getProductCodeFromAsset(assetFilter)
+		productCode := r.productCodeFromAsset(assetFilter)
 		query += " AND s.product_code = ?"
 		args = append(args, productCode)
 	}
@@ -213,7 +216,7 @@ func (r *MySQLTradeHistoryRepository) GetTotalTransactionCount(assetFilter, time
 	}
 
 	var count int
-	err := r.db.QueryRow(query, args...).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get transaction count: %w", err)
 	}
@@ -223,27 +226,24 @@ func (r *MySQLTradeHistoryRepository) GetTotalTransactionCount(assetFilter, time
 
 // Helper functions
 
-// getProductCodeFromAsset converts asset filter to product code
-func getProductCodeFromAsset(assetFilter string) string {
-	switch assetFilter {
-	case "BTC":
-		return "BTC_JPY"
-	case "ETH":
-		return "ETH_JPY"
-	default:
+// productCodeFromAsset converts asset filter (currency code) to product code
+func (r *MySQLTradeHistoryRepository) productCodeFromAsset(assetFilter string) string {
+	asset, ok := r.assets.ByCode(assetFilter)
+	if !ok {
 		return ""
 	}
+	return asset.Pair.ProductCode()
 }
 
-// getCryptocurrencyFromProductCode converts product code to cryptocurrency name
-func getCryptocurrencyFromProductCode(productCode string) generated.TransactionCryptocurrency {
-	if strings.Contains(productCode, "BTC") {
-		return generated.Bitcoin
+// cryptocurrencyFromProductCode converts product code to cryptocurrency name,
+// falling back to the currency code for pairs no longer in the asset catalog
+func (r *MySQLTradeHistoryRepository) cryptocurrencyFromProductCode(productCode string) string {
+	symbol := model.SymbolFromProductCode(productCode)
+	asset, ok := r.assets.ByPair(symbol)
+	if !ok {
+		return symbol.Base()
 	}
-	if strings.Contains(productCode, "ETH") {
-		return generated.Ethereum
-	}
-	return generated.Bitcoin // Default fallback
+	return asset.Name
 }
 
 // roundToOneDecimal rounds a float64 to 1 decimal place
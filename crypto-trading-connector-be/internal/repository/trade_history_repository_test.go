@@ -1,11 +1,13 @@
 package repository
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -68,7 +70,7 @@ func TestTradeHistoryRepository_AssetFiltering_Property(t *testing.T) {
 			require.NoError(t, err)
 			defer db.Close()
 
-			repo := NewMySQLTradeHistoryRepository(db)
+			repo := NewMySQLTradeHistoryRepository(db, model.DefaultAssetCatalog())
 
 			// Mock the query based on asset filter
 			if tt.assetFilter == "all" {
@@ -79,7 +81,7 @@ func TestTradeHistoryRepository_AssetFiltering_Property(t *testing.T) {
 						AddRow("1", "SELL001", "BUY001", tt.productCode, 6000000.0, 5800000.0, 0.1, time.Now(), 20000.0))
 			} else {
 				// For specific asset filter, expect WHERE clause with product_code
-				expectedProductCode := getProductCodeFromAsset(tt.assetFilter)
+				expectedProductCode := repo.productCodeFromAsset(tt.assetFilter)
 				mock.ExpectQuery(`SELECT.*FROM sell_orders s.*INNER JOIN buy_orders b.*WHERE s.status = 'FILLED' AND s.product_code = \?.*ORDER BY s.updatetime DESC LIMIT.*OFFSET`).
 					WithArgs(expectedProductCode, 10, 0).
 					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "buy_order_id", "product_code", "sell_price", "buy_price", "size", "updatetime", "profit"}).
@@ -91,22 +93,22 @@ func TestTradeHistoryRepository_AssetFiltering_Property(t *testing.T) {
 				mock.ExpectQuery(`SELECT COUNT\(\*\).*FROM sell_orders s.*INNER JOIN buy_orders b.*WHERE s.status = 'FILLED'`).
 					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 			} else {
-				expectedProductCode := getProductCodeFromAsset(tt.assetFilter)
+				expectedProductCode := repo.productCodeFromAsset(tt.assetFilter)
 				mock.ExpectQuery(`SELECT COUNT\(\*\).*FROM sell_orders s.*INNER JOIN buy_orders b.*WHERE s.status = 'FILLED' AND s.product_code = \?`).
 					WithArgs(expectedProductCode).
 					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 			}
 
-			result, err := repo.GetTradeTransactions(tt.assetFilter, "all", 1, 10)
+			result, err := repo.GetTradeTransactions(context.Background(), tt.assetFilter, "all", 1, 10)
 
 			if tt.shouldMatch {
 				require.NoError(t, err)
 				assert.Len(t, result.Transactions, 1)
 				// Check that the transaction has the expected product code through the cryptocurrency field
 				if tt.productCode == "BTC_JPY" {
-					assert.Equal(t, generated.Bitcoin, result.Transactions[0].Cryptocurrency)
+					assert.Equal(t, "Bitcoin", result.Transactions[0].Cryptocurrency)
 				} else if tt.productCode == "ETH_JPY" {
-					assert.Equal(t, generated.Ethereum, result.Transactions[0].Cryptocurrency)
+					assert.Equal(t, "Ethereum", result.Transactions[0].Cryptocurrency)
 				}
 			} else {
 				// For non-matching cases, we expect no results but no error
@@ -164,7 +166,7 @@ func TestTradeHistoryRepository_TimeFiltering_Property(t *testing.T) {
 			require.NoError(t, err)
 			defer db.Close()
 
-			repo := NewMySQLTradeHistoryRepository(db)
+			repo := NewMySQLTradeHistoryRepository(db, model.DefaultAssetCatalog())
 
 			// Mock the query based on time filter
 			if tt.timeFilter == "all" {
@@ -190,7 +192,7 @@ func TestTradeHistoryRepository_TimeFiltering_Property(t *testing.T) {
 					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 			}
 
-			result, err := repo.GetTradeTransactions("all", tt.timeFilter, 1, 10)
+			result, err := repo.GetTradeTransactions(context.Background(), "all", tt.timeFilter, 1, 10)
 
 			if tt.shouldMatch {
 				require.NoError(t, err)
@@ -217,7 +219,7 @@ func TestTradeHistoryRepository_DataIntegrity_Property(t *testing.T) {
 	require.NoError(t, err)
 	defer db.Close()
 
-	repo := NewMySQLTradeHistoryRepository(db)
+	repo := NewMySQLTradeHistoryRepository(db, model.DefaultAssetCatalog())
 
 	// Mock the query
 	mock.ExpectQuery(`SELECT.*FROM sell_orders s.*INNER JOIN buy_orders b.*WHERE s.status = 'FILLED'.*ORDER BY s.updatetime DESC LIMIT.*OFFSET`).
@@ -229,7 +231,7 @@ func TestTradeHistoryRepository_DataIntegrity_Property(t *testing.T) {
 	mock.ExpectQuery(`SELECT COUNT\(\*\).*FROM sell_orders s.*INNER JOIN buy_orders b.*WHERE s.status = 'FILLED'`).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-	result, err := repo.GetTradeTransactions("all", "all", 1, 10)
+	result, err := repo.GetTradeTransactions(context.Background(), "all", "all", 1, 10)
 
 	require.NoError(t, err)
 	assert.Len(t, result.Transactions, 1)
@@ -246,14 +248,14 @@ func TestTradeHistoryRepository_GetTradeStatistics(t *testing.T) {
 	require.NoError(t, err)
 	defer db.Close()
 
-	repo := NewMySQLTradeHistoryRepository(db)
+	repo := NewMySQLTradeHistoryRepository(db, model.DefaultAssetCatalog())
 
 	// Mock the statistics query
 	mock.ExpectQuery(`SELECT.*COUNT\(\*\) as execution_count.*ROUND\(SUM\(\(\(s\.price \* s\.size\) - \(b\.price \* b\.size\)\) \* 0\.9989\), 2\).*FROM sell_orders s.*INNER JOIN buy_orders b.*WHERE s\.status = 'FILLED'`).
 		WillReturnRows(sqlmock.NewRows([]string{"execution_count", "total_profit"}).
 			AddRow(5, 125000.0))
 
-	result, err := repo.GetTradeStatistics("all", "all")
+	result, err := repo.GetTradeStatistics(context.Background(), "all", "all")
 
 	require.NoError(t, err)
 	assert.Equal(t, 5, result.ExecutionCount)
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// TradingHaltRepository defines the interface for the trading kill switch
+type TradingHaltRepository interface {
+	GetTradingHalt(ctx context.Context) (*model.TradingHalt, error)
+	SaveTradingHalt(ctx context.Context, halt *model.TradingHalt) error
+}
+
+// tradingHaltID is the ID of the only row of trading_halt
+const tradingHaltID = 1
+
+// MySQLTradingHaltRepository implements TradingHaltRepository with MySQL
+type MySQLTradingHaltRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLTradingHaltRepository creates a new MySQL trading halt repository
+func NewMySQLTradingHaltRepository(db *sql.DB) *MySQLTradingHaltRepository {
+	return &MySQLTradingHaltRepository{
+		db: db,
+	}
+}
+
+// GetTradingHalt returns the trading halt. Trading is not halted if it was never engaged.
+func (r *MySQLTradingHaltRepository) GetTradingHalt(ctx context.Context) (*model.TradingHalt, error) {
+	query := `SELECT halted, reason, updated_at FROM trading_halt WHERE id = ?`
+
+	var halt model.TradingHalt
+	var reason sql.NullString
+	var updatedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, tradingHaltID).Scan(&halt.Halted, &reason, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &model.TradingHalt{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trading halt: %w", err)
+	}
+	if reason.Valid {
+		halt.Reason = &reason.String
+	}
+	if updatedAt.Valid {
+		halt.UpdatedAt = &updatedAt.Time
+	}
+
+	return &halt, nil
+}
+
+// SaveTradingHalt engages or releases the trading halt
+func (r *MySQLTradingHaltRepository) SaveTradingHalt(ctx context.Context, halt *model.TradingHalt) error {
+	query := `
+		INSERT INTO trading_halt (id, halted, reason, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE halted = VALUES(halted), reason = VALUES(reason), updated_at = VALUES(updated_at)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, tradingHaltID, halt.Halted, halt.Reason, halt.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save trading halt: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTradingHaltRepository_GetTradingHalt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMySQLTradingHaltRepository(db)
+
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectQuery(`SELECT halted, reason, updated_at FROM trading_halt WHERE id = \?`).
+		WithArgs(tradingHaltID).
+		WillReturnRows(sqlmock.NewRows([]string{"halted", "reason", "updated_at"}).AddRow(true, "exchange incident", updatedAt))
+
+	halt, err := repo.GetTradingHalt(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, halt.Halted)
+	require.NotNil(t, halt.Reason)
+	assert.Equal(t, "exchange incident", *halt.Reason)
+	require.NotNil(t, halt.UpdatedAt)
+	assert.Equal(t, updatedAt, *halt.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTradingHaltRepository_GetTradingHalt_NeverEngaged(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMySQLTradingHaltRepository(db)
+
+	mock.ExpectQuery(`SELECT halted, reason, updated_at FROM trading_halt`).
+		WillReturnRows(sqlmock.NewRows([]string{"halted", "reason", "updated_at"}))
+
+	halt, err := repo.GetTradingHalt(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, halt.Halted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTradingHaltRepository_SaveTradingHalt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMySQLTradingHaltRepository(db)
+
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	reason := "exchange incident"
+	mock.ExpectExec(`INSERT INTO trading_halt \(id, halted, reason, updated_at\)`).
+		WithArgs(tradingHaltID, true, &reason, &updatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SaveTradingHalt(context.Background(), &model.TradingHalt{Halted: true, Reason: &reason, UpdatedAt: &updatedAt})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,121 +1,187 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/crypto-trading-connector/backend/internal/client"
 	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
 	"github.com/crypto-trading-connector/backend/internal/repository"
 )
 
 // CryptoService defines the interface for cryptocurrency business logic
 type CryptoService interface {
-	GetMarketData() (*generated.MarketResponse, error)
-	GetCryptoByID(id string, period string) (*generated.CryptoData, error)
-	GetChartData(id string, period string) (*generated.ChartResponse, error)
+	GetMarketData(ctx context.Context) (*generated.MarketResponse, error)
+	GetCryptoByID(ctx context.Context, id string, period string) (*generated.CryptoData, error)
+	GetChartData(ctx context.Context, id string, period string) (*generated.ChartResponse, error)
 }
 
+// Default limits of GetMarketData
+const (
+	defaultMarketDataWorkers = 4
+	defaultMarketDataTimeout = 5 * time.Second
+)
+
 // CryptoServiceImpl implements CryptoService
 type CryptoServiceImpl struct {
-	repo           repository.CryptoRepository
-	exchangeClient client.CryptoExchangeClient
+	repo              repository.CryptoRepository
+	exchangeClient    client.CryptoExchangeClient
+	products          *ProductRegistry
+	assets            *model.AssetCatalog
+	marketDataWorkers int
+	marketDataTimeout time.Duration
+	tradingHalts      repository.TradingHaltRepository
 }
 
 // NewCryptoService creates a new crypto service
-func NewCryptoService(repo repository.CryptoRepository, exchangeClient client.CryptoExchangeClient) *CryptoServiceImpl {
+func NewCryptoService(repo repository.CryptoRepository, exchangeClient client.CryptoExchangeClient, products *ProductRegistry, assets *model.AssetCatalog) *CryptoServiceImpl {
 	return &CryptoServiceImpl{
-		repo:           repo,
-		exchangeClient: exchangeClient,
+		repo:              repo,
+		exchangeClient:    exchangeClient,
+		products:          products,
+		assets:            assets,
+		marketDataWorkers: defaultMarketDataWorkers,
+		marketDataTimeout: defaultMarketDataTimeout,
 	}
 }
 
-// cryptoConfig holds configuration for each cryptocurrency
-type cryptoConfig struct {
-	ID          string
-	Name        string
-	Symbol      string
-	Pair        string
-	Icon        string
-	IconColor   string
-	ProductCode string
+// SetMarketDataLimits changes how many cryptocurrencies GetMarketData fetches at once and how long it
+// waits for all of them
+func (s *CryptoServiceImpl) SetMarketDataLimits(workers int, timeout time.Duration) {
+	s.marketDataWorkers = max(workers, 1)
+	s.marketDataTimeout = timeout
 }
 
-var cryptoConfigs = []cryptoConfig{
-	{
-		ID:          "bitcoin",
-		Name:        "Bitcoin",
-		Symbol:      "BTC",
-		Pair:        "BTC/JPY",
-		Icon:        "₿",
-		IconColor:   "#f7931a",
-		ProductCode: "BTC_JPY",
-	},
-	{
-		ID:          "ethereum",
-		Name:        "Ethereum",
-		Symbol:      "ETH",
-		Pair:        "ETH/JPY",
-		Icon:        "Ξ",
-		IconColor:   "#627eea",
-		ProductCode: "ETH_JPY",
-	},
+// SetTradingHalts makes GetMarketData report whether the trading halt is engaged
+func (s *CryptoServiceImpl) SetTradingHalts(halts repository.TradingHaltRepository) {
+	s.tradingHalts = halts
 }
 
-// GetMarketData retrieves market data for all cryptocurrencies
-func (s *CryptoServiceImpl) GetMarketData() (*generated.MarketResponse, error) {
-	var cryptoDataList []generated.CryptoData
-
-	for _, config := range cryptoConfigs {
-		// Get current price from exchange API
-		ticker, err := s.exchangeClient.GetTicker(config.ProductCode)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get ticker for %s: %w", config.ProductCode, err)
-		}
-
-		// Get chart data from database (last 7 days)
-		chartData, err := s.repo.GetDailyAveragePrices(config.ProductCode, 7)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get chart data for %s: %w", config.ProductCode, err)
-		}
-
-		// Calculate change percent
-		changePercent := calculateChangePercent(chartData, ticker.Ltp)
-
-		cryptoData := generated.CryptoData{
-			Id:            config.ID,
-			Name:          config.Name,
-			Symbol:        config.Symbol,
-			Pair:          config.Pair,
-			Icon:          config.Icon,
-			IconColor:     config.IconColor,
-			CurrentPrice:  ticker.Ltp,
-			ChangePercent: changePercent,
-			ChartData:     chartData,
+// GetMarketData retrieves market data for all cryptocurrencies. The cryptocurrencies are fetched in
+// parallel; one that cannot be priced is reported in the statuses and left out of the data, and an error
+// is only returned when none of them could be priced.
+func (s *CryptoServiceImpl) GetMarketData(ctx context.Context) (*generated.MarketResponse, error) {
+	// The deadline only bounds the exchange queries; the trading halt is read once they are done
+	fetchCtx, cancel := context.WithTimeout(ctx, s.marketDataTimeout)
+	defer cancel()
+
+	assets := s.assets.Assets()
+	results := make([]marketAssetResult, len(assets))
+
+	// Fetch the assets with a bounded pool of workers; results are stored by index to keep the listing order
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(s.marketDataWorkers, len(assets)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = s.fetchMarketAsset(fetchCtx, assets[i])
+			}
+		}()
+	}
+	for i := range assets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	cryptoDataList := []generated.CryptoData{}
+	statuses := make([]generated.MarketAssetStatus, 0, len(results))
+	var firstErr error
+	for _, result := range results {
+		statuses = append(statuses, result.status)
+		if result.data != nil {
+			cryptoDataList = append(cryptoDataList, *result.data)
+		} else if firstErr == nil {
+			firstErr = result.err
 		}
-
-		cryptoDataList = append(cryptoDataList, cryptoData)
+	}
+	if len(cryptoDataList) == 0 && firstErr != nil {
+		return nil, firstErr
 	}
 
 	return &generated.MarketResponse{
-		Data:      cryptoDataList,
-		Timestamp: time.Now().Unix(),
+		Data:          cryptoDataList,
+		Statuses:      statuses,
+		Timestamp:     time.Now().Unix(),
+		TradingHalted: tradingHalted(ctx, s.tradingHalts),
 	}, nil
 }
 
-// GetCryptoByID retrieves data for a specific cryptocurrency
-func (s *CryptoServiceImpl) GetCryptoByID(id string, period string) (*generated.CryptoData, error) {
-	// Find config for the requested ID
-	var config *cryptoConfig
-	for _, c := range cryptoConfigs {
-		if c.ID == id {
-			config = &c
-			break
+// marketAssetResult is the outcome of fetching the market data of one asset
+type marketAssetResult struct {
+	data   *generated.CryptoData // Nil if the asset could not be priced
+	status generated.MarketAssetStatus
+	err    error // Why the asset could not be priced
+}
+
+// fetchMarketAsset fetches the price and the 7-day chart of an asset. A failed chart query still
+// returns the asset, with an empty chart.
+func (s *CryptoServiceImpl) fetchMarketAsset(ctx context.Context, asset model.Asset) marketAssetResult {
+	status := generated.MarketAssetStatus{Id: asset.ID, Status: generated.MarketAssetStatusStatusOK}
+
+	// Get current price from exchange API
+	ticker, err := s.exchangeClient.GetTicker(ctx, asset.Pair)
+	if err != nil {
+		err = fmt.Errorf("failed to get ticker for %s: %w", asset.Pair.ProductCode(), err)
+		log.Printf("Warning: market data: %v", err)
+		message := err.Error()
+		status.Status, status.Message = generated.MarketAssetStatusStatusUNAVAILABLE, &message
+		return marketAssetResult{status: status, err: err}
+	}
+	if ticker.Stale {
+		message := "price is stale: the exchange could not be reached"
+		status.Status, status.Message = generated.MarketAssetStatusStatusSTALE, &message
+	}
+
+	// Get chart data from database (last 7 days)
+	chartData, err := s.repo.GetDailyAveragePrices(ctx, asset.Pair.ProductCode(), 7)
+	if err != nil {
+		err = fmt.Errorf("failed to get chart data for %s: %w", asset.Pair.ProductCode(), err)
+		log.Printf("Warning: market data: %v", err)
+		// A stale price stays STALE, the most severe status, with both problems in the message
+		if status.Status == generated.MarketAssetStatusStatusSTALE {
+			message := *status.Message + "; " + err.Error()
+			status.Message = &message
+		} else {
+			message := err.Error()
+			status.Status, status.Message = generated.MarketAssetStatusStatusNOCHART, &message
 		}
+		chartData = []generated.ChartDataPoint{}
+	}
+
+	// Calculate change percent
+	changePercent := calculateChangePercent(chartData, ticker.Last)
+
+	return marketAssetResult{
+		data: &generated.CryptoData{
+			Id:            asset.ID,
+			Name:          asset.Name,
+			Symbol:        asset.Code(),
+			Pair:          string(asset.Pair),
+			Icon:          asset.Icon,
+			IconColor:     asset.IconColor,
+			CurrentPrice:  ticker.Last,
+			ChangePercent: changePercent,
+			ChartData:     chartData,
+			Product:       s.productInfo(asset.Pair),
+			Stale:         staleFlag(ticker),
+		},
+		status: status,
 	}
+}
 
-	if config == nil {
-		return nil, fmt.Errorf("cryptocurrency not found: %s", id)
+// GetCryptoByID retrieves data for a specific cryptocurrency
+func (s *CryptoServiceImpl) GetCryptoByID(ctx context.Context, id string, period string) (*generated.CryptoData, error) {
+	// Find the asset with the requested ID
+	asset, ok := s.assets.ByID(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCryptoNotFound, id)
 	}
 
 	// Default period is 7d
@@ -127,46 +193,41 @@ func (s *CryptoServiceImpl) GetCryptoByID(id string, period string) (*generated.
 	days := periodToDays(period)
 
 	// Get current price from exchange API
-	ticker, err := s.exchangeClient.GetTicker(config.ProductCode)
+	ticker, err := s.exchangeClient.GetTicker(ctx, asset.Pair)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get ticker for %s: %w", config.ProductCode, err)
+		return nil, fmt.Errorf("failed to get ticker for %s: %w", asset.Pair.ProductCode(), err)
 	}
 
 	// Get chart data from database with specified period
-	chartData, err := s.repo.GetDailyAveragePrices(config.ProductCode, days)
+	chartData, err := s.repo.GetDailyAveragePrices(ctx, asset.Pair.ProductCode(), days)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get chart data for %s: %w", config.ProductCode, err)
+		return nil, fmt.Errorf("failed to get chart data for %s: %w", asset.Pair.ProductCode(), err)
 	}
 
 	// Calculate change percent
-	changePercent := calculateChangePercent(chartData, ticker.Ltp)
+	changePercent := calculateChangePercent(chartData, ticker.Last)
 
 	return &generated.CryptoData{
-		Id:            config.ID,
-		Name:          config.Name,
-		Symbol:        config.Symbol,
-		Pair:          config.Pair,
-		Icon:          config.Icon,
-		IconColor:     config.IconColor,
-		CurrentPrice:  ticker.Ltp,
+		Id:            asset.ID,
+		Name:          asset.Name,
+		Symbol:        asset.Code(),
+		Pair:          string(asset.Pair),
+		Icon:          asset.Icon,
+		IconColor:     asset.IconColor,
+		CurrentPrice:  ticker.Last,
 		ChangePercent: changePercent,
 		ChartData:     chartData,
+		Product:       s.productInfo(asset.Pair),
+		Stale:         staleFlag(ticker),
 	}, nil
 }
 
 // GetChartData retrieves chart data for a specific cryptocurrency
-func (s *CryptoServiceImpl) GetChartData(id string, period string) (*generated.ChartResponse, error) {
-	// Find config for the requested ID
-	var config *cryptoConfig
-	for _, c := range cryptoConfigs {
-		if c.ID == id {
-			config = &c
-			break
-		}
-	}
-
-	if config == nil {
-		return nil, fmt.Errorf("cryptocurrency not found: %s", id)
+func (s *CryptoServiceImpl) GetChartData(ctx context.Context, id string, period string) (*generated.ChartResponse, error) {
+	// Find the asset with the requested ID
+	asset, ok := s.assets.ByID(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCryptoNotFound, id)
 	}
 
 	// Default period is 7d
@@ -178,9 +239,9 @@ func (s *CryptoServiceImpl) GetChartData(id string, period string) (*generated.C
 	days := periodToDays(period)
 
 	// Get chart data from database
-	chartData, err := s.repo.GetDailyAveragePrices(config.ProductCode, days)
+	chartData, err := s.repo.GetDailyAveragePrices(ctx, asset.Pair.ProductCode(), days)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get chart data for %s: %w", config.ProductCode, err)
+		return nil, fmt.Errorf("failed to get chart data for %s: %w", asset.Pair.ProductCode(), err)
 	}
 
 	return &generated.ChartResponse{
@@ -189,6 +250,31 @@ func (s *CryptoServiceImpl) GetChartData(id string, period string) (*generated.C
 	}, nil
 }
 
+// productInfo returns the trading rules of a pair, or nil if the pair is not in the product registry
+func (s *CryptoServiceImpl) productInfo(pair model.Symbol) *generated.ProductInfo {
+	product, ok := s.products.Get(pair)
+	if !ok {
+		return nil
+	}
+	return &generated.ProductInfo{
+		Exchange:       product.Exchange,
+		TickSize:       product.TickSize,
+		LotStep:        product.LotStep,
+		MinSize:        product.MinSize,
+		PricePrecision: product.PricePrecision,
+	}
+}
+
+// staleFlag returns the stale flag of the response, which is only set for a ticker served from the cache
+// while the exchange could not be reached
+func staleFlag(ticker *model.Ticker) *bool {
+	if !ticker.Stale {
+		return nil
+	}
+	stale := true
+	return &stale
+}
+
 // calculateChangePercent calculates the percentage change from the first chart data point to current price
 func calculateChangePercent(chartData []generated.ChartDataPoint, currentPrice float64) float64 {
 	if len(chartData) == 0 {
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// MockCryptoRepository is a mock implementation of CryptoRepository for testing
+type MockCryptoRepository struct {
+	GetDailyAveragePricesFunc func(ctx context.Context, productCode string, days int) ([]generated.ChartDataPoint, error)
+}
+
+func (m *MockCryptoRepository) GetDailyAveragePrices(ctx context.Context, productCode string, days int) ([]generated.ChartDataPoint, error) {
+	if m.GetDailyAveragePricesFunc != nil {
+		return m.GetDailyAveragePricesFunc(ctx, productCode, days)
+	}
+	return []generated.ChartDataPoint{{Day: "Mon", Price: 100}}, nil
+}
+
+func TestCryptoService_GetMarketData_PartialFailure(t *testing.T) {
+	assets, err := model.NewAssetCatalog([]model.Asset{
+		{ID: "bitcoin", Name: "Bitcoin", Pair: "BTC/JPY"},
+		{ID: "ethereum", Name: "Ethereum", Pair: "ETH/JPY"},
+		{ID: "ripple", Name: "XRP", Pair: "XRP/JPY"},
+		{ID: "monacoin", Name: "Monacoin", Pair: "MONA/JPY"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create asset catalog: %v", err)
+	}
+	exchangeClient := &client.MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			switch symbol {
+			case "ETH/JPY":
+				return nil, &client.ExchangeUnavailableError{Exchange: "gmocoin", RetryAfter: time.Second}
+			case "MONA/JPY":
+				return &model.Ticker{Symbol: symbol, Last: 50, Stale: true}, nil
+			}
+			return &model.Ticker{Symbol: symbol, Last: 110}, nil
+		},
+	}
+	repo := &MockCryptoRepository{
+		GetDailyAveragePricesFunc: func(ctx context.Context, productCode string, days int) ([]generated.ChartDataPoint, error) {
+			if productCode == "XRP_JPY" {
+				return nil, errors.New("connection refused")
+			}
+			return []generated.ChartDataPoint{{Day: "Mon", Price: 100}}, nil
+		},
+	}
+	service := NewCryptoService(repo, exchangeClient, NewProductRegistry(nil), assets)
+
+	response, err := service.GetMarketData(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(response.Data) != 3 || response.Data[0].Id != "bitcoin" || response.Data[1].Id != "ripple" || response.Data[2].Id != "monacoin" {
+		t.Fatalf("expected bitcoin, ripple and monacoin in listing order, got %+v", response.Data)
+	}
+	if response.Data[0].ChangePercent != 10 {
+		t.Errorf("expected a 10%% change, got %v", response.Data[0].ChangePercent)
+	}
+	if chart := response.Data[1].ChartData; chart == nil || len(chart) != 0 {
+		t.Errorf("expected an empty chart, got %v", chart)
+	}
+
+	want := []generated.MarketAssetStatusStatus{
+		generated.MarketAssetStatusStatusOK,
+		generated.MarketAssetStatusStatusUNAVAILABLE,
+		generated.MarketAssetStatusStatusNOCHART,
+		generated.MarketAssetStatusStatusSTALE,
+	}
+	if len(response.Statuses) != len(want) {
+		t.Fatalf("expected %d statuses, got %+v", len(want), response.Statuses)
+	}
+	for i, status := range response.Statuses {
+		if status.Id != assets.Assets()[i].ID || status.Status != want[i] {
+			t.Errorf("expected %s to be %s, got %+v", assets.Assets()[i].ID, want[i], status)
+		}
+		if (status.Message == nil) != (want[i] == generated.MarketAssetStatusStatusOK) {
+			t.Errorf("expected a message only for incomplete data, got %+v", status)
+		}
+	}
+}
+
+func TestCryptoService_GetMarketData_StaleWithoutChart(t *testing.T) {
+	assets, err := model.NewAssetCatalog([]model.Asset{{ID: "bitcoin", Name: "Bitcoin", Pair: "BTC/JPY"}})
+	if err != nil {
+		t.Fatalf("failed to create asset catalog: %v", err)
+	}
+	exchangeClient := &client.MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			return &model.Ticker{Symbol: symbol, Last: 110, Stale: true}, nil
+		},
+	}
+	repo := &MockCryptoRepository{
+		GetDailyAveragePricesFunc: func(ctx context.Context, productCode string, days int) ([]generated.ChartDataPoint, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	service := NewCryptoService(repo, exchangeClient, NewProductRegistry(nil), assets)
+
+	response, err := service.GetMarketData(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(response.Data) != 1 || len(response.Data[0].ChartData) != 0 || response.Data[0].Stale == nil || !*response.Data[0].Stale {
+		t.Fatalf("expected a stale price without chart, got %+v", response.Data)
+	}
+	status := response.Statuses[0]
+	if status.Status != generated.MarketAssetStatusStatusSTALE {
+		t.Errorf("expected STALE, got %s", status.Status)
+	}
+	if status.Message == nil || !strings.Contains(*status.Message, "stale") || !strings.Contains(*status.Message, "connection refused") {
+		t.Errorf("expected the stale price and the chart failure in the message, got %v", status.Message)
+	}
+}
+
+func TestCryptoService_GetMarketData_AllFailed(t *testing.T) {
+	exchangeClient := &client.MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			return nil, client.ErrRateLimited
+		},
+	}
+	service := NewCryptoService(&MockCryptoRepository{}, exchangeClient, NewProductRegistry(nil), model.DefaultAssetCatalog())
+
+	if _, err := service.GetMarketData(context.Background()); !errors.Is(err, client.ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestCryptoService_GetMarketData_Deadline(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	exchangeClient := &client.MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				if current := maxInFlight.Load(); n <= current || maxInFlight.CompareAndSwap(current, n) {
+					break
+				}
+			}
+
+			if symbol == "ETH/JPY" {
+				// A hanging exchange only gives up when the deadline passes
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return &model.Ticker{Symbol: symbol, Last: 110}, nil
+		},
+	}
+	service := NewCryptoService(&MockCryptoRepository{}, exchangeClient, NewProductRegistry(nil), model.DefaultAssetCatalog())
+	service.SetMarketDataLimits(1, 50*time.Millisecond)
+	service.SetTradingHalts(&fakeTradingHaltRepository{})
+
+	start := time.Now()
+	response, err := service.GetMarketData(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the deadline to cut the hanging pair, took %s", elapsed)
+	}
+	if maxInFlight.Load() != 1 {
+		t.Errorf("expected at most 1 request at once, got %d", maxInFlight.Load())
+	}
+	if len(response.Data) != 1 || response.Statuses[1].Status != generated.MarketAssetStatusStatusUNAVAILABLE {
+		t.Errorf("expected ethereum to be unavailable, got %+v", response.Statuses)
+	}
+	// The deadline of the exchange queries does not make the trading halt unreadable
+	if response.TradingHalted {
+		t.Error("expected trading not to be halted after the deadline passed")
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+)
+
+// Domain errors returned by the services. They are wrapped with the details of the failure,
+// so callers test for them with errors.Is. Failures of exchange calls keep the errors of the
+// client package (client.ErrInsufficientFunds, client.ErrRateLimited, ...) in their chain.
+var (
+	ErrInvalidRequest       = errors.New("invalid request")
+	ErrInvalidPrice         = errors.New("invalid price")
+	ErrInvalidAmount        = errors.New("invalid amount")
+	ErrUnsupportedPair      = errors.New("unsupported pair")
+	ErrInvalidFilter        = errors.New("invalid filter")
+	ErrInvalidPagination    = errors.New("invalid pagination")
+	ErrInsufficientBalance  = errors.New("insufficient balance")
+	ErrCryptoNotFound       = errors.New("cryptocurrency not found")
+	ErrOrderNotFound        = repository.ErrOrderNotFound
+	ErrOrderNotCancellable  = errors.New("order cannot be cancelled")
+	ErrOrderInProgress      = errors.New("order in progress")
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused")
+	ErrTradingHalted        = client.ErrTradingHalted
+
+	// Orders rejected by the risk engine (see RiskError)
+	ErrRiskPairNotAllowed = errors.New("pair not allowed by risk limits")
+	ErrRiskMaxNotional    = errors.New("order value exceeds risk limit")
+	ErrRiskPriceBand      = errors.New("price outside risk price band")
+	ErrRiskMaxOpenOrders  = errors.New("too many open orders")
+	ErrRiskDailyVolume    = errors.New("daily buy volume exceeds risk limit")
+)
+
+// ValidationError is a request rejected before it reached the exchange. Its message describes
+// the problem and it unwraps to the domain error it is classified as (e.g. ErrInvalidFilter).
+type ValidationError struct {
+	Kind    error
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Kind
+}
+
+// invalid returns a ValidationError of the given kind with a formatted message
+func invalid(kind error, format string, args ...any) error {
+	return &ValidationError{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// maxIdempotencyKeyLength is the longest Idempotency-Key accepted, the size of the key column
+const maxIdempotencyKeyLength = 255
+
+// createOrderOnce places the order of an Idempotency-Key unless the key was already used. The key and the client
+// order ID of the order are saved before the order is sent, so a request repeating the key while the order is
+// being placed, or after a crash, fails with ErrOrderInProgress instead of placing a second order.
+func (s *OrderServiceImpl) createOrderOnce(ctx context.Context, key string, req *generated.CreateOrderRequest) (*generated.Order, error) {
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, invalid(ErrInvalidRequest, "invalid Idempotency-Key: must be at most %d characters", maxIdempotencyKeyLength)
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode order request: %w", err)
+	}
+	hash := sha256.Sum256(body)
+
+	now := time.Now()
+	reserved := &model.IdempotencyKey{
+		Key:           key,
+		ClientOrderID: uuid.NewString(),
+		RequestHash:   hex.EncodeToString(hash[:]),
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(s.idempotencyTTL),
+	}
+	existing, err := s.idempotencyRepo.ReserveIdempotencyKey(ctx, reserved)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return replayOrder(existing, reserved.RequestHash)
+	}
+
+	prepared, err := s.prepareOrder(ctx, req)
+	if err != nil {
+		s.releaseIdempotencyKey(ctx, key)
+		return nil, err
+	}
+
+	// The key must be settled even if the client gives up on the request
+	ctx = context.WithoutCancel(ctx)
+	order, err := s.sendOrder(ctx, prepared, reserved.ClientOrderID)
+	if err != nil {
+		// The key stays in flight when the order may have been placed, so that it is not placed twice
+		if !orderStateUnknown(err) {
+			s.releaseIdempotencyKey(ctx, key)
+		}
+		return nil, err
+	}
+
+	response, err := json.Marshal(order)
+	if err == nil {
+		err = s.idempotencyRepo.CompleteIdempotencyKey(ctx, key, order.OrderId, response)
+	}
+	if err != nil {
+		// The key stays in flight: repeating it fails instead of placing the order again
+		log.Printf("Warning: failed to record order %s for idempotency key %s: %v", order.OrderId, key, err)
+	}
+
+	return order, nil
+}
+
+// replayOrder returns the result of the request that first used an idempotency key
+func replayOrder(existing *model.IdempotencyKey, requestHash string) (*generated.Order, error) {
+	if existing.RequestHash != requestHash {
+		return nil, fmt.Errorf("%w: %s was used for a different order", ErrIdempotencyKeyReused, existing.Key)
+	}
+	if existing.Status != model.IdempotencyStatusCompleted {
+		return nil, fmt.Errorf("%w: the order of %s is being placed or its state is unknown; check the order list", ErrOrderInProgress, existing.Key)
+	}
+
+	var order generated.Order
+	if err := json.Unmarshal(existing.Response, &order); err != nil {
+		return nil, fmt.Errorf("failed to decode order of idempotency key %s: %w", existing.Key, err)
+	}
+	return &order, nil
+}
+
+// releaseIdempotencyKey forgets a key whose order was not placed, so that the request can be retried with it
+func (s *OrderServiceImpl) releaseIdempotencyKey(ctx context.Context, key string) {
+	if err := s.idempotencyRepo.ReleaseIdempotencyKey(context.WithoutCancel(ctx), key); err != nil {
+		log.Printf("Warning: failed to release idempotency key %s: %v", key, err)
+	}
+}
+
+// orderStateUnknown reports whether a failed order may still have been placed: the exchange could not be
+// reached after the order was sent, or the call was abandoned while waiting for its answer. An open circuit
+// breaker fails before anything is sent.
+func orderStateUnknown(err error) bool {
+	var unavailable *client.ExchangeUnavailableError
+	if errors.As(err, &unavailable) {
+		return unavailable.Err != nil
+	}
+	return errors.Is(err, client.ErrExchangeUnavailable) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// fakeIdempotencyRepository keeps idempotency keys in memory
+type fakeIdempotencyRepository struct {
+	mu   sync.Mutex
+	keys map[string]model.IdempotencyKey
+}
+
+func newFakeIdempotencyRepository() *fakeIdempotencyRepository {
+	return &fakeIdempotencyRepository{keys: map[string]model.IdempotencyKey{}}
+}
+
+func (r *fakeIdempotencyRepository) ReserveIdempotencyKey(ctx context.Context, key *model.IdempotencyKey) (*model.IdempotencyKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.keys[key.Key]; ok && existing.ExpiresAt.After(key.CreatedAt) {
+		return &existing, nil
+	}
+	key.Status = model.IdempotencyStatusInFlight
+	r.keys[key.Key] = *key
+	return nil, nil
+}
+
+func (r *fakeIdempotencyRepository) CompleteIdempotencyKey(ctx context.Context, key, orderID string, response []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record := r.keys[key]
+	record.Status, record.OrderID, record.Response = model.IdempotencyStatusCompleted, &orderID, response
+	r.keys[key] = record
+	return nil
+}
+
+func (r *fakeIdempotencyRepository) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.keys[key].Status == model.IdempotencyStatusInFlight {
+		delete(r.keys, key)
+	}
+	return nil
+}
+
+func idempotencyKey(key string) *generated.CreateOrderParams {
+	return &generated.CreateOrderParams{IdempotencyKey: &key}
+}
+
+func TestOrderService_CreateOrder_IdempotencyKey(t *testing.T) {
+	var sent []model.OrderRequest
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(2000000), nil
+		},
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			sent = append(sent, *req)
+			return &model.OrderResponse{OrderID: "JRF20240101-000000-000001", ClientOrderID: req.ClientOrderID}, nil
+		},
+	}
+	repo := newFakeIdempotencyRepository()
+	service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+	service.SetIdempotencyKeys(repo, time.Hour)
+
+	req := &generated.CreateOrderRequest{
+		Pair:      "BTC/JPY",
+		OrderType: generated.CreateOrderRequestOrderTypeLimit,
+		Price:     14000000,
+		Amount:    0.001,
+	}
+
+	first, err := service.CreateOrder(context.Background(), idempotencyKey("key-1"), req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// The order ID is the exchange order ID, which GET and DELETE /orders/{orderId} look up
+	if first.OrderId != "JRF20240101-000000-000001" {
+		t.Errorf("expected order ID JRF20240101-000000-000001, got %s", first.OrderId)
+	}
+
+	// The retry returns the first order without placing another one
+	second, err := service.CreateOrder(context.Background(), idempotencyKey("key-1"), req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if *second != *first {
+		t.Errorf("expected the first order %+v, got %+v", first, second)
+	}
+	if len(sent) != 1 {
+		t.Errorf("expected 1 order sent, got %d", len(sent))
+	}
+
+	// The same key cannot be used for another order
+	other := *req
+	other.Amount = 0.002
+	if _, err := service.CreateOrder(context.Background(), idempotencyKey("key-1"), &other); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("expected ErrIdempotencyKeyReused, got %v", err)
+	}
+
+	// Requests without a key are not deduplicated
+	if _, err := service.CreateOrder(context.Background(), nil, req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(sent) != 2 {
+		t.Errorf("expected 2 orders sent, got %d", len(sent))
+	}
+}
+
+func TestOrderService_CreateOrder_IdempotencyKeyFailures(t *testing.T) {
+	tests := []struct {
+		name        string
+		balance     float64
+		sendErr     error
+		wantErr     error
+		wantRetried bool // Whether the key is released, so that a retry places the order
+	}{
+		{
+			name:        "rejected before sending",
+			balance:     100,
+			wantErr:     ErrInsufficientBalance,
+			wantRetried: true,
+		},
+		{
+			name:        "rejected by the exchange",
+			balance:     2000000,
+			sendErr:     &client.APIError{Exchange: "bitFlyer", StatusCode: 400, Code: "-110", Kind: client.ErrInvalidSize, Message: "size too small"},
+			wantErr:     client.ErrInvalidSize,
+			wantRetried: true,
+		},
+		{
+			name:        "circuit breaker open",
+			balance:     2000000,
+			sendErr:     &client.ExchangeUnavailableError{Exchange: "bitflyer", RetryAfter: time.Second},
+			wantErr:     client.ErrExchangeUnavailable,
+			wantRetried: true,
+		},
+		{
+			name:    "state unknown",
+			balance: 2000000,
+			sendErr: &client.ExchangeUnavailableError{Exchange: "bitflyer", Err: errors.New("connection reset")},
+			wantErr: client.ErrExchangeUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sendErr := tt.sendErr
+			mockClient := &client.MockBitFlyerClient{
+				GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+					return jpyBalances(tt.balance), nil
+				},
+				SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+					if sendErr != nil {
+						return nil, sendErr
+					}
+					return &model.OrderResponse{OrderID: "JRF20240101-000000-000001", ClientOrderID: req.ClientOrderID}, nil
+				},
+			}
+			service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+			service.SetIdempotencyKeys(newFakeIdempotencyRepository(), time.Hour)
+
+			req := &generated.CreateOrderRequest{
+				Pair:      "BTC/JPY",
+				OrderType: generated.CreateOrderRequestOrderTypeLimit,
+				Price:     14000000,
+				Amount:    0.001,
+			}
+			if _, err := service.CreateOrder(context.Background(), idempotencyKey("key-1"), req); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			// The failure is resolved and the request is retried with the same key
+			mockClient.GetBalancesFunc = func(ctx context.Context) ([]model.Balance, error) {
+				return jpyBalances(2000000), nil
+			}
+			sendErr = nil
+			_, err := service.CreateOrder(context.Background(), idempotencyKey("key-1"), req)
+			if tt.wantRetried && err != nil {
+				t.Errorf("expected the retry to place the order, got %v", err)
+			}
+			if !tt.wantRetried && !errors.Is(err, ErrOrderInProgress) {
+				t.Errorf("expected ErrOrderInProgress, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOrderService_CreateOrder_IdempotencyKeyExpires(t *testing.T) {
+	sent := 0
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(2000000), nil
+		},
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			sent++
+			return &model.OrderResponse{OrderID: "JRF20240101-000000-000001", ClientOrderID: req.ClientOrderID}, nil
+		},
+	}
+	repo := newFakeIdempotencyRepository()
+	service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+	service.SetIdempotencyKeys(repo, time.Hour)
+
+	req := &generated.CreateOrderRequest{
+		Pair:      "BTC/JPY",
+		OrderType: generated.CreateOrderRequestOrderTypeLimit,
+		Price:     14000000,
+		Amount:    0.001,
+	}
+	if _, err := service.CreateOrder(context.Background(), idempotencyKey("key-1"), req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// The key was used more than an hour ago
+	record := repo.keys["key-1"]
+	record.ExpiresAt = time.Now().Add(-time.Minute)
+	repo.keys["key-1"] = record
+
+	if _, err := service.CreateOrder(context.Background(), idempotencyKey("key-1"), req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sent != 2 {
+		t.Errorf("expected the expired key to place a new order, got %d orders", sent)
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+)
+
+// journalOrder records the order as pending before it is sent
+func (s *OrderServiceImpl) journalOrder(ctx context.Context, exchange string, req *model.OrderRequest, recordedPrice float64) error {
+	if s.journalRepo == nil {
+		return nil
+	}
+
+	err := s.journalRepo.SaveJournalEntry(ctx, &model.OrderJournalEntry{
+		ClientOrderID: req.ClientOrderID,
+		Exchange:      exchange,
+		ProductCode:   req.Symbol.ProductCode(),
+		Side:          string(req.Side),
+		OrderType:     string(req.Type),
+		Price:         req.Price,
+		RecordedPrice: recordedPrice,
+		Size:          req.Size,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to journal order, not sent: %w", err)
+	}
+	return nil
+}
+
+// failJournalEntry marks the entry of an order the exchange did not accept. An order whose state is unknown
+// stays pending for the recovery.
+func (s *OrderServiceImpl) failJournalEntry(ctx context.Context, clientOrderID string, sendErr error) {
+	if s.journalRepo == nil || orderStateUnknown(sendErr) {
+		return
+	}
+
+	message := sendErr.Error()
+	if err := s.journalRepo.UpdateJournalEntryStatus(context.WithoutCancel(ctx), clientOrderID, model.OrderJournalStatusFailed, &message); err != nil {
+		log.Printf("Warning: failed to mark journaled order %s as failed: %v", clientOrderID, err)
+	}
+}
+
+// acceptJournalEntry records the exchange order ID of an accepted order
+func (s *OrderServiceImpl) acceptJournalEntry(ctx context.Context, clientOrderID, orderID string) {
+	if s.journalRepo == nil {
+		return
+	}
+
+	// Left pending, the recovery still finds the order on the exchange
+	if err := s.journalRepo.MarkJournalEntryAccepted(context.WithoutCancel(ctx), clientOrderID, orderID); err != nil {
+		log.Printf("Warning: failed to journal order %s as accepted (%s): %v", clientOrderID, orderID, err)
+	}
+}
+
+// completeJournalEntry marks the entry of an order saved in buy_orders or sell_orders
+func (s *OrderServiceImpl) completeJournalEntry(ctx context.Context, clientOrderID string) {
+	if s.journalRepo == nil {
+		return
+	}
+
+	// Left accepted, the recovery finds the order already saved
+	if err := s.journalRepo.UpdateJournalEntryStatus(context.WithoutCancel(ctx), clientOrderID, model.OrderJournalStatusRecorded, nil); err != nil {
+		log.Printf("Warning: failed to journal order %s as recorded: %v", clientOrderID, err)
+	}
+}
+
+// RecoveryResult counts the outcome of the journal entries reconciled by a recovery
+type RecoveryResult struct {
+	Recorded   int // Saved in buy_orders or sell_orders, or found already saved
+	NotFound   int // Pending orders that are not on the exchange
+	Unresolved int // Left for the next recovery: the exchange or the database failed
+}
+
+// OrderJournalRecovery reconciles the orders journaled by CreateOrder that were not saved in buy_orders
+// or sell_orders, because the process or the database failed while they were being placed
+type OrderJournalRecovery struct {
+	exchangeClient client.CryptoExchangeClient
+	orderRepo      repository.OrderRepository
+	journalRepo    repository.OrderJournalRepository
+	createdAt      time.Time // Entries journaled since may belong to orders being placed by this process
+}
+
+// NewOrderJournalRecovery creates a new order journal recovery. It must be created before the server accepts orders.
+func NewOrderJournalRecovery(exchangeClient client.CryptoExchangeClient, orderRepo repository.OrderRepository, journalRepo repository.OrderJournalRepository) *OrderJournalRecovery {
+	return &OrderJournalRecovery{
+		exchangeClient: exchangeClient,
+		orderRepo:      orderRepo,
+		journalRepo:    journalRepo,
+		createdAt:      time.Now(),
+	}
+}
+
+// Recover reconciles the pending and accepted entries journaled before the recovery was created. Accepted
+// orders are saved unless they already are; pending orders are searched on the exchange they were sent to
+// and saved if found.
+func (r *OrderJournalRecovery) Recover(ctx context.Context) (RecoveryResult, error) {
+	var result RecoveryResult
+
+	entries, err := r.journalRepo.ListUnresolvedJournalEntries(ctx, r.createdAt)
+	if err != nil {
+		return result, err
+	}
+
+	for i := range entries {
+		if err := r.recoverEntry(ctx, &entries[i], &result); err != nil {
+			result.Unresolved++
+			log.Printf("Warning: failed to recover journaled order %s: %v", entries[i].ClientOrderID, err)
+		}
+	}
+
+	return result, nil
+}
+
+// recoverEntry reconciles one journal entry
+func (r *OrderJournalRecovery) recoverEntry(ctx context.Context, entry *model.OrderJournalEntry, result *RecoveryResult) error {
+	if entry.Status == model.OrderJournalStatusAccepted && entry.OrderID != nil {
+		return r.record(ctx, entry, *entry.OrderID, result)
+	}
+
+	req := entry.OrderRequest()
+	exchangeClient := client.ForSymbol(r.exchangeClient, req.Symbol)
+	if exchangeClient.Name() != entry.Exchange {
+		return fmt.Errorf("%s is now routed to %s instead of %s", entry.ProductCode, exchangeClient.Name(), entry.Exchange)
+	}
+
+	placed, err := client.FindPlacedOrder(ctx, exchangeClient, req, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to search orders on %s: %w", entry.Exchange, err)
+	}
+	if placed == nil {
+		message := fmt.Sprintf("not found among the recent orders of %s", entry.Exchange)
+		if err := r.journalRepo.UpdateJournalEntryStatus(ctx, entry.ClientOrderID, model.OrderJournalStatusNotFound, &message); err != nil {
+			return err
+		}
+		result.NotFound++
+		log.Printf("Warning: journaled order %s (%s %s %g %s) was %s; check it on the exchange",
+			entry.ClientOrderID, entry.Side, entry.OrderType, entry.Size, entry.ProductCode, message)
+		return nil
+	}
+
+	if err := r.journalRepo.MarkJournalEntryAccepted(ctx, entry.ClientOrderID, placed.OrderID); err != nil {
+		return err
+	}
+	return r.record(ctx, entry, placed.OrderID, result)
+}
+
+// record saves the order of an entry in buy_orders or sell_orders, unless it is already saved
+func (r *OrderJournalRecovery) record(ctx context.Context, entry *model.OrderJournalEntry, orderID string, result *RecoveryResult) error {
+	_, err := r.orderRepo.FindOrder(ctx, orderID)
+	if errors.Is(err, repository.ErrOrderNotFound) {
+		err = saveOrder(ctx, r.orderRepo, entry.Exchange, entry.Side, orderID, entry.ProductCode, entry.RecordedPrice, entry.Size)
+		if err == nil {
+			log.Printf("Recovered journaled order %s: saved %s order %s", entry.ClientOrderID, entry.ProductCode, orderID)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.journalRepo.UpdateJournalEntryStatus(ctx, entry.ClientOrderID, model.OrderJournalStatusRecorded, nil); err != nil {
+		return err
+	}
+	result.Recorded++
+	return nil
+}
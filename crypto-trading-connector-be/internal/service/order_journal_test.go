@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+)
+
+// fakeOrderJournalRepository keeps journal entries in memory
+type fakeOrderJournalRepository struct {
+	entries map[string]model.OrderJournalEntry
+	saveErr error
+}
+
+func newFakeOrderJournalRepository(entries ...model.OrderJournalEntry) *fakeOrderJournalRepository {
+	r := &fakeOrderJournalRepository{entries: map[string]model.OrderJournalEntry{}}
+	for _, entry := range entries {
+		r.entries[entry.ClientOrderID] = entry
+	}
+	return r
+}
+
+func (r *fakeOrderJournalRepository) SaveJournalEntry(ctx context.Context, entry *model.OrderJournalEntry) error {
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+	entry.Status = model.OrderJournalStatusPending
+	r.entries[entry.ClientOrderID] = *entry
+	return nil
+}
+
+func (r *fakeOrderJournalRepository) MarkJournalEntryAccepted(ctx context.Context, clientOrderID, orderID string) error {
+	entry := r.entries[clientOrderID]
+	entry.Status, entry.OrderID = model.OrderJournalStatusAccepted, &orderID
+	r.entries[clientOrderID] = entry
+	return nil
+}
+
+func (r *fakeOrderJournalRepository) UpdateJournalEntryStatus(ctx context.Context, clientOrderID, status string, message *string) error {
+	entry := r.entries[clientOrderID]
+	entry.Status, entry.Message = status, message
+	r.entries[clientOrderID] = entry
+	return nil
+}
+
+func (r *fakeOrderJournalRepository) ListUnresolvedJournalEntries(ctx context.Context, before time.Time) ([]model.OrderJournalEntry, error) {
+	var entries []model.OrderJournalEntry
+	for _, entry := range r.entries {
+		unresolved := entry.Status == model.OrderJournalStatusPending || entry.Status == model.OrderJournalStatusAccepted
+		if unresolved && entry.CreatedAt.Before(before) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// only returns the single entry of the journal
+func (r *fakeOrderJournalRepository) only(t *testing.T) model.OrderJournalEntry {
+	t.Helper()
+	if len(r.entries) != 1 {
+		t.Fatalf("expected 1 journal entry, got %d", len(r.entries))
+	}
+	for _, entry := range r.entries {
+		return entry
+	}
+	return model.OrderJournalEntry{}
+}
+
+func TestOrderService_CreateOrder_Journal(t *testing.T) {
+	tests := []struct {
+		name       string
+		sendErr    error
+		saveErr    error
+		wantStatus string
+	}{
+		{
+			name:       "saved",
+			wantStatus: model.OrderJournalStatusRecorded,
+		},
+		{
+			name:       "accepted but not saved",
+			saveErr:    errors.New("connection refused"),
+			wantStatus: model.OrderJournalStatusAccepted,
+		},
+		{
+			name:       "rejected by the exchange",
+			sendErr:    &client.APIError{Exchange: "bitFlyer", StatusCode: 400, Code: "-110", Kind: client.ErrInvalidSize, Message: "size too small"},
+			wantStatus: model.OrderJournalStatusFailed,
+		},
+		{
+			name:       "state unknown",
+			sendErr:    &client.ExchangeUnavailableError{Exchange: "bitflyer", Err: errors.New("connection reset")},
+			wantStatus: model.OrderJournalStatusPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			journal := newFakeOrderJournalRepository()
+			mockClient := &client.MockBitFlyerClient{
+				GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+					return jpyBalances(2000000), nil
+				},
+				SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+					// The order is journaled before it is sent
+					if entry := journal.entries[req.ClientOrderID]; entry.Status != model.OrderJournalStatusPending {
+						t.Errorf("expected a pending journal entry before sending, got %+v", entry)
+					}
+					if tt.sendErr != nil {
+						return nil, tt.sendErr
+					}
+					return &model.OrderResponse{OrderID: "JRF20240101-000000-000001", ClientOrderID: req.ClientOrderID}, nil
+				},
+			}
+			mockRepo := &MockOrderRepository{
+				SaveOrderFunc: func(ctx context.Context, order *model.BuyOrder) error {
+					return tt.saveErr
+				},
+			}
+			service := NewOrderService(mockClient, mockRepo, testProductRegistry())
+			service.SetOrderJournal(journal)
+
+			req := &generated.CreateOrderRequest{
+				Pair:      "BTC/JPY",
+				OrderType: generated.CreateOrderRequestOrderTypeLimit,
+				Price:     14000000,
+				Amount:    0.001,
+			}
+			_, err := service.CreateOrder(context.Background(), nil, req)
+			if (err != nil) != (tt.sendErr != nil) {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			entry := journal.only(t)
+			if entry.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s", tt.wantStatus, entry.Status)
+			}
+			if entry.Exchange != "bitflyer" || entry.ProductCode != "BTC_JPY" || entry.Side != "BUY" || entry.Price != 14000000 || entry.Size != 0.001 {
+				t.Errorf("unexpected journal entry %+v", entry)
+			}
+		})
+	}
+}
+
+func TestOrderService_CreateOrder_JournalUnavailable(t *testing.T) {
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(2000000), nil
+		},
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			t.Error("expected the order not to be sent")
+			return nil, errors.New("unexpected")
+		},
+	}
+	journal := newFakeOrderJournalRepository()
+	journal.saveErr = errors.New("connection refused")
+	service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+	service.SetOrderJournal(journal)
+
+	req := &generated.CreateOrderRequest{
+		Pair:      "BTC/JPY",
+		OrderType: generated.CreateOrderRequestOrderTypeLimit,
+		Price:     14000000,
+		Amount:    0.001,
+	}
+	if _, err := service.CreateOrder(context.Background(), nil, req); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestOrderService_CreateOrder_CancelledWhileSending(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	journal := newFakeOrderJournalRepository()
+	var placed *model.OrderRequest
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(2000000), nil
+		},
+		SendOrderFunc: func(sendCtx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			// The client gives up on the request while the order is on its way to the exchange
+			cancel()
+			if sendCtx.Err() != nil {
+				t.Error("expected the order to be sent with a context detached from the request")
+			}
+			placed = req
+			// The exchange received the order, but its answer was abandoned
+			return nil, fmt.Errorf("failed to call bitFlyer API: %w", context.Canceled)
+		},
+	}
+	mockRepo := &MockOrderRepository{}
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
+	service.SetOrderJournal(journal)
+
+	req := &generated.CreateOrderRequest{
+		Pair:      "BTC/JPY",
+		OrderType: generated.CreateOrderRequestOrderTypeLimit,
+		Price:     14000000,
+		Amount:    0.001,
+	}
+	if _, err := service.CreateOrder(ctx, nil, req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// The order may have been placed, so it is left pending for the recovery
+	if entry := journal.only(t); entry.Status != model.OrderJournalStatusPending {
+		t.Fatalf("expected a pending journal entry, got %+v", entry)
+	}
+
+	mockClient.GetChildOrdersFunc = func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+		return []model.ChildOrder{{
+			OrderID:   "JRF20240101-000000-000001",
+			Side:      placed.Side,
+			Type:      placed.Type,
+			Price:     placed.Price,
+			Size:      placed.Size,
+			OrderDate: time.Now(),
+		}}, nil
+	}
+	var saved *model.BuyOrder
+	mockRepo.FindOrderFunc = func(ctx context.Context, orderID string) (*model.OrderRecord, error) {
+		return nil, repository.ErrOrderNotFound
+	}
+	mockRepo.SaveOrderFunc = func(ctx context.Context, order *model.BuyOrder) error {
+		saved = order
+		return nil
+	}
+
+	recovery := NewOrderJournalRecovery(mockClient, mockRepo, journal)
+	result, err := recovery.Recover(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result != (RecoveryResult{Recorded: 1}) {
+		t.Errorf("expected 1 recorded, got %+v", result)
+	}
+	if saved == nil || saved.OrderID != "JRF20240101-000000-000001" {
+		t.Errorf("expected the placed order to be saved, got %+v", saved)
+	}
+	if entry := journal.only(t); entry.Status != model.OrderJournalStatusRecorded {
+		t.Errorf("expected the journal entry to be recorded, got %+v", entry)
+	}
+}
+
+func TestOrderJournalRecovery_Recover(t *testing.T) {
+	startedAt := time.Now()
+	journaledAt := startedAt.Add(-time.Hour)
+	savedOrderID := "JRF20240101-000000-000002"
+	lostOrderID := "JRF20240101-000000-000001"
+
+	journal := newFakeOrderJournalRepository(
+		// Accepted, but the database failed
+		model.OrderJournalEntry{ClientOrderID: "accepted", Exchange: "bitflyer", ProductCode: "BTC_JPY", Side: "BUY", OrderType: "LIMIT",
+			Price: 14000000, RecordedPrice: 14000000, Size: 0.001, Status: model.OrderJournalStatusAccepted, OrderID: &lostOrderID, CreatedAt: journaledAt},
+		// Saved, but the process stopped before the journal was updated
+		model.OrderJournalEntry{ClientOrderID: "saved", Exchange: "bitflyer", ProductCode: "BTC_JPY", Side: "BUY", OrderType: "LIMIT",
+			Price: 13000000, RecordedPrice: 13000000, Size: 0.001, Status: model.OrderJournalStatusAccepted, OrderID: &savedOrderID, CreatedAt: journaledAt},
+		// Sent without an answer; placed on the exchange
+		model.OrderJournalEntry{ClientOrderID: "placed", Exchange: "bitflyer", ProductCode: "ETH_JPY", Side: "SELL", OrderType: "MARKET",
+			RecordedPrice: 500000, Size: 0.1, Status: model.OrderJournalStatusPending, CreatedAt: journaledAt},
+		// Sent without an answer; never placed
+		model.OrderJournalEntry{ClientOrderID: "lost", Exchange: "bitflyer", ProductCode: "BTC_JPY", Side: "BUY", OrderType: "LIMIT",
+			Price: 12000000, RecordedPrice: 12000000, Size: 0.002, Status: model.OrderJournalStatusPending, CreatedAt: journaledAt},
+		// Being placed by this process
+		model.OrderJournalEntry{ClientOrderID: "in-flight", Exchange: "bitflyer", ProductCode: "BTC_JPY", Side: "BUY", OrderType: "LIMIT",
+			Price: 12000000, RecordedPrice: 12000000, Size: 0.002, Status: model.OrderJournalStatusPending, CreatedAt: startedAt.Add(time.Second)},
+	)
+
+	mockClient := &client.MockBitFlyerClient{
+		GetChildOrdersFunc: func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+			if query.ProductCode != "ETH_JPY" {
+				return nil, nil
+			}
+			return []model.ChildOrder{{
+				OrderID:   "JRF20240101-000000-000003",
+				Side:      "SELL",
+				Type:      "MARKET",
+				Size:      0.1,
+				OrderDate: journaledAt.Add(time.Second),
+			}}, nil
+		},
+	}
+	saved := map[string]float64{}
+	mockRepo := &MockOrderRepository{
+		FindOrderFunc: func(ctx context.Context, orderID string) (*model.OrderRecord, error) {
+			if orderID == savedOrderID {
+				return &model.OrderRecord{OrderID: orderID}, nil
+			}
+			return nil, repository.ErrOrderNotFound
+		},
+		SaveOrderFunc: func(ctx context.Context, order *model.BuyOrder) error {
+			saved[order.OrderID] = order.Price
+			return nil
+		},
+		SaveSellOrderFunc: func(ctx context.Context, order *model.SellOrder) error {
+			saved[order.OrderID] = order.Price
+			return nil
+		},
+	}
+
+	recovery := NewOrderJournalRecovery(mockClient, mockRepo, journal)
+	recovery.createdAt = startedAt
+
+	result, err := recovery.Recover(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result != (RecoveryResult{Recorded: 3, NotFound: 1}) {
+		t.Errorf("expected 3 recorded and 1 not found, got %+v", result)
+	}
+
+	wantSaved := map[string]float64{lostOrderID: 14000000, "JRF20240101-000000-000003": 500000}
+	if len(saved) != len(wantSaved) {
+		t.Errorf("expected orders %v to be saved, got %v", wantSaved, saved)
+	}
+	for orderID, price := range wantSaved {
+		if saved[orderID] != price {
+			t.Errorf("expected order %s saved at %g, got %v", orderID, price, saved)
+		}
+	}
+
+	wantStatus := map[string]string{
+		"accepted":  model.OrderJournalStatusRecorded,
+		"saved":     model.OrderJournalStatusRecorded,
+		"placed":    model.OrderJournalStatusRecorded,
+		"lost":      model.OrderJournalStatusNotFound,
+		"in-flight": model.OrderJournalStatusPending,
+	}
+	for clientOrderID, status := range wantStatus {
+		if got := journal.entries[clientOrderID].Status; got != status {
+			t.Errorf("expected %s to be %s, got %s", clientOrderID, status, got)
+		}
+	}
+	if orderID := journal.entries["placed"].OrderID; orderID == nil || *orderID != "JRF20240101-000000-000003" {
+		t.Errorf("expected the order ID found on the exchange to be journaled, got %v", orderID)
+	}
+}
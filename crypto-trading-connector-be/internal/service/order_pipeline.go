@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+)
+
+// OrderPipeline serializes the balance checks of the orders of every exchange account, so that two orders sent
+// at the same time cannot both be covered by the same funds. Every account has a queue worked by its own
+// goroutine, which checks one order at a time and reserves the funds of the orders it lets through.
+//
+// Funds are reserved for the orders being sent and for the open orders recorded in buy_orders/sell_orders.
+// The reservation of an order being sent is released once it was sent or failed; the reservation of an open
+// order is released when the synchronizer or a cancellation moves it to FILLED, CANCELLED or EXPIRED.
+//
+// The risk rules limiting what the orders add up to (QueuedRiskRule) are checked in the same turn, counting
+// the orders being sent on the account, so that they cannot be exceeded by orders sent at the same time either.
+type OrderPipeline struct {
+	orderRepo repository.OrderRepository
+
+	mu       sync.Mutex
+	accounts map[string]*orderAccount // Keyed by exchange name
+}
+
+// FundsReservation is the amount of a currency an order needs: JPY for a buy, the crypto being sold for a sell
+type FundsReservation struct {
+	Currency string
+	Amount   float64
+}
+
+// PipelineRequest is an order, or the legs of a special order, to let through the pipeline
+type PipelineRequest struct {
+	Orders     []*RiskOrder       // Checked against the queued rules of RiskEngine
+	Funds      []FundsReservation // Funds the orders need; legs not executed against the balance need none
+	RiskEngine *RiskEngine        // Nil if the orders are not checked against risk limits
+}
+
+// orderAccount is the queue of an exchange account. Its in-flight requests are only touched by run.
+type orderAccount struct {
+	name     string
+	requests chan func()
+	inFlight map[int]PipelineRequest
+	nextID   int
+}
+
+// NewOrderPipeline creates a new order pipeline reserving funds for the open orders of orderRepo. Without an
+// order repository only the orders being sent are reserved.
+func NewOrderPipeline(orderRepo repository.OrderRepository) *OrderPipeline {
+	return &OrderPipeline{
+		orderRepo: orderRepo,
+		accounts:  make(map[string]*orderAccount),
+	}
+}
+
+// orderReservation returns the funds an order needs
+func orderReservation(side, productCode string, amount, estimatedTotal float64) FundsReservation {
+	base, quote, _ := strings.Cut(productCode, "_")
+	if side == "SELL" {
+		return FundsReservation{Currency: base, Amount: amount}
+	}
+	return FundsReservation{Currency: quote, Amount: estimatedTotal}
+}
+
+// Reserve waits for its turn in the queue of the account of exchangeClient, checks the orders against the queued
+// risk rules, then reserves their funds if the balance covers them on top of every reservation, or fails with
+// ErrInsufficientBalance. The returned function releases the reservation and must be called once the orders
+// were sent or failed, after they were recorded.
+func (p *OrderPipeline) Reserve(ctx context.Context, exchangeClient client.CryptoExchangeClient, request PipelineRequest) (func(), error) {
+	account := p.account(exchangeClient.Name())
+
+	type result struct {
+		id  int
+		err error
+	}
+	done := make(chan result, 1)
+	reserve := func() {
+		if err := ctx.Err(); err != nil {
+			done <- result{err: err}
+			return
+		}
+		if err := account.checkRisk(ctx, request); err != nil {
+			done <- result{err: err}
+			return
+		}
+		if err := p.checkFunds(ctx, account, exchangeClient, request.Funds); err != nil {
+			done <- result{err: err}
+			return
+		}
+		account.nextID++
+		account.inFlight[account.nextID] = request
+		done <- result{id: account.nextID}
+	}
+
+	select {
+	case account.requests <- reserve:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	r := <-done
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			account.requests <- func() { delete(account.inFlight, r.id) }
+		})
+	}
+	return release, nil
+}
+
+// account returns the queue of an exchange account, starting it on first use
+func (p *OrderPipeline) account(name string) *orderAccount {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	account, ok := p.accounts[name]
+	if !ok {
+		account = &orderAccount{
+			name:     name,
+			requests: make(chan func()),
+			inFlight: make(map[int]PipelineRequest),
+		}
+		p.accounts[name] = account
+		go account.run()
+	}
+	return account
+}
+
+// run works the queue of the account for the life of the process
+func (a *orderAccount) run() {
+	for request := range a.requests {
+		request()
+	}
+}
+
+// checkRisk checks each order of a request against the queued risk rules, counting the orders being sent on the
+// account. The legs of a special order are checked on their own, like before the request entered the queue.
+// Called from run only.
+func (a *orderAccount) checkRisk(ctx context.Context, request PipelineRequest) error {
+	if request.RiskEngine == nil {
+		return nil
+	}
+
+	var inFlight []*RiskOrder
+	for _, r := range a.inFlight {
+		inFlight = append(inFlight, r.Orders...)
+	}
+	for _, order := range request.Orders {
+		if err := request.RiskEngine.CheckQueued(ctx, order, inFlight); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFunds verifies that the balance covers each reservation on top of the others. The exchange holds back the
+// funds of the open orders it knows of from the available balance, so a reservation must fit both in the
+// available balance less the orders being sent, and in the total balance less the orders being sent and the
+// open orders. The first covers orders placed outside the pipeline, the second orders the exchange has accepted
+// but not yet held back. Called from run only.
+func (p *OrderPipeline) checkFunds(ctx context.Context, account *orderAccount, exchangeClient client.CryptoExchangeClient, reservations []FundsReservation) error {
+	if len(reservations) == 0 {
+		return nil
+	}
+
+	balances, err := exchangeClient.GetBalances(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	var openOrders []model.OrderRecord
+	if p.orderRepo != nil {
+		if openOrders, err = p.orderRepo.ListOpenOrders(ctx); err != nil {
+			return fmt.Errorf("failed to list open orders: %w", err)
+		}
+	}
+
+	reserved := make(map[string]float64)
+	for _, r := range account.inFlight {
+		for _, funds := range r.Funds {
+			reserved[funds.Currency] += funds.Amount
+		}
+	}
+	for _, reservation := range reservations {
+		var balance model.Balance
+		for _, b := range balances {
+			if b.Currency == reservation.Currency {
+				balance = b
+				break
+			}
+		}
+
+		inFlight := reserved[reservation.Currency]
+		open := 0.0
+		for _, order := range openOrders {
+			// Orders recorded without an exchange count against every account. A partially filled order keeps its
+			// reservation for the full size until it is filled or cancelled.
+			if order.Exchange != "" && order.Exchange != account.name {
+				continue
+			}
+			if r := orderReservation(order.Side, order.ProductCode, order.Size, order.Price*order.Size); r.Currency == reservation.Currency {
+				open += r.Amount
+			}
+		}
+
+		available := math.Min(balance.Available-inFlight, balance.Amount-inFlight-open)
+		if reservation.Amount > available {
+			if reservation.Currency == "JPY" {
+				return fmt.Errorf("%w: required %.2f, available %.2f (%.2f reserved)", ErrInsufficientBalance, reservation.Amount, math.Max(available, 0), inFlight+open)
+			}
+			return fmt.Errorf("%w: required %.8f %s, available %.8f %s (%.8f reserved)", ErrInsufficientBalance, reservation.Amount, reservation.Currency, math.Max(available, 0), reservation.Currency, inFlight+open)
+		}
+		// The reservations of a request add up
+		reserved[reservation.Currency] += reservation.Amount
+	}
+	return nil
+}
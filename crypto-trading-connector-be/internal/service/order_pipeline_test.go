@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// jpyBalances returns an account holding only JPY, none of it held back for open orders
+func jpyBalances(amount float64) []model.Balance {
+	return []model.Balance{{Currency: "JPY", Amount: amount, Available: amount}}
+}
+
+func TestOrderService_CreateOrder_ConcurrentOrdersCannotOverspend(t *testing.T) {
+	var mu sync.Mutex
+	sent := 0
+	unblock := make(chan struct{})
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(20000), nil
+		},
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			mu.Lock()
+			sent++
+			mu.Unlock()
+			// The exchange only holds back the funds after the other order was checked
+			<-unblock
+			return &model.OrderResponse{OrderID: "JRF20240101-000000-000001"}, nil
+		},
+	}
+	service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+
+	req := &generated.CreateOrderRequest{
+		Pair:      "BTC/JPY",
+		OrderType: generated.CreateOrderRequestOrderTypeLimit,
+		Price:     14000000,
+		Amount:    0.001,
+	}
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := service.CreateOrder(context.Background(), nil, req)
+			errs <- err
+		}()
+	}
+
+	// One order is sent and waits for the exchange; the other is rejected while it is in flight
+	if err := <-errs; !errors.Is(err, ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+	}
+	close(unblock)
+	if err := <-errs; err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sent != 1 {
+		t.Errorf("expected 1 order sent, got %d", sent)
+	}
+
+	// The reservation of the order in flight is released once it was sent
+	if _, err := service.CreateOrder(context.Background(), nil, req); err != nil {
+		t.Fatalf("expected no error after the first order was sent, got %v", err)
+	}
+}
+
+func TestOrderPipeline_Reserve(t *testing.T) {
+	balances := []model.Balance{
+		// 14,000 JPY are held back by the exchange for the open bitFlyer buy order below
+		{Currency: "JPY", Amount: 50000, Available: 36000},
+		{Currency: "BTC", Amount: 0.01, Available: 0.01},
+	}
+	exchangeClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return balances, nil
+		},
+	}
+	openOrders := []model.OrderRecord{
+		{OrderID: "JRF-BUY-1", ProductCode: "BTC_JPY", Side: "BUY", Price: 14000000, Size: 0.001, Exchange: "bitflyer", Status: model.OrderStatusUnfilled},
+		{OrderID: "JRF-SELL-1", ProductCode: "BTC_JPY", Side: "SELL", Price: 15000000, Size: 0.004, Exchange: "bitflyer", Status: model.OrderStatusUnfilled},
+		{OrderID: "GMO-BUY-1", ProductCode: "BTC_JPY", Side: "BUY", Price: 14000000, Size: 0.002, Exchange: "gmocoin", Status: model.OrderStatusUnfilled},
+	}
+	orderRepo := &MockOrderRepository{
+		ListOpenOrdersFunc: func(ctx context.Context) ([]model.OrderRecord, error) {
+			return openOrders, nil
+		},
+	}
+	pipeline := NewOrderPipeline(orderRepo)
+	ctx := context.Background()
+
+	// The open buy order is not counted twice: 36,000 JPY are left for new orders
+	release, err := pipeline.Reserve(ctx, exchangeClient, PipelineRequest{Funds: []FundsReservation{{Currency: "JPY", Amount: 30000}}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := pipeline.Reserve(ctx, exchangeClient, PipelineRequest{Funds: []FundsReservation{{Currency: "JPY", Amount: 7000}}}); !errors.Is(err, ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance while 30,000 JPY are in flight, got %v", err)
+	}
+	release()
+	release() // Releasing twice has no effect
+	if _, err := pipeline.Reserve(ctx, exchangeClient, PipelineRequest{Funds: []FundsReservation{{Currency: "JPY", Amount: 36000}}}); err != nil {
+		t.Fatalf("expected no error after the release, got %v", err)
+	}
+
+	// The open sell order reserves 0.004 BTC even before the exchange holds it back
+	if _, err := pipeline.Reserve(ctx, exchangeClient, PipelineRequest{Funds: []FundsReservation{{Currency: "BTC", Amount: 0.007}}}); !errors.Is(err, ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+	}
+
+	// Filled or cancelled orders are no longer listed as open, which releases their funds
+	openOrders = openOrders[2:]
+	if _, err := pipeline.Reserve(ctx, exchangeClient, PipelineRequest{Funds: []FundsReservation{{Currency: "BTC", Amount: 0.007}}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestOrderPipeline_Reserve_QueuedRiskRules(t *testing.T) {
+	exchangeClient := &client.MockBitFlyerClient{}
+	orderRepo := &MockOrderRepository{
+		SumBuyOrderSizeFunc: func(ctx context.Context, productCode string, since time.Time) (float64, error) {
+			return 0.001, nil
+		},
+	}
+	riskEngine := NewRiskEngine(NewRiskRules(RiskConfig{
+		MaxOpenOrders:     2,
+		MaxDailyBuyVolume: map[string]float64{"BTC_JPY": 0.003},
+	}, orderRepo)...)
+	pipeline := NewOrderPipeline(orderRepo)
+	ctx := context.Background()
+	buy := func(size float64) PipelineRequest {
+		return PipelineRequest{
+			Orders:     []*RiskOrder{{Symbol: "BTC/JPY", Side: model.OrderSideBuy, Type: model.OrderTypeLimit, Price: 10000000, Size: size}},
+			RiskEngine: riskEngine,
+		}
+	}
+
+	// The rules depending on other orders are left to the pipeline
+	if err := riskEngine.Check(ctx, buy(0.01).Orders[0]); err != nil {
+		t.Fatalf("expected the queued rules not to be checked, got %v", err)
+	}
+
+	// Neither order is recorded yet, but both count while they are being sent
+	release, err := pipeline.Reserve(ctx, exchangeClient, buy(0.001))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := pipeline.Reserve(ctx, exchangeClient, buy(0.0015)); !errors.Is(err, ErrRiskDailyVolume) {
+		t.Fatalf("expected ErrRiskDailyVolume, got %v", err)
+	}
+	if _, err := pipeline.Reserve(ctx, exchangeClient, buy(0.001)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := pipeline.Reserve(ctx, exchangeClient, buy(0.0001)); !errors.Is(err, ErrRiskMaxOpenOrders) {
+		t.Fatalf("expected ErrRiskMaxOpenOrders, got %v", err)
+	}
+
+	release()
+	if _, err := pipeline.Reserve(ctx, exchangeClient, buy(0.0001)); err != nil {
+		t.Fatalf("expected no error after the release, got %v", err)
+	}
+}
+
+func TestOrderPipeline_Reserve_Errors(t *testing.T) {
+	exchangeClient := &client.MockBitFlyerClient{}
+	pipeline := NewOrderPipeline(&MockOrderRepository{
+		ListOpenOrdersFunc: func(ctx context.Context) ([]model.OrderRecord, error) {
+			return nil, errors.New("connection refused")
+		},
+	})
+
+	// The funds cannot be checked without the open orders
+	if _, err := pipeline.Reserve(context.Background(), exchangeClient, PipelineRequest{Funds: []FundsReservation{{Currency: "JPY", Amount: 1}}}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pipeline.Reserve(ctx, exchangeClient, PipelineRequest{Funds: []FundsReservation{{Currency: "JPY", Amount: 1}}}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -1,159 +1,686 @@
 package service
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/crypto-trading-connector/backend/internal/client"
 	"github.com/crypto-trading-connector/backend/internal/generated"
 	"github.com/crypto-trading-connector/backend/internal/model"
 	"github.com/crypto-trading-connector/backend/internal/repository"
 	"github.com/google/uuid"
-	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 // OrderService defines the interface for order business logic
 type OrderService interface {
-	CreateOrder(req *generated.CreateOrderRequest) (*generated.Order, error)
-	GetBalance() (*generated.Balance, error)
+	CreateOrder(ctx context.Context, params *generated.CreateOrderParams, req *generated.CreateOrderRequest) (*generated.Order, error)
+	GetBalance(ctx context.Context) (*generated.Balance, error)
+	GetBalances(ctx context.Context) (*generated.BalancesResponse, error)
+	CancelOrder(ctx context.Context, orderID string) (*generated.CancelOrderResponse, error)
+	ListOrders(ctx context.Context, params *generated.ListOrdersParams) (*generated.OrderListResponse, error)
+	GetOrder(ctx context.Context, orderID string) (*generated.OrderDetail, error)
 }
 
+const (
+	// defaultOrderListLimit is the page size used when no limit is specified
+	defaultOrderListLimit = 20
+	// liveOrderLookupCount is the number of recent exchange orders fetched per product
+	// when merging live state into an order list
+	liveOrderLookupCount = 100
+)
+
 // OrderServiceImpl implements OrderService
 type OrderServiceImpl struct {
-	exchangeClient client.CryptoExchangeClient
-	orderRepo      repository.OrderRepository
+	exchangeClient  client.CryptoExchangeClient
+	orderRepo       repository.OrderRepository
+	products        *ProductRegistry
+	pipeline        *OrderPipeline
+	idempotencyRepo repository.IdempotencyRepository
+	idempotencyTTL  time.Duration
+	journalRepo     repository.OrderJournalRepository
+	riskEngine      *RiskEngine
+	tradingHalts    repository.TradingHaltRepository
 }
 
 // NewOrderService creates a new order service
-func NewOrderService(exchangeClient client.CryptoExchangeClient, orderRepo repository.OrderRepository) *OrderServiceImpl {
+func NewOrderService(exchangeClient client.CryptoExchangeClient, orderRepo repository.OrderRepository, products *ProductRegistry) *OrderServiceImpl {
 	return &OrderServiceImpl{
 		exchangeClient: exchangeClient,
 		orderRepo:      orderRepo,
+		products:       products,
+		pipeline:       NewOrderPipeline(orderRepo),
 	}
 }
 
-// CreateOrder creates a new order
-func (s *OrderServiceImpl) CreateOrder(req *generated.CreateOrderRequest) (*generated.Order, error) {
+// SetIdempotencyKeys makes CreateOrder honor the Idempotency-Key header: keys are saved in repo before the order
+// is sent and are forgotten after ttl. Without it the header is ignored.
+func (s *OrderServiceImpl) SetIdempotencyKeys(repo repository.IdempotencyRepository, ttl time.Duration) {
+	s.idempotencyRepo = repo
+	s.idempotencyTTL = ttl
+}
+
+// SetOrderJournal makes CreateOrder journal every order in repo before sending it. An order that cannot be
+// journaled is not sent. Without it orders are not journaled.
+func (s *OrderServiceImpl) SetOrderJournal(repo repository.OrderJournalRepository) {
+	s.journalRepo = repo
+}
+
+// SetRiskEngine makes CreateOrder check every order against the risk engine before sending it
+func (s *OrderServiceImpl) SetRiskEngine(engine *RiskEngine) {
+	s.riskEngine = engine
+}
+
+// SetOrderPipeline makes CreateOrder reserve funds in pipeline, shared with the other services placing orders on
+// the same accounts. By default the service has a pipeline of its own.
+func (s *OrderServiceImpl) SetOrderPipeline(pipeline *OrderPipeline) {
+	s.pipeline = pipeline
+}
+
+// SetTradingHalts makes CreateOrder reject orders early while the trading halt is engaged, and GetBalance
+// report it. Orders are stopped by the client.HaltGuard of the exchange client in any case.
+func (s *OrderServiceImpl) SetTradingHalts(halts repository.TradingHaltRepository) {
+	s.tradingHalts = halts
+}
+
+// CreateOrder creates a new buy or sell order. With an Idempotency-Key the order is placed at most once
+// for the key: a repeated request returns the order created by the first one.
+func (s *OrderServiceImpl) CreateOrder(ctx context.Context, params *generated.CreateOrderParams, req *generated.CreateOrderRequest) (*generated.Order, error) {
 	// Validate input
 	if err := s.validateOrderRequest(req); err != nil {
 		return nil, err
 	}
 
-	// Get balance from exchange
-	balance, err := s.exchangeClient.GetBalance()
+	if params != nil && params.IdempotencyKey != nil && s.idempotencyRepo != nil {
+		if key := strings.TrimSpace(*params.IdempotencyKey); key != "" {
+			return s.createOrderOnce(ctx, key, req)
+		}
+	}
+
+	prepared, err := s.prepareOrder(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get balance: %w", err)
+		return nil, err
+	}
+	order, err := s.sendOrder(ctx, prepared, uuid.NewString())
+	return order, err
+}
+
+// preparedOrder is an order request that passed the price and risk checks
+type preparedOrder struct {
+	exchangeClient client.CryptoExchangeClient
+	request        *model.OrderRequest
+	req            *generated.CreateOrderRequest
+	price          float64 // Limit price, or estimated price of a market order
+	estimatedTotal float64
+	riskOrder      *RiskOrder
+}
+
+// prepareOrder prices the order and checks it against the risk limits. The balance and the risk limits depending
+// on other orders are checked when it is sent.
+func (s *OrderServiceImpl) prepareOrder(ctx context.Context, req *generated.CreateOrderRequest) (*preparedOrder, error) {
+	if s.tradingHalts != nil {
+		if err := client.CheckTradingHalt(ctx, s.tradingHalts); err != nil {
+			return nil, err
+		}
+	}
+
+	side := model.OrderSideBuy
+	if req.Side == generated.CreateOrderRequestSideSELL {
+		side = model.OrderSideSell
+	}
+
+	symbol := model.Symbol(req.Pair)
+
+	// Balance, price and the order itself all come from the exchange the pair is routed to
+	exchangeClient := client.ForSymbol(s.exchangeClient, symbol)
+
+	orderType := model.OrderTypeLimit
+	var price, limitPrice float64
+	if req.OrderType == generated.CreateOrderRequestOrderTypeMarket {
+		// Market orders are estimated from the side of the book they will execute against
+		orderType = model.OrderTypeMarket
+		estimated, err := estimateMarketPrice(ctx, exchangeClient, symbol, side)
+		if err != nil {
+			return nil, err
+		}
+		price = estimated
+	} else {
+		// Limit prices are rounded to the tick size: down for buys and up for sells
+		product, _ := s.products.Get(symbol)
+		limitPrice = product.RoundPrice(req.Price, side)
+		if limitPrice <= 0 {
+			return nil, invalid(ErrInvalidPrice, "invalid price: must be at least the tick size %g", product.TickSize)
+		}
+		price = limitPrice
 	}
 
 	// Calculate estimated total
-	estimatedTotal := req.Price * req.Amount
+	estimatedTotal := price * req.Amount
 
-	// Check if balance is sufficient
-	if estimatedTotal > balance {
-		return nil, fmt.Errorf("insufficient balance: required %.2f, available %.2f", estimatedTotal, balance)
+	// Check the pre-trade risk limits
+	riskOrder := &RiskOrder{
+		Symbol:   symbol,
+		Side:     side,
+		Type:     orderType,
+		Price:    price,
+		Size:     req.Amount,
+		Exchange: exchangeClient,
+	}
+	if s.riskEngine != nil {
+		if err := s.riskEngine.Check(ctx, riskOrder); err != nil {
+			return nil, err
+		}
 	}
 
-	// Convert pair format (BTC/JPY -> BTC_JPY)
-	productCode := strings.ReplaceAll(string(req.Pair), "/", "_")
+	return &preparedOrder{
+		exchangeClient: exchangeClient,
+		request: &model.OrderRequest{
+			Symbol:      symbol,
+			Side:        side,
+			Type:        orderType,
+			TimeInForce: model.TimeInForceGTC, // Good Till Cancelled
+			Price:       limitPrice,
+			Size:        req.Amount,
+		},
+		req:            req,
+		price:          price,
+		estimatedTotal: estimatedTotal,
+		riskOrder:      riskOrder,
+	}, nil
+}
 
-	// Send order to exchange
-	exchangeReq := &model.BitFlyerOrderRequest{
-		ProductCode:    productCode,
-		ChildOrderType: "LIMIT",
-		Side:           "BUY",
-		Price:          req.Price,
-		Size:           req.Amount,
-		TimeInForce:    "GTC", // Good Till Cancelled
+// sendOrder reserves the funds of a prepared order in the pipeline of its account, sends it to the exchange and
+// records it. The order is identified by its exchange order ID, like in the order list and the cancellation.
+func (s *OrderServiceImpl) sendOrder(ctx context.Context, prepared *preparedOrder, clientOrderID string) (*generated.Order, error) {
+	orderReq := *prepared.request
+	orderReq.ClientOrderID = clientOrderID
+	exchange := prepared.exchangeClient.Name()
+
+	// Once recorded, the order is reserved as an open order until it is filled or cancelled
+	reservation := orderReservation(string(orderReq.Side), orderReq.Symbol.ProductCode(), orderReq.Size, prepared.estimatedTotal)
+	release, err := s.pipeline.Reserve(ctx, prepared.exchangeClient, PipelineRequest{
+		Orders:     []*RiskOrder{prepared.riskOrder},
+		Funds:      []FundsReservation{reservation},
+		RiskEngine: s.riskEngine,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// A client giving up on the request must not stop an order that may already be on its way
+	ctx = context.WithoutCancel(ctx)
+
+	// Journal the order before sending it, so that it can be recovered if it cannot be saved afterwards
+	if err := s.journalOrder(ctx, exchange, &orderReq, prepared.price); err != nil {
+		return nil, err
 	}
 
-	exchangeResp, err := s.exchangeClient.SendOrder(exchangeReq)
+	// Send order to exchange
+	exchangeResp, err := prepared.exchangeClient.SendOrder(ctx, &orderReq)
 	if err != nil {
+		s.failJournalEntry(ctx, clientOrderID, err)
 		return nil, fmt.Errorf("failed to send order to exchange: %w", err)
 	}
+	s.acceptJournalEntry(ctx, clientOrderID, exchangeResp.OrderID)
 
 	// Save order to database
-	buyOrder := &model.BuyOrder{
-		OrderID:     exchangeResp.ChildOrderAcceptanceID,
+	if err := saveOrder(ctx, s.orderRepo, exchange, string(orderReq.Side), exchangeResp.OrderID, orderReq.Symbol.ProductCode(), prepared.price, orderReq.Size); err != nil {
+		// Log error but don't fail - order was already sent to exchange and the journal recovery saves it at startup
+		fmt.Printf("Warning: failed to save order to database: %v\n", err)
+	} else {
+		s.completeJournalEntry(ctx, clientOrderID)
+	}
+
+	// Create response
+	order := &generated.Order{
+		OrderId:        exchangeResp.OrderID,
+		Pair:           prepared.req.Pair,
+		Side:           generated.OrderSide(orderReq.Side),
+		OrderType:      generated.OrderOrderType(prepared.req.OrderType),
+		Price:          prepared.price,
+		Amount:         orderReq.Size,
+		EstimatedTotal: prepared.estimatedTotal,
+		Status:         generated.Pending,
+	}
+
+	return order, nil
+}
+
+// fetchOrderTicker returns the ticker an order is priced or checked against. A stale ticker, served from the
+// cache while the exchange cannot be reached, is rejected as the exchange being unavailable.
+func fetchOrderTicker(ctx context.Context, exchangeClient client.CryptoExchangeClient, symbol model.Symbol) (*model.Ticker, error) {
+	ticker, err := exchangeClient.GetTicker(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticker: %w", err)
+	}
+	if ticker.Stale {
+		return nil, fmt.Errorf("failed to get ticker: %w: %s price is stale", client.ErrExchangeUnavailable, symbol)
+	}
+	return ticker, nil
+}
+
+// estimateMarketPrice returns the price a market order is expected to execute at
+func estimateMarketPrice(ctx context.Context, exchangeClient client.CryptoExchangeClient, symbol model.Symbol, side model.OrderSide) (float64, error) {
+	ticker, err := fetchOrderTicker(ctx, exchangeClient, symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	price := ticker.Ask
+	if side == model.OrderSideSell {
+		price = ticker.Bid
+	}
+	if price <= 0 {
+		price = ticker.Last
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("failed to get ticker: no price available for %s", symbol)
+	}
+
+	return price, nil
+}
+
+// saveOrder records a newly placed order in buy_orders or sell_orders depending on its side
+func saveOrder(ctx context.Context, orderRepo repository.OrderRepository, exchange, side, orderID, productCode string, price, size float64) error {
+	if side == "SELL" {
+		return orderRepo.SaveSellOrder(ctx, &model.SellOrder{
+			OrderID:     orderID,
+			ProductCode: productCode,
+			Side:        side,
+			Price:       price,
+			Size:        size,
+			Exchange:    exchange,
+			Status:      model.OrderStatusUnfilled,
+		})
+	}
+
+	return orderRepo.SaveOrder(ctx, &model.BuyOrder{
+		OrderID:     orderID,
 		ProductCode: productCode,
-		Side:        "BUY",
-		Price:       req.Price,
-		Size:        req.Amount,
-		Exchange:    "bitflyer",
-		Status:      "UNFILLED", // UNFILLED status
+		Side:        side,
+		Price:       price,
+		Size:        size,
+		Exchange:    exchange,
+		Status:      model.OrderStatusUnfilled,
 		Strategy:    99, // 99: not recorded
 		Remarks:     nil,
+	})
+}
+
+// CancelOrder cancels an open order on the exchange and marks it as CANCELLED in the database
+func (s *OrderServiceImpl) CancelOrder(ctx context.Context, orderID string) (*generated.CancelOrderResponse, error) {
+	order, err := s.orderRepo.FindOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.orderRepo.SaveOrder(buyOrder); err != nil {
-		// Log error but don't fail - order was already sent to exchange
-		fmt.Printf("Warning: failed to save order to database: %v\n", err)
+	// Only orders that are still resting on the exchange can be cancelled
+	if !isOpenOrderStatus(order.Status) {
+		return nil, fmt.Errorf("%w: status is %s", ErrOrderNotCancellable, order.Status)
 	}
 
-	// Create response
-	// Parse UUID from string
-	orderUUID, err := uuid.Parse(exchangeResp.ChildOrderAcceptanceID)
+	if err := s.exchangeClient.CancelOrder(ctx, order.ProductCode, order.OrderID); err != nil {
+		return nil, fmt.Errorf("failed to cancel order on exchange: %w", err)
+	}
+
+	if err := s.recordCancelled(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return &generated.CancelOrderResponse{
+		OrderId: order.OrderID,
+		Pair:    strings.ReplaceAll(order.ProductCode, "_", "/"),
+		Status:  "cancelled",
+	}, nil
+}
+
+// recordCancelled moves a cancelled order to CANCELLED unless the synchronizer recorded it as filled meanwhile:
+// a fill that happened before the cancellation reached the exchange must keep its status, so that its
+// take-profit is still placed. A partial fill recorded meanwhile is cancelled from its new status.
+func (s *OrderServiceImpl) recordCancelled(ctx context.Context, order *model.OrderRecord) error {
+	status := order.Status
+	for isOpenOrderStatus(status) {
+		updated, err := s.orderRepo.TransitionOrderStatus(ctx, order.Side, order.OrderID, status, model.OrderStatusCancelled)
+		if err != nil {
+			// Log error but don't fail - order was already cancelled on exchange and the synchronizer records it
+			fmt.Printf("Warning: failed to update order status in database: %v\n", err)
+			return nil
+		}
+		if updated {
+			return nil
+		}
+
+		current, err := s.orderRepo.FindOrder(ctx, order.OrderID)
+		if err != nil {
+			fmt.Printf("Warning: failed to get order after its status changed: %v\n", err)
+			return nil
+		}
+		status = current.Status
+	}
+	return fmt.Errorf("%w: status changed to %s while cancelling", ErrOrderNotCancellable, status)
+}
+
+// isOpenOrderStatus reports whether an order with the status is still resting on the exchange
+func isOpenOrderStatus(status string) bool {
+	return status == model.OrderStatusUnfilled || status == model.OrderStatusPartiallyFilled
+}
+
+// ListOrders retrieves recorded orders merged with their live state on the exchange
+func (s *OrderServiceImpl) ListOrders(ctx context.Context, params *generated.ListOrdersParams) (*generated.OrderListResponse, error) {
+	filter, err := toOrderFilter(params)
 	if err != nil {
-		// If not a valid UUID, generate a new one
-		// In production, exchanges may return acceptance IDs that are not UUIDs
-		orderUUID = uuid.New()
+		return nil, err
 	}
 
-	order := &generated.Order{
-		OrderId:        openapi_types.UUID(orderUUID),
-		Pair:           generated.OrderPair(req.Pair),
-		OrderType:      generated.OrderOrderType(req.OrderType),
-		Price:          req.Price,
-		Amount:         req.Amount,
-		EstimatedTotal: estimatedTotal,
-		Status:         generated.Pending,
+	// Fetch one extra row to find out whether another page exists
+	requested := filter.Limit
+	filter.Limit = requested + 1
+
+	records, err := s.orderRepo.ListOrders(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
 	}
 
-	return order, nil
+	hasNext := len(records) > requested
+	if hasNext {
+		records = records[:requested]
+	}
+
+	liveOrders := s.fetchLiveOrders(ctx, records)
+
+	orders := make([]generated.OrderDetail, 0, len(records))
+	for _, record := range records {
+		var live *model.ChildOrder
+		if childOrder, ok := liveOrders[record.OrderID]; ok {
+			live = &childOrder
+		}
+		orders = append(orders, toOrderDetail(record, live))
+	}
+
+	response := &generated.OrderListResponse{
+		Orders:  orders,
+		HasNext: hasNext,
+	}
+	if hasNext {
+		last := records[len(records)-1]
+		cursor := encodeOrderCursor(&model.OrderCursor{Timestamp: last.Timestamp, OrderID: last.OrderID})
+		response.NextCursor = &cursor
+	}
+
+	return response, nil
+}
+
+// GetOrder retrieves a single recorded order merged with its live state on the exchange
+func (s *OrderServiceImpl) GetOrder(ctx context.Context, orderID string) (*generated.OrderDetail, error) {
+	record, err := s.orderRepo.FindOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var live *model.ChildOrder
+	childOrders, err := s.exchangeClient.GetChildOrders(ctx, &model.ChildOrderQuery{
+		ProductCode: record.ProductCode,
+		OrderID:     record.OrderID,
+	})
+	if err != nil {
+		// Log error but don't fail - the recorded order is still useful without live state
+		fmt.Printf("Warning: failed to get live order state from exchange: %v\n", err)
+	} else if len(childOrders) > 0 {
+		live = &childOrders[0]
+	}
+
+	detail := toOrderDetail(*record, live)
+	return &detail, nil
+}
+
+// fetchLiveOrders retrieves recent exchange orders for every product in records,
+// keyed by order ID
+func (s *OrderServiceImpl) fetchLiveOrders(ctx context.Context, records []model.OrderRecord) map[string]model.ChildOrder {
+	liveOrders := make(map[string]model.ChildOrder)
+
+	queried := make(map[string]bool)
+	for _, record := range records {
+		if queried[record.ProductCode] {
+			continue
+		}
+		queried[record.ProductCode] = true
+
+		childOrders, err := s.exchangeClient.GetChildOrders(ctx, &model.ChildOrderQuery{
+			ProductCode: record.ProductCode,
+			Count:       liveOrderLookupCount,
+		})
+		if err != nil {
+			// Log error but don't fail - orders are returned without live state
+			fmt.Printf("Warning: failed to get live orders for %s from exchange: %v\n", record.ProductCode, err)
+			continue
+		}
+
+		for _, childOrder := range childOrders {
+			liveOrders[childOrder.OrderID] = childOrder
+		}
+	}
+
+	return liveOrders
+}
+
+// toOrderFilter converts list parameters into a repository filter
+func toOrderFilter(params *generated.ListOrdersParams) (*model.OrderFilter, error) {
+	filter := &model.OrderFilter{
+		Limit: defaultOrderListLimit,
+	}
+
+	if params.Limit != nil {
+		if *params.Limit < 1 || *params.Limit > 100 {
+			return nil, invalid(ErrInvalidPagination, "invalid limit: %d. Limit must be between 1 and 100", *params.Limit)
+		}
+		filter.Limit = *params.Limit
+	}
+
+	if params.Pair != nil && *params.Pair != "" {
+		filter.ProductCode = strings.ReplaceAll(*params.Pair, "/", "_")
+	}
+
+	if params.Side != nil {
+		switch *params.Side {
+		case generated.ListOrdersParamsSideBUY, generated.ListOrdersParamsSideSELL:
+			filter.Side = string(*params.Side)
+		default:
+			return nil, invalid(ErrInvalidFilter, "invalid side filter: %s. Valid values are: BUY, SELL", *params.Side)
+		}
+	}
+
+	if params.Status != nil {
+		statuses, ok := orderStatusesByFilter[*params.Status]
+		if !ok {
+			return nil, invalid(ErrInvalidFilter, "invalid status filter: %s", *params.Status)
+		}
+		filter.Statuses = statuses
+	}
+
+	if params.From != nil && params.To != nil && !params.From.Before(*params.To) {
+		return nil, invalid(ErrInvalidFilter, "invalid time filter: from must be before to")
+	}
+	filter.From = params.From
+	filter.To = params.To
+
+	if params.Cursor != nil && *params.Cursor != "" {
+		cursor, err := decodeOrderCursor(*params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
+
+// orderStatusesByFilter maps API status filters to the statuses stored in the database
+var orderStatusesByFilter = map[generated.ListOrdersParamsStatus][]string{
+	generated.ListOrdersParamsStatusUNFILLED:        {"UNFILLED"},
+	generated.ListOrdersParamsStatusPARTIALLYFILLED: {"PARTIALLY_FILLED"},
+	generated.ListOrdersParamsStatusFILLED:          {model.OrderStatusFilled, model.OrderStatusSellOrderPending, model.OrderStatusSellOrderPlaced},
+	generated.ListOrdersParamsStatusCANCELLED:       {"CANCELLED"},
+	generated.ListOrdersParamsStatusEXPIRED:         {"EXPIRED"},
 }
 
-// GetBalance retrieves the current balance
-func (s *OrderServiceImpl) GetBalance() (*generated.Balance, error) {
-	balance, err := s.exchangeClient.GetBalance()
+// toOrderStatus converts a database status into the API status
+func toOrderStatus(status string) generated.OrderDetailStatus {
+	// Buy orders are marked e.g. "FILLED(SELL ORDER PLACED)" once the take-profit sell is placed
+	if strings.HasPrefix(status, "FILLED") {
+		return generated.OrderDetailStatusFILLED
+	}
+	return generated.OrderDetailStatus(status)
+}
+
+// toOrderDetail converts a recorded order and its optional live exchange state into the API representation
+func toOrderDetail(record model.OrderRecord, live *model.ChildOrder) generated.OrderDetail {
+	detail := generated.OrderDetail{
+		OrderId:       record.OrderID,
+		Pair:          strings.ReplaceAll(record.ProductCode, "_", "/"),
+		Side:          generated.OrderDetailSide(record.Side),
+		Price:         record.Price,
+		Amount:        record.Size,
+		Status:        toOrderStatus(record.Status),
+		Exchange:      record.Exchange,
+		ParentOrderId: record.ParentID,
+		CreatedAt:     record.Timestamp,
+		UpdatedAt:     record.Updatetime,
+	}
+
+	if live != nil {
+		detail.OutstandingSize = &live.OutstandingSize
+		detail.ExecutedSize = &live.ExecutedSize
+		detail.AveragePrice = &live.AveragePrice
+		exchangeState := string(live.State)
+		detail.ExchangeState = &exchangeState
+
+		// The database only learns about partial fills from the exchange
+		if detail.Status == generated.OrderDetailStatusUNFILLED && live.State == model.OrderStateActive && live.ExecutedSize > 0 {
+			detail.Status = generated.OrderDetailStatusPARTIALLYFILLED
+		}
+	}
+
+	return detail
+}
+
+// encodeOrderCursor encodes the position of an order into an opaque cursor string
+func encodeOrderCursor(cursor *model.OrderCursor) string {
+	raw := cursor.Timestamp.UTC().Format(time.RFC3339Nano) + "|" + cursor.OrderID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeOrderCursor decodes a cursor string produced by encodeOrderCursor
+func decodeOrderCursor(value string) (*model.OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, invalid(ErrInvalidPagination, "invalid cursor: %s", value)
+	}
+
+	timestamp, orderID, found := strings.Cut(string(raw), "|")
+	if !found || orderID == "" {
+		return nil, invalid(ErrInvalidPagination, "invalid cursor: %s", value)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return nil, invalid(ErrInvalidPagination, "invalid cursor: %s", value)
+	}
+
+	return &model.OrderCursor{Timestamp: t, OrderID: orderID}, nil
+}
+
+// GetBalance retrieves the current JPY balance of every exchange together with crypto holdings
+func (s *OrderServiceImpl) GetBalance(ctx context.Context) (*generated.Balance, error) {
+	balances, err := s.exchangeClient.GetBalances(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get balance: %w", err)
 	}
 
+	foundJPY := false
+	availableJPY := 0.0
+	holdings := []generated.CurrencyBalance{}
+	for _, balance := range balances {
+		if balance.Currency == string(generated.JPY) {
+			foundJPY = true
+			availableJPY += balance.Available
+			continue
+		}
+		holdings = append(holdings, toCurrencyBalance(balance))
+	}
+
+	if !foundJPY {
+		return nil, fmt.Errorf("failed to get balance: JPY balance not found")
+	}
+
 	return &generated.Balance{
-		AvailableBalance: balance,
+		AvailableBalance: availableJPY,
 		Currency:         generated.JPY,
-		Timestamp:        0, // TODO: Add proper timestamp
+		Holdings:         &holdings,
+		Timestamp:        time.Now().Unix(),
+		TradingHalted:    tradingHalted(ctx, s.tradingHalts),
 	}, nil
 }
 
-// validateOrderRequest validates the order request
-func (s *OrderServiceImpl) validateOrderRequest(req *generated.CreateOrderRequest) error {
-	// Validate price
-	if req.Price <= 0 {
-		return fmt.Errorf("invalid price: must be greater than 0")
+// GetBalances retrieves the balances of every currency held on every exchange
+func (s *OrderServiceImpl) GetBalances(ctx context.Context) (*generated.BalancesResponse, error) {
+	balances, err := s.exchangeClient.GetBalances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balances: %w", err)
 	}
 
-	// Validate amount
-	if req.Amount <= 0 {
-		return fmt.Errorf("invalid amount: must be greater than 0")
+	result := make([]generated.CurrencyBalance, 0, len(balances))
+	for _, balance := range balances {
+		result = append(result, toCurrencyBalance(balance))
 	}
 
-	// Validate minimum amount based on pair
-	switch req.Pair {
-	case generated.CreateOrderRequestPairBTCJPY:
-		if req.Amount < 0.001 {
-			return fmt.Errorf("invalid amount for BTC/JPY: minimum is 0.001")
-		}
-	case generated.CreateOrderRequestPairETHJPY:
-		if req.Amount < 0.01 {
-			return fmt.Errorf("invalid amount for ETH/JPY: minimum is 0.01")
+	return &generated.BalancesResponse{
+		Balances:  result,
+		Timestamp: time.Now().Unix(),
+	}, nil
+}
+
+// toCurrencyBalance converts an exchange balance into the API representation
+func toCurrencyBalance(balance model.Balance) generated.CurrencyBalance {
+	return generated.CurrencyBalance{
+		Currency:  balance.Currency,
+		Exchange:  balance.Exchange,
+		Amount:    balance.Amount,
+		Available: balance.Available,
+	}
+}
+
+// validateOrderRequest validates the order request
+func (s *OrderServiceImpl) validateOrderRequest(req *generated.CreateOrderRequest) error {
+	// Validate order type
+	switch req.OrderType {
+	case generated.CreateOrderRequestOrderTypeLimit:
+		// Validate price
+		if req.Price <= 0 {
+			return invalid(ErrInvalidPrice, "invalid price: must be greater than 0")
 		}
+	case generated.CreateOrderRequestOrderTypeMarket:
 	default:
-		return fmt.Errorf("unsupported pair: %s", req.Pair)
+		return invalid(ErrInvalidRequest, "unsupported order type: %s", req.OrderType)
 	}
 
-	// Validate order type
-	if req.OrderType != generated.CreateOrderRequestOrderTypeLimit {
-		return fmt.Errorf("unsupported order type: %s", req.OrderType)
+	// Validate side (empty means BUY)
+	switch req.Side {
+	case "", generated.CreateOrderRequestSideBUY, generated.CreateOrderRequestSideSELL:
+	default:
+		return invalid(ErrInvalidRequest, "invalid side: %s", req.Side)
+	}
+
+	// Validate amount
+	if req.Amount <= 0 {
+		return invalid(ErrInvalidAmount, "invalid amount: must be greater than 0")
+	}
+
+	// Validate minimum amount and lot step based on pair
+	if err := s.products.ValidateSize(model.Symbol(req.Pair), req.Amount); err != nil {
+		return err
 	}
 
 	return nil
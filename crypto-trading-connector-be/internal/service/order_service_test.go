@@ -1,8 +1,12 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/crypto-trading-connector/backend/internal/client"
 	"github.com/crypto-trading-connector/backend/internal/generated"
@@ -11,52 +15,124 @@ import (
 
 // MockOrderRepository is a mock implementation of OrderRepository for testing
 type MockOrderRepository struct {
-	SaveOrderFunc     func(order *model.BuyOrder) error
-	GetOrderByIDFunc  func(orderID string) (*model.BuyOrder, error)
+	SaveOrderFunc         func(ctx context.Context, order *model.BuyOrder) error
+	GetOrderByIDFunc      func(ctx context.Context, orderID string) (*model.BuyOrder, error)
+	UpdateOrderStatusFunc func(ctx context.Context, orderID, status string) error
+	ListOrdersFunc        func(ctx context.Context, filter *model.OrderFilter) ([]model.OrderRecord, error)
+	FindOrderFunc         func(ctx context.Context, orderID string) (*model.OrderRecord, error)
+	ListOpenOrdersFunc    func(ctx context.Context) ([]model.OrderRecord, error)
+	TransitionStatusFunc  func(ctx context.Context, side, orderID, fromStatus, toStatus string) (bool, error)
+	ListBuyOrdersFunc     func(ctx context.Context, status string) ([]model.BuyOrder, error)
+	SumBuyOrderSizeFunc   func(ctx context.Context, productCode string, since time.Time) (float64, error)
+	SaveSellOrderFunc     func(ctx context.Context, order *model.SellOrder) error
+	GetSellOrderFunc      func(ctx context.Context, parentID string) (*model.SellOrder, error)
 }
 
-func (m *MockOrderRepository) SaveOrder(order *model.BuyOrder) error {
+func (m *MockOrderRepository) SaveOrder(ctx context.Context, order *model.BuyOrder) error {
 	if m.SaveOrderFunc != nil {
-		return m.SaveOrderFunc(order)
+		return m.SaveOrderFunc(ctx, order)
 	}
 	return nil
 }
 
-func (m *MockOrderRepository) GetOrderByID(orderID string) (*model.BuyOrder, error) {
+func (m *MockOrderRepository) GetOrderByID(ctx context.Context, orderID string) (*model.BuyOrder, error) {
 	if m.GetOrderByIDFunc != nil {
-		return m.GetOrderByIDFunc(orderID)
+		return m.GetOrderByIDFunc(ctx, orderID)
 	}
 	return nil, errors.New("not implemented")
 }
 
+func (m *MockOrderRepository) UpdateOrderStatus(ctx context.Context, orderID, status string) error {
+	if m.UpdateOrderStatusFunc != nil {
+		return m.UpdateOrderStatusFunc(ctx, orderID, status)
+	}
+	return nil
+}
+
+func (m *MockOrderRepository) ListOrders(ctx context.Context, filter *model.OrderFilter) ([]model.OrderRecord, error) {
+	if m.ListOrdersFunc != nil {
+		return m.ListOrdersFunc(ctx, filter)
+	}
+	return []model.OrderRecord{}, nil
+}
+
+func (m *MockOrderRepository) FindOrder(ctx context.Context, orderID string) (*model.OrderRecord, error) {
+	if m.FindOrderFunc != nil {
+		return m.FindOrderFunc(ctx, orderID)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockOrderRepository) ListOpenOrders(ctx context.Context) ([]model.OrderRecord, error) {
+	if m.ListOpenOrdersFunc != nil {
+		return m.ListOpenOrdersFunc(ctx)
+	}
+	return []model.OrderRecord{}, nil
+}
+
+func (m *MockOrderRepository) TransitionOrderStatus(ctx context.Context, side, orderID, fromStatus, toStatus string) (bool, error) {
+	if m.TransitionStatusFunc != nil {
+		return m.TransitionStatusFunc(ctx, side, orderID, fromStatus, toStatus)
+	}
+	return true, nil
+}
+
+func (m *MockOrderRepository) ListBuyOrdersByStatus(ctx context.Context, status string) ([]model.BuyOrder, error) {
+	if m.ListBuyOrdersFunc != nil {
+		return m.ListBuyOrdersFunc(ctx, status)
+	}
+	return []model.BuyOrder{}, nil
+}
+
+func (m *MockOrderRepository) SumBuyOrderSize(ctx context.Context, productCode string, since time.Time) (float64, error) {
+	if m.SumBuyOrderSizeFunc != nil {
+		return m.SumBuyOrderSizeFunc(ctx, productCode, since)
+	}
+	return 0, nil
+}
+
+func (m *MockOrderRepository) SaveSellOrder(ctx context.Context, order *model.SellOrder) error {
+	if m.SaveSellOrderFunc != nil {
+		return m.SaveSellOrderFunc(ctx, order)
+	}
+	return nil
+}
+
+func (m *MockOrderRepository) GetSellOrderByParentID(ctx context.Context, parentID string) (*model.SellOrder, error) {
+	if m.GetSellOrderFunc != nil {
+		return m.GetSellOrderFunc(ctx, parentID)
+	}
+	return nil, nil
+}
+
 func TestOrderService_CreateOrder_Success(t *testing.T) {
 	mockClient := &client.MockBitFlyerClient{
-		GetBalanceFunc: func() (float64, error) {
-			return 2000000.0, nil // 2,000,000 JPY
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(2000000.0), nil // 2,000,000 JPY
 		},
-		SendOrderFunc: func(req *model.BitFlyerOrderRequest) (*model.BitFlyerOrderResponse, error) {
-			return &model.BitFlyerOrderResponse{
-				ChildOrderAcceptanceID: "ORDER_123",
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			return &model.OrderResponse{
+				OrderID: "ORDER_123",
 			}, nil
 		},
 	}
 
 	mockRepo := &MockOrderRepository{
-		SaveOrderFunc: func(order *model.BuyOrder) error {
+		SaveOrderFunc: func(ctx context.Context, order *model.BuyOrder) error {
 			return nil
 		},
 	}
 
-	service := NewOrderService(mockClient, mockRepo)
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
 
 	req := &generated.CreateOrderRequest{
-		Pair:      generated.CreateOrderRequestPairBTCJPY,
+		Pair:      "BTC/JPY",
 		OrderType: generated.CreateOrderRequestOrderTypeLimit,
 		Price:     14000000,
 		Amount:    0.001,
 	}
 
-	order, err := service.CreateOrder(req)
+	order, err := service.CreateOrder(context.Background(), nil, req)
 
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -64,9 +140,9 @@ func TestOrderService_CreateOrder_Success(t *testing.T) {
 	if order == nil {
 		t.Fatal("expected order, got nil")
 	}
-	// OrderId is UUID type, just check it's not empty
-	if order.OrderId.String() == "00000000-0000-0000-0000-000000000000" {
-		t.Error("expected valid order ID, got empty UUID")
+	// The order is identified by its exchange order ID
+	if order.OrderId != "ORDER_123" {
+		t.Errorf("expected order ID ORDER_123, got %s", order.OrderId)
 	}
 	if order.EstimatedTotal != 14000 {
 		t.Errorf("expected estimated total 14000, got %f", order.EstimatedTotal)
@@ -78,23 +154,23 @@ func TestOrderService_CreateOrder_Success(t *testing.T) {
 
 func TestOrderService_CreateOrder_InsufficientBalance(t *testing.T) {
 	mockClient := &client.MockBitFlyerClient{
-		GetBalanceFunc: func() (float64, error) {
-			return 10000.0, nil // Only 10,000 JPY
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(10000.0), nil // Only 10,000 JPY
 		},
 	}
 
 	mockRepo := &MockOrderRepository{}
 
-	service := NewOrderService(mockClient, mockRepo)
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
 
 	req := &generated.CreateOrderRequest{
-		Pair:      generated.CreateOrderRequestPairBTCJPY,
+		Pair:      "BTC/JPY",
 		OrderType: generated.CreateOrderRequestOrderTypeLimit,
 		Price:     14000000,
 		Amount:    0.001,
 	}
 
-	order, err := service.CreateOrder(req)
+	order, err := service.CreateOrder(context.Background(), nil, req)
 
 	if err == nil {
 		t.Error("expected error for insufficient balance, got nil")
@@ -104,19 +180,296 @@ func TestOrderService_CreateOrder_InsufficientBalance(t *testing.T) {
 	}
 }
 
+func TestOrderService_CreateOrder_SellLimit(t *testing.T) {
+	var sentReq *model.OrderRequest
+	mockClient := &client.MockBitFlyerClient{
+		GetBalanceFunc: func(ctx context.Context) (float64, error) {
+			t.Error("expected JPY balance not to be checked for a sell order")
+			return 0, nil
+		},
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return []model.Balance{
+				{Currency: "JPY", Amount: 0, Available: 0},
+				{Currency: "ETH", Amount: 0.5, Available: 0.2},
+			}, nil
+		},
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			sentReq = req
+			return &model.OrderResponse{OrderID: "JRF-SELL-1"}, nil
+		},
+	}
+
+	var savedSell *model.SellOrder
+	mockRepo := &MockOrderRepository{
+		SaveOrderFunc: func(ctx context.Context, order *model.BuyOrder) error {
+			t.Error("expected sell order not to be saved into buy_orders")
+			return nil
+		},
+		SaveSellOrderFunc: func(ctx context.Context, order *model.SellOrder) error {
+			savedSell = order
+			return nil
+		},
+	}
+
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
+
+	order, err := service.CreateOrder(context.Background(), nil, &generated.CreateOrderRequest{
+		Pair:      "ETH/JPY",
+		Side:      generated.CreateOrderRequestSideSELL,
+		OrderType: generated.CreateOrderRequestOrderTypeLimit,
+		Price:     500000,
+		Amount:    0.1,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sentReq.Side != "SELL" || sentReq.Type != model.OrderTypeLimit || sentReq.Price != 500000 || sentReq.Symbol != "ETH/JPY" {
+		t.Errorf("unexpected exchange request: %+v", sentReq)
+	}
+	if savedSell == nil || savedSell.OrderID != "JRF-SELL-1" || savedSell.ParentID != "" || savedSell.Status != "UNFILLED" {
+		t.Errorf("unexpected sell_orders row: %+v", savedSell)
+	}
+	if order.Side != generated.OrderSideSELL || order.EstimatedTotal != 50000 {
+		t.Errorf("unexpected order: %+v", order)
+	}
+}
+
+func TestOrderService_CreateOrder_RoundsPriceToTickSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		side     generated.CreateOrderRequestSide
+		price    float64
+		expected float64
+	}{
+		{name: "buy rounds down", side: generated.CreateOrderRequestSideBUY, price: 480000.9, expected: 480000},
+		{name: "sell rounds up", side: generated.CreateOrderRequestSideSELL, price: 480000.1, expected: 480001},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sentReq *model.OrderRequest
+			mockClient := &client.MockBitFlyerClient{
+				SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+					sentReq = req
+					return &model.OrderResponse{OrderID: "JRF-1"}, nil
+				},
+			}
+
+			service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+
+			order, err := service.CreateOrder(context.Background(), nil, &generated.CreateOrderRequest{
+				Pair:      "ETH/JPY",
+				Side:      tt.side,
+				OrderType: generated.CreateOrderRequestOrderTypeLimit,
+				Price:     tt.price,
+				Amount:    0.01,
+			})
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if sentReq.Price != tt.expected || order.Price != tt.expected {
+				t.Errorf("expected price %v, sent %v and returned %v", tt.expected, sentReq.Price, order.Price)
+			}
+		})
+	}
+}
+
+func TestOrderService_CreateOrder_RoutesToExchangeOfPair(t *testing.T) {
+	bitflyer := &client.MockBitFlyerClient{
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			t.Errorf("expected ETH/JPY not to be sent to bitflyer")
+			return nil, errors.New("unexpected order")
+		},
+	}
+	gmocoin := &client.MockBitFlyerClient{
+		NameFunc: func() string { return "gmocoin" },
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(100000.0), nil
+		},
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			return &model.OrderResponse{OrderID: "637000"}, nil
+		},
+	}
+	router, err := client.NewExchangeRouter(
+		map[string]client.CryptoExchangeClient{"bitflyer": bitflyer, "gmocoin": gmocoin},
+		map[string]string{"ETH_JPY": "gmocoin"},
+		"bitflyer",
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var saved *model.BuyOrder
+	mockRepo := &MockOrderRepository{
+		SaveOrderFunc: func(ctx context.Context, order *model.BuyOrder) error {
+			saved = order
+			return nil
+		},
+	}
+
+	service := NewOrderService(router, mockRepo, testProductRegistry())
+
+	_, err = service.CreateOrder(context.Background(), nil, &generated.CreateOrderRequest{
+		Pair:      "ETH/JPY",
+		Side:      generated.CreateOrderRequestSideBUY,
+		OrderType: generated.CreateOrderRequestOrderTypeLimit,
+		Price:     500000,
+		Amount:    0.1,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if saved == nil || saved.OrderID != "637000" || saved.Exchange != "gmocoin" {
+		t.Errorf("expected order to be recorded on gmocoin, got %+v", saved)
+	}
+}
+
+func TestOrderService_CreateOrder_SellInsufficientCrypto(t *testing.T) {
+	sent := false
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return []model.Balance{
+				{Currency: "JPY", Amount: 10000000, Available: 10000000},
+				{Currency: "BTC", Amount: 0.01, Available: 0.0005},
+			}, nil
+		},
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			sent = true
+			return &model.OrderResponse{OrderID: "JRF-1"}, nil
+		},
+	}
+
+	service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+
+	_, err := service.CreateOrder(context.Background(), nil, &generated.CreateOrderRequest{
+		Pair:      "BTC/JPY",
+		Side:      generated.CreateOrderRequestSideSELL,
+		OrderType: generated.CreateOrderRequestOrderTypeLimit,
+		Price:     15000000,
+		Amount:    0.001,
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "insufficient balance") || !strings.Contains(err.Error(), "BTC") {
+		t.Errorf("expected insufficient BTC balance error, got %v", err)
+	}
+	if sent {
+		t.Error("expected no order to be sent")
+	}
+}
+
+func TestOrderService_CreateOrder_MarketBuy(t *testing.T) {
+	var sentReq *model.OrderRequest
+	var savedBuy *model.BuyOrder
+	mockClient := &client.MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			return &model.Ticker{Symbol: symbol, Bid: 14990000, Ask: 15000000, Last: 14995000}, nil
+		},
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(20000.0), nil
+		},
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			sentReq = req
+			return &model.OrderResponse{OrderID: "JRF-1"}, nil
+		},
+	}
+	mockRepo := &MockOrderRepository{
+		SaveOrderFunc: func(ctx context.Context, order *model.BuyOrder) error {
+			savedBuy = order
+			return nil
+		},
+	}
+
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
+
+	order, err := service.CreateOrder(context.Background(), nil, &generated.CreateOrderRequest{
+		Pair:      "BTC/JPY",
+		OrderType: generated.CreateOrderRequestOrderTypeMarket,
+		Amount:    0.001,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sentReq.Type != model.OrderTypeMarket || sentReq.Side != "BUY" || sentReq.Price != 0 {
+		t.Errorf("unexpected exchange request: %+v", sentReq)
+	}
+	// Buys are estimated from the best ask
+	if order.Price != 15000000 || order.EstimatedTotal != 15000 || order.OrderType != generated.OrderOrderTypeMarket {
+		t.Errorf("unexpected order: %+v", order)
+	}
+	if savedBuy == nil || savedBuy.Price != 15000000 {
+		t.Errorf("unexpected buy_orders row: %+v", savedBuy)
+	}
+}
+
+func TestOrderService_CreateOrder_MarketInsufficientBalance(t *testing.T) {
+	mockClient := &client.MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			return &model.Ticker{Symbol: symbol, Ask: 15000000}, nil
+		},
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(10000.0), nil
+		},
+	}
+
+	service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+
+	_, err := service.CreateOrder(context.Background(), nil, &generated.CreateOrderRequest{
+		Pair:      "BTC/JPY",
+		OrderType: generated.CreateOrderRequestOrderTypeMarket,
+		Amount:    0.001,
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "insufficient balance") {
+		t.Errorf("expected insufficient balance error, got %v", err)
+	}
+}
+
+func TestOrderService_CreateOrder_MarketStaleTicker(t *testing.T) {
+	sent := false
+	mockClient := &client.MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			return &model.Ticker{Symbol: symbol, Ask: 15000000, Stale: true}, nil
+		},
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			sent = true
+			return &model.OrderResponse{OrderID: "JRF20240101-000000-000001"}, nil
+		},
+	}
+
+	service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+
+	_, err := service.CreateOrder(context.Background(), nil, &generated.CreateOrderRequest{
+		Pair:      "BTC/JPY",
+		OrderType: generated.CreateOrderRequestOrderTypeMarket,
+		Amount:    0.001,
+	})
+
+	// A market order is not priced from a cached ticker while the exchange is unreachable
+	if !errors.Is(err, client.ErrExchangeUnavailable) {
+		t.Errorf("expected ErrExchangeUnavailable, got %v", err)
+	}
+	if sent {
+		t.Error("expected the order not to be sent")
+	}
+}
+
 func TestOrderService_CreateOrder_InvalidPrice(t *testing.T) {
 	mockClient := &client.MockBitFlyerClient{}
 	mockRepo := &MockOrderRepository{}
-	service := NewOrderService(mockClient, mockRepo)
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
 
 	req := &generated.CreateOrderRequest{
-		Pair:      generated.CreateOrderRequestPairBTCJPY,
+		Pair:      "BTC/JPY",
 		OrderType: generated.CreateOrderRequestOrderTypeLimit,
 		Price:     0, // Invalid price
 		Amount:    0.001,
 	}
 
-	order, err := service.CreateOrder(req)
+	order, err := service.CreateOrder(context.Background(), nil, req)
 
 	if err == nil {
 		t.Error("expected error for invalid price, got nil")
@@ -129,16 +482,16 @@ func TestOrderService_CreateOrder_InvalidPrice(t *testing.T) {
 func TestOrderService_CreateOrder_InvalidAmount(t *testing.T) {
 	mockClient := &client.MockBitFlyerClient{}
 	mockRepo := &MockOrderRepository{}
-	service := NewOrderService(mockClient, mockRepo)
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
 
 	req := &generated.CreateOrderRequest{
-		Pair:      generated.CreateOrderRequestPairBTCJPY,
+		Pair:      "BTC/JPY",
 		OrderType: generated.CreateOrderRequestOrderTypeLimit,
 		Price:     14000000,
 		Amount:    0, // Invalid amount
 	}
 
-	order, err := service.CreateOrder(req)
+	order, err := service.CreateOrder(context.Background(), nil, req)
 
 	if err == nil {
 		t.Error("expected error for invalid amount, got nil")
@@ -151,16 +504,16 @@ func TestOrderService_CreateOrder_InvalidAmount(t *testing.T) {
 func TestOrderService_CreateOrder_AmountBelowMinimum(t *testing.T) {
 	mockClient := &client.MockBitFlyerClient{}
 	mockRepo := &MockOrderRepository{}
-	service := NewOrderService(mockClient, mockRepo)
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
 
 	req := &generated.CreateOrderRequest{
-		Pair:      generated.CreateOrderRequestPairBTCJPY,
+		Pair:      "BTC/JPY",
 		OrderType: generated.CreateOrderRequestOrderTypeLimit,
 		Price:     14000000,
 		Amount:    0.0001, // Below minimum 0.001
 	}
 
-	order, err := service.CreateOrder(req)
+	order, err := service.CreateOrder(context.Background(), nil, req)
 
 	if err == nil {
 		t.Error("expected error for amount below minimum, got nil")
@@ -172,25 +525,25 @@ func TestOrderService_CreateOrder_AmountBelowMinimum(t *testing.T) {
 
 func TestOrderService_CreateOrder_BitFlyerAPIError(t *testing.T) {
 	mockClient := &client.MockBitFlyerClient{
-		GetBalanceFunc: func() (float64, error) {
-			return 2000000.0, nil
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(2000000.0), nil
 		},
-		SendOrderFunc: func(req *model.BitFlyerOrderRequest) (*model.BitFlyerOrderResponse, error) {
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
 			return nil, errors.New("bitFlyer API error")
 		},
 	}
 
 	mockRepo := &MockOrderRepository{}
-	service := NewOrderService(mockClient, mockRepo)
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
 
 	req := &generated.CreateOrderRequest{
-		Pair:      generated.CreateOrderRequestPairBTCJPY,
+		Pair:      "BTC/JPY",
 		OrderType: generated.CreateOrderRequestOrderTypeLimit,
 		Price:     14000000,
 		Amount:    0.001,
 	}
 
-	order, err := service.CreateOrder(req)
+	order, err := service.CreateOrder(context.Background(), nil, req)
 
 	if err == nil {
 		t.Error("expected error from bitFlyer API, got nil")
@@ -202,22 +555,22 @@ func TestOrderService_CreateOrder_BitFlyerAPIError(t *testing.T) {
 
 func TestOrderService_CreateOrder_BalanceFetchError(t *testing.T) {
 	mockClient := &client.MockBitFlyerClient{
-		GetBalanceFunc: func() (float64, error) {
-			return 0, errors.New("balance fetch error")
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return nil, errors.New("balance fetch error")
 		},
 	}
 
 	mockRepo := &MockOrderRepository{}
-	service := NewOrderService(mockClient, mockRepo)
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
 
 	req := &generated.CreateOrderRequest{
-		Pair:      generated.CreateOrderRequestPairBTCJPY,
+		Pair:      "BTC/JPY",
 		OrderType: generated.CreateOrderRequestOrderTypeLimit,
 		Price:     14000000,
 		Amount:    0.001,
 	}
 
-	order, err := service.CreateOrder(req)
+	order, err := service.CreateOrder(context.Background(), nil, req)
 
 	if err == nil {
 		t.Error("expected error from balance fetch, got nil")
@@ -230,15 +583,18 @@ func TestOrderService_CreateOrder_BalanceFetchError(t *testing.T) {
 func TestOrderService_GetBalance_Success(t *testing.T) {
 	expectedBalance := 1540200.0
 	mockClient := &client.MockBitFlyerClient{
-		GetBalanceFunc: func() (float64, error) {
-			return expectedBalance, nil
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return []model.Balance{
+				{Currency: "JPY", Amount: 1600000.0, Available: expectedBalance},
+				{Currency: "BTC", Amount: 0.02, Available: 0.015},
+			}, nil
 		},
 	}
 
 	mockRepo := &MockOrderRepository{}
-	service := NewOrderService(mockClient, mockRepo)
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
 
-	balance, err := service.GetBalance()
+	balance, err := service.GetBalance(context.Background())
 
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -252,19 +608,105 @@ func TestOrderService_GetBalance_Success(t *testing.T) {
 	if balance.Currency != generated.JPY {
 		t.Errorf("expected currency JPY, got %s", balance.Currency)
 	}
+	if balance.Holdings == nil || len(*balance.Holdings) != 1 {
+		t.Fatalf("expected 1 crypto holding, got %v", balance.Holdings)
+	}
+	if (*balance.Holdings)[0].Currency != "BTC" || (*balance.Holdings)[0].Amount != 0.02 {
+		t.Errorf("unexpected holding: %+v", (*balance.Holdings)[0])
+	}
+	if balance.Timestamp == 0 {
+		t.Error("expected non-zero timestamp")
+	}
+}
+
+func TestOrderService_GetBalance_SeveralExchanges(t *testing.T) {
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return []model.Balance{
+				{Currency: "JPY", Exchange: "bitflyer", Amount: 100000, Available: 80000},
+				{Currency: "JPY", Exchange: "gmocoin", Amount: 50000, Available: 50000},
+				{Currency: "ETH", Exchange: "gmocoin", Amount: 0.5, Available: 0.3},
+			}, nil
+		},
+	}
+	service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+
+	balance, err := service.GetBalance(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if balance.AvailableBalance != 130000 {
+		t.Errorf("expected the JPY of both exchanges, got %f", balance.AvailableBalance)
+	}
+	if balance.Holdings == nil || len(*balance.Holdings) != 1 {
+		t.Fatalf("expected 1 crypto holding, got %v", balance.Holdings)
+	}
+	if holding := (*balance.Holdings)[0]; holding.Currency != "ETH" || holding.Exchange != "gmocoin" {
+		t.Errorf("unexpected holding: %+v", holding)
+	}
+
+	balances, err := service.GetBalances(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(balances.Balances) != 3 || balances.Balances[1].Exchange != "gmocoin" || balances.Balances[1].Currency != "JPY" {
+		t.Errorf("unexpected balances: %+v", balances.Balances)
+	}
+}
+
+func TestOrderService_GetBalance_JPYNotFound(t *testing.T) {
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return []model.Balance{
+				{Currency: "BTC", Amount: 0.02, Available: 0.015},
+			}, nil
+		},
+	}
+
+	service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+
+	balance, err := service.GetBalance(context.Background())
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+	if balance != nil {
+		t.Errorf("expected nil balance, got %v", balance)
+	}
+}
+
+func TestOrderService_GetBalances_Success(t *testing.T) {
+	mockClient := &client.MockBitFlyerClient{}
+	service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+
+	balances, err := service.GetBalances(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(balances.Balances) != 3 {
+		t.Fatalf("expected 3 balances, got %d", len(balances.Balances))
+	}
+	currencies := []string{}
+	for _, b := range balances.Balances {
+		currencies = append(currencies, b.Currency)
+	}
+	if currencies[0] != "JPY" || currencies[1] != "BTC" || currencies[2] != "ETH" {
+		t.Errorf("unexpected currencies: %v", currencies)
+	}
 }
 
 func TestOrderService_GetBalance_Error(t *testing.T) {
 	mockClient := &client.MockBitFlyerClient{
-		GetBalanceFunc: func() (float64, error) {
-			return 0, errors.New("balance fetch error")
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return nil, errors.New("balance fetch error")
 		},
 	}
 
 	mockRepo := &MockOrderRepository{}
-	service := NewOrderService(mockClient, mockRepo)
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
 
-	balance, err := service.GetBalance()
+	balance, err := service.GetBalance(context.Background())
 
 	if err == nil {
 		t.Error("expected error, got nil")
@@ -275,7 +717,7 @@ func TestOrderService_GetBalance_Error(t *testing.T) {
 }
 
 func TestOrderService_ValidateOrderRequest(t *testing.T) {
-	service := &OrderServiceImpl{}
+	service := &OrderServiceImpl{products: testProductRegistry()}
 
 	tests := []struct {
 		name    string
@@ -285,7 +727,7 @@ func TestOrderService_ValidateOrderRequest(t *testing.T) {
 		{
 			name: "valid BTC order",
 			req: &generated.CreateOrderRequest{
-				Pair:      generated.CreateOrderRequestPairBTCJPY,
+				Pair:      "BTC/JPY",
 				OrderType: generated.CreateOrderRequestOrderTypeLimit,
 				Price:     14000000,
 				Amount:    0.001,
@@ -295,7 +737,7 @@ func TestOrderService_ValidateOrderRequest(t *testing.T) {
 		{
 			name: "valid ETH order",
 			req: &generated.CreateOrderRequest{
-				Pair:      generated.CreateOrderRequestPairETHJPY,
+				Pair:      "ETH/JPY",
 				OrderType: generated.CreateOrderRequestOrderTypeLimit,
 				Price:     480000,
 				Amount:    0.01,
@@ -305,7 +747,7 @@ func TestOrderService_ValidateOrderRequest(t *testing.T) {
 		{
 			name: "zero price",
 			req: &generated.CreateOrderRequest{
-				Pair:      generated.CreateOrderRequestPairBTCJPY,
+				Pair:      "BTC/JPY",
 				OrderType: generated.CreateOrderRequestOrderTypeLimit,
 				Price:     0,
 				Amount:    0.001,
@@ -315,7 +757,7 @@ func TestOrderService_ValidateOrderRequest(t *testing.T) {
 		{
 			name: "negative price",
 			req: &generated.CreateOrderRequest{
-				Pair:      generated.CreateOrderRequestPairBTCJPY,
+				Pair:      "BTC/JPY",
 				OrderType: generated.CreateOrderRequestOrderTypeLimit,
 				Price:     -100,
 				Amount:    0.001,
@@ -325,7 +767,7 @@ func TestOrderService_ValidateOrderRequest(t *testing.T) {
 		{
 			name: "zero amount",
 			req: &generated.CreateOrderRequest{
-				Pair:      generated.CreateOrderRequestPairBTCJPY,
+				Pair:      "BTC/JPY",
 				OrderType: generated.CreateOrderRequestOrderTypeLimit,
 				Price:     14000000,
 				Amount:    0,
@@ -335,7 +777,7 @@ func TestOrderService_ValidateOrderRequest(t *testing.T) {
 		{
 			name: "BTC amount below minimum",
 			req: &generated.CreateOrderRequest{
-				Pair:      generated.CreateOrderRequestPairBTCJPY,
+				Pair:      "BTC/JPY",
 				OrderType: generated.CreateOrderRequestOrderTypeLimit,
 				Price:     14000000,
 				Amount:    0.0001,
@@ -345,7 +787,7 @@ func TestOrderService_ValidateOrderRequest(t *testing.T) {
 		{
 			name: "ETH amount below minimum",
 			req: &generated.CreateOrderRequest{
-				Pair:      generated.CreateOrderRequestPairETHJPY,
+				Pair:      "ETH/JPY",
 				OrderType: generated.CreateOrderRequestOrderTypeLimit,
 				Price:     480000,
 				Amount:    0.001,
@@ -363,3 +805,343 @@ func TestOrderService_ValidateOrderRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderService_CancelOrder_Success(t *testing.T) {
+	var cancelledProduct, cancelledID, transition string
+	mockClient := &client.MockBitFlyerClient{
+		CancelOrderFunc: func(ctx context.Context, productCode, orderID string) error {
+			cancelledProduct = productCode
+			cancelledID = orderID
+			return nil
+		},
+	}
+	mockRepo := &MockOrderRepository{
+		FindOrderFunc: func(ctx context.Context, orderID string) (*model.OrderRecord, error) {
+			return &model.OrderRecord{OrderID: orderID, ProductCode: "ETH_JPY", Side: "BUY", Status: "UNFILLED"}, nil
+		},
+		TransitionStatusFunc: func(ctx context.Context, side, orderID, fromStatus, toStatus string) (bool, error) {
+			transition = side + " " + fromStatus + "->" + toStatus
+			return true, nil
+		},
+	}
+
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
+
+	result, err := service.CancelOrder(context.Background(), "JRF-1")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cancelledProduct != "ETH_JPY" || cancelledID != "JRF-1" {
+		t.Errorf("expected cancel for ETH_JPY/JRF-1, got %s/%s", cancelledProduct, cancelledID)
+	}
+	if transition != "BUY UNFILLED->CANCELLED" {
+		t.Errorf("expected status CANCELLED to be stored, got %q", transition)
+	}
+	if result.OrderId != "JRF-1" || result.Pair != "ETH/JPY" || result.Status != "cancelled" {
+		t.Errorf("unexpected response: %+v", result)
+	}
+}
+
+func TestOrderService_CancelOrder_SellOrder(t *testing.T) {
+	var transition string
+	mockRepo := &MockOrderRepository{
+		FindOrderFunc: func(ctx context.Context, orderID string) (*model.OrderRecord, error) {
+			parentID := "JRF-BUY-1"
+			return &model.OrderRecord{OrderID: orderID, ParentID: &parentID, ProductCode: "BTC_JPY", Side: "SELL", Status: "PARTIALLY_FILLED"}, nil
+		},
+		TransitionStatusFunc: func(ctx context.Context, side, orderID, fromStatus, toStatus string) (bool, error) {
+			transition = side + " " + fromStatus + "->" + toStatus
+			return true, nil
+		},
+	}
+
+	service := NewOrderService(&client.MockBitFlyerClient{}, mockRepo, testProductRegistry())
+
+	if _, err := service.CancelOrder(context.Background(), "JRF-SELL-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if transition != "SELL PARTIALLY_FILLED->CANCELLED" {
+		t.Errorf("expected the sell order to be cancelled, got %q", transition)
+	}
+}
+
+func TestOrderService_CancelOrder_FilledWhileCancelling(t *testing.T) {
+	status := "UNFILLED"
+	mockRepo := &MockOrderRepository{
+		FindOrderFunc: func(ctx context.Context, orderID string) (*model.OrderRecord, error) {
+			return &model.OrderRecord{OrderID: orderID, ProductCode: "BTC_JPY", Side: "BUY", Status: status}, nil
+		},
+		TransitionStatusFunc: func(ctx context.Context, side, orderID, fromStatus, toStatus string) (bool, error) {
+			if fromStatus != status {
+				return false, nil
+			}
+			status = toStatus
+			return true, nil
+		},
+	}
+	mockClient := &client.MockBitFlyerClient{
+		CancelOrderFunc: func(ctx context.Context, productCode, orderID string) error {
+			// The synchronizer records the fill before the cancellation is recorded
+			status = "FILLED"
+			return nil
+		},
+	}
+
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
+
+	_, err := service.CancelOrder(context.Background(), "JRF-1")
+
+	if !errors.Is(err, ErrOrderNotCancellable) {
+		t.Errorf("expected ErrOrderNotCancellable, got %v", err)
+	}
+	if status != "FILLED" {
+		t.Errorf("expected the fill to be kept, got %s", status)
+	}
+}
+
+func TestOrderService_CancelOrder_NotCancellable(t *testing.T) {
+	cancelCalled := false
+	mockClient := &client.MockBitFlyerClient{
+		CancelOrderFunc: func(ctx context.Context, productCode, orderID string) error {
+			cancelCalled = true
+			return nil
+		},
+	}
+	mockRepo := &MockOrderRepository{
+		FindOrderFunc: func(ctx context.Context, orderID string) (*model.OrderRecord, error) {
+			return &model.OrderRecord{OrderID: orderID, ProductCode: "BTC_JPY", Side: "BUY", Status: "FILLED"}, nil
+		},
+	}
+
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
+
+	result, err := service.CancelOrder(context.Background(), "JRF-1")
+
+	if err == nil {
+		t.Error("expected error for filled order, got nil")
+	}
+	if result != nil {
+		t.Errorf("expected nil result, got %v", result)
+	}
+	if cancelCalled {
+		t.Error("expected exchange not to be called for a filled order")
+	}
+}
+
+func TestOrderService_CancelOrder_NotFound(t *testing.T) {
+	mockRepo := &MockOrderRepository{
+		FindOrderFunc: func(ctx context.Context, orderID string) (*model.OrderRecord, error) {
+			return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
+		},
+	}
+
+	service := NewOrderService(&client.MockBitFlyerClient{}, mockRepo, testProductRegistry())
+
+	_, err := service.CancelOrder(context.Background(), "JRF-404")
+
+	if !errors.Is(err, ErrOrderNotFound) || err.Error() != "order not found: JRF-404" {
+		t.Errorf("expected order not found error, got %v", err)
+	}
+}
+
+func TestOrderService_CancelOrder_ExchangeError(t *testing.T) {
+	statusUpdated := false
+	mockClient := &client.MockBitFlyerClient{
+		CancelOrderFunc: func(ctx context.Context, productCode, orderID string) error {
+			return errors.New("bitFlyer API error")
+		},
+	}
+	mockRepo := &MockOrderRepository{
+		FindOrderFunc: func(ctx context.Context, orderID string) (*model.OrderRecord, error) {
+			return &model.OrderRecord{OrderID: orderID, ProductCode: "BTC_JPY", Side: "BUY", Status: "UNFILLED"}, nil
+		},
+		TransitionStatusFunc: func(ctx context.Context, side, orderID, fromStatus, toStatus string) (bool, error) {
+			statusUpdated = true
+			return true, nil
+		},
+	}
+
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
+
+	_, err := service.CancelOrder(context.Background(), "JRF-1")
+
+	if err == nil {
+		t.Error("expected error from exchange, got nil")
+	}
+	if statusUpdated {
+		t.Error("expected status not to be updated when exchange cancel fails")
+	}
+}
+
+func TestOrderService_ListOrders_MergesLiveState(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	parentID := "JRF-BUY-1"
+
+	var receivedFilter *model.OrderFilter
+	mockRepo := &MockOrderRepository{
+		ListOrdersFunc: func(ctx context.Context, filter *model.OrderFilter) ([]model.OrderRecord, error) {
+			receivedFilter = filter
+			return []model.OrderRecord{
+				{OrderID: "JRF-SELL-1", ParentID: &parentID, ProductCode: "BTC_JPY", Side: "SELL", Price: 15000000, Size: 0.002, Exchange: "bitflyer", Status: "UNFILLED", Timestamp: created, Updatetime: created},
+				{OrderID: "JRF-BUY-1", ProductCode: "BTC_JPY", Side: "BUY", Price: 14000000, Size: 0.002, Exchange: "bitflyer", Status: "FILLED(SELL ORDER PLACED)", Timestamp: created.Add(-time.Hour), Updatetime: created},
+			}, nil
+		},
+	}
+
+	lookups := 0
+	mockClient := &client.MockBitFlyerClient{
+		GetChildOrdersFunc: func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+			lookups++
+			if query.ProductCode != "BTC_JPY" {
+				t.Errorf("expected lookup for BTC_JPY, got %s", query.ProductCode)
+			}
+			return []model.ChildOrder{
+				{OrderID: "JRF-SELL-1", State: "ACTIVE", OutstandingSize: 0.0015, ExecutedSize: 0.0005, AveragePrice: 15000000},
+			}, nil
+		},
+	}
+
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
+
+	side := generated.ListOrdersParamsSideSELL
+	status := generated.ListOrdersParamsStatusFILLED
+	pair := "BTC/JPY"
+	result, err := service.ListOrders(context.Background(), &generated.ListOrdersParams{Pair: &pair, Side: &side, Status: &status})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if receivedFilter.ProductCode != "BTC_JPY" || receivedFilter.Side != "SELL" || len(receivedFilter.Statuses) != 3 {
+		t.Errorf("unexpected filter: %+v", receivedFilter)
+	}
+	if receivedFilter.Limit != defaultOrderListLimit+1 {
+		t.Errorf("expected repository limit %d, got %d", defaultOrderListLimit+1, receivedFilter.Limit)
+	}
+	if lookups != 1 {
+		t.Errorf("expected a single exchange lookup per product, got %d", lookups)
+	}
+	if len(result.Orders) != 2 || result.HasNext || result.NextCursor != nil {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	sell := result.Orders[0]
+	if sell.Status != generated.OrderDetailStatusPARTIALLYFILLED {
+		t.Errorf("expected PARTIALLY_FILLED, got %s", sell.Status)
+	}
+	if sell.Pair != "BTC/JPY" || sell.ParentOrderId == nil || *sell.ParentOrderId != parentID {
+		t.Errorf("unexpected sell order: %+v", sell)
+	}
+	if sell.ExecutedSize == nil || *sell.ExecutedSize != 0.0005 || sell.OutstandingSize == nil || *sell.OutstandingSize != 0.0015 {
+		t.Errorf("expected live sizes to be merged, got %+v", sell)
+	}
+
+	buy := result.Orders[1]
+	if buy.Status != generated.OrderDetailStatusFILLED {
+		t.Errorf("expected FILLED, got %s", buy.Status)
+	}
+	if buy.ExecutedSize != nil || buy.ExchangeState != nil {
+		t.Errorf("expected no live state for order missing on exchange, got %+v", buy)
+	}
+}
+
+func TestOrderService_ListOrders_Pagination(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var filters []*model.OrderFilter
+	mockRepo := &MockOrderRepository{
+		ListOrdersFunc: func(ctx context.Context, filter *model.OrderFilter) ([]model.OrderRecord, error) {
+			filters = append(filters, filter)
+			return []model.OrderRecord{
+				{OrderID: "JRF-3", ProductCode: "BTC_JPY", Side: "BUY", Status: "UNFILLED", Timestamp: created},
+				{OrderID: "JRF-2", ProductCode: "BTC_JPY", Side: "BUY", Status: "UNFILLED", Timestamp: created.Add(-time.Minute)},
+				{OrderID: "JRF-1", ProductCode: "BTC_JPY", Side: "BUY", Status: "UNFILLED", Timestamp: created.Add(-2 * time.Minute)},
+			}, nil
+		},
+	}
+
+	service := NewOrderService(&client.MockBitFlyerClient{}, mockRepo, testProductRegistry())
+
+	limit := 2
+	result, err := service.ListOrders(context.Background(), &generated.ListOrdersParams{Limit: &limit})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Orders) != 2 || !result.HasNext || result.NextCursor == nil {
+		t.Fatalf("expected a full page with a next cursor, got %+v", result)
+	}
+
+	// The cursor must resume after the last order of the page
+	_, err = service.ListOrders(context.Background(), &generated.ListOrdersParams{Limit: &limit, Cursor: result.NextCursor})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	cursor := filters[1].Cursor
+	if cursor == nil || cursor.OrderID != "JRF-2" || !cursor.Timestamp.Equal(created.Add(-time.Minute)) {
+		t.Errorf("unexpected decoded cursor: %+v", cursor)
+	}
+}
+
+func TestOrderService_ListOrders_InvalidParams(t *testing.T) {
+	service := NewOrderService(&client.MockBitFlyerClient{}, &MockOrderRepository{}, testProductRegistry())
+
+	badCursor := "not-a-cursor!"
+	zero := 0
+	from := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+	side := generated.ListOrdersParamsSide("HOLD")
+
+	tests := []struct {
+		name   string
+		params *generated.ListOrdersParams
+	}{
+		{name: "malformed cursor", params: &generated.ListOrdersParams{Cursor: &badCursor}},
+		{name: "limit out of range", params: &generated.ListOrdersParams{Limit: &zero}},
+		{name: "from after to", params: &generated.ListOrdersParams{From: &from, To: &to}},
+		{name: "unknown side", params: &generated.ListOrdersParams{Side: &side}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ListOrders(context.Background(), tt.params)
+			if err == nil {
+				t.Error("expected error, got nil")
+			}
+			if result != nil {
+				t.Errorf("expected nil result, got %v", result)
+			}
+		})
+	}
+}
+
+func TestOrderService_GetOrder_ExchangeUnavailable(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockRepo := &MockOrderRepository{
+		FindOrderFunc: func(ctx context.Context, orderID string) (*model.OrderRecord, error) {
+			return &model.OrderRecord{OrderID: orderID, ProductCode: "ETH_JPY", Side: "BUY", Price: 300000, Size: 0.01, Exchange: "bitflyer", Status: "UNFILLED", Timestamp: created, Updatetime: created}, nil
+		},
+	}
+	mockClient := &client.MockBitFlyerClient{
+		GetChildOrdersFunc: func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+			if query.OrderID != "JRF-1" {
+				t.Errorf("expected lookup by acceptance ID JRF-1, got %q", query.OrderID)
+			}
+			return nil, errors.New("bitFlyer API error")
+		},
+	}
+
+	service := NewOrderService(mockClient, mockRepo, testProductRegistry())
+
+	result, err := service.GetOrder(context.Background(), "JRF-1")
+
+	if err != nil {
+		t.Fatalf("expected no error when live state is unavailable, got %v", err)
+	}
+	if result.OrderId != "JRF-1" || result.Pair != "ETH/JPY" || result.Status != generated.OrderDetailStatusUNFILLED {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result.ExchangeState != nil {
+		t.Errorf("expected no live state, got %v", *result.ExchangeState)
+	}
+}
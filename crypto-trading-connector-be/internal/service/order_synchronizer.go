@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+)
+
+// OrderSynchronizer keeps the status of recorded orders in sync with the exchange.
+// Orders are only ever moved forward with a compare-and-set update, so it is safe
+// to run while orders are being placed or cancelled through the API.
+type OrderSynchronizer struct {
+	exchangeClient client.CryptoExchangeClient
+	orderRepo      repository.OrderRepository
+	mu             sync.Mutex
+}
+
+// SyncResult summarizes a single synchronization pass
+type SyncResult struct {
+	Checked int
+	Updated int
+	Failed  int
+}
+
+// NewOrderSynchronizer creates a new order synchronizer
+func NewOrderSynchronizer(exchangeClient client.CryptoExchangeClient, orderRepo repository.OrderRepository) *OrderSynchronizer {
+	return &OrderSynchronizer{
+		exchangeClient: exchangeClient,
+		orderRepo:      orderRepo,
+	}
+}
+
+// Run synchronizes orders every interval until ctx is cancelled
+func (s *OrderSynchronizer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.SyncOnce(ctx)
+		if err != nil {
+			log.Printf("Warning: order sync failed: %v", err)
+		} else if result.Updated > 0 || result.Failed > 0 {
+			log.Printf("Order sync: checked %d, updated %d, failed %d", result.Checked, result.Updated, result.Failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncOnce fetches the live state of every open order and records status changes
+func (s *OrderSynchronizer) SyncOnce(ctx context.Context) (*SyncResult, error) {
+	// Overlapping passes would only duplicate exchange calls
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orders, err := s.orderRepo.ListOpenOrders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open orders: %w", err)
+	}
+
+	result := &SyncResult{}
+	liveOrders := s.fetchRecentOrders(ctx, orders)
+
+	for _, order := range orders {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		result.Checked++
+
+		live, ok := liveOrders[order.OrderID]
+		if !ok {
+			// Older orders drop out of the recent list, so look them up individually
+			found, err := s.fetchOrder(ctx, order)
+			if err != nil {
+				log.Printf("Warning: failed to get order %s from exchange: %v", order.OrderID, err)
+				result.Failed++
+				continue
+			}
+			if found == nil {
+				// Not visible on the exchange yet
+				continue
+			}
+			live = *found
+		}
+
+		newStatus := resolveOrderStatus(order.Status, live)
+		if newStatus == order.Status {
+			continue
+		}
+
+		updated, err := s.orderRepo.TransitionOrderStatus(ctx, order.Side, order.OrderID, order.Status, newStatus)
+		if err != nil {
+			log.Printf("Warning: failed to update order %s to %s: %v", order.OrderID, newStatus, err)
+			result.Failed++
+			continue
+		}
+		if updated {
+			result.Updated++
+		}
+	}
+
+	return result, nil
+}
+
+// fetchRecentOrders retrieves recent exchange orders for every product with open orders,
+// keyed by order ID
+func (s *OrderSynchronizer) fetchRecentOrders(ctx context.Context, orders []model.OrderRecord) map[string]model.ChildOrder {
+	liveOrders := make(map[string]model.ChildOrder)
+
+	queried := make(map[string]bool)
+	for _, order := range orders {
+		if queried[order.ProductCode] {
+			continue
+		}
+		queried[order.ProductCode] = true
+
+		childOrders, err := s.exchangeClient.GetChildOrders(ctx, &model.ChildOrderQuery{
+			ProductCode: order.ProductCode,
+			Count:       liveOrderLookupCount,
+		})
+		if err != nil {
+			// Orders of this product fall back to individual lookups
+			log.Printf("Warning: failed to get recent %s orders from exchange: %v", order.ProductCode, err)
+			continue
+		}
+
+		for _, childOrder := range childOrders {
+			liveOrders[childOrder.OrderID] = childOrder
+		}
+	}
+
+	return liveOrders
+}
+
+// fetchOrder retrieves a single order from the exchange, returning nil if it is not found
+func (s *OrderSynchronizer) fetchOrder(ctx context.Context, order model.OrderRecord) (*model.ChildOrder, error) {
+	childOrders, err := s.exchangeClient.GetChildOrders(ctx, &model.ChildOrderQuery{
+		ProductCode: order.ProductCode,
+		OrderID:     order.OrderID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(childOrders) == 0 {
+		return nil, nil
+	}
+	return &childOrders[0], nil
+}
+
+// resolveOrderStatus maps the live exchange state of an order to the status stored in the database
+func resolveOrderStatus(current string, live model.ChildOrder) string {
+	switch live.State {
+	case model.OrderStateCompleted:
+		return model.OrderStatusFilled
+	case model.OrderStateCanceled, model.OrderStateRejected:
+		return model.OrderStatusCancelled
+	case model.OrderStateExpired:
+		return model.OrderStatusExpired
+	case model.OrderStateActive:
+		if live.ExecutedSize > 0 {
+			return model.OrderStatusPartiallyFilled
+		}
+	}
+	return current
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+func TestResolveOrderStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		live     model.ChildOrder
+		expected string
+	}{
+		{name: "completed", current: "UNFILLED", live: model.ChildOrder{State: "COMPLETED"}, expected: "FILLED"},
+		{name: "partially executed", current: "UNFILLED", live: model.ChildOrder{State: "ACTIVE", ExecutedSize: 0.001}, expected: "PARTIALLY_FILLED"},
+		{name: "still active", current: "UNFILLED", live: model.ChildOrder{State: "ACTIVE"}, expected: "UNFILLED"},
+		{name: "canceled", current: "PARTIALLY_FILLED", live: model.ChildOrder{State: "CANCELED"}, expected: "CANCELLED"},
+		{name: "rejected", current: "UNFILLED", live: model.ChildOrder{State: "REJECTED"}, expected: "CANCELLED"},
+		{name: "expired", current: "UNFILLED", live: model.ChildOrder{State: "EXPIRED"}, expected: "EXPIRED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveOrderStatus(tt.current, tt.live); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestOrderSynchronizer_SyncOnce(t *testing.T) {
+	mockRepo := &MockOrderRepository{
+		ListOpenOrdersFunc: func(ctx context.Context) ([]model.OrderRecord, error) {
+			return []model.OrderRecord{
+				{OrderID: "JRF-BUY-1", ProductCode: "BTC_JPY", Side: "BUY", Status: "UNFILLED"},
+				{OrderID: "JRF-SELL-1", ProductCode: "BTC_JPY", Side: "SELL", Status: "PARTIALLY_FILLED"},
+				{OrderID: "JRF-BUY-2", ProductCode: "BTC_JPY", Side: "BUY", Status: "UNFILLED"},
+				{OrderID: "JRF-OLD-1", ProductCode: "ETH_JPY", Side: "BUY", Status: "UNFILLED"},
+			}, nil
+		},
+	}
+
+	type transition struct{ side, orderID, from, to string }
+	var transitions []transition
+	mockRepo.TransitionStatusFunc = func(ctx context.Context, side, orderID, fromStatus, toStatus string) (bool, error) {
+		transitions = append(transitions, transition{side, orderID, fromStatus, toStatus})
+		return true, nil
+	}
+
+	var individualLookups []string
+	mockClient := &client.MockBitFlyerClient{
+		GetChildOrdersFunc: func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+			if query.OrderID != "" {
+				individualLookups = append(individualLookups, query.OrderID)
+				return []model.ChildOrder{{OrderID: query.OrderID, State: "EXPIRED"}}, nil
+			}
+			if query.ProductCode == "ETH_JPY" {
+				return []model.ChildOrder{}, nil
+			}
+			return []model.ChildOrder{
+				{OrderID: "JRF-BUY-1", State: "COMPLETED", ExecutedSize: 0.001},
+				{OrderID: "JRF-SELL-1", State: "CANCELED", ExecutedSize: 0.001},
+				{OrderID: "JRF-BUY-2", State: "ACTIVE"},
+			}, nil
+		},
+	}
+
+	synchronizer := NewOrderSynchronizer(mockClient, mockRepo)
+
+	result, err := synchronizer.SyncOnce(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Checked != 4 || result.Updated != 3 || result.Failed != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(individualLookups) != 1 || individualLookups[0] != "JRF-OLD-1" {
+		t.Errorf("expected an individual lookup only for JRF-OLD-1, got %v", individualLookups)
+	}
+
+	expected := []transition{
+		{"BUY", "JRF-BUY-1", "UNFILLED", "FILLED"},
+		{"SELL", "JRF-SELL-1", "PARTIALLY_FILLED", "CANCELLED"},
+		{"BUY", "JRF-OLD-1", "UNFILLED", "EXPIRED"},
+	}
+	if len(transitions) != len(expected) {
+		t.Fatalf("expected %d transitions, got %v", len(expected), transitions)
+	}
+	for i, tr := range expected {
+		if transitions[i] != tr {
+			t.Errorf("transition %d: expected %+v, got %+v", i, tr, transitions[i])
+		}
+	}
+}
+
+func TestOrderSynchronizer_SyncOnce_ConcurrentUpdate(t *testing.T) {
+	mockRepo := &MockOrderRepository{
+		ListOpenOrdersFunc: func(ctx context.Context) ([]model.OrderRecord, error) {
+			return []model.OrderRecord{{OrderID: "JRF-1", ProductCode: "BTC_JPY", Side: "BUY", Status: "UNFILLED"}}, nil
+		},
+		// The order was cancelled through the API after it was listed
+		TransitionStatusFunc: func(ctx context.Context, side, orderID, fromStatus, toStatus string) (bool, error) {
+			return false, nil
+		},
+	}
+	mockClient := &client.MockBitFlyerClient{
+		GetChildOrdersFunc: func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+			return []model.ChildOrder{{OrderID: "JRF-1", State: "COMPLETED"}}, nil
+		},
+	}
+
+	result, err := NewOrderSynchronizer(mockClient, mockRepo).SyncOnce(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Updated != 0 {
+		t.Errorf("expected no update to be counted, got %d", result.Updated)
+	}
+}
+
+func TestOrderSynchronizer_SyncOnce_ExchangeError(t *testing.T) {
+	transitioned := false
+	mockRepo := &MockOrderRepository{
+		ListOpenOrdersFunc: func(ctx context.Context) ([]model.OrderRecord, error) {
+			return []model.OrderRecord{{OrderID: "JRF-1", ProductCode: "BTC_JPY", Side: "BUY", Status: "UNFILLED"}}, nil
+		},
+		TransitionStatusFunc: func(ctx context.Context, side, orderID, fromStatus, toStatus string) (bool, error) {
+			transitioned = true
+			return true, nil
+		},
+	}
+	mockClient := &client.MockBitFlyerClient{
+		GetChildOrdersFunc: func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+			return nil, errors.New("bitFlyer API error")
+		},
+	}
+
+	result, err := NewOrderSynchronizer(mockClient, mockRepo).SyncOnce(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Failed != 1 {
+		t.Errorf("expected 1 failed order, got %d", result.Failed)
+	}
+	if transitioned {
+		t.Error("expected no status change when the exchange is unavailable")
+	}
+}
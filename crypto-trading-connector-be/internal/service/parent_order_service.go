@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+)
+
+// ParentOrderService defines the interface for special order (IFD / OCO / IFDOCO) business logic
+type ParentOrderService interface {
+	CreateParentOrder(ctx context.Context, req *generated.CreateParentOrderRequest) (*generated.ParentOrder, error)
+}
+
+// parentOrderLegCounts is the number of legs each special order method takes
+var parentOrderLegCounts = map[generated.CreateParentOrderRequestMethod]int{
+	generated.CreateParentOrderRequestMethodIFD:    2,
+	generated.CreateParentOrderRequestMethodOCO:    2,
+	generated.CreateParentOrderRequestMethodIFDOCO: 3,
+}
+
+// ParentOrderServiceImpl implements ParentOrderService
+type ParentOrderServiceImpl struct {
+	exchangeClient  client.CryptoExchangeClient
+	parentOrderRepo repository.ParentOrderRepository
+	products        *ProductRegistry
+	pipeline        *OrderPipeline
+	riskEngine      *RiskEngine
+}
+
+// NewParentOrderService creates a new parent order service
+func NewParentOrderService(exchangeClient client.CryptoExchangeClient, parentOrderRepo repository.ParentOrderRepository, products *ProductRegistry) *ParentOrderServiceImpl {
+	return &ParentOrderServiceImpl{
+		exchangeClient:  exchangeClient,
+		parentOrderRepo: parentOrderRepo,
+		products:        products,
+		pipeline:        NewOrderPipeline(nil),
+	}
+}
+
+// SetOrderPipeline makes CreateParentOrder reserve funds in pipeline, shared with the other services placing
+// orders on the same accounts. By default the service has a pipeline of its own, which only knows of the orders
+// it is sending.
+func (s *ParentOrderServiceImpl) SetOrderPipeline(pipeline *OrderPipeline) {
+	s.pipeline = pipeline
+}
+
+// SetRiskEngine makes CreateParentOrder check every leg against the risk engine before sending the order
+func (s *ParentOrderServiceImpl) SetRiskEngine(engine *RiskEngine) {
+	s.riskEngine = engine
+}
+
+// CreateParentOrder places an IFD, OCO or IFDOCO order and records it for leg tracking
+func (s *ParentOrderServiceImpl) CreateParentOrder(ctx context.Context, req *generated.CreateParentOrderRequest) (*generated.ParentOrder, error) {
+	// Validate input
+	if err := validateParentOrderRequest(req, s.products); err != nil {
+		return nil, err
+	}
+
+	// Every leg uses the same pair, so the whole order goes to the exchange of that pair
+	exchangeClient := client.ForSymbol(s.exchangeClient, model.Symbol(req.Legs[0].Pair))
+
+	product, _ := s.products.Get(model.Symbol(req.Legs[0].Pair))
+	legs := make([]model.ParentOrderLeg, 0, len(req.Legs))
+	for _, leg := range req.Legs {
+		legs = append(legs, toModelParentOrderLeg(leg, product))
+	}
+
+	// Every leg may be executed, so each is checked against the pre-trade risk limits
+	riskOrders := make([]*RiskOrder, len(legs))
+	for i, leg := range legs {
+		price, err := legPrice(ctx, exchangeClient, leg)
+		if err != nil {
+			return nil, err
+		}
+		riskOrders[i] = legRiskOrder(leg, price, exchangeClient)
+
+		if s.riskEngine != nil {
+			if err := s.riskEngine.Check(ctx, riskOrders[i]); err != nil {
+				return nil, fmt.Errorf("%w (leg %d)", err, i+1)
+			}
+		}
+	}
+
+	release, err := s.pipeline.Reserve(ctx, exchangeClient, PipelineRequest{
+		Orders:     riskOrders,
+		Funds:      fundedLegReservations(req.Method, riskOrders),
+		RiskEngine: s.riskEngine,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	exchangeReq := &model.ParentOrderRequest{
+		Method: string(req.Method),
+		Legs:   legs,
+	}
+	if req.MinuteToExpire != nil {
+		exchangeReq.MinuteToExpire = *req.MinuteToExpire
+	}
+	if req.TimeInForce != nil {
+		exchangeReq.TimeInForce = string(*req.TimeInForce)
+	}
+
+	exchangeResp, err := exchangeClient.SendParentOrder(ctx, exchangeReq)
+	if errors.Is(err, client.ErrNotSupported) {
+		return nil, fmt.Errorf("unsupported order method on %s: %w", exchangeClient.Name(), err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to send parent order to exchange: %w", err)
+	}
+
+	// Save parent order to database
+	if err := s.parentOrderRepo.SaveParentOrder(ctx, &model.ParentOrderRecord{
+		AcceptanceID: exchangeResp.AcceptanceID,
+		Method:       exchangeReq.Method,
+		ProductCode:  legs[0].ProductCode,
+		Legs:         legs,
+		Exchange:     exchangeClient.Name(),
+		Status:       model.ParentOrderStatusActive,
+	}); err != nil {
+		// Log error but don't fail - order was already sent to exchange
+		fmt.Printf("Warning: failed to save parent order to database: %v\n", err)
+	}
+
+	return &generated.ParentOrder{
+		ParentOrderId: exchangeResp.AcceptanceID,
+		Method:        generated.ParentOrderMethod(req.Method),
+		Pair:          req.Legs[0].Pair,
+		Legs:          req.Legs,
+		Status:        generated.ParentOrderStatusACTIVE,
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// fundedLegReservations returns the funds a special order needs. Only the entry leg of IFD / IFDOCO is executed
+// against the current balance, while either leg of an OCO may be executed, but not both: each currency is
+// reserved for the leg needing the most of it.
+func fundedLegReservations(method generated.CreateParentOrderRequestMethod, legs []*RiskOrder) []FundsReservation {
+	fundedLegs := 1
+	if method == generated.CreateParentOrderRequestMethodOCO {
+		fundedLegs = len(legs)
+	}
+
+	var reservations []FundsReservation
+	for _, leg := range legs[:fundedLegs] {
+		reservation := orderReservation(string(leg.Side), leg.Symbol.ProductCode(), leg.Size, leg.Notional())
+		merged := false
+		for i := range reservations {
+			if reservations[i].Currency == reservation.Currency {
+				reservations[i].Amount = max(reservations[i].Amount, reservation.Amount)
+				merged = true
+			}
+		}
+		if !merged {
+			reservations = append(reservations, reservation)
+		}
+	}
+	return reservations
+}
+
+// legPrice returns the price a leg is expected to execute at
+func legPrice(ctx context.Context, exchangeClient client.CryptoExchangeClient, leg model.ParentOrderLeg) (float64, error) {
+	switch leg.ConditionType {
+	case model.ConditionTypeLimit, model.ConditionTypeStopLimit:
+		return leg.Price, nil
+	case model.ConditionTypeStop:
+		return leg.TriggerPrice, nil
+	default:
+		return estimateMarketPrice(ctx, exchangeClient, model.SymbolFromProductCode(leg.ProductCode), model.OrderSide(leg.Side))
+	}
+}
+
+// legRiskOrder returns the order the risk engine checks for a leg. Only LIMIT and STOP_LIMIT legs rest on the
+// book at their price; the other legs execute at the market once triggered.
+func legRiskOrder(leg model.ParentOrderLeg, price float64, exchangeClient client.CryptoExchangeClient) *RiskOrder {
+	orderType := model.OrderTypeMarket
+	if leg.ConditionType == model.ConditionTypeLimit || leg.ConditionType == model.ConditionTypeStopLimit {
+		orderType = model.OrderTypeLimit
+	}
+	return &RiskOrder{
+		Symbol:   model.SymbolFromProductCode(leg.ProductCode),
+		Side:     model.OrderSide(leg.Side),
+		Type:     orderType,
+		Price:    price,
+		Size:     leg.Size,
+		Exchange: exchangeClient,
+	}
+}
+
+// toModelParentOrderLeg converts an API leg to the exchange-agnostic model,
+// rounding its prices to the tick size of the product (down for buys, up for sells)
+func toModelParentOrderLeg(leg generated.ParentOrderLeg, product model.Product) model.ParentOrderLeg {
+	side := model.OrderSide(leg.Side)
+	result := model.ParentOrderLeg{
+		ProductCode:   model.Symbol(leg.Pair).ProductCode(),
+		Side:          string(leg.Side),
+		ConditionType: string(leg.ConditionType),
+		Size:          leg.Size,
+	}
+	if leg.Price != nil {
+		result.Price = product.RoundPrice(*leg.Price, side)
+	}
+	if leg.TriggerPrice != nil {
+		result.TriggerPrice = product.RoundPrice(*leg.TriggerPrice, side)
+	}
+	if leg.Offset != nil {
+		result.Offset = *leg.Offset
+	}
+	return result
+}
+
+// validateParentOrderRequest validates the parent order request
+func validateParentOrderRequest(req *generated.CreateParentOrderRequest, products *ProductRegistry) error {
+	// Validate method and number of legs
+	count, ok := parentOrderLegCounts[req.Method]
+	if !ok {
+		return invalid(ErrInvalidRequest, "unsupported order method: %s", req.Method)
+	}
+	if len(req.Legs) != count {
+		return invalid(ErrInvalidRequest, "invalid legs: %s requires %d legs", req.Method, count)
+	}
+
+	if req.MinuteToExpire != nil && *req.MinuteToExpire <= 0 {
+		return invalid(ErrInvalidRequest, "invalid minute to expire: must be greater than 0")
+	}
+	if req.TimeInForce != nil {
+		switch *req.TimeInForce {
+		case generated.GTC, generated.IOC, generated.FOK:
+		default:
+			return invalid(ErrInvalidRequest, "invalid time in force: %s", *req.TimeInForce)
+		}
+	}
+
+	for i, leg := range req.Legs {
+		// Every leg is placed on the same pair
+		if leg.Pair != req.Legs[0].Pair {
+			return invalid(ErrInvalidRequest, "invalid legs: all legs must use the same pair")
+		}
+		if err := validateParentOrderLeg(leg, products); err != nil {
+			return fmt.Errorf("%w (leg %d)", err, i+1)
+		}
+	}
+
+	return nil
+}
+
+// validateParentOrderLeg validates a single leg of a parent order
+func validateParentOrderLeg(leg generated.ParentOrderLeg, products *ProductRegistry) error {
+	if _, ok := products.Get(model.Symbol(leg.Pair)); !ok {
+		return invalid(ErrUnsupportedPair, "unsupported pair: %s", leg.Pair)
+	}
+
+	switch leg.Side {
+	case generated.ParentOrderLegSideBUY, generated.ParentOrderLegSideSELL:
+	default:
+		return invalid(ErrInvalidRequest, "invalid side: %s", leg.Side)
+	}
+
+	if leg.Size <= 0 {
+		return invalid(ErrInvalidAmount, "invalid amount: must be greater than 0")
+	}
+	if err := products.ValidateSize(model.Symbol(leg.Pair), leg.Size); err != nil {
+		return err
+	}
+
+	// Validate the prices each condition type needs
+	switch leg.ConditionType {
+	case generated.LIMIT:
+		if !isPositive(leg.Price) {
+			return invalid(ErrInvalidPrice, "invalid price: LIMIT requires a price greater than 0")
+		}
+	case generated.MARKET:
+	case generated.STOP:
+		if !isPositive(leg.TriggerPrice) {
+			return invalid(ErrInvalidPrice, "invalid trigger price: STOP requires a trigger price greater than 0")
+		}
+	case generated.STOPLIMIT:
+		if !isPositive(leg.Price) {
+			return invalid(ErrInvalidPrice, "invalid price: STOP_LIMIT requires a price greater than 0")
+		}
+		if !isPositive(leg.TriggerPrice) {
+			return invalid(ErrInvalidPrice, "invalid trigger price: STOP_LIMIT requires a trigger price greater than 0")
+		}
+	case generated.TRAIL:
+		if !isPositive(leg.Offset) {
+			return invalid(ErrInvalidPrice, "invalid offset: TRAIL requires an offset greater than 0")
+		}
+	default:
+		return invalid(ErrInvalidRequest, "unsupported condition type: %s", leg.ConditionType)
+	}
+
+	return nil
+}
+
+// isPositive reports whether an optional number is set and greater than 0
+func isPositive(value *float64) bool {
+	return value != nil && *value > 0
+}
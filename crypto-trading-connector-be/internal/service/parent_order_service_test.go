@@ -0,0 +1,414 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// MockParentOrderRepository is a mock implementation of ParentOrderRepository
+type MockParentOrderRepository struct {
+	SaveParentOrderFunc        func(ctx context.Context, order *model.ParentOrderRecord) error
+	ListActiveParentOrdersFunc func(ctx context.Context) ([]model.ParentOrderRecord, error)
+	UpdateParentOrderFunc      func(ctx context.Context, acceptanceID, exchangeOrderID, status string) error
+	ListChildOrdersFunc        func(ctx context.Context, acceptanceID string) ([]model.OrderRecord, error)
+	SaveChildOrderFunc         func(ctx context.Context, acceptanceID string, child *model.OrderRecord) error
+}
+
+func (m *MockParentOrderRepository) SaveParentOrder(ctx context.Context, order *model.ParentOrderRecord) error {
+	if m.SaveParentOrderFunc != nil {
+		return m.SaveParentOrderFunc(ctx, order)
+	}
+	return nil
+}
+
+func (m *MockParentOrderRepository) ListActiveParentOrders(ctx context.Context) ([]model.ParentOrderRecord, error) {
+	if m.ListActiveParentOrdersFunc != nil {
+		return m.ListActiveParentOrdersFunc(ctx)
+	}
+	return []model.ParentOrderRecord{}, nil
+}
+
+func (m *MockParentOrderRepository) UpdateParentOrder(ctx context.Context, acceptanceID, exchangeOrderID, status string) error {
+	if m.UpdateParentOrderFunc != nil {
+		return m.UpdateParentOrderFunc(ctx, acceptanceID, exchangeOrderID, status)
+	}
+	return nil
+}
+
+func (m *MockParentOrderRepository) ListChildOrders(ctx context.Context, acceptanceID string) ([]model.OrderRecord, error) {
+	if m.ListChildOrdersFunc != nil {
+		return m.ListChildOrdersFunc(ctx, acceptanceID)
+	}
+	return []model.OrderRecord{}, nil
+}
+
+func (m *MockParentOrderRepository) SaveChildOrder(ctx context.Context, acceptanceID string, child *model.OrderRecord) error {
+	if m.SaveChildOrderFunc != nil {
+		return m.SaveChildOrderFunc(ctx, acceptanceID, child)
+	}
+	return nil
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func TestParentOrderService_CreateParentOrder_IFD(t *testing.T) {
+	var sentReq *model.ParentOrderRequest
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(20000), nil
+		},
+		SendParentOrderFunc: func(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error) {
+			sentReq = req
+			return &model.ParentOrderResponse{AcceptanceID: "JRF-PARENT-1"}, nil
+		},
+	}
+
+	var saved *model.ParentOrderRecord
+	mockRepo := &MockParentOrderRepository{
+		SaveParentOrderFunc: func(ctx context.Context, order *model.ParentOrderRecord) error {
+			saved = order
+			return nil
+		},
+	}
+
+	service := NewParentOrderService(mockClient, mockRepo, testProductRegistry())
+
+	minutes := 1440
+	order, err := service.CreateParentOrder(context.Background(), &generated.CreateParentOrderRequest{
+		Method:         generated.CreateParentOrderRequestMethodIFD,
+		MinuteToExpire: &minutes,
+		Legs: []generated.ParentOrderLeg{
+			{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideBUY, ConditionType: generated.LIMIT, Size: 0.001, Price: float64Ptr(14000000)},
+			{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideSELL, ConditionType: generated.STOP, Size: 0.001, TriggerPrice: float64Ptr(13500000)},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if order.ParentOrderId != "JRF-PARENT-1" || order.Status != generated.ParentOrderStatusACTIVE || order.Pair != "BTC/JPY" {
+		t.Errorf("unexpected order: %+v", order)
+	}
+
+	if sentReq.Method != "IFD" || sentReq.MinuteToExpire != 1440 || len(sentReq.Legs) != 2 {
+		t.Fatalf("unexpected exchange request: %+v", sentReq)
+	}
+	expectedExit := model.ParentOrderLeg{ProductCode: "BTC_JPY", Side: "SELL", ConditionType: "STOP", Size: 0.001, TriggerPrice: 13500000}
+	if sentReq.Legs[1] != expectedExit {
+		t.Errorf("expected exit leg %+v, got %+v", expectedExit, sentReq.Legs[1])
+	}
+
+	if saved == nil || saved.AcceptanceID != "JRF-PARENT-1" || saved.ProductCode != "BTC_JPY" || saved.Status != model.ParentOrderStatusActive {
+		t.Errorf("unexpected saved parent order: %+v", saved)
+	}
+}
+
+func TestParentOrderService_CreateParentOrder_InsufficientBalance(t *testing.T) {
+	sent := false
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(10000), nil
+		},
+		SendParentOrderFunc: func(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error) {
+			sent = true
+			return &model.ParentOrderResponse{AcceptanceID: "JRF-PARENT-1"}, nil
+		},
+	}
+
+	service := NewParentOrderService(mockClient, &MockParentOrderRepository{}, testProductRegistry())
+
+	_, err := service.CreateParentOrder(context.Background(), &generated.CreateParentOrderRequest{
+		Method: generated.CreateParentOrderRequestMethodIFD,
+		Legs: []generated.ParentOrderLeg{
+			{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideBUY, ConditionType: generated.LIMIT, Size: 0.001, Price: float64Ptr(14000000)},
+			{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideSELL, ConditionType: generated.LIMIT, Size: 0.001, Price: float64Ptr(14200000)},
+		},
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "insufficient balance") {
+		t.Errorf("expected insufficient balance error, got %v", err)
+	}
+	if sent {
+		t.Error("expected no order to be sent to the exchange")
+	}
+}
+
+func TestParentOrderService_CreateParentOrder_OCOChecksEveryLeg(t *testing.T) {
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return []model.Balance{{Currency: "BTC", Amount: 0.01, Available: 0.01}}, nil
+		},
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			return &model.Ticker{Bid: 14000000, Ask: 14001000}, nil
+		},
+	}
+
+	service := NewParentOrderService(mockClient, &MockParentOrderRepository{}, testProductRegistry())
+
+	_, err := service.CreateParentOrder(context.Background(), &generated.CreateParentOrderRequest{
+		Method: generated.CreateParentOrderRequestMethodOCO,
+		Legs: []generated.ParentOrderLeg{
+			{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideSELL, ConditionType: generated.LIMIT, Size: 0.01, Price: float64Ptr(15000000)},
+			{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideSELL, ConditionType: generated.TRAIL, Size: 0.02, Offset: float64Ptr(100000)},
+		},
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "insufficient balance") {
+		t.Errorf("expected insufficient balance error for second leg, got %v", err)
+	}
+}
+
+func TestParentOrderService_CreateParentOrder_SharesOrderPipeline(t *testing.T) {
+	orderRepo := &MockOrderRepository{}
+	pipeline := NewOrderPipeline(orderRepo)
+	var orderErr error
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(20000), nil
+		},
+	}
+	orderService := NewOrderService(mockClient, orderRepo, testProductRegistry())
+	orderService.SetOrderPipeline(pipeline)
+	mockClient.SendParentOrderFunc = func(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error) {
+		// The entry leg still holds 14,000 JPY while the special order is being sent
+		_, orderErr = orderService.CreateOrder(ctx, nil, &generated.CreateOrderRequest{
+			Pair:      "BTC/JPY",
+			OrderType: generated.CreateOrderRequestOrderTypeLimit,
+			Price:     14000000,
+			Amount:    0.001,
+		})
+		return &model.ParentOrderResponse{AcceptanceID: "JRF-PARENT-1"}, nil
+	}
+
+	service := NewParentOrderService(mockClient, &MockParentOrderRepository{}, testProductRegistry())
+	service.SetOrderPipeline(pipeline)
+
+	_, err := service.CreateParentOrder(context.Background(), &generated.CreateParentOrderRequest{
+		Method: generated.CreateParentOrderRequestMethodIFD,
+		Legs: []generated.ParentOrderLeg{
+			{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideBUY, ConditionType: generated.LIMIT, Size: 0.001, Price: float64Ptr(14000000)},
+			{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideSELL, ConditionType: generated.LIMIT, Size: 0.001, Price: float64Ptr(14200000)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !errors.Is(orderErr, ErrInsufficientBalance) {
+		t.Errorf("expected ErrInsufficientBalance for the order sent meanwhile, got %v", orderErr)
+	}
+}
+
+func TestParentOrderService_CreateParentOrder_RiskRejected(t *testing.T) {
+	sent := false
+	mockClient := &client.MockBitFlyerClient{
+		SendParentOrderFunc: func(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error) {
+			sent = true
+			return &model.ParentOrderResponse{AcceptanceID: "JRF-PARENT-1"}, nil
+		},
+	}
+
+	service := NewParentOrderService(mockClient, &MockParentOrderRepository{}, testProductRegistry())
+	service.SetRiskEngine(NewRiskEngine(NewRiskRules(RiskConfig{MaxOrderNotional: 20000}, &MockOrderRepository{})...))
+
+	// The entry leg is within the limit, but the exit leg is worth 21,000 JPY once it executes
+	_, err := service.CreateParentOrder(context.Background(), &generated.CreateParentOrderRequest{
+		Method: generated.CreateParentOrderRequestMethodIFD,
+		Legs: []generated.ParentOrderLeg{
+			{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideBUY, ConditionType: generated.LIMIT, Size: 0.001, Price: float64Ptr(14000000)},
+			{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideSELL, ConditionType: generated.LIMIT, Size: 0.001, Price: float64Ptr(21000000)},
+		},
+	})
+
+	if !errors.Is(err, ErrRiskMaxNotional) || !strings.Contains(err.Error(), "(leg 2)") {
+		t.Errorf("expected ErrRiskMaxNotional for the second leg, got %v", err)
+	}
+	if sent {
+		t.Error("expected no order to be sent to the exchange")
+	}
+}
+
+func TestParentOrderService_CreateParentOrder_ExchangeError(t *testing.T) {
+	mockClient := &client.MockBitFlyerClient{
+		SendParentOrderFunc: func(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error) {
+			return nil, errors.New("bitFlyer API returned status 400")
+		},
+	}
+
+	saved := false
+	mockRepo := &MockParentOrderRepository{
+		SaveParentOrderFunc: func(ctx context.Context, order *model.ParentOrderRecord) error {
+			saved = true
+			return nil
+		},
+	}
+
+	service := NewParentOrderService(mockClient, mockRepo, testProductRegistry())
+
+	_, err := service.CreateParentOrder(context.Background(), &generated.CreateParentOrderRequest{
+		Method: generated.CreateParentOrderRequestMethodIFD,
+		Legs: []generated.ParentOrderLeg{
+			{Pair: "ETH/JPY", Side: generated.ParentOrderLegSideBUY, ConditionType: generated.LIMIT, Size: 0.01, Price: float64Ptr(480000)},
+			{Pair: "ETH/JPY", Side: generated.ParentOrderLegSideSELL, ConditionType: generated.LIMIT, Size: 0.01, Price: float64Ptr(490000)},
+		},
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "failed to send parent order") {
+		t.Errorf("expected exchange error, got %v", err)
+	}
+	if saved {
+		t.Error("expected nothing to be saved when the exchange rejects the order")
+	}
+}
+
+func TestParentOrderService_CreateParentOrder_ExchangeWithoutSpecialOrders(t *testing.T) {
+	mockClient := &client.MockBitFlyerClient{
+		NameFunc: func() string { return "gmocoin" },
+		SendParentOrderFunc: func(ctx context.Context, req *model.ParentOrderRequest) (*model.ParentOrderResponse, error) {
+			return nil, fmt.Errorf("special orders are %w", client.ErrNotSupported)
+		},
+	}
+
+	service := NewParentOrderService(mockClient, &MockParentOrderRepository{}, testProductRegistry())
+
+	_, err := service.CreateParentOrder(context.Background(), &generated.CreateParentOrderRequest{
+		Method: generated.CreateParentOrderRequestMethodIFD,
+		Legs: []generated.ParentOrderLeg{
+			{Pair: "ETH/JPY", Side: generated.ParentOrderLegSideBUY, ConditionType: generated.LIMIT, Size: 0.01, Price: float64Ptr(480000)},
+			{Pair: "ETH/JPY", Side: generated.ParentOrderLegSideSELL, ConditionType: generated.LIMIT, Size: 0.01, Price: float64Ptr(490000)},
+		},
+	})
+
+	// The "unsupported" prefix is reported to the client as an invalid request
+	if err == nil || !strings.HasPrefix(err.Error(), "unsupported order method on gmocoin") {
+		t.Errorf("expected unsupported order method error, got %v", err)
+	}
+}
+
+func TestValidateParentOrderRequest(t *testing.T) {
+	buyLimit := generated.ParentOrderLeg{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideBUY, ConditionType: generated.LIMIT, Size: 0.001, Price: float64Ptr(14000000)}
+	sellLimit := generated.ParentOrderLeg{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideSELL, ConditionType: generated.LIMIT, Size: 0.001, Price: float64Ptr(14200000)}
+	sellStop := generated.ParentOrderLeg{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideSELL, ConditionType: generated.STOP, Size: 0.001, TriggerPrice: float64Ptr(13800000)}
+	sellStopLimit := generated.ParentOrderLeg{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideSELL, ConditionType: generated.STOPLIMIT, Size: 0.001, Price: float64Ptr(13790000), TriggerPrice: float64Ptr(13800000)}
+	sellTrail := generated.ParentOrderLeg{Pair: "BTC/JPY", Side: generated.ParentOrderLegSideSELL, ConditionType: generated.TRAIL, Size: 0.001, Offset: float64Ptr(50000)}
+
+	withLeg := func(leg generated.ParentOrderLeg, modify func(*generated.ParentOrderLeg)) generated.ParentOrderLeg {
+		modify(&leg)
+		return leg
+	}
+	ioc := generated.IOC
+	zero := 0
+
+	tests := []struct {
+		name    string
+		req     *generated.CreateParentOrderRequest
+		wantErr string
+	}{
+		{
+			name: "valid IFD",
+			req:  &generated.CreateParentOrderRequest{Method: generated.CreateParentOrderRequestMethodIFD, Legs: []generated.ParentOrderLeg{buyLimit, sellLimit}, TimeInForce: &ioc},
+		},
+		{
+			name: "valid OCO",
+			req:  &generated.CreateParentOrderRequest{Method: generated.CreateParentOrderRequestMethodOCO, Legs: []generated.ParentOrderLeg{sellLimit, sellStopLimit}},
+		},
+		{
+			name: "valid IFDOCO",
+			req:  &generated.CreateParentOrderRequest{Method: generated.CreateParentOrderRequestMethodIFDOCO, Legs: []generated.ParentOrderLeg{buyLimit, sellLimit, sellTrail}},
+		},
+		{
+			name:    "unknown method",
+			req:     &generated.CreateParentOrderRequest{Method: "OTO", Legs: []generated.ParentOrderLeg{buyLimit, sellLimit}},
+			wantErr: "unsupported order method",
+		},
+		{
+			name:    "IFDOCO with two legs",
+			req:     &generated.CreateParentOrderRequest{Method: generated.CreateParentOrderRequestMethodIFDOCO, Legs: []generated.ParentOrderLeg{buyLimit, sellLimit}},
+			wantErr: "requires 3 legs",
+		},
+		{
+			name:    "non-positive expiry",
+			req:     &generated.CreateParentOrderRequest{Method: generated.CreateParentOrderRequestMethodIFD, Legs: []generated.ParentOrderLeg{buyLimit, sellLimit}, MinuteToExpire: &zero},
+			wantErr: "invalid minute to expire",
+		},
+		{
+			name: "mixed pairs",
+			req: &generated.CreateParentOrderRequest{Method: generated.CreateParentOrderRequestMethodIFD, Legs: []generated.ParentOrderLeg{
+				buyLimit,
+				withLeg(sellLimit, func(l *generated.ParentOrderLeg) { l.Pair = "ETH/JPY"; l.Size = 0.01 }),
+			}},
+			wantErr: "same pair",
+		},
+		{
+			name: "limit without price",
+			req: &generated.CreateParentOrderRequest{Method: generated.CreateParentOrderRequestMethodIFD, Legs: []generated.ParentOrderLeg{
+				withLeg(buyLimit, func(l *generated.ParentOrderLeg) { l.Price = nil }),
+				sellLimit,
+			}},
+			wantErr: "invalid price",
+		},
+		{
+			name: "stop without trigger price",
+			req: &generated.CreateParentOrderRequest{Method: generated.CreateParentOrderRequestMethodIFD, Legs: []generated.ParentOrderLeg{
+				buyLimit,
+				withLeg(sellStop, func(l *generated.ParentOrderLeg) { l.TriggerPrice = nil }),
+			}},
+			wantErr: "invalid trigger price",
+		},
+		{
+			name: "stop limit without price",
+			req: &generated.CreateParentOrderRequest{Method: generated.CreateParentOrderRequestMethodOCO, Legs: []generated.ParentOrderLeg{
+				sellLimit,
+				withLeg(sellStopLimit, func(l *generated.ParentOrderLeg) { l.Price = nil }),
+			}},
+			wantErr: "invalid price",
+		},
+		{
+			name: "trail without offset",
+			req: &generated.CreateParentOrderRequest{Method: generated.CreateParentOrderRequestMethodIFD, Legs: []generated.ParentOrderLeg{
+				buyLimit,
+				withLeg(sellTrail, func(l *generated.ParentOrderLeg) { l.Offset = nil }),
+			}},
+			wantErr: "invalid offset",
+		},
+		{
+			name: "size below minimum",
+			req: &generated.CreateParentOrderRequest{Method: generated.CreateParentOrderRequestMethodIFD, Legs: []generated.ParentOrderLeg{
+				withLeg(buyLimit, func(l *generated.ParentOrderLeg) { l.Size = 0.0001 }),
+				sellLimit,
+			}},
+			wantErr: "invalid amount",
+		},
+		{
+			name: "unknown condition type",
+			req: &generated.CreateParentOrderRequest{Method: generated.CreateParentOrderRequestMethodIFD, Legs: []generated.ParentOrderLeg{
+				buyLimit,
+				withLeg(sellLimit, func(l *generated.ParentOrderLeg) { l.ConditionType = "ICEBERG" }),
+			}},
+			wantErr: "unsupported condition type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateParentOrderRequest(tt.req, testProductRegistry())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+)
+
+// ParentOrderSynchronizer records the legs of special orders as the exchange triggers them.
+// Each triggered leg is saved to buy_orders or sell_orders with its parent order, and an
+// exit leg is paired with its entry leg so trade history can match them. The status of
+// the recorded legs is then kept up to date by OrderSynchronizer.
+type ParentOrderSynchronizer struct {
+	exchangeClient  client.CryptoExchangeClient
+	parentOrderRepo repository.ParentOrderRepository
+	mu              sync.Mutex
+}
+
+// NewParentOrderSynchronizer creates a new parent order synchronizer
+func NewParentOrderSynchronizer(exchangeClient client.CryptoExchangeClient, parentOrderRepo repository.ParentOrderRepository) *ParentOrderSynchronizer {
+	return &ParentOrderSynchronizer{
+		exchangeClient:  exchangeClient,
+		parentOrderRepo: parentOrderRepo,
+	}
+}
+
+// Run synchronizes parent orders every interval until ctx is cancelled
+func (s *ParentOrderSynchronizer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.SyncOnce(ctx)
+		if err != nil {
+			log.Printf("Warning: parent order sync failed: %v", err)
+		} else if result.Updated > 0 || result.Failed > 0 {
+			log.Printf("Parent order sync: checked %d, updated %d, failed %d", result.Checked, result.Updated, result.Failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncOnce records newly triggered legs and the state of every active parent order
+func (s *ParentOrderSynchronizer) SyncOnce(ctx context.Context) (*SyncResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orders, err := s.parentOrderRepo.ListActiveParentOrders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active parent orders: %w", err)
+	}
+
+	result := &SyncResult{}
+	liveOrders := s.fetchRecentParentOrders(ctx, orders)
+
+	for i := range orders {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		result.Checked++
+
+		order := &orders[i]
+		live, ok := liveOrders[order.AcceptanceID]
+		if !ok {
+			// Not visible on the exchange yet
+			continue
+		}
+
+		recorded, err := s.recordChildOrders(ctx, order, live.ExchangeOrderID)
+		if err != nil {
+			// Leave the parent order active so its legs are picked up on the next pass
+			log.Printf("Warning: failed to record legs of parent order %s: %v", order.AcceptanceID, err)
+			result.Failed++
+			continue
+		}
+
+		newStatus := resolveParentOrderStatus(order.Status, live)
+		if newStatus == order.Status && order.ExchangeOrderID != nil {
+			if recorded > 0 {
+				result.Updated++
+			}
+			continue
+		}
+
+		if err := s.parentOrderRepo.UpdateParentOrder(ctx, order.AcceptanceID, live.ExchangeOrderID, newStatus); err != nil {
+			log.Printf("Warning: failed to update parent order %s to %s: %v", order.AcceptanceID, newStatus, err)
+			result.Failed++
+			continue
+		}
+		result.Updated++
+	}
+
+	return result, nil
+}
+
+// fetchRecentParentOrders retrieves recent exchange parent orders for every product with
+// active parent orders, keyed by acceptance ID
+func (s *ParentOrderSynchronizer) fetchRecentParentOrders(ctx context.Context, orders []model.ParentOrderRecord) map[string]model.ParentOrder {
+	liveOrders := make(map[string]model.ParentOrder)
+
+	queried := make(map[string]bool)
+	for _, order := range orders {
+		if queried[order.ProductCode] {
+			continue
+		}
+		queried[order.ProductCode] = true
+
+		parentOrders, err := s.exchangeClient.GetParentOrders(ctx, &model.ParentOrderQuery{
+			ProductCode: order.ProductCode,
+			Count:       liveOrderLookupCount,
+		})
+		if err != nil {
+			log.Printf("Warning: failed to get recent %s parent orders from exchange: %v", order.ProductCode, err)
+			continue
+		}
+
+		for _, parentOrder := range parentOrders {
+			liveOrders[parentOrder.AcceptanceID] = parentOrder
+		}
+	}
+
+	return liveOrders
+}
+
+// recordChildOrders saves the legs triggered on the exchange that are not recorded yet
+// and returns how many were saved
+func (s *ParentOrderSynchronizer) recordChildOrders(ctx context.Context, order *model.ParentOrderRecord, exchangeOrderID string) (int, error) {
+	childOrders, err := s.exchangeClient.GetChildOrders(ctx, &model.ChildOrderQuery{
+		ProductCode:   order.ProductCode,
+		ParentOrderID: exchangeOrderID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get child orders from exchange: %w", err)
+	}
+
+	existing, err := s.parentOrderRepo.ListChildOrders(ctx, order.AcceptanceID)
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := make(map[string]bool, len(existing))
+	entryID := ""
+	for _, child := range existing {
+		recorded[child.OrderID] = true
+		if child.Side == "BUY" {
+			entryID = child.OrderID
+		}
+	}
+
+	// Record buy legs first so that sell legs can be paired with them
+	saved := 0
+	for _, side := range []model.OrderSide{model.OrderSideBuy, model.OrderSideSell} {
+		for _, childOrder := range childOrders {
+			if childOrder.Side != side || recorded[childOrder.OrderID] {
+				continue
+			}
+
+			child := toChildOrderRecord(childOrder, order.Exchange)
+			if side == model.OrderSideBuy {
+				entryID = child.OrderID
+			} else if pairsEntryLeg(order) && entryID != "" {
+				child.ParentID = &entryID
+			}
+
+			if err := s.parentOrderRepo.SaveChildOrder(ctx, order.AcceptanceID, child); err != nil {
+				return saved, err
+			}
+			recorded[child.OrderID] = true
+			saved++
+		}
+	}
+
+	return saved, nil
+}
+
+// pairsEntryLeg reports whether the sell legs of a parent order close its buy entry leg
+func pairsEntryLeg(order *model.ParentOrderRecord) bool {
+	if order.Method == model.ParentOrderMethodOCO || len(order.Legs) == 0 {
+		return false
+	}
+	return order.Legs[0].Side == "BUY"
+}
+
+// toChildOrderRecord converts a triggered leg reported by the exchange to an order record
+// placed on the same exchange as its parent order
+func toChildOrderRecord(childOrder model.ChildOrder, exchange string) *model.OrderRecord {
+	// Market and stop legs have no limit price, so record the execution price instead
+	price := childOrder.Price
+	if price == 0 {
+		price = childOrder.AveragePrice
+	}
+
+	return &model.OrderRecord{
+		OrderID:     childOrder.OrderID,
+		ProductCode: childOrder.ProductCode,
+		Side:        string(childOrder.Side),
+		Price:       price,
+		Size:        childOrder.Size,
+		Exchange:    exchange,
+		Status:      resolveOrderStatus(model.OrderStatusUnfilled, childOrder),
+	}
+}
+
+// resolveParentOrderStatus maps the live exchange state of a parent order to the status stored in the database
+func resolveParentOrderStatus(current string, live model.ParentOrder) string {
+	switch live.State {
+	case model.OrderStateCompleted:
+		return model.ParentOrderStatusCompleted
+	case model.OrderStateCanceled, model.OrderStateRejected:
+		return model.ParentOrderStatusCancelled
+	case model.OrderStateExpired:
+		return model.ParentOrderStatusExpired
+	}
+	return current
+}
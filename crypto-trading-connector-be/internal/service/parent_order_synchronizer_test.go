@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+func TestParentOrderSynchronizer_SyncOnce_RecordsTriggeredLegs(t *testing.T) {
+	mockRepo := &MockParentOrderRepository{
+		ListActiveParentOrdersFunc: func(ctx context.Context) ([]model.ParentOrderRecord, error) {
+			return []model.ParentOrderRecord{
+				{
+					AcceptanceID: "JRF-PARENT-1",
+					Method:       model.ParentOrderMethodIFDOCO,
+					ProductCode:  "BTC_JPY",
+					Legs: []model.ParentOrderLeg{
+						{Side: "BUY", ConditionType: "LIMIT"},
+						{Side: "SELL", ConditionType: "LIMIT"},
+						{Side: "SELL", ConditionType: "STOP"},
+					},
+					Exchange: "bitflyer",
+					Status:   model.ParentOrderStatusActive,
+				},
+				// Not visible on the exchange yet
+				{AcceptanceID: "JRF-PARENT-2", Method: model.ParentOrderMethodIFD, ProductCode: "BTC_JPY", Status: model.ParentOrderStatusActive},
+			}, nil
+		},
+	}
+
+	var saved []model.OrderRecord
+	mockRepo.SaveChildOrderFunc = func(ctx context.Context, acceptanceID string, child *model.OrderRecord) error {
+		if acceptanceID != "JRF-PARENT-1" {
+			t.Errorf("unexpected parent order %s", acceptanceID)
+		}
+		saved = append(saved, *child)
+		return nil
+	}
+
+	type update struct{ acceptanceID, exchangeOrderID, status string }
+	var updates []update
+	mockRepo.UpdateParentOrderFunc = func(ctx context.Context, acceptanceID, exchangeOrderID, status string) error {
+		updates = append(updates, update{acceptanceID, exchangeOrderID, status})
+		return nil
+	}
+
+	mockClient := &client.MockBitFlyerClient{
+		GetParentOrdersFunc: func(ctx context.Context, query *model.ParentOrderQuery) ([]model.ParentOrder, error) {
+			return []model.ParentOrder{
+				{AcceptanceID: "JRF-PARENT-1", ExchangeOrderID: "JCP-1", State: "COMPLETED"},
+			}, nil
+		},
+		GetChildOrdersFunc: func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+			if query.ParentOrderID != "JCP-1" {
+				t.Errorf("expected child orders of JCP-1, got %+v", query)
+			}
+			// Exchange returns the newest leg first
+			return []model.ChildOrder{
+				{OrderID: "JRF-EXIT", ProductCode: "BTC_JPY", Side: "SELL", AveragePrice: 13800000, Size: 0.001, State: "COMPLETED"},
+				{OrderID: "JRF-ENTRY", ProductCode: "BTC_JPY", Side: "BUY", Price: 14000000, Size: 0.001, State: "COMPLETED"},
+			}, nil
+		},
+	}
+
+	synchronizer := NewParentOrderSynchronizer(mockClient, mockRepo)
+
+	result, err := synchronizer.SyncOnce(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Checked != 2 || result.Updated != 1 || result.Failed != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if len(saved) != 2 {
+		t.Fatalf("expected 2 legs to be recorded, got %d", len(saved))
+	}
+	if saved[0].OrderID != "JRF-ENTRY" || saved[0].Status != model.OrderStatusFilled || saved[0].Price != 14000000 || saved[0].Exchange != "bitflyer" {
+		t.Errorf("unexpected entry leg: %+v", saved[0])
+	}
+	if saved[1].OrderID != "JRF-EXIT" || saved[1].Price != 13800000 {
+		t.Errorf("unexpected exit leg: %+v", saved[1])
+	}
+	if saved[1].ParentID == nil || *saved[1].ParentID != "JRF-ENTRY" {
+		t.Errorf("expected exit leg to be paired with JRF-ENTRY, got %v", saved[1].ParentID)
+	}
+
+	if len(updates) != 1 || updates[0] != (update{"JRF-PARENT-1", "JCP-1", model.ParentOrderStatusCompleted}) {
+		t.Errorf("unexpected parent order updates: %+v", updates)
+	}
+}
+
+func TestParentOrderSynchronizer_SyncOnce_SkipsRecordedLegs(t *testing.T) {
+	exchangeOrderID := "JCP-1"
+	mockRepo := &MockParentOrderRepository{
+		ListActiveParentOrdersFunc: func(ctx context.Context) ([]model.ParentOrderRecord, error) {
+			return []model.ParentOrderRecord{
+				{
+					AcceptanceID:    "JRF-PARENT-1",
+					ExchangeOrderID: &exchangeOrderID,
+					Method:          model.ParentOrderMethodIFD,
+					ProductCode:     "BTC_JPY",
+					Legs:            []model.ParentOrderLeg{{Side: "BUY"}, {Side: "SELL"}},
+					Status:          model.ParentOrderStatusActive,
+				},
+			}, nil
+		},
+		ListChildOrdersFunc: func(ctx context.Context, acceptanceID string) ([]model.OrderRecord, error) {
+			return []model.OrderRecord{{OrderID: "JRF-ENTRY", Side: "BUY"}}, nil
+		},
+		UpdateParentOrderFunc: func(ctx context.Context, acceptanceID, exchangeOrderID, status string) error {
+			t.Errorf("expected no update for an unchanged parent order, got %s", status)
+			return nil
+		},
+	}
+
+	var saved []model.OrderRecord
+	mockRepo.SaveChildOrderFunc = func(ctx context.Context, acceptanceID string, child *model.OrderRecord) error {
+		saved = append(saved, *child)
+		return nil
+	}
+
+	mockClient := &client.MockBitFlyerClient{
+		GetParentOrdersFunc: func(ctx context.Context, query *model.ParentOrderQuery) ([]model.ParentOrder, error) {
+			return []model.ParentOrder{
+				{AcceptanceID: "JRF-PARENT-1", ExchangeOrderID: "JCP-1", State: "ACTIVE"},
+			}, nil
+		},
+		GetChildOrdersFunc: func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+			return []model.ChildOrder{
+				{OrderID: "JRF-EXIT", Side: "SELL", Price: 14200000, Size: 0.001, State: "ACTIVE"},
+				{OrderID: "JRF-ENTRY", Side: "BUY", Price: 14000000, Size: 0.001, State: "COMPLETED"},
+			}, nil
+		},
+	}
+
+	synchronizer := NewParentOrderSynchronizer(mockClient, mockRepo)
+
+	result, err := synchronizer.SyncOnce(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Updated != 1 {
+		t.Errorf("expected 1 updated parent order, got %+v", result)
+	}
+	if len(saved) != 1 || saved[0].OrderID != "JRF-EXIT" || saved[0].Status != model.OrderStatusUnfilled {
+		t.Fatalf("expected only the exit leg to be recorded, got %+v", saved)
+	}
+	if saved[0].ParentID == nil || *saved[0].ParentID != "JRF-ENTRY" {
+		t.Errorf("expected exit leg to be paired with the recorded entry, got %v", saved[0].ParentID)
+	}
+}
+
+func TestParentOrderSynchronizer_SyncOnce_KeepsActiveOnChildError(t *testing.T) {
+	mockRepo := &MockParentOrderRepository{
+		ListActiveParentOrdersFunc: func(ctx context.Context) ([]model.ParentOrderRecord, error) {
+			return []model.ParentOrderRecord{
+				{AcceptanceID: "JRF-PARENT-1", Method: model.ParentOrderMethodOCO, ProductCode: "BTC_JPY", Status: model.ParentOrderStatusActive},
+			}, nil
+		},
+		UpdateParentOrderFunc: func(ctx context.Context, acceptanceID, exchangeOrderID, status string) error {
+			t.Errorf("expected parent order to stay active until its legs are recorded, got %s", status)
+			return nil
+		},
+	}
+
+	mockClient := &client.MockBitFlyerClient{
+		GetParentOrdersFunc: func(ctx context.Context, query *model.ParentOrderQuery) ([]model.ParentOrder, error) {
+			return []model.ParentOrder{
+				{AcceptanceID: "JRF-PARENT-1", ExchangeOrderID: "JCP-1", State: "COMPLETED"},
+			}, nil
+		},
+		GetChildOrdersFunc: func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+			return nil, errors.New("connection reset")
+		},
+	}
+
+	synchronizer := NewParentOrderSynchronizer(mockClient, mockRepo)
+
+	result, err := synchronizer.SyncOnce(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Failed != 1 || result.Updated != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestResolveParentOrderStatus(t *testing.T) {
+	tests := []struct {
+		state    model.OrderState
+		expected string
+	}{
+		{state: "ACTIVE", expected: model.ParentOrderStatusActive},
+		{state: "COMPLETED", expected: model.ParentOrderStatusCompleted},
+		{state: "CANCELED", expected: model.ParentOrderStatusCancelled},
+		{state: "REJECTED", expected: model.ParentOrderStatusCancelled},
+		{state: "EXPIRED", expected: model.ParentOrderStatusExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			got := resolveParentOrderStatus(model.ParentOrderStatusActive, model.ParentOrder{State: tt.state})
+			if got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
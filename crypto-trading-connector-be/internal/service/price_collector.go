@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+)
+
+// PriceCollectorConfig configures a PriceCollector
+type PriceCollectorConfig struct {
+	Interval  time.Duration // How often every asset is sampled
+	Tolerance time.Duration // How far from exactly 24 hours earlier the sample compared against for price_ratio_24h may be
+}
+
+// DefaultPriceCollectorConfig returns the collector settings used by the price-collector command
+func DefaultPriceCollectorConfig() PriceCollectorConfig {
+	return PriceCollectorConfig{
+		Interval:  5 * time.Minute,
+		Tolerance: 30 * time.Minute,
+	}
+}
+
+// PriceCollector samples the price of every listed asset into price_histories, which the charts are drawn from.
+// Every sample records price_ratio_24h against the sample taken closest to 24 hours earlier, and none when
+// there is no sample within Tolerance of that time. A pair whose latest sample is less than half an interval
+// old is skipped, so restarting the collector or running a second one does not record duplicate samples.
+type PriceCollector struct {
+	exchangeClient client.CryptoExchangeClient
+	repo           repository.PriceHistoryRepository
+	assets         *model.AssetCatalog
+	config         PriceCollectorConfig
+	now            func() time.Time
+}
+
+// CollectResult summarizes a single collection pass
+type CollectResult struct {
+	Sampled int
+	Skipped int
+	Failed  int
+}
+
+// NewPriceCollector creates a new price collector
+func NewPriceCollector(exchangeClient client.CryptoExchangeClient, repo repository.PriceHistoryRepository, assets *model.AssetCatalog, config PriceCollectorConfig) *PriceCollector {
+	return &PriceCollector{
+		exchangeClient: exchangeClient,
+		repo:           repo,
+		assets:         assets,
+		config:         config,
+		now:            time.Now,
+	}
+}
+
+// Run samples every asset each interval until ctx is cancelled. A pass that takes longer than the
+// interval is reported, as the next samples will be late.
+func (c *PriceCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		started := c.now()
+		result := c.CollectOnce(ctx)
+		if elapsed := c.now().Sub(started); elapsed > c.config.Interval {
+			log.Printf("Warning: price collector is falling behind: collecting took %s, longer than the %s interval", elapsed.Round(time.Millisecond), c.config.Interval)
+		}
+		if result.Failed > 0 {
+			log.Printf("Price collector: sampled %d, skipped %d, failed %d", result.Sampled, result.Skipped, result.Failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CollectOnce samples every asset whose latest sample is due
+func (c *PriceCollector) CollectOnce(ctx context.Context) CollectResult {
+	var result CollectResult
+
+	for _, asset := range c.assets.Assets() {
+		sampled, err := c.collect(ctx, asset.Pair)
+		switch {
+		case err != nil:
+			log.Printf("Warning: failed to sample %s: %v", asset.Pair, err)
+			result.Failed++
+		case sampled:
+			result.Sampled++
+		default:
+			result.Skipped++
+		}
+	}
+
+	return result
+}
+
+// collect records a sample of the pair unless its latest sample is recent enough
+func (c *PriceCollector) collect(ctx context.Context, pair model.Symbol) (bool, error) {
+	productCode := pair.ProductCode()
+	now := c.now()
+
+	latest, err := c.repo.GetLatestPriceHistory(ctx, productCode)
+	if err != nil {
+		return false, err
+	}
+	if latest != nil {
+		age := now.Sub(latest.Datetime)
+		if age < c.config.Interval/2 {
+			return false, nil
+		}
+		// Report the gap left by a stopped collector or by failing samples; the charts are missing points for it
+		if age > 2*c.config.Interval {
+			log.Printf("Warning: %s has not been sampled for %s (last sample at %s)", pair, age.Round(time.Second), latest.Datetime.Format(time.RFC3339))
+		}
+	}
+
+	ticker, err := c.exchangeClient.GetTicker(ctx, pair)
+	if err != nil {
+		return false, fmt.Errorf("failed to get ticker: %w", err)
+	}
+	if ticker.Stale {
+		return false, errors.New("price is stale")
+	}
+
+	history := &model.PriceHistory{
+		Datetime:    now.Truncate(time.Second),
+		ProductCode: productCode,
+		Price:       ticker.Last,
+	}
+
+	previous, err := c.repo.FindClosestPriceHistory(ctx, productCode, now.Add(-24*time.Hour), c.config.Tolerance)
+	if err != nil {
+		return false, err
+	}
+	if previous != nil && previous.Price > 0 {
+		ratio := ticker.Last / previous.Price
+		history.PriceRatio24h = &ratio
+	}
+
+	if err := c.repo.SavePriceHistory(ctx, history); err != nil {
+		return false, err
+	}
+	return true, nil
+}
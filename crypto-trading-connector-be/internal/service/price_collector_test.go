@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// fakePriceHistoryRepository keeps price histories in memory
+type fakePriceHistoryRepository struct {
+	histories []model.PriceHistory
+	saveErr   error
+}
+
+func (r *fakePriceHistoryRepository) SavePriceHistory(ctx context.Context, history *model.PriceHistory) error {
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+	history.ID = len(r.histories) + 1
+	r.histories = append(r.histories, *history)
+	return nil
+}
+
+func (r *fakePriceHistoryRepository) GetLatestPriceHistory(ctx context.Context, productCode string) (*model.PriceHistory, error) {
+	var latest *model.PriceHistory
+	for i, history := range r.histories {
+		if history.ProductCode == productCode && (latest == nil || history.Datetime.After(latest.Datetime)) {
+			latest = &r.histories[i]
+		}
+	}
+	return latest, nil
+}
+
+func (r *fakePriceHistoryRepository) FindClosestPriceHistory(ctx context.Context, productCode string, at time.Time, tolerance time.Duration) (*model.PriceHistory, error) {
+	var closest *model.PriceHistory
+	for i, history := range r.histories {
+		distance := history.Datetime.Sub(at).Abs()
+		if history.ProductCode != productCode || distance > tolerance {
+			continue
+		}
+		if closest == nil || distance < closest.Datetime.Sub(at).Abs() {
+			closest = &r.histories[i]
+		}
+	}
+	return closest, nil
+}
+
+func TestPriceCollector_CollectOnce(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	repo := &fakePriceHistoryRepository{histories: []model.PriceHistory{
+		// Samples around 24 hours earlier; the closest one is compared against
+		{ProductCode: "BTC_JPY", Price: 9000000, Datetime: now.Add(-24*time.Hour - 20*time.Minute)},
+		{ProductCode: "BTC_JPY", Price: 10000000, Datetime: now.Add(-24*time.Hour + 4*time.Minute)},
+		// The last ETH sample was taken a minute ago, before a restart
+		{ProductCode: "ETH_JPY", Price: 500000, Datetime: now.Add(-time.Minute)},
+	}}
+	exchangeClient := &client.MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			return &model.Ticker{Symbol: symbol, Last: 10500000}, nil
+		},
+	}
+	collector := NewPriceCollector(exchangeClient, repo, model.DefaultAssetCatalog(), DefaultPriceCollectorConfig())
+	collector.now = func() time.Time { return now }
+
+	result := collector.CollectOnce(context.Background())
+	if result != (CollectResult{Sampled: 1, Skipped: 1}) {
+		t.Fatalf("expected 1 sampled and 1 skipped, got %+v", result)
+	}
+
+	saved := repo.histories[len(repo.histories)-1]
+	if saved.ProductCode != "BTC_JPY" || saved.Price != 10500000 || !saved.Datetime.Equal(now) {
+		t.Errorf("unexpected sample %+v", saved)
+	}
+	if saved.PriceRatio24h == nil || *saved.PriceRatio24h != 1.05 {
+		t.Errorf("expected a ratio of 1.05, got %v", saved.PriceRatio24h)
+	}
+
+	// After a gap of more than a day there is nothing to compare against
+	now = now.Add(48 * time.Hour)
+	collector.CollectOnce(context.Background())
+	if saved := repo.histories[len(repo.histories)-1]; saved.PriceRatio24h != nil {
+		t.Errorf("expected no ratio after a gap, got %v", *saved.PriceRatio24h)
+	}
+}
+
+func TestPriceCollector_CollectOnce_Failures(t *testing.T) {
+	repo := &fakePriceHistoryRepository{}
+	exchangeClient := &client.MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			if symbol == "ETH/JPY" {
+				return nil, client.ErrExchangeUnavailable
+			}
+			// A cached price the exchange could not refresh is not recorded
+			return &model.Ticker{Symbol: symbol, Last: 10000000, Stale: true}, nil
+		},
+	}
+	collector := NewPriceCollector(exchangeClient, repo, model.DefaultAssetCatalog(), DefaultPriceCollectorConfig())
+
+	if result := collector.CollectOnce(context.Background()); result != (CollectResult{Failed: 2}) {
+		t.Errorf("expected 2 failures, got %+v", result)
+	}
+	if len(repo.histories) != 0 {
+		t.Errorf("expected no samples, got %+v", repo.histories)
+	}
+
+	exchangeClient.GetTickerFunc = func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+		return &model.Ticker{Symbol: symbol, Last: 10000000}, nil
+	}
+	repo.saveErr = errors.New("connection refused")
+	if result := collector.CollectOnce(context.Background()); result != (CollectResult{Failed: 2}) {
+		t.Errorf("expected 2 failures, got %+v", result)
+	}
+}
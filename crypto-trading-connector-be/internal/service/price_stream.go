@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// PriceStream delivers live prices to any number of subscribers
+type PriceStream interface {
+	// Subscribe delivers the price updates of the given cryptocurrency IDs (every listed one if empty)
+	// until ctx is done, starting with the latest known prices. The channel is closed at the end.
+	Subscribe(ctx context.Context, ids []string) (<-chan generated.PriceUpdate, error)
+}
+
+// PriceStreamHub implements PriceStream by fanning out a single upstream feed per cryptocurrency.
+// Pairs traded on an exchange with a realtime market data stream follow its tickers; other pairs
+// are polled with GetTicker, but only while someone is subscribed to them.
+type PriceStreamHub struct {
+	exchangeClient client.CryptoExchangeClient
+	streams        map[string]client.MarketDataStream // Keyed by exchange name
+	assets         *model.AssetCatalog
+	pollInterval   time.Duration
+	done           chan struct{} // Closed when Run returns
+
+	mu          sync.Mutex
+	subscribers map[*priceSubscriber]struct{}
+	latest      map[string]generated.PriceUpdate // Keyed by cryptocurrency ID
+	stopped     bool
+}
+
+// priceSubscriber is a client of the price stream
+type priceSubscriber struct {
+	ids     map[string]bool // Requested cryptocurrency IDs; nil for all
+	updates chan generated.PriceUpdate
+}
+
+func (s *priceSubscriber) wants(id string) bool {
+	return s.ids == nil || s.ids[id]
+}
+
+// NewPriceStreamHub creates a price stream hub. streams holds the realtime market data streams
+// keyed by exchange name; pairs routed to other exchanges are polled every pollInterval.
+func NewPriceStreamHub(exchangeClient client.CryptoExchangeClient, streams map[string]client.MarketDataStream, assets *model.AssetCatalog, pollInterval time.Duration) *PriceStreamHub {
+	return &PriceStreamHub{
+		exchangeClient: exchangeClient,
+		streams:        streams,
+		assets:         assets,
+		pollInterval:   pollInterval,
+		done:           make(chan struct{}),
+		subscribers:    map[*priceSubscriber]struct{}{},
+		latest:         map[string]generated.PriceUpdate{},
+	}
+}
+
+// Run follows the price of every listed cryptocurrency until ctx is cancelled,
+// then closes the channels of every subscriber
+func (h *PriceStreamHub) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, asset := range h.assets.Assets() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.follow(ctx, asset)
+		}()
+	}
+	wg.Wait()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stopped = true
+	close(h.done)
+	for sub := range h.subscribers {
+		close(sub.updates)
+	}
+	h.subscribers = map[*priceSubscriber]struct{}{}
+}
+
+// follow publishes the prices of one cryptocurrency from the stream of its exchange, or by polling
+func (h *PriceStreamHub) follow(ctx context.Context, asset model.Asset) {
+	exchange := client.ForSymbol(h.exchangeClient, asset.Pair).Name()
+	stream, ok := h.streams[exchange]
+	if !ok {
+		h.poll(ctx, asset)
+		return
+	}
+
+	tickers, err := stream.SubscribeTicker(ctx, asset.Pair)
+	if err != nil {
+		log.Printf("Warning: failed to subscribe to %s tickers on %s: %v", asset.Pair, exchange, err)
+		return
+	}
+	for ticker := range tickers {
+		h.publish(asset, &ticker)
+	}
+}
+
+// poll publishes the price of one cryptocurrency every poll interval while it has subscribers
+func (h *PriceStreamHub) poll(ctx context.Context, asset model.Asset) {
+	interval := time.NewTicker(h.pollInterval)
+	defer interval.Stop()
+
+	for {
+		if h.hasSubscribers(asset.ID) {
+			ticker, err := h.exchangeClient.GetTicker(ctx, asset.Pair)
+			if err == nil {
+				h.publish(asset, ticker)
+			} else if ctx.Err() == nil {
+				log.Printf("Warning: failed to poll price of %s: %v", asset.Pair, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-interval.C:
+		}
+	}
+}
+
+// publish records the latest price of a cryptocurrency and sends it to its subscribers
+func (h *PriceStreamHub) publish(asset model.Asset, ticker *model.Ticker) {
+	update := generated.PriceUpdate{
+		Id:        asset.ID,
+		Symbol:    asset.Code(),
+		Pair:      string(asset.Pair),
+		Price:     ticker.Last,
+		Bid:       ticker.Bid,
+		Ask:       ticker.Ask,
+		Timestamp: ticker.Timestamp.UnixMilli(),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.latest[asset.ID] = update
+	for sub := range h.subscribers {
+		if sub.wants(asset.ID) {
+			sendPriceUpdate(sub.updates, update)
+		}
+	}
+}
+
+func (h *PriceStreamHub) hasSubscribers(id string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		if sub.wants(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribe delivers the price updates of the given cryptocurrency IDs (every listed one if empty)
+func (h *PriceStreamHub) Subscribe(ctx context.Context, ids []string) (<-chan generated.PriceUpdate, error) {
+	sub := &priceSubscriber{updates: make(chan generated.PriceUpdate, 16)}
+	for _, id := range ids {
+		if _, ok := h.assets.ByID(id); !ok {
+			return nil, invalid(ErrInvalidRequest, "unknown cryptocurrency: %s", id)
+		}
+		if sub.ids == nil {
+			sub.ids = map[string]bool{}
+		}
+		sub.ids[id] = true
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.stopped {
+		return nil, client.ErrStreamClosed
+	}
+	for _, asset := range h.assets.Assets() {
+		if update, ok := h.latest[asset.ID]; ok && sub.wants(asset.ID) {
+			sendPriceUpdate(sub.updates, update)
+		}
+	}
+	h.subscribers[sub] = struct{}{}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			h.unsubscribe(sub)
+		case <-h.done:
+		}
+	}()
+	return sub.updates, nil
+}
+
+func (h *PriceStreamHub) unsubscribe(sub *priceSubscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.updates)
+	}
+}
+
+// sendPriceUpdate sends an update without blocking, dropping the oldest buffered update of a slow
+// subscriber. Updates are only sent with h.mu held, so the loop ends as soon as a slot has been freed.
+func sendPriceUpdate(updates chan generated.PriceUpdate, update generated.PriceUpdate) {
+	for {
+		select {
+		case updates <- update:
+			return
+		default:
+		}
+		select {
+		case <-updates:
+		default:
+		}
+	}
+}
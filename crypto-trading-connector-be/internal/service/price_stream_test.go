@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// fakeMarketDataStream pushes the tickers sent to it by the test
+type fakeMarketDataStream struct {
+	tickers chan model.Ticker
+}
+
+func (s *fakeMarketDataStream) SubscribeTicker(ctx context.Context, symbol model.Symbol) (<-chan model.Ticker, error) {
+	return s.tickers, nil
+}
+
+func (s *fakeMarketDataStream) SubscribeExecutions(ctx context.Context, symbol model.Symbol) (<-chan []model.Execution, error) {
+	return nil, client.ErrNotSupported
+}
+
+func (s *fakeMarketDataStream) SubscribeOrderBook(ctx context.Context, symbol model.Symbol, depth int) (<-chan model.OrderBook, error) {
+	return nil, client.ErrNotSupported
+}
+
+func (s *fakeMarketDataStream) OrderBook(symbol model.Symbol, depth int) (*model.OrderBook, bool) {
+	return nil, false
+}
+
+func receivePrice(t *testing.T, updates <-chan generated.PriceUpdate) generated.PriceUpdate {
+	t.Helper()
+	select {
+	case update, ok := <-updates:
+		if !ok {
+			t.Fatal("expected a price update, got a closed channel")
+		}
+		return update
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a price update")
+	}
+	return generated.PriceUpdate{}
+}
+
+func TestPriceStreamHub(t *testing.T) {
+	// BTC/JPY is streamed from bitFlyer; ETH/JPY is routed to GMO Coin and polled
+	var polls atomic.Int32
+	gmocoin := &client.MockBitFlyerClient{
+		NameFunc: func() string { return "gmocoin" },
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			polls.Add(1)
+			return &model.Ticker{Symbol: symbol, Last: 500000, Timestamp: time.UnixMilli(1704067200000)}, nil
+		},
+	}
+	router, err := client.NewExchangeRouter(map[string]client.CryptoExchangeClient{
+		"bitflyer": &client.MockBitFlyerClient{},
+		"gmocoin":  gmocoin,
+	}, map[string]string{"ETH_JPY": "gmocoin"}, "bitflyer")
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	stream := &fakeMarketDataStream{tickers: make(chan model.Ticker)}
+	hub := NewPriceStreamHub(router, map[string]client.MarketDataStream{"bitflyer": stream}, model.DefaultAssetCatalog(), 10*time.Millisecond)
+
+	ctx, stop := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		hub.Run(ctx)
+		close(stopped)
+	}()
+
+	if _, err := hub.Subscribe(context.Background(), []string{"dogecoin"}); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for an unknown ID, got %v", err)
+	}
+
+	// Nobody follows ETH/JPY yet, so it is not polled
+	time.Sleep(30 * time.Millisecond)
+	if polls.Load() != 0 {
+		t.Errorf("expected no polling without subscribers, got %d polls", polls.Load())
+	}
+
+	bitcoin, err := hub.Subscribe(context.Background(), []string{"bitcoin"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	all, err := hub.Subscribe(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// One upstream ticker reaches every subscriber of the pair
+	stream.tickers <- model.Ticker{Symbol: "BTC/JPY", Last: 15000000, Bid: 14999000, Ask: 15001000, Timestamp: time.UnixMilli(1704067200123)}
+	update := receivePrice(t, bitcoin)
+	want := generated.PriceUpdate{Id: "bitcoin", Symbol: "BTC", Pair: "BTC/JPY", Price: 15000000, Bid: 14999000, Ask: 15001000, Timestamp: 1704067200123}
+	if update != want {
+		t.Errorf("expected %+v, got %+v", want, update)
+	}
+
+	seen := map[string]bool{}
+	for len(seen) < 2 {
+		seen[receivePrice(t, all).Id] = true
+	}
+
+	// A late subscriber starts with the latest price
+	late, err := hub.Subscribe(context.Background(), []string{"bitcoin"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if update := receivePrice(t, late); update.Price != 15000000 {
+		t.Errorf("expected the latest price, got %+v", update)
+	}
+
+	// Stopping the hub closes every subscription
+	stop()
+	close(stream.tickers)
+	<-stopped
+	for range bitcoin {
+	}
+	if _, err := hub.Subscribe(context.Background(), nil); !errors.Is(err, client.ErrStreamClosed) {
+		t.Errorf("expected ErrStreamClosed, got %v", err)
+	}
+}
+
+func TestPriceStreamHub_Unsubscribe(t *testing.T) {
+	stream := &fakeMarketDataStream{tickers: make(chan model.Ticker)}
+	hub := NewPriceStreamHub(&client.MockBitFlyerClient{}, map[string]client.MarketDataStream{"bitflyer": stream}, model.DefaultAssetCatalog(), time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates, err := hub.Subscribe(ctx, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Error("expected no update")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the channel to be closed after the subscriber left")
+	}
+	if hub.hasSubscribers("bitcoin") {
+		t.Error("expected the subscriber to be removed")
+	}
+}
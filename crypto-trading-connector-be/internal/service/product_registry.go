@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// productRetryInterval is how often Run retries to load the products while the registry is empty
+const productRetryInterval = 30 * time.Second
+
+// ProductRegistry holds the trading rules (tick size, lot step, minimum size and price precision)
+// of every tradable product. Pairs missing from the registry are rejected as unsupported.
+type ProductRegistry struct {
+	mu       sync.RWMutex
+	products map[model.Symbol]model.Product
+}
+
+// productOverride is an entry of the product override file; unset fields keep the exchange value
+type productOverride struct {
+	TickSize       *float64 `yaml:"tick_size"`
+	LotStep        *float64 `yaml:"lot_step"`
+	MinSize        *float64 `yaml:"min_size"`
+	PricePrecision *int     `yaml:"price_precision"`
+}
+
+// NewProductRegistry creates a product registry from a list of products
+func NewProductRegistry(products []model.Product) *ProductRegistry {
+	registry := &ProductRegistry{products: make(map[model.Symbol]model.Product, len(products))}
+	for _, product := range products {
+		registry.products[product.Symbol] = product
+	}
+	return registry
+}
+
+// LoadProductRegistry loads the products of the exchange and applies the override file at
+// overridePath, if any. The override file is YAML keyed by pair, e.g.
+//
+//	BTC/JPY:
+//	  min_size: 0.01
+//	XRP/JPY:
+//	  tick_size: 0.001
+//	  lot_step: 0.000001
+//	  min_size: 0.1
+//
+// A pair the exchange does not report is added when tick_size, lot_step and min_size are all set.
+// Only the pairs of the asset catalog are kept, and every one of them must have trading rules.
+func LoadProductRegistry(ctx context.Context, exchangeClient client.CryptoExchangeClient, assets *model.AssetCatalog, overridePath string) (*ProductRegistry, error) {
+	registry := NewProductRegistry(nil)
+	if err := registry.Refresh(ctx, exchangeClient, assets, overridePath); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// Refresh reloads the products the way LoadProductRegistry does. The registry is left unchanged on failure.
+func (r *ProductRegistry) Refresh(ctx context.Context, exchangeClient client.CryptoExchangeClient, assets *model.AssetCatalog, overridePath string) error {
+	products, err := exchangeClient.GetProducts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get products from exchange: %w", err)
+	}
+	loaded := NewProductRegistry(products)
+
+	if err := loaded.applyOverrideFile(exchangeClient, overridePath); err != nil {
+		return err
+	}
+
+	listed := make(map[model.Symbol]model.Product, len(assets.Assets()))
+	for _, asset := range assets.Assets() {
+		product, ok := loaded.products[asset.Pair]
+		if !ok {
+			return fmt.Errorf("no trading rules for %s: the exchange does not report it, add it to the product override file", asset.Pair)
+		}
+		listed[asset.Pair] = product
+	}
+
+	r.mu.Lock()
+	r.products = listed
+	r.mu.Unlock()
+	return nil
+}
+
+// Run refreshes the products every interval until ctx is done. While the registry is empty, e.g. because
+// the exchange could not be reached at startup, it retries every productRetryInterval instead.
+func (r *ProductRegistry) Run(ctx context.Context, exchangeClient client.CryptoExchangeClient, assets *model.AssetCatalog, overridePath string, interval time.Duration) {
+	for {
+		wait := interval
+		empty := len(r.Products()) == 0
+		if empty {
+			wait = productRetryInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		if err := r.Refresh(ctx, exchangeClient, assets, overridePath); err != nil {
+			log.Printf("Warning: failed to refresh products: %v", err)
+			continue
+		}
+		if empty {
+			log.Printf("Products loaded (%d pairs)", len(r.Products()))
+		}
+	}
+}
+
+// applyOverrideFile applies the override file at overridePath, if any
+func (r *ProductRegistry) applyOverrideFile(exchangeClient client.CryptoExchangeClient, overridePath string) error {
+	if overridePath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(overridePath)
+	if err != nil {
+		return fmt.Errorf("failed to read product override file: %w", err)
+	}
+
+	var overrides map[string]productOverride
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return fmt.Errorf("failed to parse product override file: %w", err)
+	}
+
+	for pair, override := range overrides {
+		symbol := model.SymbolFromProductCode(strings.ToUpper(pair))
+		if err := r.applyOverride(symbol, override, client.ForSymbol(exchangeClient, symbol).Name()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyOverride merges an override file entry into the product of the symbol
+func (r *ProductRegistry) applyOverride(symbol model.Symbol, override productOverride, exchange string) error {
+	product, ok := r.products[symbol]
+	if !ok {
+		if override.TickSize == nil || override.LotStep == nil || override.MinSize == nil {
+			return fmt.Errorf("invalid product override for %s: tick_size, lot_step and min_size are required for a pair the exchange does not report", symbol)
+		}
+		product = model.Product{Symbol: symbol, Exchange: exchange}
+	}
+
+	if override.TickSize != nil {
+		product.TickSize = *override.TickSize
+		product.PricePrecision = model.DecimalPlaces(product.TickSize)
+	}
+	if override.LotStep != nil {
+		product.LotStep = *override.LotStep
+	}
+	if override.MinSize != nil {
+		product.MinSize = *override.MinSize
+	}
+	if override.PricePrecision != nil {
+		product.PricePrecision = *override.PricePrecision
+	}
+
+	if product.TickSize <= 0 || product.LotStep <= 0 || product.MinSize <= 0 || product.PricePrecision < 0 {
+		return fmt.Errorf("invalid product override for %s: sizes must be greater than 0", symbol)
+	}
+
+	r.products[symbol] = product
+	return nil
+}
+
+// Get returns the product of a pair
+func (r *ProductRegistry) Get(symbol model.Symbol) (model.Product, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	product, ok := r.products[symbol]
+	return product, ok
+}
+
+// Products returns every product ordered by pair
+func (r *ProductRegistry) Products() []model.Product {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	products := make([]model.Product, 0, len(r.products))
+	for _, product := range r.products {
+		products = append(products, product)
+	}
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].Symbol < products[j].Symbol
+	})
+	return products
+}
+
+// ValidateSize checks an order size against the minimum size and lot step of the pair
+func (r *ProductRegistry) ValidateSize(symbol model.Symbol, size float64) error {
+	product, ok := r.Get(symbol)
+	if !ok {
+		return invalid(ErrUnsupportedPair, "unsupported pair: %s", symbol)
+	}
+	if size < product.MinSize {
+		return invalid(ErrInvalidAmount, "invalid amount for %s: minimum is %g", symbol, product.MinSize)
+	}
+	if !product.IsLotMultiple(size) {
+		return invalid(ErrInvalidAmount, "invalid amount for %s: must be a multiple of %g", symbol, product.LotStep)
+	}
+	return nil
+}
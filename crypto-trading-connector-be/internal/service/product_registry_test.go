@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// testProductRegistry returns the bitFlyer rules of BTC/JPY and ETH/JPY
+func testProductRegistry() *ProductRegistry {
+	return NewProductRegistry([]model.Product{
+		{Symbol: "BTC/JPY", Exchange: "bitflyer", TickSize: 1, LotStep: 0.00000001, MinSize: 0.001},
+		{Symbol: "ETH/JPY", Exchange: "bitflyer", TickSize: 1, LotStep: 0.00000001, MinSize: 0.01},
+	})
+}
+
+func TestLoadProductRegistry_AppliesOverrides(t *testing.T) {
+	overridePath := filepath.Join(t.TempDir(), "products.yaml")
+	err := os.WriteFile(overridePath, []byte(`
+BTC/JPY:
+  min_size: 0.01
+xrp_jpy:
+  tick_size: 0.001
+  lot_step: 0.000001
+  min_size: 0.1
+`), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write override file: %v", err)
+	}
+
+	assets, err := model.NewAssetCatalog([]model.Asset{
+		{ID: "bitcoin", Name: "Bitcoin", Pair: "BTC/JPY"},
+		{ID: "ethereum", Name: "Ethereum", Pair: "ETH/JPY"},
+		{ID: "ripple", Name: "XRP", Pair: "XRP/JPY"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create asset catalog: %v", err)
+	}
+
+	registry, err := LoadProductRegistry(context.Background(), &client.MockBitFlyerClient{}, assets, overridePath)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	products := registry.Products()
+	if len(products) != 3 || products[0].Symbol != "BTC/JPY" || products[2].Symbol != "XRP/JPY" {
+		t.Fatalf("unexpected products: %+v", products)
+	}
+
+	btc, _ := registry.Get("BTC/JPY")
+	if btc.MinSize != 0.01 || btc.TickSize != 1 || btc.Exchange != "bitflyer" {
+		t.Errorf("expected only the minimum size of BTC/JPY to be overridden, got %+v", btc)
+	}
+
+	xrp, _ := registry.Get("XRP/JPY")
+	expected := model.Product{Symbol: "XRP/JPY", Exchange: "bitflyer", TickSize: 0.001, LotStep: 0.000001, MinSize: 0.1, PricePrecision: 3}
+	if xrp != expected {
+		t.Errorf("expected %+v, got %+v", expected, xrp)
+	}
+}
+
+func TestLoadProductRegistry_KeepsListedAssetsOnly(t *testing.T) {
+	assets, err := model.NewAssetCatalog([]model.Asset{{ID: "bitcoin", Name: "Bitcoin", Pair: "BTC/JPY"}})
+	if err != nil {
+		t.Fatalf("failed to create asset catalog: %v", err)
+	}
+
+	registry, err := LoadProductRegistry(context.Background(), &client.MockBitFlyerClient{}, assets, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// ETH/JPY is reported by the exchange but not listed, so orders for it are rejected
+	if err := registry.ValidateSize("ETH/JPY", 0.01); err == nil || err.Error() != "unsupported pair: ETH/JPY" {
+		t.Errorf("expected unsupported pair error, got %v", err)
+	}
+}
+
+func TestLoadProductRegistry_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		override string
+		client   *client.MockBitFlyerClient
+		expected string
+	}{
+		{
+			name:     "exchange error",
+			client:   &client.MockBitFlyerClient{GetProductsFunc: func(ctx context.Context) ([]model.Product, error) { return nil, errors.New("timeout") }},
+			expected: "failed to get products from exchange",
+		},
+		{
+			name:     "incomplete new pair",
+			override: "XRP/JPY:\n  min_size: 0.1\n",
+			client:   &client.MockBitFlyerClient{},
+			expected: "invalid product override for XRP/JPY",
+		},
+		{
+			name:     "listed asset without trading rules",
+			client:   &client.MockBitFlyerClient{GetProductsFunc: func(ctx context.Context) ([]model.Product, error) { return nil, nil }},
+			expected: "no trading rules for BTC/JPY",
+		},
+		{
+			name:     "non-positive size",
+			override: "BTC/JPY:\n  tick_size: 0\n",
+			client:   &client.MockBitFlyerClient{},
+			expected: "invalid product override for BTC/JPY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overridePath := ""
+			if tt.override != "" {
+				overridePath = filepath.Join(t.TempDir(), "products.yaml")
+				if err := os.WriteFile(overridePath, []byte(tt.override), 0o600); err != nil {
+					t.Fatalf("failed to write override file: %v", err)
+				}
+			}
+
+			_, err := LoadProductRegistry(context.Background(), tt.client, model.DefaultAssetCatalog(), overridePath)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestProductRegistry_Refresh(t *testing.T) {
+	// The server starts with an empty registry when the exchange cannot be reached
+	registry := NewProductRegistry(nil)
+	service := NewOrderService(&client.MockBitFlyerClient{}, &MockOrderRepository{}, registry)
+	req := &generated.CreateOrderRequest{
+		Pair:      "BTC/JPY",
+		OrderType: generated.CreateOrderRequestOrderTypeLimit,
+		Price:     14000000,
+		Amount:    0.001,
+	}
+	if _, err := service.CreateOrder(context.Background(), nil, req); !errors.Is(err, ErrUnsupportedPair) {
+		t.Fatalf("expected ErrUnsupportedPair before the products are loaded, got %v", err)
+	}
+
+	unreachable := &client.MockBitFlyerClient{GetProductsFunc: func(ctx context.Context) ([]model.Product, error) {
+		return nil, errors.New("timeout")
+	}}
+	if err := registry.Refresh(context.Background(), unreachable, model.DefaultAssetCatalog(), ""); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if err := registry.Refresh(context.Background(), &client.MockBitFlyerClient{}, model.DefaultAssetCatalog(), ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := service.CreateOrder(context.Background(), nil, req); err != nil {
+		t.Fatalf("expected no error once the products are loaded, got %v", err)
+	}
+
+	// A failed refresh keeps the products loaded before
+	if err := registry.Refresh(context.Background(), unreachable, model.DefaultAssetCatalog(), ""); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, ok := registry.Get("BTC/JPY"); !ok {
+		t.Error("expected BTC/JPY to be kept after a failed refresh")
+	}
+}
+
+func TestProductRegistry_ValidateSize(t *testing.T) {
+	registry := testProductRegistry()
+
+	tests := []struct {
+		symbol   model.Symbol
+		size     float64
+		expected string
+	}{
+		{"BTC/JPY", 0.001, ""},
+		{"BTC/JPY", 0.00123456, ""},
+		{"BTC/JPY", 0.0009, "invalid amount for BTC/JPY: minimum is 0.001"},
+		{"BTC/JPY", 0.001000001, "invalid amount for BTC/JPY: must be a multiple of 1e-08"},
+		{"XRP/JPY", 1, "unsupported pair: XRP/JPY"},
+	}
+
+	for _, tt := range tests {
+		err := registry.ValidateSize(tt.symbol, tt.size)
+		if tt.expected == "" && err != nil {
+			t.Errorf("ValidateSize(%s, %v): expected no error, got %v", tt.symbol, tt.size, err)
+		}
+		if tt.expected != "" && (err == nil || err.Error() != tt.expected) {
+			t.Errorf("ValidateSize(%s, %v): expected %q, got %v", tt.symbol, tt.size, tt.expected, err)
+		}
+	}
+}
+
+func TestProduct_RoundPrice(t *testing.T) {
+	yen := model.Product{TickSize: 1}
+	fine := model.Product{TickSize: 0.001, PricePrecision: 3}
+	coarse := model.Product{TickSize: 5}
+
+	tests := []struct {
+		product  model.Product
+		price    float64
+		side     model.OrderSide
+		expected float64
+	}{
+		{yen, 14000000.7, model.OrderSideBuy, 14000000},
+		{yen, 14000000.2, model.OrderSideSell, 14000001},
+		{yen, 14000000, model.OrderSideSell, 14000000},
+		{fine, 80.1239, model.OrderSideBuy, 80.123},
+		{fine, 80.1231, model.OrderSideSell, 80.124},
+		// 0.1 + 0.2 is not exactly 0.3 in floating point
+		{fine, 0.1 + 0.2, model.OrderSideSell, 0.3},
+		{coarse, 1003, model.OrderSideBuy, 1000},
+		{coarse, 1003, model.OrderSideSell, 1005},
+	}
+
+	for _, tt := range tests {
+		if got := tt.product.RoundPrice(tt.price, tt.side); got != tt.expected {
+			t.Errorf("RoundPrice(%v, %s) with tick %v: expected %v, got %v", tt.price, tt.side, tt.product.TickSize, tt.expected, got)
+		}
+	}
+}
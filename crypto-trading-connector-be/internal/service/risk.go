@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+	"github.com/crypto-trading-connector/backend/utils"
+)
+
+// RiskOrder is an order checked by the risk engine before it is sent
+type RiskOrder struct {
+	Symbol   model.Symbol
+	Side     model.OrderSide
+	Type     model.OrderType
+	Price    float64 // Limit price, or estimated price of a market order
+	Size     float64
+	Exchange client.CryptoExchangeClient // Exchange the pair is routed to
+}
+
+// Notional returns the value of the order in the quote currency
+func (o *RiskOrder) Notional() float64 {
+	return o.Price * o.Size
+}
+
+// RiskRule is a pre-trade check. Check returns a RiskError if the order breaks the rule,
+// or another error if the rule could not be checked; the order is not sent in both cases.
+type RiskRule interface {
+	Name() string
+	Check(ctx context.Context, order *RiskOrder) error
+}
+
+// QueuedRiskRule is a risk rule limiting what the orders placed add up to. It is checked in the queue of the
+// account by the OrderPipeline, with the orders being sent that are not recorded yet, so that orders sent at the
+// same time are counted against each other.
+type QueuedRiskRule interface {
+	RiskRule
+	CheckQueued(ctx context.Context, order *RiskOrder, inFlight []*RiskOrder) error
+}
+
+// RiskError is an order rejected by a risk rule. It unwraps to the domain error of the rule
+// (e.g. ErrRiskMaxNotional).
+type RiskError struct {
+	Rule    string
+	Kind    error
+	Message string
+}
+
+func (e *RiskError) Error() string {
+	return e.Message
+}
+
+func (e *RiskError) Unwrap() error {
+	return e.Kind
+}
+
+// rejected returns a RiskError of the given rule and kind with a formatted message
+func rejected(rule string, kind error, format string, args ...any) error {
+	return &RiskError{Rule: rule, Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// RiskEngine runs risk rules in order and stops at the first one the order breaks.
+// Every rejection is logged for audit.
+type RiskEngine struct {
+	rules  []RiskRule
+	queued []QueuedRiskRule
+}
+
+// NewRiskEngine creates a risk engine checking the given rules in order. Queued rules are left to CheckQueued.
+func NewRiskEngine(rules ...RiskRule) *RiskEngine {
+	engine := &RiskEngine{}
+	for _, rule := range rules {
+		if queued, ok := rule.(QueuedRiskRule); ok {
+			engine.queued = append(engine.queued, queued)
+		} else {
+			engine.rules = append(engine.rules, rule)
+		}
+	}
+	return engine
+}
+
+// Check runs the rules that do not depend on other orders against the order. It is called before the order
+// enters the OrderPipeline, which runs CheckQueued.
+func (e *RiskEngine) Check(ctx context.Context, order *RiskOrder) error {
+	for _, rule := range e.rules {
+		if err := rule.Check(ctx, order); err != nil {
+			return auditRejection(rule, order, err)
+		}
+	}
+	return nil
+}
+
+// CheckQueued runs the queued rules against the order, counting the orders being sent on its account
+func (e *RiskEngine) CheckQueued(ctx context.Context, order *RiskOrder, inFlight []*RiskOrder) error {
+	for _, rule := range e.queued {
+		if err := rule.CheckQueued(ctx, order, inFlight); err != nil {
+			return auditRejection(rule, order, err)
+		}
+	}
+	return nil
+}
+
+// auditRejection logs an order rejected by a rule and returns err
+func auditRejection(rule RiskRule, order *RiskOrder, err error) error {
+	var riskErr *RiskError
+	if errors.As(err, &riskErr) {
+		log.Printf("Audit: order rejected by risk rule %s: %s %s %s size=%g price=%g notional=%.0f: %v",
+			rule.Name(), order.Side, order.Type, order.Symbol, order.Size, order.Price, order.Notional(), err)
+	} else {
+		log.Printf("Audit: order rejected because risk rule %s could not be checked: %s %s %s size=%g price=%g: %v",
+			rule.Name(), order.Side, order.Type, order.Symbol, order.Size, order.Price, err)
+	}
+	return err
+}
+
+// RiskConfig holds the limits of the risk rules. Zero values disable a rule.
+type RiskConfig struct {
+	AllowedPairs      []model.Symbol     // Pairs that can be traded; empty allows every supported pair
+	MaxOrderNotional  float64            // Maximum value of an order in the quote currency
+	PriceBandPercent  float64            // Maximum distance of a limit price from the last price, in percent
+	MaxOpenOrders     int                // Maximum number of unfilled and partially filled orders on each exchange
+	MaxDailyBuyVolume map[string]float64 // Maximum size bought per day, keyed by product code or "*"
+}
+
+// NewRiskRules returns the rules enabled by the config, cheapest first
+func NewRiskRules(config RiskConfig, orderRepo repository.OrderRepository) []RiskRule {
+	var rules []RiskRule
+	if len(config.AllowedPairs) > 0 {
+		rules = append(rules, NewAllowedPairsRule(config.AllowedPairs))
+	}
+	if config.MaxOrderNotional > 0 {
+		rules = append(rules, NewMaxNotionalRule(config.MaxOrderNotional))
+	}
+	if config.PriceBandPercent > 0 {
+		rules = append(rules, NewPriceBandRule(config.PriceBandPercent))
+	}
+	if config.MaxOpenOrders > 0 {
+		rules = append(rules, NewMaxOpenOrdersRule(config.MaxOpenOrders, orderRepo))
+	}
+	if len(config.MaxDailyBuyVolume) > 0 {
+		rules = append(rules, NewDailyBuyVolumeRule(config.MaxDailyBuyVolume, orderRepo))
+	}
+	return rules
+}
+
+// ParseRiskPairLimits parses a comma-separated list of per-pair limits, e.g. "*=1,BTC_JPY=0.5"
+func ParseRiskPairLimits(spec string) (map[string]float64, error) {
+	limits := make(map[string]float64)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(entry, "=")
+		if !found || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid risk limit %q: expected PRODUCT_CODE=LIMIT", entry)
+		}
+
+		limit, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid risk limit %q: limit must be a positive number", entry)
+		}
+
+		limits[strings.TrimSpace(key)] = limit
+	}
+
+	return limits, nil
+}
+
+// LoadRiskConfigFromEnv reads the risk limits from the RISK_* environment variables, so that every process
+// sending orders checks the same limits. The allowed pairs must be listed in the asset catalog.
+func LoadRiskConfigFromEnv(assets *model.AssetCatalog) (RiskConfig, error) {
+	config := RiskConfig{}
+	var err error
+
+	for _, pair := range strings.Split(utils.GetEnv("RISK_ALLOWED_PAIRS", ""), ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		if _, ok := assets.ByPair(model.Symbol(pair)); !ok {
+			return config, fmt.Errorf("invalid RISK_ALLOWED_PAIRS: unsupported pair %q", pair)
+		}
+		config.AllowedPairs = append(config.AllowedPairs, model.Symbol(pair))
+	}
+	if config.MaxOrderNotional, err = strconv.ParseFloat(utils.GetEnv("RISK_MAX_ORDER_NOTIONAL", "0"), 64); err != nil || config.MaxOrderNotional < 0 {
+		return config, fmt.Errorf("invalid RISK_MAX_ORDER_NOTIONAL: %q", utils.GetEnv("RISK_MAX_ORDER_NOTIONAL", "0"))
+	}
+	if config.PriceBandPercent, err = strconv.ParseFloat(utils.GetEnv("RISK_PRICE_BAND_PERCENT", "20"), 64); err != nil || config.PriceBandPercent < 0 {
+		return config, fmt.Errorf("invalid RISK_PRICE_BAND_PERCENT: %q", utils.GetEnv("RISK_PRICE_BAND_PERCENT", "20"))
+	}
+	if config.MaxOpenOrders, err = strconv.Atoi(utils.GetEnv("RISK_MAX_OPEN_ORDERS", "0")); err != nil || config.MaxOpenOrders < 0 {
+		return config, fmt.Errorf("invalid RISK_MAX_OPEN_ORDERS: %q", utils.GetEnv("RISK_MAX_OPEN_ORDERS", "0"))
+	}
+	if config.MaxDailyBuyVolume, err = ParseRiskPairLimits(utils.GetEnv("RISK_MAX_DAILY_BUY_VOLUME", "")); err != nil {
+		return config, fmt.Errorf("invalid RISK_MAX_DAILY_BUY_VOLUME: %w", err)
+	}
+
+	return config, nil
+}
+
+// allowedPairsRule rejects pairs that are not allowed
+type allowedPairsRule struct {
+	pairs map[model.Symbol]bool
+}
+
+// NewAllowedPairsRule creates a rule allowing only the given pairs
+func NewAllowedPairsRule(pairs []model.Symbol) RiskRule {
+	rule := &allowedPairsRule{pairs: make(map[model.Symbol]bool)}
+	for _, pair := range pairs {
+		rule.pairs[pair] = true
+	}
+	return rule
+}
+
+func (r *allowedPairsRule) Name() string {
+	return "allowed_pairs"
+}
+
+func (r *allowedPairsRule) Check(ctx context.Context, order *RiskOrder) error {
+	if !r.pairs[order.Symbol] {
+		return rejected(r.Name(), ErrRiskPairNotAllowed, "%s is not allowed to be traded", order.Symbol)
+	}
+	return nil
+}
+
+// maxNotionalRule rejects orders worth more than a maximum
+type maxNotionalRule struct {
+	limit float64
+}
+
+// NewMaxNotionalRule creates a rule rejecting orders worth more than limit in the quote currency
+func NewMaxNotionalRule(limit float64) RiskRule {
+	return &maxNotionalRule{limit: limit}
+}
+
+func (r *maxNotionalRule) Name() string {
+	return "max_notional"
+}
+
+func (r *maxNotionalRule) Check(ctx context.Context, order *RiskOrder) error {
+	if notional := order.Notional(); notional > r.limit {
+		return rejected(r.Name(), ErrRiskMaxNotional, "order value %.0f %s exceeds the maximum of %.0f", notional, order.Symbol.Quote(), r.limit)
+	}
+	return nil
+}
+
+// priceBandRule rejects limit prices too far from the last price in the direction that loses money:
+// buys above it and sells below it
+type priceBandRule struct {
+	percent float64
+}
+
+// NewPriceBandRule creates a rule rejecting limit prices more than percent away from the last price
+func NewPriceBandRule(percent float64) RiskRule {
+	return &priceBandRule{percent: percent}
+}
+
+func (r *priceBandRule) Name() string {
+	return "price_band"
+}
+
+func (r *priceBandRule) Check(ctx context.Context, order *RiskOrder) error {
+	// Market orders are priced from the ticker already
+	if order.Type != model.OrderTypeLimit {
+		return nil
+	}
+
+	ticker, err := fetchOrderTicker(ctx, order.Exchange, order.Symbol)
+	if err != nil {
+		return err
+	}
+	if ticker.Last <= 0 {
+		return fmt.Errorf("failed to get ticker: no price available for %s", order.Symbol)
+	}
+
+	if order.Side == model.OrderSideBuy && order.Price > ticker.Last*(1+r.percent/100) {
+		return rejected(r.Name(), ErrRiskPriceBand, "buy price %g is more than %g%% above the last price %g", order.Price, r.percent, ticker.Last)
+	}
+	if order.Side == model.OrderSideSell && order.Price < ticker.Last*(1-r.percent/100) {
+		return rejected(r.Name(), ErrRiskPriceBand, "sell price %g is more than %g%% below the last price %g", order.Price, r.percent, ticker.Last)
+	}
+	return nil
+}
+
+// maxOpenOrdersRule rejects orders once the number of open orders reaches a maximum
+type maxOpenOrdersRule struct {
+	limit     int
+	orderRepo repository.OrderRepository
+}
+
+// NewMaxOpenOrdersRule creates a rule allowing at most limit unfilled and partially filled orders on each exchange.
+// The limit is per exchange, like the queues of the OrderPipeline that count the orders being sent.
+func NewMaxOpenOrdersRule(limit int, orderRepo repository.OrderRepository) RiskRule {
+	return &maxOpenOrdersRule{limit: limit, orderRepo: orderRepo}
+}
+
+func (r *maxOpenOrdersRule) Name() string {
+	return "max_open_orders"
+}
+
+func (r *maxOpenOrdersRule) Check(ctx context.Context, order *RiskOrder) error {
+	return r.CheckQueued(ctx, order, nil)
+}
+
+// CheckQueued counts the orders being sent on the exchange of the order as open, since they are recorded as open
+// once sent
+func (r *maxOpenOrdersRule) CheckQueued(ctx context.Context, order *RiskOrder, inFlight []*RiskOrder) error {
+	open, err := r.orderRepo.ListOpenOrders(ctx)
+	if err != nil {
+		return err
+	}
+
+	exchange := ""
+	if order.Exchange != nil {
+		exchange = order.Exchange.Name()
+	}
+	count := len(inFlight)
+	for _, openOrder := range open {
+		// Orders recorded without an exchange count against every exchange
+		if openOrder.Exchange == "" || exchange == "" || openOrder.Exchange == exchange {
+			count++
+		}
+	}
+	if count >= r.limit {
+		return rejected(r.Name(), ErrRiskMaxOpenOrders, "%d orders are open on %s, the maximum is %d", count, exchange, r.limit)
+	}
+	return nil
+}
+
+// dailyBuyVolumeRule rejects buy orders that would take the size bought today over a maximum
+type dailyBuyVolumeRule struct {
+	limits    map[string]float64
+	orderRepo repository.OrderRepository
+	now       func() time.Time
+}
+
+// NewDailyBuyVolumeRule creates a rule limiting the size bought per day (local time) for each pair.
+// Limits are keyed by product code, or "*" for pairs without their own limit.
+func NewDailyBuyVolumeRule(limits map[string]float64, orderRepo repository.OrderRepository) RiskRule {
+	return &dailyBuyVolumeRule{limits: limits, orderRepo: orderRepo, now: time.Now}
+}
+
+func (r *dailyBuyVolumeRule) Name() string {
+	return "daily_buy_volume"
+}
+
+func (r *dailyBuyVolumeRule) Check(ctx context.Context, order *RiskOrder) error {
+	return r.CheckQueued(ctx, order, nil)
+}
+
+// CheckQueued adds the buy orders of the pair being sent to the size bought today
+func (r *dailyBuyVolumeRule) CheckQueued(ctx context.Context, order *RiskOrder, inFlight []*RiskOrder) error {
+	if order.Side != model.OrderSideBuy {
+		return nil
+	}
+
+	productCode := order.Symbol.ProductCode()
+	limit, ok := r.limits[productCode]
+	if !ok {
+		if limit, ok = r.limits["*"]; !ok {
+			return nil
+		}
+	}
+
+	now := r.now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	bought, err := r.orderRepo.SumBuyOrderSize(ctx, productCode, startOfDay)
+	if err != nil {
+		return err
+	}
+	for _, other := range inFlight {
+		if other.Side == model.OrderSideBuy && other.Symbol == order.Symbol {
+			bought += other.Size
+		}
+	}
+	if bought+order.Size > limit {
+		return rejected(r.Name(), ErrRiskDailyVolume, "buying %g %s would exceed the daily maximum of %g (bought today: %g)",
+			order.Size, order.Symbol.Base(), limit, bought)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+func TestRiskRules(t *testing.T) {
+	exchangeClient := &client.MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			return &model.Ticker{Symbol: symbol, Last: 10000000}, nil
+		},
+	}
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.Local)
+	var since time.Time
+	orderRepo := &MockOrderRepository{
+		ListOpenOrdersFunc: func(ctx context.Context) ([]model.OrderRecord, error) {
+			// The orders on other exchanges do not count, those recorded without an exchange count everywhere
+			return []model.OrderRecord{{Exchange: "bitflyer"}, {Exchange: "bitflyer"}, {}, {Exchange: "gmocoin"}, {Exchange: "gmocoin"}}, nil
+		},
+		SumBuyOrderSizeFunc: func(ctx context.Context, productCode string, from time.Time) (float64, error) {
+			since = from
+			return 0.4, nil
+		},
+	}
+	daily := NewDailyBuyVolumeRule(map[string]float64{"BTC_JPY": 0.5, "*": 10}, orderRepo)
+	daily.(*dailyBuyVolumeRule).now = func() time.Time { return now }
+
+	tests := []struct {
+		name    string
+		rule    RiskRule
+		order   RiskOrder
+		wantErr error
+	}{
+		{"allowed pair", NewAllowedPairsRule([]model.Symbol{"BTC/JPY"}), RiskOrder{Symbol: "BTC/JPY"}, nil},
+		{"pair not allowed", NewAllowedPairsRule([]model.Symbol{"BTC/JPY"}), RiskOrder{Symbol: "ETH/JPY"}, ErrRiskPairNotAllowed},
+		{"notional within limit", NewMaxNotionalRule(100000), RiskOrder{Symbol: "BTC/JPY", Price: 10000000, Size: 0.01}, nil},
+		{"notional over limit", NewMaxNotionalRule(100000), RiskOrder{Symbol: "BTC/JPY", Price: 10000000, Size: 0.02}, ErrRiskMaxNotional},
+		{"buy within band", NewPriceBandRule(20), RiskOrder{Symbol: "BTC/JPY", Side: model.OrderSideBuy, Type: model.OrderTypeLimit, Price: 12000000}, nil},
+		{"buy above band", NewPriceBandRule(20), RiskOrder{Symbol: "BTC/JPY", Side: model.OrderSideBuy, Type: model.OrderTypeLimit, Price: 12000001}, ErrRiskPriceBand},
+		{"buy far below", NewPriceBandRule(20), RiskOrder{Symbol: "BTC/JPY", Side: model.OrderSideBuy, Type: model.OrderTypeLimit, Price: 1000000}, nil},
+		{"sell below band", NewPriceBandRule(20), RiskOrder{Symbol: "BTC/JPY", Side: model.OrderSideSell, Type: model.OrderTypeLimit, Price: 7999999}, ErrRiskPriceBand},
+		{"market order", NewPriceBandRule(20), RiskOrder{Symbol: "BTC/JPY", Side: model.OrderSideBuy, Type: model.OrderTypeMarket, Price: 20000000}, nil},
+		{"open orders under limit", NewMaxOpenOrdersRule(4, orderRepo), RiskOrder{Symbol: "BTC/JPY"}, nil},
+		{"open orders at limit", NewMaxOpenOrdersRule(3, orderRepo), RiskOrder{Symbol: "BTC/JPY"}, ErrRiskMaxOpenOrders},
+		{"daily volume within limit", daily, RiskOrder{Symbol: "BTC/JPY", Side: model.OrderSideBuy, Size: 0.1}, nil},
+		{"daily volume over limit", daily, RiskOrder{Symbol: "BTC/JPY", Side: model.OrderSideBuy, Size: 0.2}, ErrRiskDailyVolume},
+		{"daily volume default limit", daily, RiskOrder{Symbol: "ETH/JPY", Side: model.OrderSideBuy, Size: 1}, nil},
+		{"daily volume of sells", daily, RiskOrder{Symbol: "BTC/JPY", Side: model.OrderSideSell, Size: 1}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.order.Exchange = exchangeClient
+			err := tt.rule.Check(context.Background(), &tt.order)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			var riskErr *RiskError
+			if tt.wantErr != nil && (!errors.As(err, &riskErr) || riskErr.Rule != tt.rule.Name()) {
+				t.Errorf("expected a RiskError of rule %s, got %#v", tt.rule.Name(), err)
+			}
+		})
+	}
+
+	if want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local); !since.Equal(want) {
+		t.Errorf("expected the daily volume to be counted since %s, got %s", want, since)
+	}
+}
+
+func TestPriceBandRule_StaleTicker(t *testing.T) {
+	exchangeClient := &client.MockBitFlyerClient{
+		GetTickerFunc: func(ctx context.Context, symbol model.Symbol) (*model.Ticker, error) {
+			return &model.Ticker{Symbol: symbol, Last: 10000000, Stale: true}, nil
+		},
+	}
+
+	// The band cannot be checked against a cached price while the exchange is unreachable
+	order := RiskOrder{Symbol: "BTC/JPY", Side: model.OrderSideBuy, Type: model.OrderTypeLimit, Price: 10000000, Exchange: exchangeClient}
+	if err := NewPriceBandRule(20).Check(context.Background(), &order); !errors.Is(err, client.ErrExchangeUnavailable) {
+		t.Fatalf("expected ErrExchangeUnavailable, got %v", err)
+	}
+}
+
+func TestOrderService_CreateOrder_RiskRejected(t *testing.T) {
+	sent := 0
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return jpyBalances(2000000), nil
+		},
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			sent++
+			return &model.OrderResponse{OrderID: "JRF20240101-000000-000001"}, nil
+		},
+	}
+	service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+	service.SetRiskEngine(NewRiskEngine(NewRiskRules(RiskConfig{MaxOrderNotional: 20000}, &MockOrderRepository{})...))
+
+	req := &generated.CreateOrderRequest{
+		Pair:      "BTC/JPY",
+		OrderType: generated.CreateOrderRequestOrderTypeLimit,
+		Price:     14000000,
+		Amount:    0.002,
+	}
+	if _, err := service.CreateOrder(context.Background(), nil, req); !errors.Is(err, ErrRiskMaxNotional) {
+		t.Fatalf("expected ErrRiskMaxNotional, got %v", err)
+	}
+	if sent != 0 {
+		t.Errorf("expected the rejected order not to be sent, got %d orders", sent)
+	}
+
+	req.Amount = 0.001
+	if _, err := service.CreateOrder(context.Background(), nil, req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sent != 1 {
+		t.Errorf("expected 1 order sent, got %d", sent)
+	}
+}
+
+func TestParseRiskPairLimits(t *testing.T) {
+	limits, err := ParseRiskPairLimits(" *=10, BTC_JPY=0.5 ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(limits) != 2 || limits["*"] != 10 || limits["BTC_JPY"] != 0.5 {
+		t.Errorf("unexpected limits %v", limits)
+	}
+
+	for _, spec := range []string{"BTC_JPY", "=1", "BTC_JPY=0", "BTC_JPY=abc"} {
+		if _, err := ParseRiskPairLimits(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+)
+
+// defaultTakeProfitMarkupPercent is used when no markup is configured for a buy order
+const defaultTakeProfitMarkupPercent = 1.0
+
+// TakeProfitConfig holds the markup (in percent) applied to the buy price of filled orders.
+// Markups are looked up by "PAIR:STRATEGY", "*:STRATEGY", "PAIR" and "*" in that order.
+type TakeProfitConfig struct {
+	Markups map[string]float64
+}
+
+// ParseTakeProfitMarkups parses a comma-separated list of markups,
+// e.g. "*=1.0,BTC_JPY=1.5,BTC_JPY:1=2.0"
+func ParseTakeProfitMarkups(spec string) (*TakeProfitConfig, error) {
+	config := &TakeProfitConfig{Markups: make(map[string]float64)}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(entry, "=")
+		if !found || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid take-profit markup %q: expected KEY=PERCENT", entry)
+		}
+
+		markup, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || markup <= 0 {
+			return nil, fmt.Errorf("invalid take-profit markup %q: percent must be a positive number", entry)
+		}
+
+		config.Markups[strings.TrimSpace(key)] = markup
+	}
+
+	return config, nil
+}
+
+// MarkupFor returns the markup percent for a buy order of the given product and strategy
+func (c *TakeProfitConfig) MarkupFor(productCode string, strategy int) float64 {
+	keys := []string{
+		fmt.Sprintf("%s:%d", productCode, strategy),
+		fmt.Sprintf("*:%d", strategy),
+		productCode,
+		"*",
+	}
+	for _, key := range keys {
+		if markup, ok := c.Markups[key]; ok {
+			return markup
+		}
+	}
+	return defaultTakeProfitMarkupPercent
+}
+
+// SellPrice returns the take-profit limit price for a buy order, rounded up to the tick size of the product
+func (c *TakeProfitConfig) SellPrice(buy *model.BuyOrder, product model.Product) float64 {
+	return product.RoundPrice(buy.Price*(1+c.MarkupFor(buy.ProductCode, buy.Strategy)/100), model.OrderSideSell)
+}
+
+// TakeProfitEngine places a limit sell order for every filled buy order.
+//
+// A buy order is claimed by moving it from FILLED to FILLED(SELL ORDER PENDING) with a
+// compare-and-set update, so only one worker ever places its sell order. Once the sell
+// order is recorded in sell_orders the buy order becomes FILLED(SELL ORDER PLACED).
+// Orders left PENDING by a crash are reconciled against sell_orders and the exchange
+// before anything is sent again.
+type TakeProfitEngine struct {
+	exchangeClient client.CryptoExchangeClient
+	orderRepo      repository.OrderRepository
+	config         *TakeProfitConfig
+	products       *ProductRegistry
+	pipeline       *OrderPipeline
+	riskEngine     *RiskEngine
+	mu             sync.Mutex
+}
+
+// NewTakeProfitEngine creates a new take-profit engine
+func NewTakeProfitEngine(exchangeClient client.CryptoExchangeClient, orderRepo repository.OrderRepository, config *TakeProfitConfig, products *ProductRegistry) *TakeProfitEngine {
+	return &TakeProfitEngine{
+		exchangeClient: exchangeClient,
+		orderRepo:      orderRepo,
+		config:         config,
+		products:       products,
+		pipeline:       NewOrderPipeline(orderRepo),
+	}
+}
+
+// SetOrderPipeline makes the engine reserve the funds of its sell orders in pipeline, shared with the other
+// services placing orders on the same accounts. By default the engine has a pipeline of its own.
+func (e *TakeProfitEngine) SetOrderPipeline(pipeline *OrderPipeline) {
+	e.pipeline = pipeline
+}
+
+// SetRiskEngine makes the engine check every sell order against the risk engine before sending it
+func (e *TakeProfitEngine) SetRiskEngine(engine *RiskEngine) {
+	e.riskEngine = engine
+}
+
+// Run places take-profit orders every interval until ctx is cancelled
+func (e *TakeProfitEngine) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		placed, err := e.ProcessOnce(ctx)
+		if err != nil {
+			log.Printf("Warning: take-profit processing failed: %v", err)
+		} else if placed > 0 {
+			log.Printf("Take-profit: placed %d sell order(s)", placed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessOnce places sell orders for filled buy orders and returns how many were placed
+func (e *TakeProfitEngine) ProcessOnce(ctx context.Context) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	placed := 0
+
+	// Finish orders interrupted by a previous run first
+	pending, err := e.orderRepo.ListBuyOrdersByStatus(ctx, model.OrderStatusSellOrderPending)
+	if err != nil {
+		return placed, fmt.Errorf("failed to list pending buy orders: %w", err)
+	}
+	for i := range pending {
+		sent, err := e.recover(ctx, &pending[i])
+		if err != nil {
+			log.Printf("Warning: failed to recover take-profit for buy order %s: %v", pending[i].OrderID, err)
+			continue
+		}
+		if sent {
+			placed++
+		}
+	}
+
+	filled, err := e.orderRepo.ListBuyOrdersByStatus(ctx, model.OrderStatusFilled)
+	if err != nil {
+		return placed, fmt.Errorf("failed to list filled buy orders: %w", err)
+	}
+	for i := range filled {
+		if ctx.Err() != nil {
+			return placed, ctx.Err()
+		}
+
+		buy := &filled[i]
+		claimed, err := e.orderRepo.TransitionOrderStatus(ctx, "BUY", buy.OrderID, model.OrderStatusFilled, model.OrderStatusSellOrderPending)
+		if err != nil {
+			log.Printf("Warning: failed to claim buy order %s: %v", buy.OrderID, err)
+			continue
+		}
+		if !claimed {
+			// Another worker got there first
+			continue
+		}
+
+		if err := e.placeSellOrder(ctx, buy); err != nil {
+			log.Printf("Warning: failed to place take-profit for buy order %s: %v", buy.OrderID, err)
+			continue
+		}
+		placed++
+	}
+
+	return placed, nil
+}
+
+// recover completes a buy order left in FILLED(SELL ORDER PENDING) and reports whether a new sell order was sent
+func (e *TakeProfitEngine) recover(ctx context.Context, buy *model.BuyOrder) (bool, error) {
+	existing, err := e.orderRepo.GetSellOrderByParentID(ctx, buy.OrderID)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
+		return false, e.markPlaced(ctx, buy)
+	}
+
+	// The sell order may have reached the exchange without being recorded
+	sent, err := e.findSentSellOrder(ctx, buy)
+	if err != nil {
+		return false, err
+	}
+	if sent != nil {
+		if err := e.orderRepo.SaveSellOrder(ctx, e.newSellOrder(buy, sent.OrderID, sent.Price)); err != nil {
+			return false, err
+		}
+		return false, e.markPlaced(ctx, buy)
+	}
+
+	if err := e.placeSellOrder(ctx, buy); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// sellPrice returns the take-profit limit price for a buy order
+func (e *TakeProfitEngine) sellPrice(buy *model.BuyOrder) float64 {
+	product, ok := e.products.Get(model.SymbolFromProductCode(buy.ProductCode))
+	if !ok {
+		// Pairs missing from the registry keep whole-yen prices
+		product = model.Product{TickSize: 1}
+	}
+	return e.config.SellPrice(buy, product)
+}
+
+// placeSellOrder sends the take-profit order for a claimed buy order and records it
+func (e *TakeProfitEngine) placeSellOrder(ctx context.Context, buy *model.BuyOrder) error {
+	price := e.sellPrice(buy)
+	req := &model.OrderRequest{
+		Symbol:      model.SymbolFromProductCode(buy.ProductCode),
+		Side:        model.OrderSideSell,
+		Type:        model.OrderTypeLimit,
+		TimeInForce: model.TimeInForceGTC,
+		Price:       price,
+		Size:        buy.Size,
+	}
+
+	exchangeClient := client.ForSymbol(e.exchangeClient, req.Symbol)
+	riskOrder := &RiskOrder{
+		Symbol:   req.Symbol,
+		Side:     req.Side,
+		Type:     req.Type,
+		Price:    req.Price,
+		Size:     req.Size,
+		Exchange: exchangeClient,
+	}
+	if e.riskEngine != nil {
+		if err := e.riskEngine.Check(ctx, riskOrder); err != nil {
+			// Nothing was sent, so the buy order is released and tried again on the next pass
+			e.releaseClaim(ctx, buy)
+			return err
+		}
+	}
+
+	release, err := e.pipeline.Reserve(ctx, exchangeClient, PipelineRequest{
+		Orders:     []*RiskOrder{riskOrder},
+		Funds:      []FundsReservation{orderReservation(string(req.Side), buy.ProductCode, req.Size, riskOrder.Notional())},
+		RiskEngine: e.riskEngine,
+	})
+	if err != nil {
+		e.releaseClaim(ctx, buy)
+		return err
+	}
+	defer release()
+
+	resp, err := exchangeClient.SendOrder(ctx, req)
+	if err != nil {
+		// The order may still have been accepted (e.g. on a timeout), so the buy order
+		// stays PENDING and the next pass checks the exchange before sending again
+		return fmt.Errorf("failed to send sell order to exchange: %w", err)
+	}
+
+	// If saving fails the buy order stays PENDING and is recovered from the exchange
+	if err := e.orderRepo.SaveSellOrder(ctx, e.newSellOrder(buy, resp.OrderID, price)); err != nil {
+		return err
+	}
+
+	return e.markPlaced(ctx, buy)
+}
+
+// findSentSellOrder looks for a recent unrecorded sell order on the exchange matching the take-profit for buy
+func (e *TakeProfitEngine) findSentSellOrder(ctx context.Context, buy *model.BuyOrder) (*model.ChildOrder, error) {
+	childOrders, err := e.exchangeClient.GetChildOrders(ctx, &model.ChildOrderQuery{
+		ProductCode: buy.ProductCode,
+		Count:       liveOrderLookupCount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent orders from exchange: %w", err)
+	}
+
+	price := e.sellPrice(buy)
+	for i, childOrder := range childOrders {
+		if childOrder.Side != model.OrderSideSell || !storedFloatEquals(childOrder.Size, buy.Size) || !storedFloatEquals(childOrder.Price, price) {
+			continue
+		}
+		// Skip sell orders that already belong to another buy order
+		if _, err := e.orderRepo.FindOrder(ctx, childOrder.OrderID); err == nil {
+			continue
+		} else if !errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, err
+		}
+		return &childOrders[i], nil
+	}
+
+	return nil, nil
+}
+
+// storedFloatEquals compares a price or size from the exchange with one read back from the FLOAT columns of
+// buy_orders/sell_orders, which keep single precision: the exchange holds the value that was sent, the column
+// its nearest float32
+func storedFloatEquals(exchangeValue, storedValue float64) bool {
+	return float32(exchangeValue) == float32(storedValue)
+}
+
+// markPlaced flips a buy order from FILLED(SELL ORDER PENDING) to FILLED(SELL ORDER PLACED)
+func (e *TakeProfitEngine) markPlaced(ctx context.Context, buy *model.BuyOrder) error {
+	if _, err := e.orderRepo.TransitionOrderStatus(ctx, "BUY", buy.OrderID, model.OrderStatusSellOrderPending, model.OrderStatusSellOrderPlaced); err != nil {
+		return err
+	}
+	return nil
+}
+
+// releaseClaim moves a buy order whose sell order was not sent back from FILLED(SELL ORDER PENDING) to FILLED
+func (e *TakeProfitEngine) releaseClaim(ctx context.Context, buy *model.BuyOrder) {
+	if _, err := e.orderRepo.TransitionOrderStatus(ctx, "BUY", buy.OrderID, model.OrderStatusSellOrderPending, model.OrderStatusFilled); err != nil {
+		// Left PENDING, the next pass finds no sell order on the exchange and tries again
+		log.Printf("Warning: failed to release buy order %s: %v", buy.OrderID, err)
+	}
+}
+
+// newSellOrder builds the sell_orders row for the take-profit of buy
+func (e *TakeProfitEngine) newSellOrder(buy *model.BuyOrder, orderID string, price float64) *model.SellOrder {
+	return &model.SellOrder{
+		ParentID:    buy.OrderID,
+		OrderID:     orderID,
+		ProductCode: buy.ProductCode,
+		Side:        "SELL",
+		Price:       price,
+		Size:        buy.Size,
+		Exchange:    buy.Exchange,
+		Status:      model.OrderStatusUnfilled,
+	}
+}
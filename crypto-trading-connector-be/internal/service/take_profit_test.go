@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+)
+
+func TestParseTakeProfitMarkups(t *testing.T) {
+	config, err := ParseTakeProfitMarkups("*=1.0, BTC_JPY=1.5,BTC_JPY:1=2.0,*:2=3")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		productCode string
+		strategy    int
+		expected    float64
+	}{
+		{"BTC_JPY", 1, 2.0},
+		{"BTC_JPY", 2, 3.0},
+		{"BTC_JPY", 99, 1.5},
+		{"ETH_JPY", 99, 1.0},
+	}
+	for _, tt := range tests {
+		if got := config.MarkupFor(tt.productCode, tt.strategy); got != tt.expected {
+			t.Errorf("MarkupFor(%s, %d): expected %v, got %v", tt.productCode, tt.strategy, tt.expected, got)
+		}
+	}
+
+	empty, err := ParseTakeProfitMarkups("")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := empty.MarkupFor("BTC_JPY", 99); got != defaultTakeProfitMarkupPercent {
+		t.Errorf("expected default markup, got %v", got)
+	}
+
+	for _, spec := range []string{"BTC_JPY", "BTC_JPY=abc", "=1.0", "BTC_JPY=-1"} {
+		if _, err := ParseTakeProfitMarkups(spec); err == nil {
+			t.Errorf("expected error for %q, got nil", spec)
+		}
+	}
+}
+
+func TestTakeProfitConfig_SellPrice(t *testing.T) {
+	config := &TakeProfitConfig{Markups: map[string]float64{"*": 1.5}}
+
+	price := config.SellPrice(&model.BuyOrder{ProductCode: "ETH_JPY", Price: 300001, Strategy: 99}, model.Product{TickSize: 1})
+
+	// 300001 * 1.015 = 304501.015, rounded up
+	if price != 304502 {
+		t.Errorf("expected 304502, got %v", price)
+	}
+
+	// Rounded up to the tick size of the product
+	price = config.SellPrice(&model.BuyOrder{ProductCode: "XRP_JPY", Price: 80.001, Strategy: 99}, model.Product{TickSize: 0.001, PricePrecision: 3})
+	if price != 81.202 {
+		t.Errorf("expected 81.202, got %v", price)
+	}
+}
+
+// takeProfitRepo is an in-memory buy order store for take-profit tests
+type takeProfitRepo struct {
+	*MockOrderRepository
+	statuses   map[string]string
+	sellOrders []*model.SellOrder
+}
+
+func newTakeProfitRepo(buys ...model.BuyOrder) *takeProfitRepo {
+	repo := &takeProfitRepo{MockOrderRepository: &MockOrderRepository{}, statuses: make(map[string]string)}
+	for _, buy := range buys {
+		repo.statuses[buy.OrderID] = buy.Status
+	}
+
+	repo.ListBuyOrdersFunc = func(ctx context.Context, status string) ([]model.BuyOrder, error) {
+		result := []model.BuyOrder{}
+		for _, buy := range buys {
+			if repo.statuses[buy.OrderID] == status {
+				buy.Status = status
+				result = append(result, buy)
+			}
+		}
+		return result, nil
+	}
+	repo.TransitionStatusFunc = func(ctx context.Context, side, orderID, fromStatus, toStatus string) (bool, error) {
+		if repo.statuses[orderID] != fromStatus {
+			return false, nil
+		}
+		repo.statuses[orderID] = toStatus
+		return true, nil
+	}
+	repo.SaveSellOrderFunc = func(ctx context.Context, order *model.SellOrder) error {
+		repo.sellOrders = append(repo.sellOrders, order)
+		return nil
+	}
+	repo.GetSellOrderFunc = func(ctx context.Context, parentID string) (*model.SellOrder, error) {
+		for _, order := range repo.sellOrders {
+			if order.ParentID == parentID {
+				return order, nil
+			}
+		}
+		return nil, nil
+	}
+	repo.FindOrderFunc = func(ctx context.Context, orderID string) (*model.OrderRecord, error) {
+		for _, order := range repo.sellOrders {
+			if order.OrderID == orderID {
+				return &model.OrderRecord{OrderID: orderID}, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %s", repository.ErrOrderNotFound, orderID)
+	}
+	return repo
+}
+
+func TestTakeProfitEngine_ProcessOnce(t *testing.T) {
+	repo := newTakeProfitRepo(
+		model.BuyOrder{OrderID: "JRF-BUY-1", ProductCode: "BTC_JPY", Price: 10000000, Size: 0.001, Exchange: "bitflyer", Status: "FILLED", Strategy: 1},
+		model.BuyOrder{OrderID: "JRF-BUY-2", ProductCode: "BTC_JPY", Price: 10000000, Size: 0.002, Exchange: "bitflyer", Status: "UNFILLED", Strategy: 1},
+	)
+
+	var sent []*model.OrderRequest
+	mockClient := &client.MockBitFlyerClient{
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			sent = append(sent, req)
+			return &model.OrderResponse{OrderID: "JRF-SELL-1"}, nil
+		},
+	}
+
+	config := &TakeProfitConfig{Markups: map[string]float64{"BTC_JPY:1": 2.0}}
+	engine := NewTakeProfitEngine(mockClient, repo, config, testProductRegistry())
+
+	placed, err := engine.ProcessOnce(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if placed != 1 || len(sent) != 1 {
+		t.Fatalf("expected exactly one sell order, placed=%d sent=%d", placed, len(sent))
+	}
+	if sent[0].Side != "SELL" || sent[0].Price != 10200000 || sent[0].Size != 0.001 || sent[0].Symbol != "BTC/JPY" {
+		t.Errorf("unexpected sell order request: %+v", sent[0])
+	}
+	if len(repo.sellOrders) != 1 || repo.sellOrders[0].ParentID != "JRF-BUY-1" || repo.sellOrders[0].OrderID != "JRF-SELL-1" {
+		t.Errorf("unexpected sell_orders rows: %+v", repo.sellOrders)
+	}
+	if repo.statuses["JRF-BUY-1"] != "FILLED(SELL ORDER PLACED)" {
+		t.Errorf("expected buy order to be marked as placed, got %s", repo.statuses["JRF-BUY-1"])
+	}
+	if repo.statuses["JRF-BUY-2"] != "UNFILLED" {
+		t.Errorf("expected unfilled buy order to be untouched, got %s", repo.statuses["JRF-BUY-2"])
+	}
+
+	// A second pass must not place another sell order
+	placed, err = engine.ProcessOnce(context.Background())
+	if err != nil || placed != 0 || len(sent) != 1 {
+		t.Errorf("expected no further sell orders, placed=%d sent=%d err=%v", placed, len(sent), err)
+	}
+}
+
+func TestTakeProfitEngine_RecoversPendingOrders(t *testing.T) {
+	tests := []struct {
+		name           string
+		recorded       bool
+		onExchange     bool
+		size           float64 // Size sent to the exchange; the buy order holds it as read from a FLOAT column
+		expectedSends  int
+		expectedSellID string
+	}{
+		{name: "sell order already recorded", recorded: true, size: 0.01, expectedSends: 0, expectedSellID: "JRF-SELL-DB"},
+		{name: "sell order sent but not recorded", onExchange: true, size: 0.01, expectedSends: 0, expectedSellID: "JRF-SELL-EX"},
+		{name: "sell order sent with a size rounded by the column", onExchange: true, size: 0.123456, expectedSends: 0, expectedSellID: "JRF-SELL-EX"},
+		{name: "sell order never sent", size: 0.01, expectedSends: 1, expectedSellID: "JRF-SELL-NEW"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTakeProfitRepo(
+				model.BuyOrder{OrderID: "JRF-BUY-1", ProductCode: "ETH_JPY", Price: 300000, Size: float64(float32(tt.size)), Exchange: "bitflyer", Status: "FILLED(SELL ORDER PENDING)", Strategy: 99},
+			)
+			if tt.recorded {
+				repo.sellOrders = append(repo.sellOrders, &model.SellOrder{ParentID: "JRF-BUY-1", OrderID: "JRF-SELL-DB"})
+			}
+
+			sends := 0
+			mockClient := &client.MockBitFlyerClient{
+				GetChildOrdersFunc: func(ctx context.Context, query *model.ChildOrderQuery) ([]model.ChildOrder, error) {
+					orders := []model.ChildOrder{
+						// Same size but a different price: not ours
+						{OrderID: "JRF-OTHER", Side: "SELL", Price: 310000, Size: tt.size},
+					}
+					if tt.onExchange {
+						orders = append(orders, model.ChildOrder{OrderID: "JRF-SELL-EX", Side: "SELL", Price: 303000, Size: tt.size})
+					}
+					return orders, nil
+				},
+				SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+					sends++
+					return &model.OrderResponse{OrderID: "JRF-SELL-NEW"}, nil
+				},
+			}
+
+			engine := NewTakeProfitEngine(mockClient, repo, &TakeProfitConfig{Markups: map[string]float64{}}, testProductRegistry())
+
+			if _, err := engine.ProcessOnce(context.Background()); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if sends != tt.expectedSends {
+				t.Errorf("expected %d sends, got %d", tt.expectedSends, sends)
+			}
+			sell, _ := repo.GetSellOrderByParentID(context.Background(), "JRF-BUY-1")
+			if sell == nil || sell.OrderID != tt.expectedSellID {
+				t.Errorf("expected sell order %s to be recorded, got %+v", tt.expectedSellID, sell)
+			}
+			if repo.statuses["JRF-BUY-1"] != "FILLED(SELL ORDER PLACED)" {
+				t.Errorf("expected buy order to be marked as placed, got %s", repo.statuses["JRF-BUY-1"])
+			}
+		})
+	}
+}
+
+func TestTakeProfitEngine_RiskRejectedReleasesClaim(t *testing.T) {
+	repo := newTakeProfitRepo(
+		model.BuyOrder{OrderID: "JRF-BUY-1", ProductCode: "BTC_JPY", Price: 10000000, Size: 0.001, Status: "FILLED", Strategy: 1},
+	)
+	sent := 0
+	mockClient := &client.MockBitFlyerClient{
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			sent++
+			return &model.OrderResponse{OrderID: "JRF-SELL-1"}, nil
+		},
+	}
+
+	config := &TakeProfitConfig{Markups: map[string]float64{"BTC_JPY:1": 2.0}}
+	engine := NewTakeProfitEngine(mockClient, repo, config, testProductRegistry())
+	// The sell order is worth 10,200 JPY
+	engine.SetRiskEngine(NewRiskEngine(NewRiskRules(RiskConfig{MaxOrderNotional: 10000}, &MockOrderRepository{})...))
+
+	placed, err := engine.ProcessOnce(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if placed != 0 || sent != 0 {
+		t.Errorf("expected no sell order to be sent, placed=%d sent=%d", placed, sent)
+	}
+	// Nothing reached the exchange, so the buy order is tried again on the next pass
+	if repo.statuses["JRF-BUY-1"] != "FILLED" {
+		t.Errorf("expected buy order to be released, got %s", repo.statuses["JRF-BUY-1"])
+	}
+}
+
+func TestTakeProfitEngine_InsufficientBalanceReleasesClaim(t *testing.T) {
+	repo := newTakeProfitRepo(
+		model.BuyOrder{OrderID: "JRF-BUY-1", ProductCode: "BTC_JPY", Price: 10000000, Size: 0.02, Status: "FILLED", Strategy: 1},
+	)
+	sent := 0
+	mockClient := &client.MockBitFlyerClient{
+		// Only 0.01 BTC of the 0.02 BTC bought are left to sell
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			sent++
+			return &model.OrderResponse{OrderID: "JRF-SELL-1"}, nil
+		},
+	}
+
+	config := &TakeProfitConfig{Markups: map[string]float64{"BTC_JPY:1": 2.0}}
+	engine := NewTakeProfitEngine(mockClient, repo, config, testProductRegistry())
+
+	placed, err := engine.ProcessOnce(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if placed != 0 || sent != 0 {
+		t.Errorf("expected no sell order to be sent, placed=%d sent=%d", placed, sent)
+	}
+	if repo.statuses["JRF-BUY-1"] != "FILLED" {
+		t.Errorf("expected buy order to be released, got %s", repo.statuses["JRF-BUY-1"])
+	}
+}
+
+func TestTakeProfitEngine_SendFailureKeepsClaim(t *testing.T) {
+	repo := newTakeProfitRepo(
+		model.BuyOrder{OrderID: "JRF-BUY-1", ProductCode: "BTC_JPY", Price: 10000000, Size: 0.001, Status: "FILLED", Strategy: 99},
+	)
+	mockClient := &client.MockBitFlyerClient{
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			return nil, errors.New("timeout")
+		},
+	}
+
+	engine := NewTakeProfitEngine(mockClient, repo, &TakeProfitConfig{Markups: map[string]float64{}}, testProductRegistry())
+
+	placed, err := engine.ProcessOnce(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if placed != 0 {
+		t.Errorf("expected no sell order to be placed, got %d", placed)
+	}
+	// The order may have reached the exchange, so it must be reconciled rather than resent blindly
+	if repo.statuses["JRF-BUY-1"] != "FILLED(SELL ORDER PENDING)" {
+		t.Errorf("expected buy order to stay pending, got %s", repo.statuses["JRF-BUY-1"])
+	}
+}
@@ -1,39 +1,44 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
 	"github.com/crypto-trading-connector/backend/internal/repository"
 )
 
 // TradeHistoryService defines the interface for trade history business logic
 type TradeHistoryService interface {
-	GetTradeStatistics(assetFilter, timeFilter string) (*generated.TradeStatistics, error)
-	GetTradeTransactions(assetFilter, timeFilter string, page, limit int) (*generated.TransactionLogResponse, error)
+	GetTradeStatistics(ctx context.Context, assetFilter, timeFilter string) (*generated.TradeStatistics, error)
+	GetTradeTransactions(ctx context.Context, assetFilter, timeFilter string, page, limit int) (*generated.TransactionLogResponse, error)
 }
 
 // TradeHistoryServiceImpl implements TradeHistoryService
 type TradeHistoryServiceImpl struct {
 	tradeHistoryRepo repository.TradeHistoryRepository
+	assets           *model.AssetCatalog
 }
 
 // NewTradeHistoryService creates a new trade history service
-func NewTradeHistoryService(tradeHistoryRepo repository.TradeHistoryRepository) *TradeHistoryServiceImpl {
+func NewTradeHistoryService(tradeHistoryRepo repository.TradeHistoryRepository, assets *model.AssetCatalog) *TradeHistoryServiceImpl {
 	return &TradeHistoryServiceImpl{
 		tradeHistoryRepo: tradeHistoryRepo,
+		assets:           assets,
 	}
 }
 
 // GetTradeStatistics retrieves aggregated trade statistics with filtering
-func (s *TradeHistoryServiceImpl) GetTradeStatistics(assetFilter, timeFilter string) (*generated.TradeStatistics, error) {
+func (s *TradeHistoryServiceImpl) GetTradeStatistics(ctx context.Context, assetFilter, timeFilter string) (*generated.TradeStatistics, error) {
 	// Validate filters
 	if err := s.validateFilters(assetFilter, timeFilter); err != nil {
 		return nil, err
 	}
 
 	// Get statistics from repository
-	statistics, err := s.tradeHistoryRepo.GetTradeStatistics(assetFilter, timeFilter)
+	statistics, err := s.tradeHistoryRepo.GetTradeStatistics(ctx, assetFilter, timeFilter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trade statistics: %w", err)
 	}
@@ -42,7 +47,7 @@ func (s *TradeHistoryServiceImpl) GetTradeStatistics(assetFilter, timeFilter str
 }
 
 // GetTradeTransactions retrieves paginated trade transactions with filtering
-func (s *TradeHistoryServiceImpl) GetTradeTransactions(assetFilter, timeFilter string, page, limit int) (*generated.TransactionLogResponse, error) {
+func (s *TradeHistoryServiceImpl) GetTradeTransactions(ctx context.Context, assetFilter, timeFilter string, page, limit int) (*generated.TransactionLogResponse, error) {
 	// Validate filters
 	if err := s.validateFilters(assetFilter, timeFilter); err != nil {
 		return nil, err
@@ -54,7 +59,7 @@ func (s *TradeHistoryServiceImpl) GetTradeTransactions(assetFilter, timeFilter s
 	}
 
 	// Get transactions from repository
-	response, err := s.tradeHistoryRepo.GetTradeTransactions(assetFilter, timeFilter, page, limit)
+	response, err := s.tradeHistoryRepo.GetTradeTransactions(ctx, assetFilter, timeFilter, page, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trade transactions: %w", err)
 	}
@@ -64,14 +69,9 @@ func (s *TradeHistoryServiceImpl) GetTradeTransactions(assetFilter, timeFilter s
 
 // validateFilters validates asset and time filter parameters
 func (s *TradeHistoryServiceImpl) validateFilters(assetFilter, timeFilter string) error {
-	// Validate asset filter
-	validAssetFilters := map[string]bool{
-		"all": true,
-		"BTC": true,
-		"ETH": true,
-	}
-	if !validAssetFilters[assetFilter] {
-		return fmt.Errorf("invalid asset filter: %s. Valid values are: all, BTC, ETH", assetFilter)
+	// Validate asset filter against the currency codes of the asset catalog
+	if _, ok := s.assets.ByCode(assetFilter); !ok && assetFilter != "all" {
+		return invalid(ErrInvalidFilter, "invalid asset filter: %s. Valid values are: all, %s", assetFilter, strings.Join(s.assets.Codes(), ", "))
 	}
 
 	// Validate time filter
@@ -80,7 +80,7 @@ func (s *TradeHistoryServiceImpl) validateFilters(assetFilter, timeFilter string
 		"7days": true,
 	}
 	if !validTimeFilters[timeFilter] {
-		return fmt.Errorf("invalid time filter: %s. Valid values are: all, 7days", timeFilter)
+		return invalid(ErrInvalidFilter, "invalid time filter: %s. Valid values are: all, 7days", timeFilter)
 	}
 
 	return nil
@@ -89,15 +89,15 @@ func (s *TradeHistoryServiceImpl) validateFilters(assetFilter, timeFilter string
 // validatePagination validates pagination parameters
 func (s *TradeHistoryServiceImpl) validatePagination(page, limit int) error {
 	if page < 1 {
-		return fmt.Errorf("invalid page: %d. Page must be >= 1", page)
+		return invalid(ErrInvalidPagination, "invalid page: %d. Page must be >= 1", page)
 	}
 
 	if limit < 1 {
-		return fmt.Errorf("invalid limit: %d. Limit must be >= 1", limit)
+		return invalid(ErrInvalidPagination, "invalid limit: %d. Limit must be >= 1", limit)
 	}
 
 	if limit > 100 {
-		return fmt.Errorf("invalid limit: %d. Limit must be <= 100", limit)
+		return invalid(ErrInvalidPagination, "invalid limit: %d. Limit must be <= 100", limit)
 	}
 
 	return nil
@@ -1,11 +1,13 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -16,7 +18,7 @@ type MockTradeHistoryRepository struct {
 	mock.Mock
 }
 
-func (m *MockTradeHistoryRepository) GetTradeStatistics(assetFilter, timeFilter string) (*generated.TradeStatistics, error) {
+func (m *MockTradeHistoryRepository) GetTradeStatistics(ctx context.Context, assetFilter, timeFilter string) (*generated.TradeStatistics, error) {
 	args := m.Called(assetFilter, timeFilter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -24,7 +26,7 @@ func (m *MockTradeHistoryRepository) GetTradeStatistics(assetFilter, timeFilter
 	return args.Get(0).(*generated.TradeStatistics), args.Error(1)
 }
 
-func (m *MockTradeHistoryRepository) GetTradeTransactions(assetFilter, timeFilter string, page, limit int) (*generated.TransactionLogResponse, error) {
+func (m *MockTradeHistoryRepository) GetTradeTransactions(ctx context.Context, assetFilter, timeFilter string, page, limit int) (*generated.TransactionLogResponse, error) {
 	args := m.Called(assetFilter, timeFilter, page, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -32,7 +34,7 @@ func (m *MockTradeHistoryRepository) GetTradeTransactions(assetFilter, timeFilte
 	return args.Get(0).(*generated.TransactionLogResponse), args.Error(1)
 }
 
-func (m *MockTradeHistoryRepository) GetTotalTransactionCount(assetFilter, timeFilter string) (int, error) {
+func (m *MockTradeHistoryRepository) GetTotalTransactionCount(ctx context.Context, assetFilter, timeFilter string) (int, error) {
 	args := m.Called(assetFilter, timeFilter)
 	return args.Int(0), args.Error(1)
 }
@@ -84,7 +86,7 @@ func TestTradeHistoryService_ProfitCalculation_Property(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(MockTradeHistoryRepository)
-			service := NewTradeHistoryService(mockRepo)
+			service := NewTradeHistoryService(mockRepo, model.DefaultAssetCatalog())
 
 			// Create mock transaction with calculated profit
 			calculatedProfit := (tt.sellPrice - tt.buyPrice) * tt.amount
@@ -92,7 +94,7 @@ func TestTradeHistoryService_ProfitCalculation_Property(t *testing.T) {
 
 			transaction := generated.Transaction{
 				Id:             "1",
-				Cryptocurrency: generated.Bitcoin,
+				Cryptocurrency: "Bitcoin",
 				Timestamp:      time.Now(),
 				Profit:         roundedProfit,
 				OrderType:      generated.Sell,
@@ -115,7 +117,7 @@ func TestTradeHistoryService_ProfitCalculation_Property(t *testing.T) {
 
 			mockRepo.On("GetTradeTransactions", "all", "all", 1, 10).Return(mockResponse, nil)
 
-			result, err := service.GetTradeTransactions("all", "all", 1, 10)
+			result, err := service.GetTradeTransactions(context.Background(), "all", "all", 1, 10)
 			require.NoError(t, err)
 			require.Len(t, result.Transactions, 1)
 
@@ -165,7 +167,7 @@ func TestTradeHistoryService_StatisticsConsistency_Property(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(MockTradeHistoryRepository)
-			service := NewTradeHistoryService(mockRepo)
+			service := NewTradeHistoryService(mockRepo, model.DefaultAssetCatalog())
 
 			// Mock statistics
 			expectedStats := &generated.TradeStatistics{
@@ -179,7 +181,7 @@ func TestTradeHistoryService_StatisticsConsistency_Property(t *testing.T) {
 			transactions := []generated.Transaction{
 				{
 					Id:             "1",
-					Cryptocurrency: generated.Bitcoin,
+					Cryptocurrency: "Bitcoin",
 					Timestamp:      time.Now(),
 					Profit:         25000.0,
 					OrderType:      generated.Sell,
@@ -191,7 +193,7 @@ func TestTradeHistoryService_StatisticsConsistency_Property(t *testing.T) {
 				},
 				{
 					Id:             "2",
-					Cryptocurrency: generated.Ethereum,
+					Cryptocurrency: "Ethereum",
 					Timestamp:      time.Now(),
 					Profit:         25000.0,
 					OrderType:      generated.Sell,
@@ -202,9 +204,9 @@ func TestTradeHistoryService_StatisticsConsistency_Property(t *testing.T) {
 					BuyOrderId:     "BUY002",
 				},
 				// Add more transactions to reach total of 5 with 125000.0 total profit
-				{Id: "3", Profit: 25000.0, Cryptocurrency: generated.Bitcoin},
-				{Id: "4", Profit: 25000.0, Cryptocurrency: generated.Ethereum},
-				{Id: "5", Profit: 25000.0, Cryptocurrency: generated.Bitcoin},
+				{Id: "3", Profit: 25000.0, Cryptocurrency: "Bitcoin"},
+				{Id: "4", Profit: 25000.0, Cryptocurrency: "Ethereum"},
+				{Id: "5", Profit: 25000.0, Cryptocurrency: "Bitcoin"},
 			}
 
 			mockTransactionResponse := &generated.TransactionLogResponse{
@@ -221,11 +223,11 @@ func TestTradeHistoryService_StatisticsConsistency_Property(t *testing.T) {
 			mockRepo.On("GetTradeTransactions", tt.assetFilter, tt.timeFilter, 1, 100).Return(mockTransactionResponse, nil)
 
 			// Get statistics
-			stats, err := service.GetTradeStatistics(tt.assetFilter, tt.timeFilter)
+			stats, err := service.GetTradeStatistics(context.Background(), tt.assetFilter, tt.timeFilter)
 			require.NoError(t, err)
 
 			// Get all transactions (using large limit to get all)
-			transactionResponse, err := service.GetTradeTransactions(tt.assetFilter, tt.timeFilter, 1, 100)
+			transactionResponse, err := service.GetTradeTransactions(context.Background(), tt.assetFilter, tt.timeFilter, 1, 100)
 			require.NoError(t, err)
 
 			// Verify consistency between statistics and transactions
@@ -255,12 +257,12 @@ func TestTradeHistoryService_StatisticsConsistency_Property(t *testing.T) {
 
 func TestTradeHistoryService_TransactionCompleteness_Property(t *testing.T) {
 	mockRepo := new(MockTradeHistoryRepository)
-	service := NewTradeHistoryService(mockRepo)
+	service := NewTradeHistoryService(mockRepo, model.DefaultAssetCatalog())
 
 	// Create a complete transaction
 	completeTransaction := generated.Transaction{
 		Id:             "TXN001",
-		Cryptocurrency: generated.Bitcoin,
+		Cryptocurrency: "Bitcoin",
 		Timestamp:      time.Now(),
 		Profit:         25000.0,
 		OrderType:      generated.Sell,
@@ -283,7 +285,7 @@ func TestTradeHistoryService_TransactionCompleteness_Property(t *testing.T) {
 
 	mockRepo.On("GetTradeTransactions", "all", "all", 1, 10).Return(mockResponse, nil)
 
-	result, err := service.GetTradeTransactions("all", "all", 1, 10)
+	result, err := service.GetTradeTransactions(context.Background(), "all", "all", 1, 10)
 	require.NoError(t, err)
 	require.Len(t, result.Transactions, 1)
 
@@ -301,9 +303,9 @@ func TestTradeHistoryService_TransactionCompleteness_Property(t *testing.T) {
 	assert.Greater(t, transaction.Amount, 0.0, "Amount should be positive")
 
 	// Verify cryptocurrency is valid enum value
-	validCryptocurrencies := []generated.TransactionCryptocurrency{
-		generated.Bitcoin,
-		generated.Ethereum,
+	validCryptocurrencies := []string{
+		"Bitcoin",
+		"Ethereum",
 	}
 	assert.Contains(t, validCryptocurrencies, transaction.Cryptocurrency, "Cryptocurrency should be valid enum value")
 
@@ -315,7 +317,7 @@ func TestTradeHistoryService_TransactionCompleteness_Property(t *testing.T) {
 
 func TestTradeHistoryService_ValidateFilters(t *testing.T) {
 	mockRepo := new(MockTradeHistoryRepository)
-	service := NewTradeHistoryService(mockRepo)
+	service := NewTradeHistoryService(mockRepo, model.DefaultAssetCatalog())
 
 	tests := []struct {
 		name        string
@@ -349,7 +351,7 @@ func TestTradeHistoryService_ValidateFilters(t *testing.T) {
 				mockRepo.On("GetTradeStatistics", tt.assetFilter, tt.timeFilter).Return(&generated.TradeStatistics{}, nil)
 			}
 
-			_, err := service.GetTradeStatistics(tt.assetFilter, tt.timeFilter)
+			_, err := service.GetTradeStatistics(context.Background(), tt.assetFilter, tt.timeFilter)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -362,7 +364,7 @@ func TestTradeHistoryService_ValidateFilters(t *testing.T) {
 
 func TestTradeHistoryService_ValidatePagination(t *testing.T) {
 	mockRepo := new(MockTradeHistoryRepository)
-	service := NewTradeHistoryService(mockRepo)
+	service := NewTradeHistoryService(mockRepo, model.DefaultAssetCatalog())
 
 	tests := []struct {
 		name        string
@@ -402,7 +404,7 @@ func TestTradeHistoryService_ValidatePagination(t *testing.T) {
 				mockRepo.On("GetTradeTransactions", "all", "all", tt.page, tt.limit).Return(&generated.TransactionLogResponse{}, nil)
 			}
 
-			_, err := service.GetTradeTransactions("all", "all", tt.page, tt.limit)
+			_, err := service.GetTradeTransactions(context.Background(), "all", "all", tt.page, tt.limit)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -415,17 +417,17 @@ func TestTradeHistoryService_ValidatePagination(t *testing.T) {
 
 func TestTradeHistoryService_ErrorHandling(t *testing.T) {
 	mockRepo := new(MockTradeHistoryRepository)
-	service := NewTradeHistoryService(mockRepo)
+	service := NewTradeHistoryService(mockRepo, model.DefaultAssetCatalog())
 
 	// Test repository error handling
 	mockRepo.On("GetTradeStatistics", "all", "all").Return(nil, errors.New("database error"))
 	mockRepo.On("GetTradeTransactions", "all", "all", 1, 10).Return(nil, errors.New("database error"))
 
-	_, err := service.GetTradeStatistics("all", "all")
+	_, err := service.GetTradeStatistics(context.Background(), "all", "all")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to get trade statistics")
 
-	_, err = service.GetTradeTransactions("all", "all", 1, 10)
+	_, err = service.GetTradeTransactions(context.Background(), "all", "all", 1, 10)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to get trade transactions")
 
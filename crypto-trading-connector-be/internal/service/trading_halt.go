@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+	"github.com/crypto-trading-connector/backend/internal/repository"
+)
+
+// maxTradingHaltReasonLength is the longest reason accepted, the size of the reason column
+const maxTradingHaltReasonLength = 255
+
+// TradingHaltService defines the interface for the trading kill switch
+type TradingHaltService interface {
+	GetTradingHalt(ctx context.Context) (*generated.TradingHalt, error)
+	UpdateTradingHalt(ctx context.Context, req *generated.UpdateTradingHaltRequest) (*generated.UpdateTradingHaltResponse, error)
+}
+
+// TradingHaltServiceImpl implements TradingHaltService
+type TradingHaltServiceImpl struct {
+	exchangeClient client.CryptoExchangeClient
+	repo           repository.TradingHaltRepository
+	orderRepo      repository.OrderRepository
+	assets         *model.AssetCatalog
+	now            func() time.Time
+}
+
+// NewTradingHaltService creates a new trading halt service. Open orders are cancelled for the pairs of assets
+// and recorded as cancelled in orderRepo.
+func NewTradingHaltService(exchangeClient client.CryptoExchangeClient, repo repository.TradingHaltRepository, orderRepo repository.OrderRepository, assets *model.AssetCatalog) *TradingHaltServiceImpl {
+	return &TradingHaltServiceImpl{
+		exchangeClient: exchangeClient,
+		repo:           repo,
+		orderRepo:      orderRepo,
+		assets:         assets,
+		now:            time.Now,
+	}
+}
+
+// GetTradingHalt returns the state of the trading halt
+func (s *TradingHaltServiceImpl) GetTradingHalt(ctx context.Context) (*generated.TradingHalt, error) {
+	halt, err := s.repo.GetTradingHalt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toTradingHalt(halt), nil
+}
+
+// UpdateTradingHalt engages or releases the trading halt. With CancelOpenOrders the open orders of every pair
+// are then cancelled on their exchange; a pair that fails is reported without stopping the others.
+func (s *TradingHaltServiceImpl) UpdateTradingHalt(ctx context.Context, req *generated.UpdateTradingHaltRequest) (*generated.UpdateTradingHaltResponse, error) {
+	var reason *string
+	if req.Reason != nil {
+		if trimmed := strings.TrimSpace(*req.Reason); trimmed != "" {
+			reason = &trimmed
+		}
+	}
+	if reason != nil && len(*reason) > maxTradingHaltReasonLength {
+		return nil, invalid(ErrInvalidRequest, "invalid reason: must be at most %d characters", maxTradingHaltReasonLength)
+	}
+
+	now := s.now()
+	halt := &model.TradingHalt{Halted: req.Halted, Reason: reason, UpdatedAt: &now}
+	if err := s.repo.SaveTradingHalt(ctx, halt); err != nil {
+		return nil, err
+	}
+
+	action := "released"
+	if halt.Halted {
+		action = "engaged"
+	}
+	log.Printf("Audit: trading halt %s (reason: %s)", action, stringOrNone(reason))
+
+	response := &generated.UpdateTradingHaltResponse{TradingHalt: *toTradingHalt(halt)}
+	if req.CancelOpenOrders != nil && *req.CancelOpenOrders {
+		cancellations := s.cancelOpenOrders(ctx)
+		response.Cancellations = &cancellations
+	}
+
+	return response, nil
+}
+
+// cancelOpenOrders asks the exchange of every pair to cancel all of its open orders, then records them as
+// cancelled so that their funds and their count are no longer reserved once trading resumes
+func (s *TradingHaltServiceImpl) cancelOpenOrders(ctx context.Context) []generated.PairCancellation {
+	// Cancelling must not stop halfway because the caller gave up
+	ctx = context.WithoutCancel(ctx)
+
+	cancellations := make([]generated.PairCancellation, 0, len(s.assets.Assets()))
+	for _, asset := range s.assets.Assets() {
+		cancellation := generated.PairCancellation{Pair: string(asset.Pair), Cancelled: true}
+		exchangeClient := client.ForSymbol(s.exchangeClient, asset.Pair)
+		if err := exchangeClient.CancelAllOrders(ctx, asset.Pair.ProductCode()); err != nil {
+			message := err.Error()
+			cancellation.Cancelled, cancellation.Message = false, &message
+			log.Printf("Warning: failed to cancel open %s orders on %s: %v", asset.Pair, exchangeClient.Name(), err)
+		} else {
+			log.Printf("Audit: cancelled open %s orders on %s", asset.Pair, exchangeClient.Name())
+			s.markCancelled(ctx, asset.Pair.ProductCode(), exchangeClient.Name())
+		}
+		cancellations = append(cancellations, cancellation)
+	}
+	return cancellations
+}
+
+// markCancelled moves the recorded open orders of a pair on an exchange to CANCELLED. Orders changed in the
+// meantime, e.g. filled before the cancellation, are left alone; the synchronizer records any order missed here.
+func (s *TradingHaltServiceImpl) markCancelled(ctx context.Context, productCode, exchange string) {
+	orders, err := s.orderRepo.ListOpenOrders(ctx)
+	if err != nil {
+		log.Printf("Warning: failed to list open orders, the synchronizer records the cancellation: %v", err)
+		return
+	}
+
+	for _, order := range orders {
+		// Orders recorded without an exchange went to the exchange of their pair
+		if order.ProductCode != productCode || (order.Exchange != "" && order.Exchange != exchange) {
+			continue
+		}
+		if _, err := s.orderRepo.TransitionOrderStatus(ctx, order.Side, order.OrderID, order.Status, model.OrderStatusCancelled); err != nil {
+			log.Printf("Warning: failed to update order %s to %s: %v", order.OrderID, model.OrderStatusCancelled, err)
+		}
+	}
+}
+
+// toTradingHalt converts the trading halt into the API representation
+func toTradingHalt(halt *model.TradingHalt) *generated.TradingHalt {
+	return &generated.TradingHalt{
+		Halted:    halt.Halted,
+		Reason:    halt.Reason,
+		UpdatedAt: halt.UpdatedAt,
+	}
+}
+
+// stringOrNone returns the string, or "none" if it is nil
+func stringOrNone(s *string) string {
+	if s == nil {
+		return "none"
+	}
+	return *s
+}
+
+// tradingHalted reports whether trading is halted, for responses that show it. A halt that cannot be read is
+// reported as engaged: orders are rejected while it cannot be read. Without a repository trading is never halted.
+func tradingHalted(ctx context.Context, halts repository.TradingHaltRepository) bool {
+	if halts == nil {
+		return false
+	}
+	halt, err := halts.GetTradingHalt(ctx)
+	if err != nil {
+		log.Printf("Warning: failed to get trading halt: %v", err)
+		return true
+	}
+	return halt.Halted
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crypto-trading-connector/backend/internal/client"
+	"github.com/crypto-trading-connector/backend/internal/generated"
+	"github.com/crypto-trading-connector/backend/internal/model"
+)
+
+// fakeTradingHaltRepository keeps the trading halt in memory
+type fakeTradingHaltRepository struct {
+	halt model.TradingHalt
+	err  error
+}
+
+func (r *fakeTradingHaltRepository) GetTradingHalt(ctx context.Context) (*model.TradingHalt, error) {
+	// A query on an expired context fails like it would with the database
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	halt := r.halt
+	return &halt, nil
+}
+
+func (r *fakeTradingHaltRepository) SaveTradingHalt(ctx context.Context, halt *model.TradingHalt) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.halt = *halt
+	return nil
+}
+
+func TestTradingHaltService_UpdateTradingHalt(t *testing.T) {
+	var cancelled []string
+	exchangeClient := &client.MockBitFlyerClient{
+		CancelAllOrdersFunc: func(ctx context.Context, productCode string) error {
+			cancelled = append(cancelled, productCode)
+			if productCode == "ETH_JPY" {
+				return errors.New("exchange unavailable")
+			}
+			return nil
+		},
+	}
+	repo := &fakeTradingHaltRepository{}
+	openOrders := []model.OrderRecord{
+		{OrderID: "JRF-BUY-1", ProductCode: "BTC_JPY", Side: "BUY", Exchange: "bitflyer", Status: model.OrderStatusUnfilled},
+		{OrderID: "JRF-SELL-1", ProductCode: "BTC_JPY", Side: "SELL", Status: model.OrderStatusPartiallyFilled},
+		{OrderID: "GMO-BUY-1", ProductCode: "BTC_JPY", Side: "BUY", Exchange: "gmocoin", Status: model.OrderStatusUnfilled},
+		{OrderID: "JRF-BUY-2", ProductCode: "ETH_JPY", Side: "BUY", Exchange: "bitflyer", Status: model.OrderStatusUnfilled},
+	}
+	var transitions []string
+	orderRepo := &MockOrderRepository{
+		ListOpenOrdersFunc: func(ctx context.Context) ([]model.OrderRecord, error) {
+			return openOrders, nil
+		},
+		TransitionStatusFunc: func(ctx context.Context, side, orderID, fromStatus, toStatus string) (bool, error) {
+			transitions = append(transitions, fmt.Sprintf("%s %s %s->%s", side, orderID, fromStatus, toStatus))
+			return true, nil
+		},
+	}
+	service := NewTradingHaltService(exchangeClient, repo, orderRepo, model.DefaultAssetCatalog())
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	service.now = func() time.Time { return now }
+
+	reason := "  exchange incident "
+	cancelOpenOrders := true
+	response, err := service.UpdateTradingHalt(context.Background(), &generated.UpdateTradingHaltRequest{
+		Halted:           true,
+		Reason:           &reason,
+		CancelOpenOrders: &cancelOpenOrders,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !repo.halt.Halted || repo.halt.Reason == nil || *repo.halt.Reason != "exchange incident" || !repo.halt.UpdatedAt.Equal(now) {
+		t.Errorf("unexpected saved halt %+v", repo.halt)
+	}
+	if !response.TradingHalt.Halted {
+		t.Errorf("expected the halt to be engaged, got %+v", response.TradingHalt)
+	}
+	if len(cancelled) != 2 {
+		t.Fatalf("expected the orders of every pair to be cancelled, got %v", cancelled)
+	}
+	if response.Cancellations == nil || len(*response.Cancellations) != 2 {
+		t.Fatalf("expected 2 cancellations, got %v", response.Cancellations)
+	}
+	for _, cancellation := range *response.Cancellations {
+		failed := cancellation.Pair == "ETH/JPY"
+		if cancellation.Cancelled == failed || (cancellation.Message != nil) != failed {
+			t.Errorf("unexpected cancellation %+v", cancellation)
+		}
+	}
+	// Only the orders of the pair cancelled on its exchange are recorded as cancelled
+	expected := []string{"BUY JRF-BUY-1 UNFILLED->CANCELLED", "SELL JRF-SELL-1 PARTIALLY_FILLED->CANCELLED"}
+	if strings.Join(transitions, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected transitions %v, got %v", expected, transitions)
+	}
+
+	// Releasing without cancelling leaves the open orders alone
+	response, err = service.UpdateTradingHalt(context.Background(), &generated.UpdateTradingHaltRequest{Halted: false})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.halt.Halted || repo.halt.Reason != nil || response.Cancellations != nil || len(cancelled) != 2 {
+		t.Errorf("unexpected release: halt %+v, cancellations %v", repo.halt, response.Cancellations)
+	}
+}
+
+func TestTradingHaltService_UpdateTradingHalt_ReasonTooLong(t *testing.T) {
+	repo := &fakeTradingHaltRepository{}
+	service := NewTradingHaltService(&client.MockBitFlyerClient{}, repo, &MockOrderRepository{}, model.DefaultAssetCatalog())
+
+	reason := strings.Repeat("a", maxTradingHaltReasonLength+1)
+	_, err := service.UpdateTradingHalt(context.Background(), &generated.UpdateTradingHaltRequest{Halted: true, Reason: &reason})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+	if repo.halt.Halted {
+		t.Error("expected the halt not to be engaged")
+	}
+}
+
+func TestOrderService_TradingHalted(t *testing.T) {
+	sent := 0
+	mockClient := &client.MockBitFlyerClient{
+		GetBalancesFunc: func(ctx context.Context) ([]model.Balance, error) {
+			return []model.Balance{{Currency: "JPY", Amount: 2000000, Available: 2000000}}, nil
+		},
+		SendOrderFunc: func(ctx context.Context, req *model.OrderRequest) (*model.OrderResponse, error) {
+			sent++
+			return &model.OrderResponse{OrderID: "JRF20240101-000000-000001"}, nil
+		},
+	}
+	reason := "exchange incident"
+	halts := &fakeTradingHaltRepository{halt: model.TradingHalt{Halted: true, Reason: &reason}}
+	service := NewOrderService(mockClient, &MockOrderRepository{}, testProductRegistry())
+	service.SetTradingHalts(halts)
+
+	req := &generated.CreateOrderRequest{
+		Pair:      "BTC/JPY",
+		OrderType: generated.CreateOrderRequestOrderTypeLimit,
+		Price:     14000000,
+		Amount:    0.001,
+	}
+	if _, err := service.CreateOrder(context.Background(), nil, req); !errors.Is(err, ErrTradingHalted) {
+		t.Fatalf("expected ErrTradingHalted, got %v", err)
+	}
+	if sent != 0 {
+		t.Errorf("expected no order sent while halted, got %d", sent)
+	}
+	balance, err := service.GetBalance(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !balance.TradingHalted {
+		t.Error("expected the balance to report the halt")
+	}
+
+	halts.halt = model.TradingHalt{}
+	if _, err := service.CreateOrder(context.Background(), nil, req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sent != 1 {
+		t.Errorf("expected 1 order sent, got %d", sent)
+	}
+	if balance, err = service.GetBalance(context.Background()); err != nil || balance.TradingHalted {
+		t.Errorf("expected the balance not to report a halt, got %+v, %v", balance, err)
+	}
+}
@@ -215,9 +215,8 @@ export interface components {
         };
         Order: {
             /**
-             * Format: uuid
-             * @description Unique order identifier
-             * @example 550e8400-e29b-41d4-a716-446655440000
+             * @description Exchange order ID (child order acceptance ID), used by GET and DELETE /orders/{orderId}
+             * @example JRF20150707-050237-639234
              */
             orderId: string;
             /**
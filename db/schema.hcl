@@ -48,7 +48,7 @@ table "buy_orders" {
     type = varchar(100)
     null = true
     default = "UNFILLED"
-    comment = "UNFILLED / FILLED / FILLED(SELL ORDER PLACED) / CANCELLED"
+    comment = "UNFILLED / PARTIALLY_FILLED / FILLED / FILLED(SELL ORDER PENDING) / FILLED(SELL ORDER PLACED) / CANCELLED / EXPIRED"
   }
 
   column "strategy" {
@@ -58,6 +58,12 @@ table "buy_orders" {
     comment = "99:not recorded"
   }
 
+  column "parent_order_id" {
+    type = varchar(50)
+    null = true
+    comment = "parent_orders.parent_order_acceptance_id when placed as a leg of an IFD / OCO / IFDOCO order"
+  }
+
   column "remarks" {
     type = text
     null = true
@@ -136,7 +142,13 @@ table "sell_orders" {
     type = varchar(100)
     null = true
     default = "UNFILLED"
-    comment = "UNFILLED / FILLED / CANCELLED"
+    comment = "UNFILLED / PARTIALLY_FILLED / FILLED / CANCELLED / EXPIRED"
+  }
+
+  column "parent_order_id" {
+    type = varchar(50)
+    null = true
+    comment = "parent_orders.parent_order_acceptance_id when placed as a leg of an IFD / OCO / IFDOCO order"
   }
 
   column "remarks" {
@@ -167,6 +179,263 @@ table "sell_orders" {
   }
 }
 
+table "parent_orders" {
+  schema = schema.crypto_trading_db
+  comment = "bitflyer_parentorders (IFD / OCO / IFDOCO)"
+
+  column "id" {
+    type = int
+    unsigned = true
+    null = false
+    auto_increment = true
+  }
+
+  column "parent_order_acceptance_id" {
+    type = varchar(50)
+    null = false
+  }
+
+  column "parent_order_id" {
+    type = varchar(50)
+    null = true
+    comment = "Exchange parent order ID, known once the order is accepted"
+  }
+
+  column "order_method" {
+    type = varchar(20)
+    null = false
+    comment = "IFD / OCO / IFDOCO"
+  }
+
+  column "product_code" {
+    type = varchar(50)
+    null = false
+  }
+
+  column "legs" {
+    type = text
+    null = false
+    comment = "JSON array of the order conditions in exchange order"
+  }
+
+  column "exchange" {
+    type = varchar(50)
+    null = true
+  }
+
+  column "status" {
+    type = varchar(100)
+    null = false
+    default = "ACTIVE"
+    comment = "ACTIVE / COMPLETED / CANCELLED / EXPIRED"
+  }
+
+  column "timestamp" {
+    type = timestamp
+    null = false
+    default = sql("CURRENT_TIMESTAMP")
+  }
+
+  column "updatetime" {
+    type = timestamp
+    null = false
+    default = sql("CURRENT_TIMESTAMP")
+    on_update = sql("CURRENT_TIMESTAMP")
+  }
+
+  primary_key {
+    columns = [column.id]
+  }
+
+  index "parentOrderAcceptanceId" {
+    unique = true
+    columns = [column.parent_order_acceptance_id]
+  }
+}
+
+table "order_idempotency_keys" {
+  schema = schema.crypto_trading_db
+  comment = "注文APIの冪等キー（同じキーの再送で二重発注しないため、発注前に保存）"
+
+  column "idempotency_key" {
+    type = varchar(255)
+    null = false
+  }
+
+  column "client_order_id" {
+    type = varchar(64)
+    null = false
+    comment = "リクエストごとに生成する注文ID（取引所の注文IDとは独立）"
+  }
+
+  column "request_hash" {
+    type = char(64)
+    null = false
+    comment = "リクエストボディのSHA-256"
+  }
+
+  column "status" {
+    type = varchar(20)
+    null = false
+    comment = "IN_FLIGHT / COMPLETED"
+  }
+
+  column "order_id" {
+    type = varchar(255)
+    null = true
+    comment = "取引所の注文ID"
+  }
+
+  column "response" {
+    type = text
+    null = true
+    comment = "最初のリクエストに返した注文（JSON）"
+  }
+
+  column "created_at" {
+    type = timestamp
+    null = false
+    default = sql("CURRENT_TIMESTAMP")
+  }
+
+  column "expires_at" {
+    type = timestamp
+    null = false
+  }
+
+  primary_key {
+    columns = [column.idempotency_key]
+  }
+
+  index "idx_expires_at" {
+    columns = [column.expires_at]
+  }
+}
+
+table "order_journal" {
+  schema = schema.crypto_trading_db
+  comment = "注文ジャーナル（発注前に記録し、取引所が受け付けた注文をDBに保存し損ねても起動時に復旧するため）"
+
+  column "client_order_id" {
+    type = varchar(64)
+    null = false
+    comment = "リクエストごとに生成する注文ID"
+  }
+
+  column "exchange" {
+    type = varchar(20)
+    null = false
+    comment = "発注先の取引所"
+  }
+
+  column "product_code" {
+    type = varchar(20)
+    null = false
+  }
+
+  column "side" {
+    type = varchar(10)
+    null = false
+    comment = "BUY / SELL"
+  }
+
+  column "order_type" {
+    type = varchar(10)
+    null = false
+    comment = "LIMIT / MARKET"
+  }
+
+  column "price" {
+    type = double
+    null = false
+    comment = "取引所に送った指値（成行は0）。取引所の注文と照合するため送信値のまま保存"
+  }
+
+  column "recorded_price" {
+    type = double
+    null = false
+    comment = "buy_orders / sell_orders に保存する価格（成行は見積価格）"
+  }
+
+  column "size" {
+    type = double
+    null = false
+  }
+
+  column "status" {
+    type = varchar(20)
+    null = false
+    comment = "PENDING / ACCEPTED / RECORDED / FAILED / NOT_FOUND"
+  }
+
+  column "order_id" {
+    type = varchar(255)
+    null = true
+    comment = "取引所の注文ID（受付後）"
+  }
+
+  column "message" {
+    type = text
+    null = true
+    comment = "失敗・保存できなかった理由"
+  }
+
+  column "created_at" {
+    type = timestamp
+    null = false
+    default = sql("CURRENT_TIMESTAMP")
+  }
+
+  column "updated_at" {
+    type = timestamp
+    null = false
+    default = sql("CURRENT_TIMESTAMP")
+    on_update = sql("CURRENT_TIMESTAMP")
+  }
+
+  primary_key {
+    columns = [column.client_order_id]
+  }
+
+  index "idx_status_created_at" {
+    columns = [column.status, column.created_at]
+  }
+}
+
+table "trading_halt" {
+  schema = schema.crypto_trading_db
+  comment = "取引停止スイッチ（1行のみ。停止中はサーバー・CLIのすべての注文を拒否）"
+
+  column "id" {
+    type = tinyint
+    unsigned = true
+    null = false
+    comment = "常に1"
+  }
+
+  column "halted" {
+    type = bool
+    null = false
+    default = false
+  }
+
+  column "reason" {
+    type = varchar(255)
+    null = true
+    comment = "停止・解除の理由"
+  }
+
+  column "updated_at" {
+    type = timestamp
+    null = false
+    default = sql("CURRENT_TIMESTAMP")
+  }
+
+  primary_key {
+    columns = [column.id]
+  }
+}
+
 table "price_histories" {
   schema = schema.crypto_trading_db
   comment = "価格履歴テーブル"